@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS tokens_email_purpose_idx;
+
+ALTER TABLE tokens
+DROP COLUMN created_at,
+DROP COLUMN purpose;
@@ -0,0 +1,5 @@
+ALTER TABLE tokens
+ADD COLUMN purpose VARCHAR(50) NOT NULL DEFAULT '',
+ADD COLUMN created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS tokens_email_purpose_idx ON tokens (email, purpose);
@@ -0,0 +1,2 @@
+ALTER TABLE users
+ADD COLUMN name VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS user_mfa (
+	user_id UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+	secret TEXT NOT NULL,
+	last_used_step BIGINT NOT NULL DEFAULT 0,
+	confirmed_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
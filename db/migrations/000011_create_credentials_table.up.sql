@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS credentials (
+	id TEXT PRIMARY KEY,
+	user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	public_key BYTEA NOT NULL,
+	sign_count BIGINT NOT NULL DEFAULT 0,
+	created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+	last_used_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS credentials_user_id_idx ON credentials (user_id);
+
+CREATE TABLE IF NOT EXISTS webauthn_challenges (
+	challenge TEXT PRIMARY KEY,
+	user_id UUID REFERENCES users (id) ON DELETE CASCADE,
+	ceremony VARCHAR(20) NOT NULL,
+	expires_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS login_failures (
+	key TEXT PRIMARY KEY,
+	failures INT NOT NULL DEFAULT 0,
+	locked_until TIMESTAMPTZ,
+	last_failed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS login_failures_last_failed_at_idx ON login_failures (last_failed_at);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS oauth_clients (
+	id TEXT PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	secret_hash TEXT,
+	redirect_uris JSONB NOT NULL DEFAULT '[]',
+	first_party BOOLEAN NOT NULL DEFAULT FALSE,
+	created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS authorization_codes (
+	code_hash TEXT PRIMARY KEY,
+	client_id TEXT NOT NULL REFERENCES oauth_clients (id) ON DELETE CASCADE,
+	user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	redirect_uri TEXT NOT NULL,
+	scope TEXT NOT NULL,
+	nonce TEXT NOT NULL DEFAULT '',
+	code_challenge TEXT NOT NULL,
+	expires_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,42 @@
+CREATE TABLE IF NOT EXISTS roles (
+	name VARCHAR(50) PRIMARY KEY,
+	description TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+	name VARCHAR(100) PRIMARY KEY,
+	description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+	role VARCHAR(50) NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+	permission VARCHAR(100) NOT NULL REFERENCES permissions (name) ON DELETE CASCADE,
+	PRIMARY KEY (role, permission)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+	user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+	role VARCHAR(50) NOT NULL REFERENCES roles (name) ON DELETE CASCADE,
+	created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (user_id, role)
+);
+
+CREATE INDEX IF NOT EXISTS role_permissions_permission_idx ON role_permissions (permission);
+
+-- The first administrator is granted by hand:
+-- INSERT INTO user_roles (user_id, role) VALUES ('<user id>', 'admin');
+INSERT INTO roles (name, description) VALUES
+	('admin', 'Manages users and their roles');
+
+INSERT INTO permissions (name, description) VALUES
+	('users:read', 'View any user'),
+	('users:write', 'Change any user'),
+	('roles:read', 'View the roles of any user'),
+	('roles:write', 'Assign and revoke roles');
+
+INSERT INTO role_permissions (role, permission) VALUES
+	('admin', 'users:read'),
+	('admin', 'users:write'),
+	('admin', 'roles:read'),
+	('admin', 'roles:write');
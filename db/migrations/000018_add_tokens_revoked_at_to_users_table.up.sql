@@ -0,0 +1,5 @@
+ALTER TABLE users
+ADD COLUMN tokens_revoked_at TIMESTAMPTZ;
+
+-- Deleted accounts are purged by age once their grace period has passed.
+CREATE INDEX IF NOT EXISTS users_deleted_at_idx ON users (deleted_at) WHERE deleted_at IS NOT NULL;
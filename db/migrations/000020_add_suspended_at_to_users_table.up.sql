@@ -0,0 +1,5 @@
+ALTER TABLE users
+ADD COLUMN suspended_at TIMESTAMPTZ;
+
+-- The admin user list pages through users by creation time, with the id breaking ties.
+CREATE INDEX IF NOT EXISTS users_created_at_id_idx ON users (created_at, id);
@@ -0,0 +1,4 @@
+ALTER TABLE users
+DROP COLUMN IF EXISTS suspension_reason,
+DROP COLUMN IF EXISTS suspended_by,
+DROP COLUMN IF EXISTS suspended_until;
@@ -0,0 +1,4 @@
+ALTER TABLE users
+ADD COLUMN suspended_until TIMESTAMPTZ,
+ADD COLUMN suspended_by UUID REFERENCES users (id) ON DELETE SET NULL,
+ADD COLUMN suspension_reason TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+-- The administrator who took an action on the user; NULL for actions the user took.
+ALTER TABLE audit_events
+ADD COLUMN actor_id UUID REFERENCES users (id) ON DELETE SET NULL;
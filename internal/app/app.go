@@ -1,7 +1,12 @@
 package app
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/ferdiebergado/goexpress"
 	"github.com/ferdiebergado/gojeep/internal/config"
@@ -23,6 +28,7 @@ type dependencies struct {
 	Hasher    security.Hasher
 	Mailer    email.Mailer
 	Signer    security.Signer
+	Encrypter security.Encrypter
 }
 
 // NewDependencies creates and initializes all dependencies.
@@ -32,6 +38,26 @@ func newDependencies(cfg *config.Config, db *sql.DB, validate *validator.Validat
 		return nil, err
 	}
 
+	signer, err := security.NewSigner(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.OIDC != nil {
+		if _, err := signer.IDTokenAlgorithm(); err != nil {
+			return nil, fmt.Errorf("openid provider needs an asymmetric jwt.signing_key: %w", err)
+		}
+
+		if cfg.OIDC.AuthorizationEndpoint == "" {
+			return nil, errors.New("openid provider needs oidc.authorization_endpoint")
+		}
+	}
+
+	encrypter, err := security.NewAESEncrypter(cfg.Server.Key, "totp")
+	if err != nil {
+		return nil, err
+	}
+
 	deps := &dependencies{
 		Config:    cfg,
 		DB:        db,
@@ -39,7 +65,8 @@ func newDependencies(cfg *config.Config, db *sql.DB, validate *validator.Validat
 		Validator: validate,
 		Hasher:    security.NewArgon2Hasher(cfg.Hash, cfg.Server.Key),
 		Mailer:    mailer,
-		Signer:    security.NewSigner(cfg),
+		Signer:    signer,
+		Encrypter: encrypter,
 	}
 	return deps, nil
 }
@@ -53,6 +80,8 @@ type application struct {
 	hasher    security.Hasher
 	mailer    email.Mailer
 	signer    security.Signer
+	encrypter security.Encrypter
+	svc       *service.Service
 }
 
 // New creates a new Application instance with the provided dependencies.
@@ -65,7 +94,9 @@ func New(deps *dependencies) *application {
 		hasher:    deps.Hasher,
 		mailer:    deps.Mailer,
 		signer:    deps.Signer,
+		encrypter: deps.Encrypter,
 	}
+	app.svc = app.newService()
 	app.SetupMiddlewares()
 	return app
 }
@@ -79,17 +110,91 @@ func (a *application) SetupMiddlewares() {
 	a.handler.Use(handler.LogRequest)
 }
 
-func (a *application) SetupRoutes() {
+func (a *application) newService() *service.Service {
 	repo := repository.NewRepository(a.db)
 	deps := &service.Dependencies{
-		Repo:   *repo,
-		Hasher: a.hasher,
-		Signer: a.signer,
-		Mailer: a.mailer,
-		Cfg:    a.cfg,
+		Repo:      *repo,
+		Hasher:    a.hasher,
+		Signer:    a.signer,
+		Encrypter: a.encrypter,
+		Mailer:    a.mailer,
+		Cfg:       a.cfg,
 	}
-	svc := service.NewService(deps)
+	return service.NewService(deps)
+}
 
-	apiHandler := handler.New(*svc, a.signer, a.cfg)
+func (a *application) SetupRoutes() {
+	apiHandler := handler.New(*a.svc, a.signer, a.cfg)
 	handler.MountRoutes(a.handler, apiHandler, a.validater)
 }
+
+// StartJobs runs the background maintenance jobs until ctx is cancelled.
+func (a *application) StartJobs(ctx context.Context) {
+	purgeInterval := time.Duration(a.cfg.Jobs.PurgeInterval) * time.Second
+	go runPeriodically(ctx, "purge_expired_tokens", purgeInterval, func(ctx context.Context) error {
+		purged, err := a.svc.Revocation.PurgeExpiredTokens(ctx)
+		if err != nil {
+			return err
+		}
+		slog.Info("Purged expired tokens", "count", purged)
+		return nil
+	})
+	go runPeriodically(ctx, "purge_webauthn_challenges", purgeInterval, func(ctx context.Context) error {
+		purged, err := a.svc.WebAuthn.PurgeExpiredChallenges(ctx)
+		if err != nil {
+			return err
+		}
+		slog.Info("Purged expired webauthn challenges", "count", purged)
+		return nil
+	})
+	go runPeriodically(ctx, "purge_oauth_states", purgeInterval, func(ctx context.Context) error {
+		purged, err := a.svc.OAuth.PurgeExpiredStates(ctx)
+		if err != nil {
+			return err
+		}
+		slog.Info("Purged expired oauth states", "count", purged)
+		return nil
+	})
+	if a.svc.Provider != nil {
+		go runPeriodically(ctx, "purge_authorization_codes", purgeInterval, func(ctx context.Context) error {
+			purged, err := a.svc.Provider.PurgeExpiredCodes(ctx)
+			if err != nil {
+				return err
+			}
+			slog.Info("Purged expired authorization codes", "count", purged)
+			return nil
+		})
+	}
+	go runPeriodically(ctx, "purge_sessions", purgeInterval, func(ctx context.Context) error {
+		purged, err := a.svc.Session.PurgeStaleSessions(ctx)
+		if err != nil {
+			return err
+		}
+		slog.Info("Purged stale sessions", "count", purged)
+		return nil
+	})
+	go runPeriodically(ctx, "purge_login_failures", purgeInterval, func(ctx context.Context) error {
+		purged, err := a.svc.Lockout.PurgeStale(ctx)
+		if err != nil {
+			return err
+		}
+		slog.Info("Purged stale login failures", "count", purged)
+		return nil
+	})
+	go runPeriodically(ctx, "purge_deleted_users", purgeInterval, func(ctx context.Context) error {
+		purged, err := a.svc.User.PurgeDeletedUsers(ctx)
+		if err != nil {
+			return err
+		}
+		slog.Info("Purged deleted users", "count", purged)
+		return nil
+	})
+	go runPeriodically(ctx, "purge_data_exports", purgeInterval, func(ctx context.Context) error {
+		purged, err := a.svc.Export.PurgeExpiredExports(ctx)
+		if err != nil {
+			return err
+		}
+		slog.Info("Purged expired data exports", "count", purged)
+		return nil
+	})
+}
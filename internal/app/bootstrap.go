@@ -61,6 +61,7 @@ func Run(ctx context.Context) error {
 
 	application := New(deps)
 	application.SetupRoutes()
+	application.StartJobs(signalCtx)
 
 	apiServer := server.New(signalCtx, cfg.Server, application.Router())
 	apiServerErr := apiServer.Start()
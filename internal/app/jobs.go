@@ -0,0 +1,30 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// runPeriodically calls job on every tick of interval until ctx is cancelled.
+// A non-positive interval disables the job.
+func runPeriodically(ctx context.Context, name string, interval time.Duration, job func(context.Context) error) {
+	if interval <= 0 {
+		slog.Warn("Job disabled", "job", name)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := job(ctx); err != nil {
+				slog.Error("job failed", "job", name, "reason", err)
+			}
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/ferdiebergado/gopherkit/env"
 )
@@ -16,6 +17,19 @@ const (
 	envDefaultSMTPPort = 587
 )
 
+// Defaults of the sections that may be left out of the config file.
+const (
+	defaultLockoutThreshold    = 5
+	defaultLockoutIPThreshold  = 50
+	defaultLockoutBaseDelay    = 30
+	defaultLockoutMaxDelay     = 3600
+	defaultLockoutWindow       = 3600
+	defaultDeletionGracePeriod = 30 * 24 * 3600
+	defaultExportTTL           = 24 * 3600
+	defaultExportInterval      = 3600
+	defaultStatusCacheTTL      = 30
+)
+
 type ServerOptions struct {
 	ReadTimeout     int `json:"read_timeout,omitempty"`
 	WriteTimeout    int `json:"write_timeout,omitempty"`
@@ -71,10 +85,13 @@ func (c *DBConfig) LogValue() slog.Value {
 }
 
 type EmailOptions struct {
-	Sender       string `json:"sender,omitempty"`
-	VerifyTTL    int    `json:"verify_ttl,omitempty"`
-	TemplatePath string `json:"template_path,omitempty"`
-	LayoutFile   string `json:"layout_file,omitempty"`
+	Sender         string `json:"sender,omitempty"`
+	VerifyTTL      int    `json:"verify_ttl,omitempty"`
+	ResetTTL       int    `json:"reset_ttl,omitempty"`
+	MagicLinkTTL   int    `json:"magic_link_ttl,omitempty"`
+	ResendInterval int    `json:"resend_interval,omitempty"`
+	TemplatePath   string `json:"template_path,omitempty"`
+	LayoutFile     string `json:"layout_file,omitempty"`
 }
 
 type SMTPConfig struct {
@@ -93,10 +110,19 @@ func (c *SMTPConfig) LogValue() slog.Value {
 	)
 }
 
+// JWTKeyOptions points to a PEM key file. The ID is the token kid and defaults to the key's thumbprint.
+type JWTKeyOptions struct {
+	ID   string `json:"id,omitempty"`
+	File string `json:"file,omitempty"`
+}
+
 type JWTOptions struct {
-	JTILen   uint32 `json:"jti_len,omitempty"`
-	Issuer   string `json:"issuer,omitempty"`
-	Duration int    `json:"duration,omitempty"`
+	JTILen           uint32          `json:"jti_len,omitempty"`
+	Issuer           string          `json:"issuer,omitempty"`
+	Duration         int             `json:"duration,omitempty"`
+	RefreshDuration  int             `json:"refresh_duration,omitempty"`
+	SigningKey       *JWTKeyOptions  `json:"signing_key,omitempty"`
+	VerificationKeys []JWTKeyOptions `json:"verification_keys,omitempty"`
 }
 
 type Argon2Options struct {
@@ -112,22 +138,122 @@ type CookieOptions struct {
 	MaxAge int    `json:"max_age,omitempty"`
 }
 
+type MFAOptions struct {
+	Issuer       string `json:"issuer,omitempty"`
+	ChallengeTTL int    `json:"challenge_ttl,omitempty"`
+}
+
+// WebAuthnOptions identify the relying party of passkeys. Origin is where the ceremonies run,
+// for example the frontend's URL.
+type WebAuthnOptions struct {
+	RPID         string `json:"rp_id,omitempty"`
+	RPName       string `json:"rp_name,omitempty"`
+	Origin       string `json:"origin,omitempty"`
+	ChallengeTTL int    `json:"challenge_ttl,omitempty"`
+}
+
+// RegistrationOptions configure sign-up. With EnumerationSafe set, registering an email that is taken
+// gets the same response as a new registration, and the owner of the email is notified instead.
+type RegistrationOptions struct {
+	EnumerationSafe bool `json:"enumeration_safe,omitempty"`
+}
+
+// AccountOptions control account closing, data exports and status checks. A deleted account can be
+// restored by logging in during DeletionGracePeriod, after which it is purged. A data export can be
+// downloaded for ExportTTL, and another cannot be requested until it expires or, while it is still being
+// built, for ExportInterval after the request. The status of a user, checked on every authenticated
+// request, is cached for StatusCacheTTL, so a suspension can take that long to reach other instances.
+// Durations are in seconds.
+type AccountOptions struct {
+	DeletionGracePeriod int `json:"deletion_grace_period,omitempty"`
+	ExportTTL           int `json:"export_ttl,omitempty"`
+	ExportInterval      int `json:"export_interval,omitempty"`
+	StatusCacheTTL      int `json:"status_cache_ttl,omitempty"`
+}
+
+// LockoutOptions control the backoff after failed logins. Once an account or an IP address reaches its
+// threshold of failures within Window, logins from it are locked for BaseDelay, doubling with each further
+// failure up to MaxDelay. Durations are in seconds.
+type LockoutOptions struct {
+	Threshold   int `json:"threshold,omitempty"`
+	IPThreshold int `json:"ip_threshold,omitempty"`
+	BaseDelay   int `json:"base_delay,omitempty"`
+	MaxDelay    int `json:"max_delay,omitempty"`
+	Window      int `json:"window,omitempty"`
+}
+
+// OAuthProviderOptions configure an OpenID Connect provider users can sign in with. The client secret
+// is read from the OAUTH_<NAME>_CLIENT_SECRET environment variable, where NAME is the provider's key.
+type OAuthProviderOptions struct {
+	Issuer       string   `json:"issuer,omitempty"`
+	ClientID     string   `json:"client_id,omitempty"`
+	ClientSecret string   `json:"-"`
+	Scopes       []string `json:"scopes,omitempty"`
+}
+
+func (o OAuthProviderOptions) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("issuer", o.Issuer),
+		slog.String("client_id", o.ClientID),
+		slog.Any("scopes", o.Scopes),
+	)
+}
+
+// OAuthOptions configure social login. StateTTL is how long a sign-in may take at the provider,
+// in seconds.
+type OAuthOptions struct {
+	StateTTL  int                             `json:"state_ttl,omitempty"`
+	Providers map[string]OAuthProviderOptions `json:"providers,omitempty"`
+}
+
+// OIDCOptions configure gojeep as an OpenID provider for other apps. Without them the provider is off.
+// ID tokens are verified by clients with the published keys, so the provider needs an asymmetric
+// jwt.signing_key. AuthorizationEndpoint is the frontend page clients send the browser to; it signs the
+// user in, asks for consent and completes the request with the /oauth/authorize API. The TTLs are in
+// seconds.
+type OIDCOptions struct {
+	AuthorizationEndpoint string `json:"authorization_endpoint,omitempty"`
+	CodeTTL               int    `json:"code_ttl,omitempty"`
+	AccessTokenTTL        int    `json:"access_token_ttl,omitempty"`
+	IDTokenTTL            int    `json:"id_token_ttl,omitempty"`
+}
+
+type JobOptions struct {
+	PurgeInterval int `json:"purge_interval,omitempty"`
+}
+
 type Options struct {
-	Server *ServerOptions `json:"server,omitempty"`
-	DB     *DBOptions     `json:"db,omitempty"`
-	JWT    *JWTOptions    `json:"jwt,omitempty"`
-	Email  *EmailOptions  `json:"email,omitempty"`
-	Hash   *Argon2Options `json:"hash,omitempty"`
-	Cookie *CookieOptions `json:"cookie,omitempty"`
+	Server       *ServerOptions       `json:"server,omitempty"`
+	DB           *DBOptions           `json:"db,omitempty"`
+	JWT          *JWTOptions          `json:"jwt,omitempty"`
+	Email        *EmailOptions        `json:"email,omitempty"`
+	Hash         *Argon2Options       `json:"hash,omitempty"`
+	Cookie       *CookieOptions       `json:"cookie,omitempty"`
+	Jobs         *JobOptions          `json:"jobs,omitempty"`
+	MFA          *MFAOptions          `json:"mfa,omitempty"`
+	WebAuthn     *WebAuthnOptions     `json:"webauthn,omitempty"`
+	Lockout      *LockoutOptions      `json:"lockout,omitempty"`
+	Registration *RegistrationOptions `json:"registration,omitempty"`
+	OAuth        *OAuthOptions        `json:"oauth,omitempty"`
+	OIDC         *OIDCOptions         `json:"oidc,omitempty"`
+	Account      *AccountOptions      `json:"account,omitempty"`
 }
 
 type Config struct {
-	Server *ServerConfig
-	DB     *DBConfig
-	Email  *SMTPConfig
-	JWT    *JWTOptions
-	Hash   *Argon2Options
-	Cookie *CookieOptions
+	Server       *ServerConfig
+	DB           *DBConfig
+	Email        *SMTPConfig
+	JWT          *JWTOptions
+	Hash         *Argon2Options
+	Cookie       *CookieOptions
+	Jobs         *JobOptions
+	MFA          *MFAOptions
+	WebAuthn     *WebAuthnOptions
+	Lockout      *LockoutOptions
+	Registration *RegistrationOptions
+	OAuth        *OAuthOptions
+	OIDC         *OIDCOptions
+	Account      *AccountOptions
 }
 
 func (c *Config) LogValue() slog.Value {
@@ -138,6 +264,14 @@ func (c *Config) LogValue() slog.Value {
 		slog.Any("jwt", c.JWT),
 		slog.Any("hash", c.Hash),
 		slog.Any("cookie", c.Cookie),
+		slog.Any("jobs", c.Jobs),
+		slog.Any("mfa", c.MFA),
+		slog.Any("webauthn", c.WebAuthn),
+		slog.Any("lockout", c.Lockout),
+		slog.Any("registration", c.Registration),
+		slog.Any("oauth", c.OAuth),
+		slog.Any("oidc", c.OIDC),
+		slog.Any("account", c.Account),
 	)
 }
 
@@ -174,9 +308,26 @@ func Load(cfgFile string) (*Config, error) {
 			Port:     env.GetInt("SMTP_PORT", envDefaultSMTPPort),
 			Options:  opts.Email,
 		},
-		JWT:    opts.JWT,
-		Hash:   opts.Hash,
-		Cookie: opts.Cookie,
+		JWT:          opts.JWT,
+		Hash:         opts.Hash,
+		Cookie:       opts.Cookie,
+		Jobs:         opts.Jobs,
+		MFA:          opts.MFA,
+		WebAuthn:     opts.WebAuthn,
+		Lockout:      opts.Lockout,
+		Registration: opts.Registration,
+		OAuth:        opts.OAuth,
+		OIDC:         opts.OIDC,
+		Account:      opts.Account,
+	}
+
+	applyDefaults(cfg)
+
+	if cfg.OAuth != nil {
+		for name, provider := range cfg.OAuth.Providers {
+			provider.ClientSecret = env.MustGet("OAUTH_" + strings.ToUpper(name) + "_CLIENT_SECRET")
+			cfg.OAuth.Providers[name] = provider
+		}
 	}
 
 	slog.Debug("config loaded", slog.Any("config", cfg))
@@ -184,6 +335,34 @@ func Load(cfgFile string) (*Config, error) {
 	return cfg, nil
 }
 
+// applyDefaults fills in the sections that the services read without a nil check, and the settings
+// left out of them.
+func applyDefaults(cfg *Config) {
+	if cfg.Lockout == nil {
+		cfg.Lockout = &LockoutOptions{}
+	}
+	setDefault(&cfg.Lockout.Threshold, defaultLockoutThreshold)
+	setDefault(&cfg.Lockout.IPThreshold, defaultLockoutIPThreshold)
+	setDefault(&cfg.Lockout.BaseDelay, defaultLockoutBaseDelay)
+	setDefault(&cfg.Lockout.MaxDelay, defaultLockoutMaxDelay)
+	setDefault(&cfg.Lockout.Window, defaultLockoutWindow)
+
+	if cfg.Account == nil {
+		cfg.Account = &AccountOptions{}
+	}
+	setDefault(&cfg.Account.DeletionGracePeriod, defaultDeletionGracePeriod)
+	setDefault(&cfg.Account.ExportTTL, defaultExportTTL)
+	setDefault(&cfg.Account.ExportInterval, defaultExportInterval)
+	setDefault(&cfg.Account.StatusCacheTTL, defaultStatusCacheTTL)
+}
+
+// setDefault sets a setting that was left out, and so is zero, to its default.
+func setDefault(setting *int, value int) {
+	if *setting == 0 {
+		*setting = value
+	}
+}
+
 func parseCfgFile(cfgFile string) (*Options, error) {
 	cfgFile = filepath.Clean(cfgFile)
 	configFile, err := os.ReadFile(cfgFile)
@@ -0,0 +1,282 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/pkg/message"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/ferdiebergado/gopherkit/http/response"
+)
+
+// AdminHandler lets administrators browse users and act on their accounts. Its routes are mounted behind
+// RequireAuth and RequirePermission.
+type AdminHandler struct {
+	service service.AdminService
+}
+
+func NewAdminHandler(adminService service.AdminService) *AdminHandler {
+	return &AdminHandler{service: adminService}
+}
+
+type AdminUserResponse struct {
+	ID               string     `json:"id"`
+	Email            string     `json:"email"`
+	Name             string     `json:"name"`
+	VerifiedAt       *time.Time `json:"verified_at,omitempty"`
+	SuspendedAt      *time.Time `json:"suspended_at,omitempty"`
+	SuspendedUntil   *time.Time `json:"suspended_until,omitempty"`
+	SuspendedBy      *string    `json:"suspended_by,omitempty"`
+	SuspensionReason string     `json:"suspension_reason,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	Roles            []string   `json:"roles,omitempty"`
+}
+
+type UserListResponse struct {
+	Users      []*AdminUserResponse `json:"users"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+// HandleListUsers serves a page of users. The query takes the filters verified, created_after,
+// created_before (RFC 3339) and email (a prefix), the sort order, the cursor of the next page and a limit.
+func (h *AdminHandler) HandleListUsers(w http.ResponseWriter, r *http.Request) {
+	params, err := parseListUsersQuery(r.URL.Query())
+	if err != nil {
+		badRequestResponse(w, err, message.UserInputInvalid)
+		return
+	}
+
+	page, err := h.service.ListUsers(r.Context(), params)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	data := &UserListResponse{
+		Users:      make([]*AdminUserResponse, 0, len(page.Users)),
+		NextCursor: page.NextCursor,
+	}
+	for _, user := range page.Users {
+		data.Users = append(data.Users, &AdminUserResponse{
+			ID:          user.ID,
+			Email:       user.Email,
+			Name:        user.Name,
+			VerifiedAt:  user.VerifiedAt,
+			SuspendedAt: user.SuspendedAt,
+			CreatedAt:   user.CreatedAt,
+			UpdatedAt:   user.UpdatedAt,
+		})
+	}
+
+	response.JSON(w, http.StatusOK, Response[*UserListResponse]{Data: data})
+}
+
+func parseListUsersQuery(query url.Values) (service.ListUsersParams, error) {
+	params := service.ListUsersParams{
+		EmailPrefix: query.Get("email"),
+		Sort:        query.Get("sort"),
+		Cursor:      query.Get("cursor"),
+	}
+
+	if v := query.Get("verified"); v != "" {
+		verified, err := strconv.ParseBool(v)
+		if err != nil {
+			return service.ListUsersParams{}, fmt.Errorf("parse verified: %w", err)
+		}
+		params.Verified = &verified
+	}
+
+	var err error
+	if params.CreatedAfter, err = parseTimeQuery(query, "created_after"); err != nil {
+		return service.ListUsersParams{}, err
+	}
+	if params.CreatedBefore, err = parseTimeQuery(query, "created_before"); err != nil {
+		return service.ListUsersParams{}, err
+	}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			return service.ListUsersParams{}, fmt.Errorf("invalid limit %q", v)
+		}
+		params.Limit = limit
+	}
+
+	return params, nil
+}
+
+// parseTimeQuery parses an RFC 3339 query parameter. It returns nil if the parameter is absent.
+func parseTimeQuery(query url.Values, name string) (*time.Time, error) {
+	v := query.Get(name)
+	if v == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", name, err)
+	}
+	return &t, nil
+}
+
+func (h *AdminHandler) HandleGetUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := pathID(r)
+	if err != nil {
+		notFoundResponse(w, err, message.UserProfileNotFound)
+		return
+	}
+
+	user, err := h.service.GetUser(r.Context(), userID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	res := Response[*AdminUserResponse]{
+		Data: &AdminUserResponse{
+			ID:               user.ID,
+			Email:            user.Email,
+			Name:             user.Name,
+			VerifiedAt:       user.VerifiedAt,
+			SuspendedAt:      user.SuspendedAt,
+			SuspendedUntil:   user.SuspendedUntil,
+			SuspendedBy:      user.SuspendedBy,
+			SuspensionReason: user.SuspensionReason,
+			CreatedAt:        user.CreatedAt,
+			UpdatedAt:        user.UpdatedAt,
+			Roles:            user.Roles,
+		},
+	}
+	response.JSON(w, http.StatusOK, res)
+}
+
+func (h *AdminHandler) HandleVerifyUser(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := FromUserContext(r.Context())
+	if !ok {
+		unauthorizedResponse(w, errors.New("missing user in context"), "Unauthorized")
+		return
+	}
+
+	userID, err := pathID(r)
+	if err != nil {
+		notFoundResponse(w, err, message.UserProfileNotFound)
+		return
+	}
+
+	if err := h.service.VerifyUser(r.Context(), userID, actorID); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, Response[any]{Message: message.UserMarkedVerified})
+}
+
+// SuspendUserRequest gives the reason for a suspension. Without an end time, the suspension lasts until
+// it is lifted.
+type SuspendUserRequest struct {
+	Reason string     `json:"reason,omitempty" validate:"required,max=500"`
+	Until  *time.Time `json:"until,omitempty"`
+}
+
+func (h *AdminHandler) HandleSuspendUser(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := FromUserContext(r.Context())
+	if !ok {
+		unauthorizedResponse(w, errors.New("missing user in context"), "Unauthorized")
+		return
+	}
+
+	userID, err := pathID(r)
+	if err != nil {
+		notFoundResponse(w, err, message.UserProfileNotFound)
+		return
+	}
+
+	_, req, _ := FromParamsContext[SuspendUserRequest](r.Context())
+	params := service.SuspendUserParams{
+		Reason:  req.Reason,
+		Until:   req.Until,
+		ActorID: actorID,
+	}
+	if err := h.service.SuspendUser(r.Context(), userID, params); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, Response[any]{Message: message.UserSuspended})
+}
+
+func (h *AdminHandler) HandleUnsuspendUser(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := FromUserContext(r.Context())
+	if !ok {
+		unauthorizedResponse(w, errors.New("missing user in context"), "Unauthorized")
+		return
+	}
+
+	userID, err := pathID(r)
+	if err != nil {
+		notFoundResponse(w, err, message.UserProfileNotFound)
+		return
+	}
+
+	if err := h.service.UnsuspendUser(r.Context(), userID, actorID); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, Response[any]{Message: message.UserUnsuspended})
+}
+
+// HandleSignOutUser signs the user out of every device, as when an account is compromised.
+func (h *AdminHandler) HandleSignOutUser(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := FromUserContext(r.Context())
+	if !ok {
+		unauthorizedResponse(w, errors.New("missing user in context"), "Unauthorized")
+		return
+	}
+
+	userID, err := pathID(r)
+	if err != nil {
+		notFoundResponse(w, err, message.UserProfileNotFound)
+		return
+	}
+
+	if err := h.service.SignOutUser(r.Context(), userID, actorID); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, Response[any]{Message: message.UserSignedOut})
+}
+
+func (h *AdminHandler) handleError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrUserNotFound) {
+		notFoundResponse(w, err, message.UserProfileNotFound)
+		return
+	}
+
+	if errors.Is(err, service.ErrInvalidUserSort) || errors.Is(err, service.ErrInvalidCursor) {
+		badRequestResponse(w, err, message.UserInputInvalid)
+		return
+	}
+
+	if errors.Is(err, service.ErrSuspendSelf) {
+		unprocessableResponse(w, err, message.SuspendSelf)
+		return
+	}
+
+	if errors.Is(err, service.ErrSuspensionExpired) {
+		unprocessableResponse(w, err, message.SuspensionExpired)
+		return
+	}
+
+	if isContextError(err) {
+		return
+	}
+
+	response.ServerError(w, err)
+}
@@ -0,0 +1,268 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/handler"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/pkg/message"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/ferdiebergado/gojeep/internal/service/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// testActorID is the administrator acting on users.
+const testActorID = "admin"
+
+func TestAdminHandler_HandleListUsers(t *testing.T) {
+	t.Parallel()
+	createdAfter := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	verified := true
+	page := service.UserPage{
+		Users:      []model.User{{Model: model.Model{ID: testUserID}, Email: "abc@example.com"}},
+		NextCursor: "next",
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		params         *service.ListUsersParams
+		listErr        error
+		expectedStatus int
+	}{
+		{
+			name:  "Listed",
+			query: "?verified=true&created_after=2024-05-01T00:00:00Z&email=abc&sort=-email&cursor=c&limit=10",
+			params: &service.ListUsersParams{
+				Verified:     &verified,
+				CreatedAfter: &createdAfter,
+				EmailPrefix:  "abc",
+				Sort:         "-email",
+				Cursor:       "c",
+				Limit:        10,
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Malformed date",
+			query:          "?created_before=yesterday",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Malformed limit",
+			query:          "?limit=0",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unknown sort",
+			query:          "?sort=password_hash",
+			params:         &service.ListUsersParams{Sort: "password_hash"},
+			listErr:        service.ErrInvalidUserSort,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockAdminService(ctrl)
+			if tt.params != nil {
+				mockService.EXPECT().ListUsers(gomock.Any(), *tt.params).Return(page, tt.listErr)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/users"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			handler.NewAdminHandler(mockService).HandleListUsers(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			require.Equal(t, tt.expectedStatus, res.StatusCode)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var body handler.Response[handler.UserListResponse]
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+			require.Len(t, body.Data.Users, 1)
+			assert.Equal(t, "abc@example.com", body.Data.Users[0].Email)
+			assert.Equal(t, "next", body.Data.NextCursor)
+		})
+	}
+}
+
+func TestAdminHandler_HandleGetUser(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockAdminService(ctrl)
+	detail := service.UserDetail{
+		User:  model.User{Model: model.Model{ID: testUserID}, Email: "abc@example.com"},
+		Roles: []string{"admin"},
+	}
+	mockService.EXPECT().GetUser(gomock.Any(), testUserID).Return(detail, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/users/"+testUserID, nil)
+	req.SetPathValue("id", testUserID)
+	rec := httptest.NewRecorder()
+
+	handler.NewAdminHandler(mockService).HandleGetUser(rec, req)
+	res := rec.Result()
+	defer res.Body.Close()
+
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	var body handler.Response[handler.AdminUserResponse]
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+	assert.Equal(t, testUserID, body.Data.ID)
+	assert.Equal(t, []string{"admin"}, body.Data.Roles)
+}
+
+func TestAdminHandler_AccountActions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		message string
+		expect  func(m *mock.MockAdminService) *gomock.Call
+		handle  func(h *handler.AdminHandler) http.HandlerFunc
+	}{
+		{
+			name:    "Verify",
+			message: message.UserMarkedVerified,
+			expect: func(m *mock.MockAdminService) *gomock.Call {
+				return m.EXPECT().VerifyUser(gomock.Any(), testUserID, testActorID)
+			},
+			handle: func(h *handler.AdminHandler) http.HandlerFunc { return h.HandleVerifyUser },
+		},
+		{
+			name:    "Unsuspend",
+			message: message.UserUnsuspended,
+			expect: func(m *mock.MockAdminService) *gomock.Call {
+				return m.EXPECT().UnsuspendUser(gomock.Any(), testUserID, testActorID)
+			},
+			handle: func(h *handler.AdminHandler) http.HandlerFunc { return h.HandleUnsuspendUser },
+		},
+		{
+			name:    "Sign out",
+			message: message.UserSignedOut,
+			expect: func(m *mock.MockAdminService) *gomock.Call {
+				return m.EXPECT().SignOutUser(gomock.Any(), testUserID, testActorID)
+			},
+			handle: func(h *handler.AdminHandler) http.HandlerFunc { return h.HandleSignOutUser },
+		},
+	}
+
+	for _, tt := range tests {
+		for _, c := range []struct {
+			name           string
+			userID         string
+			err            error
+			expectedStatus int
+			expectedMsg    string
+		}{
+			{name: "OK", userID: testUserID, expectedStatus: http.StatusOK, expectedMsg: tt.message},
+			{
+				name:           "Not found",
+				userID:         testUserID,
+				err:            service.ErrUserNotFound,
+				expectedStatus: http.StatusNotFound,
+				expectedMsg:    message.UserProfileNotFound,
+			},
+			{
+				name:           "Malformed id",
+				userID:         "1",
+				expectedStatus: http.StatusNotFound,
+				expectedMsg:    message.UserProfileNotFound,
+			},
+		} {
+			t.Run(tt.name+" "+c.name, func(t *testing.T) {
+				t.Parallel()
+				ctrl := gomock.NewController(t)
+				mockService := mock.NewMockAdminService(ctrl)
+				if c.userID == testUserID {
+					tt.expect(mockService).Return(c.err)
+				}
+
+				req := withUser(httptest.NewRequest(http.MethodPost, "/admin/users/"+c.userID, nil), testActorID)
+				req.SetPathValue("id", c.userID)
+				rec := httptest.NewRecorder()
+
+				tt.handle(handler.NewAdminHandler(mockService))(rec, req)
+				res := rec.Result()
+				defer res.Body.Close()
+
+				assert.Equal(t, c.expectedStatus, res.StatusCode)
+				var body handler.Response[any]
+				require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+				assert.Equal(t, c.expectedMsg, body.Message)
+			})
+		}
+	}
+}
+
+func TestAdminHandler_HandleSuspendUser(t *testing.T) {
+	t.Parallel()
+	until := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	req := handler.SuspendUserRequest{Reason: "spam", Until: &until}
+
+	tests := []struct {
+		name           string
+		suspendErr     error
+		expectedStatus int
+		expectedMsg    string
+	}{
+		{name: "Suspended", expectedStatus: http.StatusOK, expectedMsg: message.UserSuspended},
+		{
+			name:           "Not found",
+			suspendErr:     service.ErrUserNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedMsg:    message.UserProfileNotFound,
+		},
+		{
+			name:           "Self",
+			suspendErr:     service.ErrSuspendSelf,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedMsg:    message.SuspendSelf,
+		},
+		{
+			name:           "Ends in the past",
+			suspendErr:     service.ErrSuspensionExpired,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedMsg:    message.SuspensionExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockAdminService(ctrl)
+			mockService.EXPECT().SuspendUser(gomock.Any(), testUserID, service.SuspendUserParams{
+				Reason:  "spam",
+				Until:   &until,
+				ActorID: testActorID,
+			}).Return(tt.suspendErr)
+
+			r := httptest.NewRequest(http.MethodPost, "/admin/users/"+testUserID+"/suspend", nil)
+			r.SetPathValue("id", testUserID)
+			r = withUser(r, testActorID)
+			r = r.WithContext(handler.NewParamsContext(r.Context(), req))
+			rec := httptest.NewRecorder()
+
+			handler.NewAdminHandler(mockService).HandleSuspendUser(rec, r)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+			var body handler.Response[any]
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+			assert.Equal(t, tt.expectedMsg, body.Message)
+		})
+	}
+}
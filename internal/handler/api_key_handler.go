@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/pkg/message"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/ferdiebergado/gopherkit/http/response"
+)
+
+// APIKeyHandler manages the signed-in user's API keys. Its routes are mounted behind RequireAuth.
+type APIKeyHandler struct {
+	service service.APIKeyService
+}
+
+var errUnknownScope = errors.New("unknown scope")
+
+func NewAPIKeyHandler(apiKeyService service.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{service: apiKeyService}
+}
+
+// CreateAPIKeyRequest names a new key. Scopes are permissions the key is limited to; a key without scopes
+// can do whatever its owner can. HandleCreate checks the scopes against the permissions.
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name" validate:"required,max=100"`
+	Scopes        []string `json:"scopes,omitempty"`
+	ExpiresInDays int      `json:"expires_in_days,omitempty" validate:"omitempty,min=1,max=365"`
+}
+
+type APIKeyResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedAPIKeyResponse includes the full key, which is returned only once.
+type CreatedAPIKeyResponse struct {
+	Key string `json:"key"`
+	*APIKeyResponse
+}
+
+func newAPIKeyResponse(key model.APIKey) *APIKeyResponse {
+	return &APIKeyResponse{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+// HandleCreate creates a key. It is mounted behind RequireSession, so a leaked key cannot be used to mint
+// more.
+func (h *APIKeyHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	userID, ok := FromUserContext(r.Context())
+	if !ok {
+		unauthorizedResponse(w, errors.New("missing user in context"), "Unauthorized")
+		return
+	}
+
+	_, req, _ := FromParamsContext[CreateAPIKeyRequest](r.Context())
+	for _, scope := range req.Scopes {
+		if !isPermission(scope) {
+			badRequestResponse(w, fmt.Errorf("%w: %q", errUnknownScope, scope), message.APIKeyScopeInvalid)
+			return
+		}
+	}
+
+	params := service.CreateAPIKeyParams{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresIn: time.Duration(req.ExpiresInDays) * 24 * time.Hour,
+	}
+	created, err := h.service.CreateKey(r.Context(), userID, params)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	res := Response[*CreatedAPIKeyResponse]{
+		Message: message.APIKeyCreated,
+		Data: &CreatedAPIKeyResponse{
+			Key:            created.Key,
+			APIKeyResponse: newAPIKeyResponse(created.APIKey),
+		},
+	}
+	response.JSON(w, http.StatusCreated, res)
+}
+
+func (h *APIKeyHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	userID, ok := FromUserContext(r.Context())
+	if !ok {
+		unauthorizedResponse(w, errors.New("missing user in context"), "Unauthorized")
+		return
+	}
+
+	keys, err := h.service.ListKeys(r.Context(), userID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	data := make([]*APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		data = append(data, newAPIKeyResponse(key))
+	}
+
+	response.JSON(w, http.StatusOK, Response[[]*APIKeyResponse]{Data: data})
+}
+
+func (h *APIKeyHandler) HandleRevoke(w http.ResponseWriter, r *http.Request) {
+	userID, ok := FromUserContext(r.Context())
+	if !ok {
+		unauthorizedResponse(w, errors.New("missing user in context"), "Unauthorized")
+		return
+	}
+
+	keyID, err := pathID(r)
+	if err != nil {
+		notFoundResponse(w, err, message.APIKeyNotFound)
+		return
+	}
+
+	if err := h.service.RevokeKey(r.Context(), userID, keyID); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, Response[any]{Message: message.APIKeyRevoked})
+}
+
+func (h *APIKeyHandler) handleError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrAPIKeyNotFound) {
+		notFoundResponse(w, err, message.APIKeyNotFound)
+		return
+	}
+
+	if isContextError(err) {
+		return
+	}
+
+	response.ServerError(w, err)
+}
@@ -0,0 +1,133 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/handler"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/pkg/message"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/ferdiebergado/gojeep/internal/service/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAPIKeyHandler_HandleCreate(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockAPIKeyService(ctrl)
+	mockService.EXPECT().CreateKey(gomock.Any(), testUserID, service.CreateAPIKeyParams{
+		Name:      "ci",
+		Scopes:    []string{"users:read"},
+		ExpiresIn: 30 * 24 * time.Hour,
+	}).Return(service.NewAPIKey{
+		Key:    "gjp_prefix_secret",
+		APIKey: model.APIKey{ID: "key-1", Name: "ci", Prefix: "prefix", Scopes: []string{"users:read"}},
+	}, nil)
+
+	params := handler.CreateAPIKeyRequest{Name: "ci", Scopes: []string{"users:read"}, ExpiresInDays: 30}
+	req := withUser(httptest.NewRequest(http.MethodPost, "/users/me/api-keys", nil), testUserID)
+	req = req.WithContext(handler.NewParamsContext(req.Context(), params))
+	rec := httptest.NewRecorder()
+
+	handler.NewAPIKeyHandler(mockService).HandleCreate(rec, req)
+	res := rec.Result()
+	defer res.Body.Close()
+
+	require.Equal(t, http.StatusCreated, res.StatusCode)
+	var body handler.Response[handler.CreatedAPIKeyResponse]
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+	assert.Equal(t, "gjp_prefix_secret", body.Data.Key)
+	assert.Equal(t, "prefix", body.Data.Prefix)
+}
+
+func TestAPIKeyHandler_HandleCreate_UnknownScope(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockAPIKeyService(ctrl)
+
+	params := handler.CreateAPIKeyRequest{Name: "ci", Scopes: []string{"users:read", "users:delete"}}
+	req := withUser(httptest.NewRequest(http.MethodPost, "/users/me/api-keys", nil), testUserID)
+	req = req.WithContext(handler.NewParamsContext(req.Context(), params))
+	rec := httptest.NewRecorder()
+
+	handler.NewAPIKeyHandler(mockService).HandleCreate(rec, req)
+	res := rec.Result()
+	defer res.Body.Close()
+
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	var body handler.Response[any]
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+	assert.Equal(t, message.APIKeyScopeInvalid, body.Message)
+}
+
+func TestAPIKeyHandler_HandleList(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockAPIKeyService(ctrl)
+	mockService.EXPECT().ListKeys(gomock.Any(), testUserID).Return([]model.APIKey{
+		{ID: "key-1", Name: "ci", Prefix: "prefix", KeyHash: "hash", Scopes: []string{}},
+	}, nil)
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/users/me/api-keys", nil), testUserID)
+	rec := httptest.NewRecorder()
+
+	handler.NewAPIKeyHandler(mockService).HandleList(rec, req)
+	res := rec.Result()
+	defer res.Body.Close()
+
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+	keys, ok := body["data"].([]any)
+	require.True(t, ok)
+	require.Len(t, keys, 1)
+	key, ok := keys[0].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "ci", key["name"])
+	assert.NotContains(t, key, "key")
+	assert.NotContains(t, key, "key_hash")
+}
+
+func TestAPIKeyHandler_HandleRevoke(t *testing.T) {
+	t.Parallel()
+
+	const keyID = "6f1c2a9e-8b3d-4e7f-a1c5-2d9e4b7a3f60"
+
+	tests := []struct {
+		name           string
+		keyID          string
+		revokeErr      error
+		expectedStatus int
+	}{
+		{name: "Revoked", keyID: keyID, expectedStatus: http.StatusOK},
+		{name: "Unknown key", keyID: keyID, revokeErr: service.ErrAPIKeyNotFound, expectedStatus: http.StatusNotFound},
+		{name: "Malformed key id", keyID: "key-1", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockAPIKeyService(ctrl)
+			if tt.keyID == keyID {
+				mockService.EXPECT().RevokeKey(gomock.Any(), testUserID, keyID).Return(tt.revokeErr)
+			}
+
+			req := withUser(httptest.NewRequest(http.MethodDelete, "/users/me/api-keys/"+tt.keyID, nil), testUserID)
+			req.SetPathValue("id", tt.keyID)
+			rec := httptest.NewRecorder()
+
+			handler.NewAPIKeyHandler(mockService).HandleRevoke(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+		})
+	}
+}
@@ -8,21 +8,18 @@ import (
 
 	"github.com/ferdiebergado/gojeep/internal/config"
 	"github.com/ferdiebergado/gojeep/internal/pkg/message"
-	"github.com/ferdiebergado/gojeep/internal/pkg/security"
 	"github.com/ferdiebergado/gojeep/internal/service"
 	"github.com/ferdiebergado/gopherkit/http/response"
 )
 
 type AuthHandler struct {
 	service service.AuthService
-	signer  security.Signer
 	cfg     *config.Config
 }
 
-func NewAuthHandler(userService service.AuthService, signer security.Signer, cfg *config.Config) *AuthHandler {
+func NewAuthHandler(userService service.AuthService, cfg *config.Config) *AuthHandler {
 	return &AuthHandler{
 		service: userService,
-		signer:  signer,
 		cfg:     cfg,
 	}
 }
@@ -55,6 +52,13 @@ func (h *AuthHandler) HandleUserRegister(w http.ResponseWriter, r *http.Request)
 		Password: req.Password,
 	}
 	user, err := h.service.RegisterUser(r.Context(), params)
+	enumerationSafe := h.cfg.Registration != nil && h.cfg.Registration.EnumerationSafe
+	if enumerationSafe && (err == nil || errors.Is(err, service.ErrUserExists)) {
+		// The same answer whether or not the email is taken; the owner of a taken email is notified.
+		response.JSON(w, http.StatusCreated, Response[any]{Message: message.UserRegSuccess})
+		return
+	}
+
 	if err != nil {
 		if errors.Is(err, service.ErrUserExists) {
 			unprocessableResponse(w, err, message.UserExists)
@@ -119,16 +123,26 @@ func (r *UserLoginRequest) LogValue() slog.Value {
 
 type UserLoginResponse struct {
 	AccessToken string `json:"access_token,omitempty"`
+	MFAToken    string `json:"mfa_token,omitempty"`
 }
 
 func (h *AuthHandler) HandleUserLogin(w http.ResponseWriter, r *http.Request) {
 	_, req, _ := FromParamsContext[UserLoginRequest](r.Context())
+	// Failed logins are counted against the remote address rather than getIPAddress, since the forwarding
+	// headers are set by the client and would let it pick the address to be locked out.
 	params := service.LoginUserParams{
-		Email:    req.Email,
-		Password: req.Password,
+		Email:     req.Email,
+		Password:  req.Password,
+		IPAddress: r.RemoteAddr,
 	}
-	accessToken, refreshToken, err := h.service.LoginUser(r.Context(), params)
+	result, err := h.service.LoginUser(clientContext(r), params)
 	if err != nil {
+		var locked *service.AccountLockedError
+		if errors.As(err, &locked) {
+			tooManyRequestsResponse(w, err, message.AccountLocked, locked.RetryAfter)
+			return
+		}
+
 		if errors.Is(err, service.ErrUserNotFound) {
 			unauthorizedResponse(w, err, message.UserNotFound)
 			return
@@ -139,55 +153,129 @@ func (h *AuthHandler) HandleUserLogin(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		if errors.Is(err, service.ErrUserSuspended) {
+			forbiddenResponse(w, err, message.AccountSuspended)
+			return
+		}
+
 		response.ServerError(w, err)
 		return
 	}
 
-	cookieCfg := h.cfg.Cookie
+	// No session yet: the client must send the challenge and a code to HandleVerifyMFA.
+	if result.MFAToken != "" {
+		res := Response[*UserLoginResponse]{
+			Message: message.MFARequired,
+			Data: &UserLoginResponse{
+				MFAToken: result.MFAToken,
+			},
+		}
+		response.JSON(w, http.StatusOK, res)
+		return
+	}
 
-	http.SetCookie(w, &http.Cookie{
-		Name:     cookieCfg.Name,
-		Value:    refreshToken,
-		Path:     "/",
-		HttpOnly: true,
-		Secure:   true,
-		SameSite: http.SameSiteStrictMode,
-		MaxAge:   cookieCfg.MaxAge,
-	})
+	setRefreshCookie(w, h.cfg, result.RefreshToken, h.cfg.Cookie.MaxAge)
 
 	res := Response[*UserLoginResponse]{
 		Message: message.UserLoginSuccess,
 		Data: &UserLoginResponse{
-			AccessToken: accessToken,
+			AccessToken: result.AccessToken,
 		},
 	}
 
 	response.JSON(w, http.StatusOK, res)
 }
 
-func (h *AuthHandler) HandleRefreshToken(w http.ResponseWriter, r *http.Request) {
-	cookie, err := r.Cookie(h.cfg.Cookie.Name)
+type VerifyMFARequest struct {
+	MFAToken string `json:"mfa_token,omitempty" validate:"required"`
+	Code     string `json:"code,omitempty" validate:"required"`
+}
+
+func (r *VerifyMFARequest) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("mfa_token", maskChar),
+		slog.String("code", maskChar),
+	)
+}
+
+// HandleVerifyMFA completes a login that required MFA.
+func (h *AuthHandler) HandleVerifyMFA(w http.ResponseWriter, r *http.Request) {
+	_, req, _ := FromParamsContext[VerifyMFARequest](r.Context())
+	params := service.VerifyMFAParams{
+		Token: req.MFAToken,
+		Code:  req.Code,
+	}
+	accessToken, refreshToken, err := h.service.VerifyMFA(clientContext(r), params)
 	if err != nil {
-		unauthorizedResponse(w, err, "Unauthorized")
+		// A user that is not found was purged after the password was checked, so the token is no longer valid.
+		if errors.Is(err, service.ErrInvalidToken) || errors.Is(err, service.ErrUserNotFound) {
+			unauthorizedResponse(w, err, message.TokenInvalid)
+			return
+		}
+
+		var locked *service.AccountLockedError
+		if errors.As(err, &locked) {
+			tooManyRequestsResponse(w, err, message.MFALocked, locked.RetryAfter)
+			return
+		}
+
+		if errors.Is(err, service.ErrInvalidMFACode) || errors.Is(err, service.ErrMFANotEnabled) {
+			unauthorizedResponse(w, err, message.MFACodeInvalid)
+			return
+		}
+
+		if errors.Is(err, service.ErrUserSuspended) {
+			forbiddenResponse(w, err, message.AccountSuspended)
+			return
+		}
+
+		response.ServerError(w, err)
 		return
 	}
 
-	userID, err := h.signer.Verify(cookie.Value)
+	setRefreshCookie(w, h.cfg, refreshToken, h.cfg.Cookie.MaxAge)
+
+	res := Response[*UserLoginResponse]{
+		Message: message.UserLoginSuccess,
+		Data: &UserLoginResponse{
+			AccessToken: accessToken,
+		},
+	}
+
+	response.JSON(w, http.StatusOK, res)
+}
+
+func (h *AuthHandler) HandleRefreshToken(w http.ResponseWriter, r *http.Request) {
+	cookie, err := r.Cookie(h.cfg.Cookie.Name)
 	if err != nil {
 		unauthorizedResponse(w, err, "Unauthorized")
 		return
 	}
 
-	newAccessToken, err := h.signer.Sign(userID, []string{h.cfg.JWT.Issuer}, time.Duration(h.cfg.JWT.Duration)*time.Minute)
+	accessToken, refreshToken, err := h.service.RefreshToken(r.Context(), cookie.Value)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidToken) || errors.Is(err, service.ErrTokenReused) {
+			setRefreshCookie(w, h.cfg, "", -1)
+			unauthorizedResponse(w, err, "Unauthorized")
+			return
+		}
+
+		if errors.Is(err, service.ErrUserSuspended) {
+			setRefreshCookie(w, h.cfg, "", -1)
+			forbiddenResponse(w, err, message.AccountSuspended)
+			return
+		}
+
 		response.ServerError(w, err)
 		return
 	}
 
+	setRefreshCookie(w, h.cfg, refreshToken, h.cfg.Cookie.MaxAge)
+
 	res := Response[*UserLoginResponse]{
 		Message: message.UserLoginSuccess,
 		Data: &UserLoginResponse{
-			AccessToken: newAccessToken,
+			AccessToken: accessToken,
 		},
 	}
 
@@ -195,27 +283,32 @@ func (h *AuthHandler) HandleRefreshToken(w http.ResponseWriter, r *http.Request)
 }
 
 func (h *AuthHandler) HandleLogout(w http.ResponseWriter, r *http.Request) {
-	cookieName := h.cfg.Cookie.Name
-	_, err := r.Cookie(cookieName)
-	// if err == nil {
-	//     _ = InvalidateRefreshToken(cookie.Value) // optional: best effort
-	// }
-
+	cookie, err := r.Cookie(h.cfg.Cookie.Name)
 	if err != nil {
-		response.ServerError(w, err)
+		unauthorizedResponse(w, err, "Unauthorized")
 		return
 	}
 
+	// The access token is optional; when present it is revoked along with the session.
+	accessToken, _ := extractBearerToken(r.Header.Get("Authorization"))
+	params := service.LogoutUserParams{
+		RefreshToken: cookie.Value,
+		AccessToken:  accessToken,
+		AllSessions:  r.URL.Query().Get("all") == "true",
+	}
+
 	// Expire the cookie
-	http.SetCookie(w, &http.Cookie{
-		Name:     cookieName,
-		Value:    "",
-		Path:     "/",
-		HttpOnly: true,
-		Secure:   true,
-		SameSite: http.SameSiteStrictMode,
-		MaxAge:   -1, // expire immediately
-	})
+	setRefreshCookie(w, h.cfg, "", -1)
+
+	if err := h.service.LogoutUser(r.Context(), params); err != nil {
+		if errors.Is(err, service.ErrInvalidToken) {
+			unauthorizedResponse(w, err, "Unauthorized")
+			return
+		}
+
+		response.ServerError(w, err)
+		return
+	}
 
 	res := Response[any]{
 		Message: message.UserLogoutSuccess,
@@ -223,3 +316,254 @@ func (h *AuthHandler) HandleLogout(w http.ResponseWriter, r *http.Request) {
 
 	response.JSON(w, http.StatusOK, res)
 }
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email,omitempty" validate:"required,email"`
+}
+
+func (r *ForgotPasswordRequest) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("email", maskChar),
+	)
+}
+
+func (h *AuthHandler) HandleForgotPassword(w http.ResponseWriter, r *http.Request) {
+	_, req, _ := FromParamsContext[ForgotPasswordRequest](r.Context())
+	if err := h.service.ForgotPassword(r.Context(), req.Email); err != nil {
+		if isContextError(err) {
+			return
+		}
+
+		response.ServerError(w, err)
+		return
+	}
+
+	res := Response[any]{
+		Message: message.PasswordResetRequested,
+	}
+
+	response.JSON(w, http.StatusOK, res)
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email,omitempty" validate:"required,email"`
+}
+
+func (r *ResendVerificationRequest) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("email", maskChar),
+	)
+}
+
+func (h *AuthHandler) HandleResendVerification(w http.ResponseWriter, r *http.Request) {
+	_, req, _ := FromParamsContext[ResendVerificationRequest](r.Context())
+	if err := h.service.ResendVerification(r.Context(), req.Email); err != nil {
+		if isContextError(err) {
+			return
+		}
+
+		response.ServerError(w, err)
+		return
+	}
+
+	res := Response[any]{
+		Message: message.VerificationResent,
+	}
+
+	response.JSON(w, http.StatusOK, res)
+}
+
+type ResetPasswordRequest struct {
+	Token           string `json:"token,omitempty" validate:"required"`
+	Password        string `json:"password,omitempty" validate:"required"`
+	PasswordConfirm string `json:"password_confirm,omitempty" validate:"required,eqfield=Password"`
+}
+
+func (r *ResetPasswordRequest) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("token", maskChar),
+		slog.String("password", maskChar),
+		slog.String("password_confirm", maskChar),
+	)
+}
+
+func (h *AuthHandler) HandleResetPassword(w http.ResponseWriter, r *http.Request) {
+	_, req, _ := FromParamsContext[ResetPasswordRequest](r.Context())
+	params := service.ResetPasswordParams{
+		Token:    req.Token,
+		Password: req.Password,
+	}
+	if err := h.service.ResetPassword(r.Context(), params); err != nil {
+		if errors.Is(err, service.ErrInvalidToken) {
+			badRequestResponse(w, err, message.TokenInvalid)
+			return
+		}
+
+		response.ServerError(w, err)
+		return
+	}
+
+	res := Response[any]{
+		Message: message.PasswordResetSuccess,
+	}
+
+	response.JSON(w, http.StatusOK, res)
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password,omitempty" validate:"required"`
+	Password        string `json:"password,omitempty" validate:"required"`
+	PasswordConfirm string `json:"password_confirm,omitempty" validate:"required,eqfield=Password"`
+}
+
+func (r *ChangePasswordRequest) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("current_password", maskChar),
+		slog.String("password", maskChar),
+		slog.String("password_confirm", maskChar),
+	)
+}
+
+// HandleChangePassword changes the signed-in user's password. The session of the refresh cookie, if sent,
+// stays signed in.
+func (h *AuthHandler) HandleChangePassword(w http.ResponseWriter, r *http.Request) {
+	userID, ok := FromUserContext(r.Context())
+	if !ok {
+		unauthorizedResponse(w, errors.New("missing user in context"), "Unauthorized")
+		return
+	}
+
+	_, req, _ := FromParamsContext[ChangePasswordRequest](r.Context())
+	params := service.ChangePasswordParams{
+		UserID:          userID,
+		CurrentPassword: req.CurrentPassword,
+		NewPassword:     req.Password,
+	}
+	if cookie, err := r.Cookie(h.cfg.Cookie.Name); err == nil {
+		params.RefreshToken = cookie.Value
+	}
+
+	if err := h.service.ChangePassword(r.Context(), params); err != nil {
+		if errors.Is(err, service.ErrWrongPassword) {
+			badRequestResponse(w, err, message.PasswordIncorrect)
+			return
+		}
+
+		if errors.Is(err, service.ErrUserNotFound) {
+			unauthorizedResponse(w, err, "Unauthorized")
+			return
+		}
+
+		if isContextError(err) {
+			return
+		}
+
+		response.ServerError(w, err)
+		return
+	}
+
+	res := Response[any]{
+		Message: message.PasswordChanged,
+	}
+
+	response.JSON(w, http.StatusOK, res)
+}
+
+type ChangeEmailRequest struct {
+	Email    string `json:"email,omitempty" validate:"required,email"`
+	Password string `json:"password,omitempty" validate:"required"`
+}
+
+func (r *ChangeEmailRequest) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("email", maskChar),
+		slog.String("password", maskChar),
+	)
+}
+
+// HandleChangeEmail starts an email change. The new address receives a link to HandleConfirmEmailChange.
+func (h *AuthHandler) HandleChangeEmail(w http.ResponseWriter, r *http.Request) {
+	userID, ok := FromUserContext(r.Context())
+	if !ok {
+		unauthorizedResponse(w, errors.New("missing user in context"), "Unauthorized")
+		return
+	}
+
+	_, req, _ := FromParamsContext[ChangeEmailRequest](r.Context())
+	params := service.RequestEmailChangeParams{
+		UserID:   userID,
+		NewEmail: req.Email,
+		Password: req.Password,
+	}
+	if err := h.service.RequestEmailChange(r.Context(), params); err != nil {
+		if errors.Is(err, service.ErrWrongPassword) {
+			badRequestResponse(w, err, message.PasswordIncorrect)
+			return
+		}
+
+		if errors.Is(err, service.ErrUserExists) {
+			unprocessableResponse(w, err, message.UserExists)
+			return
+		}
+
+		if errors.Is(err, service.ErrUserNotFound) {
+			unauthorizedResponse(w, err, "Unauthorized")
+			return
+		}
+
+		if isContextError(err) {
+			return
+		}
+
+		response.ServerError(w, err)
+		return
+	}
+
+	res := Response[any]{
+		Message: message.EmailChangeRequested,
+	}
+
+	response.JSON(w, http.StatusOK, res)
+}
+
+func (h *AuthHandler) HandleConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+
+	if token == "" {
+		badRequestResponse(w, service.ErrInvalidToken, message.TokenInvalid)
+		return
+	}
+
+	if err := h.service.ConfirmEmailChange(r.Context(), token); err != nil {
+		if errors.Is(err, service.ErrInvalidToken) {
+			badRequestResponse(w, err, message.TokenInvalid)
+			return
+		}
+
+		if errors.Is(err, service.ErrUserExists) {
+			unprocessableResponse(w, err, message.UserExists)
+			return
+		}
+
+		response.ServerError(w, err)
+		return
+	}
+
+	res := Response[any]{
+		Message: message.EmailChanged,
+	}
+	response.JSON(w, http.StatusOK, res)
+}
+
+// setRefreshCookie writes the refresh token cookie. A negative maxAge expires it immediately.
+func setRefreshCookie(w http.ResponseWriter, cfg *config.Config, token string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     cfg.Cookie.Name,
+		Value:    token,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   maxAge,
+	})
+}
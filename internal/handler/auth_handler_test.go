@@ -14,7 +14,6 @@ import (
 	"github.com/ferdiebergado/gojeep/internal/model"
 	"github.com/ferdiebergado/gojeep/internal/pkg/logging"
 	"github.com/ferdiebergado/gojeep/internal/pkg/message"
-	secMock "github.com/ferdiebergado/gojeep/internal/pkg/security/mock"
 
 	"github.com/ferdiebergado/gojeep/internal/service"
 	"github.com/ferdiebergado/gojeep/internal/service/mock"
@@ -32,12 +31,13 @@ const (
 )
 
 type testCase struct {
-	name           string
-	request        handler.RegisterUserRequest
-	setupMocks     func(mockService *mock.MockUserService)
-	expectedStatus int
-	expectedMsg    string
-	verifyResponse func(t *testing.T, res handler.Response[handler.RegisterUserResponse])
+	name            string
+	enumerationSafe bool
+	request         handler.RegisterUserRequest
+	setupMocks      func(mockService *mock.MockAuthService)
+	expectedStatus  int
+	expectedMsg     string
+	verifyResponse  func(t *testing.T, res handler.Response[handler.RegisterUserResponse])
 }
 
 var validate *validator.Validate
@@ -63,7 +63,7 @@ func TestUserHandler_HandleUserRegister(t *testing.T) {
 				Password:        testPass,
 				PasswordConfirm: testPass,
 			},
-			setupMocks: func(mockService *mock.MockUserService) {
+			setupMocks: func(mockService *mock.MockAuthService) {
 				mockService.EXPECT().
 					RegisterUser(gomock.Any(), service.RegisterUserParams{
 						Email:    testEmail,
@@ -87,7 +87,7 @@ func TestUserHandler_HandleUserRegister(t *testing.T) {
 				Password:        testPass,
 				PasswordConfirm: testPass,
 			},
-			setupMocks: func(mockService *mock.MockUserService) {
+			setupMocks: func(mockService *mock.MockAuthService) {
 				mockService.EXPECT().RegisterUser(gomock.Any(), gomock.Any()).Times(0)
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -100,7 +100,7 @@ func TestUserHandler_HandleUserRegister(t *testing.T) {
 				Password:        testPass,
 				PasswordConfirm: testPass,
 			},
-			setupMocks: func(mockService *mock.MockUserService) {
+			setupMocks: func(mockService *mock.MockAuthService) {
 				mockService.EXPECT().RegisterUser(gomock.Any(), gomock.Any()).Times(0)
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -113,7 +113,7 @@ func TestUserHandler_HandleUserRegister(t *testing.T) {
 				Password:        testPass,
 				PasswordConfirm: testPass,
 			},
-			setupMocks: func(mockService *mock.MockUserService) {
+			setupMocks: func(mockService *mock.MockAuthService) {
 				mockService.EXPECT().
 					RegisterUser(gomock.Any(), service.RegisterUserParams{
 						Email:    testEmail,
@@ -124,6 +124,42 @@ func TestUserHandler_HandleUserRegister(t *testing.T) {
 			expectedStatus: http.StatusUnprocessableEntity,
 			expectedMsg:    message.UserExists,
 		},
+		{
+			name:            "Enumeration-safe - new user",
+			enumerationSafe: true,
+			request: handler.RegisterUserRequest{
+				Email:           testEmail,
+				Password:        testPass,
+				PasswordConfirm: testPass,
+			},
+			setupMocks: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().RegisterUser(gomock.Any(), gomock.Any()).Return(user, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedMsg:    message.UserRegSuccess,
+			verifyResponse: func(t *testing.T, res handler.Response[handler.RegisterUserResponse]) {
+				t.Helper()
+				assert.Empty(t, res.Data)
+			},
+		},
+		{
+			name:            "Enumeration-safe - duplicate user",
+			enumerationSafe: true,
+			request: handler.RegisterUserRequest{
+				Email:           testEmail,
+				Password:        testPass,
+				PasswordConfirm: testPass,
+			},
+			setupMocks: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().RegisterUser(gomock.Any(), gomock.Any()).Return(model.User{}, service.ErrUserExists)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedMsg:    message.UserRegSuccess,
+			verifyResponse: func(t *testing.T, res handler.Response[handler.RegisterUserResponse]) {
+				t.Helper()
+				assert.Empty(t, res.Data)
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -140,20 +176,22 @@ func runRegisterUserTest(t *testing.T, tc testCase) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockService := mock.NewMockUserService(ctrl)
-	mockSigner := secMock.NewMockSigner(ctrl)
+	mockService := mock.NewMockAuthService(ctrl)
 
 	cfg := &config.Config{
 		JWT: &config.JWTOptions{
 			Issuer:   "localhost:8888",
 			Duration: 15,
 		},
+		Registration: &config.RegistrationOptions{
+			EnumerationSafe: tc.enumerationSafe,
+		},
 	}
 	if tc.setupMocks != nil {
 		tc.setupMocks(mockService)
 	}
 
-	userHandler := handler.NewAuthHandler(mockService, mockSigner, cfg)
+	userHandler := handler.NewAuthHandler(mockService, cfg)
 	registerHandler := handler.ValidateInput[handler.RegisterUserRequest](validate)(
 		http.HandlerFunc(userHandler.HandleUserRegister))
 	registerHandler = handler.DecodeJSON[handler.RegisterUserRequest]()(registerHandler)
@@ -185,16 +223,22 @@ func runRegisterUserTest(t *testing.T, tc testCase) {
 
 func TestUserHandler_HandleUserLogin(t *testing.T) {
 	t.Parallel()
-	const url = "/auth/login"
+	const (
+		url = "/auth/login"
+		// remoteAddr is the address httptest.NewRequest gives requests.
+		remoteAddr = "192.0.2.1:1234"
+	)
 	ctrl := gomock.NewController(t)
 
 	tests := []struct {
-		name            string
-		email           string
-		password        string
-		expectedStatus  int
-		expectedMessage string
-		mockServiceCall func(mockService *mock.MockUserService)
+		name               string
+		email              string
+		password           string
+		expectedStatus     int
+		expectedMessage    string
+		expectedRetryAfter string
+		forwardedFor       string
+		mockServiceCall    func(mockService *mock.MockAuthService)
 	}{
 		{
 			name:            "Valid login credentials",
@@ -202,13 +246,47 @@ func TestUserHandler_HandleUserLogin(t *testing.T) {
 			password:        testPass,
 			expectedStatus:  http.StatusOK,
 			expectedMessage: message.UserLoginSuccess,
-			mockServiceCall: func(mockService *mock.MockUserService) {
+			mockServiceCall: func(mockService *mock.MockAuthService) {
 				mockService.EXPECT().
 					LoginUser(gomock.Any(), service.LoginUserParams{
-						Email:    testEmail,
-						Password: testPass,
+						Email:     testEmail,
+						Password:  testPass,
+						IPAddress: remoteAddr,
+					}).
+					Return(service.LoginResult{AccessToken: "mock_access_token", RefreshToken: "mock_refresh_token"}, nil)
+			},
+		},
+		{
+			name:            "MFA required",
+			email:           testEmail,
+			password:        testPass,
+			expectedStatus:  http.StatusOK,
+			expectedMessage: message.MFARequired,
+			mockServiceCall: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().
+					LoginUser(gomock.Any(), service.LoginUserParams{
+						Email:     testEmail,
+						Password:  testPass,
+						IPAddress: remoteAddr,
+					}).
+					Return(service.LoginResult{MFAToken: "mock_mfa_token"}, nil)
+			},
+		},
+		{
+			name:            "Forwarding headers ignored for the lockout",
+			email:           testEmail,
+			password:        "wrongpass",
+			forwardedFor:    "203.0.113.7",
+			expectedStatus:  http.StatusUnauthorized,
+			expectedMessage: message.UserNotFound,
+			mockServiceCall: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().
+					LoginUser(gomock.Any(), service.LoginUserParams{
+						Email:     testEmail,
+						Password:  "wrongpass",
+						IPAddress: remoteAddr,
 					}).
-					Return("mock_access_token", "mock_refresh_token", nil)
+					Return(service.LoginResult{}, service.ErrUserNotFound)
 			},
 		},
 		{
@@ -217,7 +295,7 @@ func TestUserHandler_HandleUserLogin(t *testing.T) {
 			password:        testPass,
 			expectedStatus:  http.StatusBadRequest,
 			expectedMessage: message.UserInputInvalid,
-			mockServiceCall: func(mockService *mock.MockUserService) {
+			mockServiceCall: func(mockService *mock.MockAuthService) {
 			},
 		},
 		{
@@ -225,7 +303,7 @@ func TestUserHandler_HandleUserLogin(t *testing.T) {
 			password:        testPass,
 			expectedStatus:  http.StatusBadRequest,
 			expectedMessage: message.UserInputInvalid,
-			mockServiceCall: func(mockService *mock.MockUserService) {
+			mockServiceCall: func(mockService *mock.MockAuthService) {
 			},
 		},
 		{
@@ -233,7 +311,7 @@ func TestUserHandler_HandleUserLogin(t *testing.T) {
 			email:           testEmail,
 			expectedStatus:  http.StatusBadRequest,
 			expectedMessage: message.UserInputInvalid,
-			mockServiceCall: func(mockService *mock.MockUserService) {
+			mockServiceCall: func(mockService *mock.MockAuthService) {
 			},
 		},
 		{
@@ -242,13 +320,39 @@ func TestUserHandler_HandleUserLogin(t *testing.T) {
 			password:        "wrongpass",
 			expectedStatus:  http.StatusUnauthorized,
 			expectedMessage: message.UserNotFound,
-			mockServiceCall: func(mockService *mock.MockUserService) {
+			mockServiceCall: func(mockService *mock.MockAuthService) {
 				mockService.EXPECT().
 					LoginUser(gomock.Any(), service.LoginUserParams{
-						Email:    testEmail,
-						Password: "wrongpass",
+						Email:     testEmail,
+						Password:  "wrongpass",
+						IPAddress: remoteAddr,
 					}).
-					Return("", "", service.ErrUserNotFound)
+					Return(service.LoginResult{}, service.ErrUserNotFound)
+			},
+		},
+		{
+			name:            "Account suspended",
+			email:           testEmail,
+			password:        testPass,
+			expectedStatus:  http.StatusForbidden,
+			expectedMessage: message.AccountSuspended,
+			mockServiceCall: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().
+					LoginUser(gomock.Any(), gomock.Any()).
+					Return(service.LoginResult{}, service.ErrUserSuspended)
+			},
+		},
+		{
+			name:               "Account locked",
+			email:              testEmail,
+			password:           testPass,
+			expectedStatus:     http.StatusTooManyRequests,
+			expectedMessage:    message.AccountLocked,
+			expectedRetryAfter: "90",
+			mockServiceCall: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().
+					LoginUser(gomock.Any(), gomock.Any()).
+					Return(service.LoginResult{}, &service.AccountLockedError{RetryAfter: 89500 * time.Millisecond})
 			},
 		},
 	}
@@ -256,8 +360,7 @@ func TestUserHandler_HandleUserLogin(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			mockService := mock.NewMockUserService(ctrl)
-			mockSigner := secMock.NewMockSigner(ctrl)
+			mockService := mock.NewMockAuthService(ctrl)
 
 			cfg := &config.Config{
 				JWT: &config.JWTOptions{
@@ -271,7 +374,7 @@ func TestUserHandler_HandleUserLogin(t *testing.T) {
 			}
 			tt.mockServiceCall(mockService)
 
-			userHandler := handler.NewAuthHandler(mockService, mockSigner, cfg)
+			userHandler := handler.NewAuthHandler(mockService, cfg)
 			userLoginHandler := handler.ValidateInput[handler.UserLoginRequest](validate)(
 				http.HandlerFunc(userHandler.HandleUserLogin))
 			userLoginHandler = handler.DecodeJSON[handler.UserLoginRequest]()(userLoginHandler)
@@ -287,6 +390,10 @@ func TestUserHandler_HandleUserLogin(t *testing.T) {
 
 			req := httptest.NewRequest(http.MethodPost, url, bytes.NewReader(reqBody))
 			req.Header.Set(handler.HeaderContentType, handler.MimeJSON)
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Real-IP", tt.forwardedFor)
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
 			rec := httptest.NewRecorder()
 
 			userLoginHandler.ServeHTTP(rec, req)
@@ -294,6 +401,7 @@ func TestUserHandler_HandleUserLogin(t *testing.T) {
 			defer res.Body.Close()
 
 			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+			assert.Equal(t, tt.expectedRetryAfter, res.Header.Get("Retry-After"))
 
 			if tt.expectedMessage != "" {
 				var apiRes handler.Response[any]
@@ -303,3 +411,724 @@ func TestUserHandler_HandleUserLogin(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthHandler_HandleRefreshToken(t *testing.T) {
+	t.Parallel()
+	const (
+		url        = "/auth/refresh"
+		cookieName = "refresh_token"
+		oldToken   = "old_refresh_token"
+	)
+
+	tests := []struct {
+		name            string
+		cookie          string
+		expectedStatus  int
+		expectedMessage string
+		expectedCookie  string
+		mockServiceCall func(mockService *mock.MockAuthService)
+	}{
+		{
+			name:            "Token rotated",
+			cookie:          oldToken,
+			expectedStatus:  http.StatusOK,
+			expectedMessage: message.UserLoginSuccess,
+			expectedCookie:  "new_refresh_token",
+			mockServiceCall: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().RefreshToken(gomock.Any(), oldToken).
+					Return("new_access_token", "new_refresh_token", nil)
+			},
+		},
+		{
+			name:            "Missing cookie",
+			expectedStatus:  http.StatusUnauthorized,
+			expectedMessage: "Unauthorized",
+			mockServiceCall: func(_ *mock.MockAuthService) {},
+		},
+		{
+			name:            "Reused token",
+			cookie:          oldToken,
+			expectedStatus:  http.StatusUnauthorized,
+			expectedMessage: "Unauthorized",
+			mockServiceCall: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().RefreshToken(gomock.Any(), oldToken).
+					Return("", "", service.ErrTokenReused)
+			},
+		},
+		{
+			name:            "Account suspended",
+			cookie:          oldToken,
+			expectedStatus:  http.StatusForbidden,
+			expectedMessage: message.AccountSuspended,
+			mockServiceCall: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().RefreshToken(gomock.Any(), oldToken).
+					Return("", "", service.ErrUserSuspended)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockAuthService(ctrl)
+			tt.mockServiceCall(mockService)
+
+			cfg := &config.Config{
+				Cookie: &config.CookieOptions{
+					Name:   cookieName,
+					MaxAge: 60,
+				},
+			}
+			authHandler := handler.NewAuthHandler(mockService, cfg)
+
+			req := httptest.NewRequest(http.MethodPost, url, nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: cookieName, Value: tt.cookie})
+			}
+			rec := httptest.NewRecorder()
+
+			authHandler.HandleRefreshToken(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			var apiRes handler.Response[handler.UserLoginResponse]
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&apiRes), "failed to decode response")
+			assert.Equal(t, tt.expectedMessage, apiRes.Message)
+
+			if tt.expectedCookie != "" {
+				cookies := res.Cookies()
+				require.Len(t, cookies, 1)
+				assert.Equal(t, tt.expectedCookie, cookies[0].Value)
+				assert.Equal(t, "new_access_token", apiRes.Data.AccessToken)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_HandleLogout(t *testing.T) {
+	t.Parallel()
+	const (
+		cookieName   = "refresh_token"
+		refreshToken = "refresh_token_value"
+	)
+
+	tests := []struct {
+		name            string
+		url             string
+		cookie          string
+		authHeader      string
+		expectedStatus  int
+		expectedMessage string
+		mockServiceCall func(mockService *mock.MockAuthService)
+	}{
+		{
+			name:            "Logout current session",
+			url:             "/auth/logout",
+			cookie:          refreshToken,
+			authHeader:      "Bearer access_token",
+			expectedStatus:  http.StatusOK,
+			expectedMessage: message.UserLogoutSuccess,
+			mockServiceCall: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().LogoutUser(gomock.Any(), service.LogoutUserParams{
+					RefreshToken: refreshToken,
+					AccessToken:  "access_token",
+				}).Return(nil)
+			},
+		},
+		{
+			name:            "Logout all sessions",
+			url:             "/auth/logout?all=true",
+			cookie:          refreshToken,
+			expectedStatus:  http.StatusOK,
+			expectedMessage: message.UserLogoutSuccess,
+			mockServiceCall: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().LogoutUser(gomock.Any(), service.LogoutUserParams{
+					RefreshToken: refreshToken,
+					AllSessions:  true,
+				}).Return(nil)
+			},
+		},
+		{
+			name:            "Missing cookie",
+			url:             "/auth/logout",
+			expectedStatus:  http.StatusUnauthorized,
+			expectedMessage: "Unauthorized",
+			mockServiceCall: func(_ *mock.MockAuthService) {},
+		},
+		{
+			name:            "Invalid refresh token",
+			url:             "/auth/logout",
+			cookie:          refreshToken,
+			expectedStatus:  http.StatusUnauthorized,
+			expectedMessage: "Unauthorized",
+			mockServiceCall: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().LogoutUser(gomock.Any(), gomock.Any()).Return(service.ErrInvalidToken)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockAuthService(ctrl)
+			tt.mockServiceCall(mockService)
+
+			cfg := &config.Config{
+				Cookie: &config.CookieOptions{Name: cookieName, MaxAge: 60},
+			}
+			authHandler := handler.NewAuthHandler(mockService, cfg)
+
+			req := httptest.NewRequest(http.MethodPost, tt.url, nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: cookieName, Value: tt.cookie})
+			}
+			if tt.authHeader != "" {
+				req.Header.Set("Authorization", tt.authHeader)
+			}
+			rec := httptest.NewRecorder()
+
+			authHandler.HandleLogout(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			var apiRes handler.Response[any]
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&apiRes), "failed to decode response")
+			assert.Equal(t, tt.expectedMessage, apiRes.Message)
+		})
+	}
+}
+
+func TestAuthHandler_HandleForgotPassword(t *testing.T) {
+	t.Parallel()
+	const url = "/auth/forgot-password"
+
+	tests := []struct {
+		name            string
+		email           string
+		expectedStatus  int
+		expectedMessage string
+		mockServiceCall func(mockService *mock.MockAuthService)
+	}{
+		{
+			name:            "Reset link requested",
+			email:           testEmail,
+			expectedStatus:  http.StatusOK,
+			expectedMessage: message.PasswordResetRequested,
+			mockServiceCall: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().ForgotPassword(gomock.Any(), testEmail).Return(nil)
+			},
+		},
+		{
+			name:            "Invalid email",
+			email:           "notanemail",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: message.UserInputInvalid,
+			mockServiceCall: func(_ *mock.MockAuthService) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockAuthService(ctrl)
+			tt.mockServiceCall(mockService)
+
+			authHandler := handler.NewAuthHandler(mockService, &config.Config{})
+			forgotHandler := handler.ValidateInput[handler.ForgotPasswordRequest](validate)(
+				http.HandlerFunc(authHandler.HandleForgotPassword))
+			forgotHandler = handler.DecodeJSON[handler.ForgotPasswordRequest]()(forgotHandler)
+
+			reqBody, err := json.Marshal(handler.ForgotPasswordRequest{Email: tt.email})
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, url, bytes.NewReader(reqBody))
+			req.Header.Set(handler.HeaderContentType, handler.MimeJSON)
+			rec := httptest.NewRecorder()
+
+			forgotHandler.ServeHTTP(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			var apiRes handler.Response[any]
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&apiRes), "failed to decode response")
+			assert.Equal(t, tt.expectedMessage, apiRes.Message)
+		})
+	}
+}
+
+func TestAuthHandler_HandleResendVerification(t *testing.T) {
+	t.Parallel()
+	const url = "/auth/verify/resend"
+
+	tests := []struct {
+		name            string
+		email           string
+		expectedStatus  int
+		expectedMessage string
+		mockServiceCall func(mockService *mock.MockAuthService)
+	}{
+		{
+			name:            "Verification resent",
+			email:           testEmail,
+			expectedStatus:  http.StatusOK,
+			expectedMessage: message.VerificationResent,
+			mockServiceCall: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().ResendVerification(gomock.Any(), testEmail).Return(nil)
+			},
+		},
+		{
+			name:            "Invalid email",
+			email:           "notanemail",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: message.UserInputInvalid,
+			mockServiceCall: func(_ *mock.MockAuthService) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockAuthService(ctrl)
+			tt.mockServiceCall(mockService)
+
+			authHandler := handler.NewAuthHandler(mockService, &config.Config{})
+			resendHandler := handler.ValidateInput[handler.ResendVerificationRequest](validate)(
+				http.HandlerFunc(authHandler.HandleResendVerification))
+			resendHandler = handler.DecodeJSON[handler.ResendVerificationRequest]()(resendHandler)
+
+			reqBody, err := json.Marshal(handler.ResendVerificationRequest{Email: tt.email})
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, url, bytes.NewReader(reqBody))
+			req.Header.Set(handler.HeaderContentType, handler.MimeJSON)
+			rec := httptest.NewRecorder()
+
+			resendHandler.ServeHTTP(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			var apiRes handler.Response[any]
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&apiRes), "failed to decode response")
+			assert.Equal(t, tt.expectedMessage, apiRes.Message)
+		})
+	}
+}
+
+func TestAuthHandler_HandleResetPassword(t *testing.T) {
+	t.Parallel()
+	const (
+		url   = "/auth/reset-password"
+		token = "reset_token"
+	)
+
+	tests := []struct {
+		name            string
+		request         handler.ResetPasswordRequest
+		expectedStatus  int
+		expectedMessage string
+		mockServiceCall func(mockService *mock.MockAuthService)
+	}{
+		{
+			name:            "Password reset",
+			request:         handler.ResetPasswordRequest{Token: token, Password: testPass, PasswordConfirm: testPass},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: message.PasswordResetSuccess,
+			mockServiceCall: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().ResetPassword(gomock.Any(), service.ResetPasswordParams{
+					Token:    token,
+					Password: testPass,
+				}).Return(nil)
+			},
+		},
+		{
+			name:            "Passwords do not match",
+			request:         handler.ResetPasswordRequest{Token: token, Password: testPass, PasswordConfirm: "other"},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: message.UserInputInvalid,
+			mockServiceCall: func(_ *mock.MockAuthService) {},
+		},
+		{
+			name:            "Used token",
+			request:         handler.ResetPasswordRequest{Token: token, Password: testPass, PasswordConfirm: testPass},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: message.TokenInvalid,
+			mockServiceCall: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().ResetPassword(gomock.Any(), gomock.Any()).Return(service.ErrInvalidToken)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockAuthService(ctrl)
+			tt.mockServiceCall(mockService)
+
+			authHandler := handler.NewAuthHandler(mockService, &config.Config{})
+			resetHandler := handler.ValidateInput[handler.ResetPasswordRequest](validate)(
+				http.HandlerFunc(authHandler.HandleResetPassword))
+			resetHandler = handler.DecodeJSON[handler.ResetPasswordRequest]()(resetHandler)
+
+			reqBody, err := json.Marshal(tt.request)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, url, bytes.NewReader(reqBody))
+			req.Header.Set(handler.HeaderContentType, handler.MimeJSON)
+			rec := httptest.NewRecorder()
+
+			resetHandler.ServeHTTP(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			var apiRes handler.Response[any]
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&apiRes), "failed to decode response")
+			assert.Equal(t, tt.expectedMessage, apiRes.Message)
+		})
+	}
+}
+
+func TestAuthHandler_HandleChangePassword(t *testing.T) {
+	t.Parallel()
+	const (
+		url          = "/users/me/password"
+		userID       = "1"
+		cookieName   = "refresh_token"
+		refreshToken = "refresh_token_value"
+		newPass      = "new_password"
+	)
+
+	tests := []struct {
+		name            string
+		request         handler.ChangePasswordRequest
+		expectedStatus  int
+		expectedMessage string
+		mockServiceCall func(mockService *mock.MockAuthService)
+	}{
+		{
+			name:            "Password changed",
+			request:         handler.ChangePasswordRequest{CurrentPassword: testPass, Password: newPass, PasswordConfirm: newPass},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: message.PasswordChanged,
+			mockServiceCall: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().ChangePassword(gomock.Any(), service.ChangePasswordParams{
+					UserID:          userID,
+					CurrentPassword: testPass,
+					NewPassword:     newPass,
+					RefreshToken:    refreshToken,
+				}).Return(nil)
+			},
+		},
+		{
+			name:            "Wrong current password",
+			request:         handler.ChangePasswordRequest{CurrentPassword: "wrong", Password: newPass, PasswordConfirm: newPass},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: message.PasswordIncorrect,
+			mockServiceCall: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().ChangePassword(gomock.Any(), gomock.Any()).Return(service.ErrWrongPassword)
+			},
+		},
+		{
+			name:            "Passwords do not match",
+			request:         handler.ChangePasswordRequest{CurrentPassword: testPass, Password: newPass, PasswordConfirm: "other"},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: message.UserInputInvalid,
+			mockServiceCall: func(_ *mock.MockAuthService) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockAuthService(ctrl)
+			tt.mockServiceCall(mockService)
+
+			cfg := &config.Config{Cookie: &config.CookieOptions{Name: cookieName}}
+			authHandler := handler.NewAuthHandler(mockService, cfg)
+			changeHandler := handler.ValidateInput[handler.ChangePasswordRequest](validate)(
+				http.HandlerFunc(authHandler.HandleChangePassword))
+			changeHandler = handler.DecodeJSON[handler.ChangePasswordRequest]()(changeHandler)
+
+			reqBody, err := json.Marshal(tt.request)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, url, bytes.NewReader(reqBody))
+			req.Header.Set(handler.HeaderContentType, handler.MimeJSON)
+			req.AddCookie(&http.Cookie{Name: cookieName, Value: refreshToken})
+			req = req.WithContext(handler.NewUserContext(req.Context(), userID))
+			rec := httptest.NewRecorder()
+
+			changeHandler.ServeHTTP(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			var apiRes handler.Response[any]
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&apiRes), "failed to decode response")
+			assert.Equal(t, tt.expectedMessage, apiRes.Message)
+		})
+	}
+}
+
+func TestAuthHandler_HandleChangeEmail(t *testing.T) {
+	t.Parallel()
+	const (
+		url      = "/users/me/email"
+		userID   = "1"
+		newEmail = "new@example.com"
+	)
+
+	tests := []struct {
+		name            string
+		request         handler.ChangeEmailRequest
+		expectedStatus  int
+		expectedMessage string
+		mockServiceCall func(mockService *mock.MockAuthService)
+	}{
+		{
+			name:            "Confirmation sent",
+			request:         handler.ChangeEmailRequest{Email: newEmail, Password: testPass},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: message.EmailChangeRequested,
+			mockServiceCall: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().RequestEmailChange(gomock.Any(), service.RequestEmailChangeParams{
+					UserID:   userID,
+					NewEmail: newEmail,
+					Password: testPass,
+				}).Return(nil)
+			},
+		},
+		{
+			name:            "Email taken",
+			request:         handler.ChangeEmailRequest{Email: newEmail, Password: testPass},
+			expectedStatus:  http.StatusUnprocessableEntity,
+			expectedMessage: message.UserExists,
+			mockServiceCall: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().RequestEmailChange(gomock.Any(), gomock.Any()).Return(service.ErrUserExists)
+			},
+		},
+		{
+			name:            "Wrong password",
+			request:         handler.ChangeEmailRequest{Email: newEmail, Password: "wrong"},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: message.PasswordIncorrect,
+			mockServiceCall: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().RequestEmailChange(gomock.Any(), gomock.Any()).Return(service.ErrWrongPassword)
+			},
+		},
+		{
+			name:            "Invalid email",
+			request:         handler.ChangeEmailRequest{Email: "notanemail", Password: testPass},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: message.UserInputInvalid,
+			mockServiceCall: func(_ *mock.MockAuthService) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockAuthService(ctrl)
+			tt.mockServiceCall(mockService)
+
+			authHandler := handler.NewAuthHandler(mockService, &config.Config{})
+			changeHandler := handler.ValidateInput[handler.ChangeEmailRequest](validate)(
+				http.HandlerFunc(authHandler.HandleChangeEmail))
+			changeHandler = handler.DecodeJSON[handler.ChangeEmailRequest]()(changeHandler)
+
+			reqBody, err := json.Marshal(tt.request)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, url, bytes.NewReader(reqBody))
+			req.Header.Set(handler.HeaderContentType, handler.MimeJSON)
+			req = req.WithContext(handler.NewUserContext(req.Context(), userID))
+			rec := httptest.NewRecorder()
+
+			changeHandler.ServeHTTP(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			var apiRes handler.Response[any]
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&apiRes), "failed to decode response")
+			assert.Equal(t, tt.expectedMessage, apiRes.Message)
+		})
+	}
+}
+
+func TestAuthHandler_HandleConfirmEmailChange(t *testing.T) {
+	t.Parallel()
+	const token = "email_change_token"
+
+	tests := []struct {
+		name            string
+		token           string
+		expectedStatus  int
+		expectedMessage string
+		mockServiceCall func(mockService *mock.MockAuthService)
+	}{
+		{
+			name:            "Email changed",
+			token:           token,
+			expectedStatus:  http.StatusOK,
+			expectedMessage: message.EmailChanged,
+			mockServiceCall: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().ConfirmEmailChange(gomock.Any(), token).Return(nil)
+			},
+		},
+		{
+			name:            "Invalid token",
+			token:           token,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: message.TokenInvalid,
+			mockServiceCall: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().ConfirmEmailChange(gomock.Any(), token).Return(service.ErrInvalidToken)
+			},
+		},
+		{
+			name:            "Missing token",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: message.TokenInvalid,
+			mockServiceCall: func(_ *mock.MockAuthService) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockAuthService(ctrl)
+			tt.mockServiceCall(mockService)
+
+			authHandler := handler.NewAuthHandler(mockService, &config.Config{})
+			req := httptest.NewRequest(http.MethodGet, "/auth/email/confirm?token="+tt.token, nil)
+			rec := httptest.NewRecorder()
+
+			authHandler.HandleConfirmEmailChange(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			var apiRes handler.Response[any]
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&apiRes), "failed to decode response")
+			assert.Equal(t, tt.expectedMessage, apiRes.Message)
+		})
+	}
+}
+
+func TestAuthHandler_HandleVerifyMFA(t *testing.T) {
+	t.Parallel()
+	const (
+		cookieName = "refresh_token"
+		mfaToken   = "mfa_challenge_token"
+		code       = "123456"
+	)
+	params := service.VerifyMFAParams{Token: mfaToken, Code: code}
+
+	tests := []struct {
+		name            string
+		expectedStatus  int
+		expectedMessage string
+		expectedCookie  string
+		mockServiceCall func(mockService *mock.MockAuthService)
+	}{
+		{
+			name:            "Login completed",
+			expectedStatus:  http.StatusOK,
+			expectedMessage: message.UserLoginSuccess,
+			expectedCookie:  "new_refresh_token",
+			mockServiceCall: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().VerifyMFA(gomock.Any(), params).
+					Return("new_access_token", "new_refresh_token", nil)
+			},
+		},
+		{
+			name:            "Wrong code",
+			expectedStatus:  http.StatusUnauthorized,
+			expectedMessage: message.MFACodeInvalid,
+			mockServiceCall: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().VerifyMFA(gomock.Any(), params).Return("", "", service.ErrInvalidMFACode)
+			},
+		},
+		{
+			name:            "Too many wrong codes",
+			expectedStatus:  http.StatusTooManyRequests,
+			expectedMessage: message.MFALocked,
+			mockServiceCall: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().VerifyMFA(gomock.Any(), params).
+					Return("", "", &service.AccountLockedError{RetryAfter: time.Minute})
+			},
+		},
+		{
+			name:            "Expired challenge",
+			expectedStatus:  http.StatusUnauthorized,
+			expectedMessage: message.TokenInvalid,
+			mockServiceCall: func(mockService *mock.MockAuthService) {
+				mockService.EXPECT().VerifyMFA(gomock.Any(), params).Return("", "", service.ErrInvalidToken)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockAuthService(ctrl)
+			tt.mockServiceCall(mockService)
+
+			cfg := &config.Config{
+				Cookie: &config.CookieOptions{
+					Name:   cookieName,
+					MaxAge: 60,
+				},
+			}
+			authHandler := handler.NewAuthHandler(mockService, cfg)
+			verifyHandler := handler.ValidateInput[handler.VerifyMFARequest](validate)(
+				http.HandlerFunc(authHandler.HandleVerifyMFA))
+			verifyHandler = handler.DecodeJSON[handler.VerifyMFARequest]()(verifyHandler)
+
+			reqBody, err := json.Marshal(handler.VerifyMFARequest{MFAToken: mfaToken, Code: code})
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/mfa/verify", bytes.NewReader(reqBody))
+			req.Header.Set(handler.HeaderContentType, handler.MimeJSON)
+			rec := httptest.NewRecorder()
+
+			verifyHandler.ServeHTTP(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			var apiRes handler.Response[handler.UserLoginResponse]
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&apiRes), "failed to decode response")
+			assert.Equal(t, tt.expectedMessage, apiRes.Message)
+
+			if tt.expectedCookie != "" {
+				cookies := res.Cookies()
+				require.Len(t, cookies, 1)
+				assert.Equal(t, tt.expectedCookie, cookies[0].Value)
+				assert.Equal(t, "new_access_token", apiRes.Data.AccessToken)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ type ctxKey int
 const (
 	paramsCtxKey ctxKey = iota
 	userCtxKey
+	apiKeyCtxKey
 )
 
 func NewParamsContext[T any](ctx context.Context, t T) context.Context {
@@ -28,3 +29,15 @@ func FromUserContext(ctx context.Context) (string, bool) {
 	userID, ok := ctxVal.(string)
 	return userID, ok
 }
+
+// NewAPIKeyContext marks a request as authenticated with an API key limited to the given scopes.
+func NewAPIKeyContext(ctx context.Context, scopes []string) context.Context {
+	return context.WithValue(ctx, apiKeyCtxKey, scopes)
+}
+
+// FromAPIKeyContext returns the scopes of the API key a request was authenticated with, and false if it
+// was authenticated with an access token.
+func FromAPIKeyContext(ctx context.Context) ([]string, bool) {
+	scopes, ok := ctx.Value(apiKeyCtxKey).([]string)
+	return scopes, ok
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/ferdiebergado/gopherkit/http/response"
 )
@@ -21,6 +24,20 @@ func unauthorizedResponse(w http.ResponseWriter, err error, msg string) {
 	errorResponse(w, http.StatusUnauthorized, err, msg)
 }
 
+func forbiddenResponse(w http.ResponseWriter, err error, msg string) {
+	errorResponse(w, http.StatusForbidden, err, msg)
+}
+
+func notFoundResponse(w http.ResponseWriter, err error, msg string) {
+	errorResponse(w, http.StatusNotFound, err, msg)
+}
+
+// tooManyRequestsResponse tells the client how many seconds to wait in the Retry-After header.
+func tooManyRequestsResponse(w http.ResponseWriter, err error, msg string, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	errorResponse(w, http.StatusTooManyRequests, err, msg)
+}
+
 func unsupportedContentTypeResponse(w http.ResponseWriter, err error, msg string) {
 	errorResponse(w, http.StatusUnsupportedMediaType, err, msg)
 }
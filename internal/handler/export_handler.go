@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ferdiebergado/gojeep/internal/pkg/message"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/ferdiebergado/gopherkit/http/response"
+)
+
+// ExportHandler lets users download the personal data held on them.
+type ExportHandler struct {
+	service service.ExportService
+}
+
+func NewExportHandler(exportService service.ExportService) *ExportHandler {
+	return &ExportHandler{service: exportService}
+}
+
+// HandleRequest starts an export of the signed-in user's data. It is mounted behind RequireAuth.
+func (h *ExportHandler) HandleRequest(w http.ResponseWriter, r *http.Request) {
+	userID, ok := FromUserContext(r.Context())
+	if !ok {
+		unauthorizedResponse(w, errors.New("missing user in context"), "Unauthorized")
+		return
+	}
+
+	if err := h.service.RequestExport(r.Context(), userID); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			notFoundResponse(w, err, message.UserProfileNotFound)
+			return
+		}
+
+		var pending *service.ExportPendingError
+		if errors.As(err, &pending) {
+			tooManyRequestsResponse(w, err, message.DataExportPending, pending.RetryAfter)
+			return
+		}
+
+		if isContextError(err) {
+			return
+		}
+
+		response.ServerError(w, err)
+		return
+	}
+
+	res := Response[any]{
+		Message: message.DataExportRequested,
+	}
+
+	response.JSON(w, http.StatusAccepted, res)
+}
+
+// HandleDownload serves the archive of an emailed download link. The signed link is the credential, so
+// the route is public.
+func (h *ExportHandler) HandleDownload(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		badRequestResponse(w, service.ErrInvalidToken, message.DataExportInvalid)
+		return
+	}
+
+	export, err := h.service.DownloadExport(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidToken) {
+			badRequestResponse(w, err, message.DataExportInvalid)
+			return
+		}
+
+		if isContextError(err) {
+			return
+		}
+
+		response.ServerError(w, err)
+		return
+	}
+
+	filename := "data-export-" + export.CreatedAt.Format("2006-01-02") + ".zip"
+	w.Header().Set(HeaderContentType, "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.Header().Set("Content-Length", strconv.Itoa(len(export.Archive)))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(export.Archive)
+}
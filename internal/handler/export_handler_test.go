@@ -0,0 +1,132 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/handler"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/pkg/message"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/ferdiebergado/gojeep/internal/service/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestExportHandler_HandleRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name               string
+		requestErr         error
+		expectedStatus     int
+		expectedMessage    string
+		expectedRetryAfter string
+	}{
+		{
+			name:            "Requested",
+			expectedStatus:  http.StatusAccepted,
+			expectedMessage: message.DataExportRequested,
+		},
+		{
+			name:               "Export pending",
+			requestErr:         &service.ExportPendingError{RetryAfter: time.Hour},
+			expectedStatus:     http.StatusTooManyRequests,
+			expectedMessage:    message.DataExportPending,
+			expectedRetryAfter: "3600",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockExportService(ctrl)
+			mockService.EXPECT().RequestExport(gomock.Any(), testUserID).Return(tt.requestErr)
+
+			req := withUser(httptest.NewRequest(http.MethodPost, "/users/me/export", nil), testUserID)
+			rec := httptest.NewRecorder()
+
+			handler.NewExportHandler(mockService).HandleRequest(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+			assert.Equal(t, tt.expectedRetryAfter, res.Header.Get("Retry-After"))
+
+			var apiRes handler.Response[any]
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&apiRes), "failed to decode response")
+			assert.Equal(t, tt.expectedMessage, apiRes.Message)
+		})
+	}
+}
+
+func TestExportHandler_HandleDownload(t *testing.T) {
+	t.Parallel()
+	createdAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	export := model.DataExport{ID: "export-1", UserID: testUserID, Archive: []byte("zip"), CreatedAt: createdAt}
+
+	tests := []struct {
+		name           string
+		query          string
+		downloadErr    error
+		callsService   bool
+		expectedStatus int
+	}{
+		{
+			name:           "Downloaded",
+			query:          "?token=token",
+			callsService:   true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Missing token",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Expired link",
+			query:          "?token=token",
+			downloadErr:    service.ErrInvalidToken,
+			callsService:   true,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockExportService(ctrl)
+			if tt.callsService {
+				mockService.EXPECT().DownloadExport(gomock.Any(), "token").Return(export, tt.downloadErr)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/exports/download"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			handler.NewExportHandler(mockService).HandleDownload(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			require.Equal(t, tt.expectedStatus, res.StatusCode)
+			if tt.expectedStatus != http.StatusOK {
+				var apiRes handler.Response[any]
+				require.NoError(t, json.NewDecoder(res.Body).Decode(&apiRes), "failed to decode response")
+				assert.Equal(t, message.DataExportInvalid, apiRes.Message)
+				return
+			}
+
+			assert.Equal(t, "application/zip", res.Header.Get("Content-Type"))
+			assert.Equal(t, `attachment; filename="data-export-2024-05-01.zip"`, res.Header.Get("Content-Disposition"))
+			assert.Equal(t, "no-store", res.Header.Get("Cache-Control"))
+			body, err := io.ReadAll(res.Body)
+			require.NoError(t, err)
+			assert.Equal(t, export.Archive, body)
+		})
+	}
+}
@@ -1,8 +1,12 @@
 package handler
 
 import (
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/ferdiebergado/gojeep/internal/config"
 	"github.com/ferdiebergado/gojeep/internal/pkg/security"
@@ -23,15 +27,59 @@ type Response[T any] struct {
 }
 
 type Handler struct {
-	Base BaseHandler
-	Auth AuthHandler
+	Base      BaseHandler
+	Auth      AuthHandler
+	User      UserHandler
+	MFA       MFAHandler
+	WebAuthn  WebAuthnHandler
+	OAuth     OAuthHandler
+	MagicLink MagicLinkHandler
+	Provider  ProviderHandler
+	Role      RoleHandler
+	APIKey    APIKeyHandler
+	Session   SessionHandler
+	Export    ExportHandler
+	Admin     AdminHandler
+	WellKnown WellKnownHandler
+
+	// OIDC tells whether gojeep is an OpenID provider, so the provider routes are mounted.
+	OIDC bool
+	// Authenticate is the RequireAuth middleware for routes that need a signed-in user.
+	Authenticate func(http.Handler) http.Handler
+	// Authorize returns the RequirePermission middleware for a permission. It goes after Authenticate.
+	Authorize func(permission string) func(http.Handler) http.Handler
 }
 
 func New(svc service.Service, signer security.Signer, cfg *config.Config) *Handler {
 	return &Handler{
-		Base: *NewBaseHandler(svc.Base),
-		Auth: *NewAuthHandler(svc.User, signer, cfg),
+		Base:         *NewBaseHandler(svc.Base),
+		Auth:         *NewAuthHandler(svc.Auth, cfg),
+		User:         *NewUserHandler(svc.User),
+		MFA:          *NewMFAHandler(svc.MFA),
+		WebAuthn:     *NewWebAuthnHandler(svc.WebAuthn, cfg),
+		OAuth:        *NewOAuthHandler(svc.OAuth, cfg),
+		MagicLink:    *NewMagicLinkHandler(svc.MagicLink, cfg),
+		Provider:     *NewProviderHandler(svc.Provider),
+		Role:         *NewRoleHandler(svc.Role),
+		APIKey:       *NewAPIKeyHandler(svc.APIKey),
+		Session:      *NewSessionHandler(svc.Session),
+		Export:       *NewExportHandler(svc.Export),
+		Admin:        *NewAdminHandler(svc.Admin),
+		WellKnown:    *NewWellKnownHandler(signer, cfg.JWT.Issuer, authorizationEndpoint(cfg)),
+		OIDC:         svc.Provider != nil,
+		Authenticate: RequireAuth(signer, svc.Revocation, svc.APIKey, cfg.JWT.Issuer),
+		Authorize: func(permission string) func(http.Handler) http.Handler {
+			return RequirePermission(svc.Role, permission)
+		},
+	}
+}
+
+// authorizationEndpoint is the frontend page OpenID clients send users to, if gojeep is a provider.
+func authorizationEndpoint(cfg *config.Config) string {
+	if cfg.OIDC == nil {
+		return ""
 	}
+	return cfg.OIDC.AuthorizationEndpoint
 }
 
 type BaseHandler struct {
@@ -54,3 +102,34 @@ func (h *BaseHandler) HandleHealth(w http.ResponseWriter, r *http.Request) {
 
 	response.JSON(w, status, Response[any]{Message: msg})
 }
+
+var errInvalidID = errors.New("invalid id")
+
+// pathID returns the id path value of the request. Ids are UUIDs, so a malformed one is refused here
+// instead of failing in the database.
+func pathID(r *http.Request) (string, error) {
+	id := r.PathValue("id")
+	if !isUUID(id) {
+		return "", fmt.Errorf("%w: %q", errInvalidID, id)
+	}
+	return id, nil
+}
+
+// isUUID reports whether s is a UUID in its hyphenated form.
+func isUUID(s string) bool {
+	groups := strings.Split(s, "-")
+	sizes := [...]int{8, 4, 4, 4, 12}
+	if len(groups) != len(sizes) {
+		return false
+	}
+
+	for i, group := range groups {
+		if len(group) != sizes[i] {
+			return false
+		}
+		if _, err := hex.DecodeString(group); err != nil {
+			return false
+		}
+	}
+	return true
+}
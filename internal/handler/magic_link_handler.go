@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/ferdiebergado/gojeep/internal/config"
+	"github.com/ferdiebergado/gojeep/internal/pkg/message"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/ferdiebergado/gopherkit/http/response"
+)
+
+// magicLinkCookie holds the binding of a requested login link, so the link only works in the browser
+// that asked for it.
+const magicLinkCookie = "magic_link"
+
+// MagicLinkHandler signs users in with links sent to their email.
+type MagicLinkHandler struct {
+	service service.MagicLinkService
+	cfg     *config.Config
+}
+
+func NewMagicLinkHandler(magicLinkService service.MagicLinkService, cfg *config.Config) *MagicLinkHandler {
+	return &MagicLinkHandler{
+		service: magicLinkService,
+		cfg:     cfg,
+	}
+}
+
+type MagicLinkRequest struct {
+	Email string `json:"email,omitempty" validate:"required,email"`
+}
+
+func (r *MagicLinkRequest) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("email", maskChar),
+	)
+}
+
+// HandleRequest emails a login link and binds it to the browser with a cookie.
+func (h *MagicLinkHandler) HandleRequest(w http.ResponseWriter, r *http.Request) {
+	_, req, _ := FromParamsContext[MagicLinkRequest](r.Context())
+	binding, err := h.service.RequestLink(r.Context(), req.Email)
+	if err != nil {
+		if isContextError(err) {
+			return
+		}
+
+		response.ServerError(w, err)
+		return
+	}
+
+	h.setBindingCookie(w, binding, h.cfg.Email.Options.MagicLinkTTL)
+
+	res := Response[any]{
+		Message: message.MagicLinkRequested,
+	}
+
+	response.JSON(w, http.StatusOK, res)
+}
+
+// HandleConsume signs the user in from a login link. It answers like HandleUserLogin.
+func (h *MagicLinkHandler) HandleConsume(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+
+	cookie, err := r.Cookie(magicLinkCookie)
+	if err != nil || token == "" {
+		badRequestResponse(w, service.ErrInvalidToken, message.MagicLinkInvalid)
+		return
+	}
+
+	result, err := h.service.ConsumeLink(clientContext(r), token, cookie.Value)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidToken) {
+			badRequestResponse(w, err, message.MagicLinkInvalid)
+			return
+		}
+
+		if errors.Is(err, service.ErrUserSuspended) {
+			forbiddenResponse(w, err, message.AccountSuspended)
+			return
+		}
+
+		if isContextError(err) {
+			return
+		}
+
+		response.ServerError(w, err)
+		return
+	}
+	h.setBindingCookie(w, "", -1)
+
+	if result.MFAToken != "" {
+		res := Response[*UserLoginResponse]{
+			Message: message.MFARequired,
+			Data: &UserLoginResponse{
+				MFAToken: result.MFAToken,
+			},
+		}
+		response.JSON(w, http.StatusOK, res)
+		return
+	}
+
+	setRefreshCookie(w, h.cfg, result.RefreshToken, h.cfg.Cookie.MaxAge)
+
+	res := Response[*UserLoginResponse]{
+		Message: message.UserLoginSuccess,
+		Data: &UserLoginResponse{
+			AccessToken: result.AccessToken,
+		},
+	}
+
+	response.JSON(w, http.StatusOK, res)
+}
+
+// setBindingCookie is SameSite Lax because the link is opened by a cross-site navigation from the mail client.
+func (h *MagicLinkHandler) setBindingCookie(w http.ResponseWriter, binding string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     magicLinkCookie,
+		Value:    binding,
+		Path:     "/auth/magic-link",
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   maxAge,
+	})
+}
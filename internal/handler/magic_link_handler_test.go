@@ -0,0 +1,161 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ferdiebergado/gojeep/internal/config"
+	"github.com/ferdiebergado/gojeep/internal/handler"
+	"github.com/ferdiebergado/gojeep/internal/pkg/message"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/ferdiebergado/gojeep/internal/service/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	magicLinkBinding = "binding"
+	magicLinkCookie  = "magic_link"
+)
+
+func magicLinkConfig() *config.Config {
+	return &config.Config{
+		Email:  &config.SMTPConfig{Options: &config.EmailOptions{MagicLinkTTL: 300}},
+		Cookie: &config.CookieOptions{Name: "refresh_token", MaxAge: 3600},
+	}
+}
+
+func findCookie(res *http.Response, name string) *http.Cookie {
+	for _, c := range res.Cookies() {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+func TestMagicLinkHandler_HandleRequest(t *testing.T) {
+	t.Parallel()
+	const testEmail = "abc@example.com"
+
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockMagicLinkService(ctrl)
+	mockService.EXPECT().RequestLink(gomock.Any(), testEmail).Return(magicLinkBinding, nil)
+
+	params := handler.MagicLinkRequest{Email: testEmail}
+	req := httptest.NewRequest(http.MethodPost, "/auth/magic-link", nil)
+	req = req.WithContext(handler.NewParamsContext(req.Context(), params))
+	rec := httptest.NewRecorder()
+
+	handler.NewMagicLinkHandler(mockService, magicLinkConfig()).HandleRequest(rec, req)
+	res := rec.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var apiRes handler.Response[any]
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&apiRes), "failed to decode response")
+	assert.Equal(t, message.MagicLinkRequested, apiRes.Message)
+
+	cookie := findCookie(res, magicLinkCookie)
+	require.NotNil(t, cookie, "binding cookie not set")
+	assert.Equal(t, magicLinkBinding, cookie.Value)
+	assert.True(t, cookie.HttpOnly)
+	assert.True(t, cookie.Secure)
+	assert.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+	assert.Equal(t, "/auth/magic-link", cookie.Path)
+	assert.Equal(t, 300, cookie.MaxAge)
+}
+
+func TestMagicLinkHandler_HandleConsume(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		query           string
+		cookie          string
+		result          service.LoginResult
+		consumeErr      error
+		callsService    bool
+		expectedStatus  int
+		expectedMessage string
+		expectedRefresh string
+	}{
+		{
+			name:            "Signed in",
+			query:           "?token=token",
+			cookie:          magicLinkBinding,
+			result:          service.LoginResult{AccessToken: "access", RefreshToken: "refresh"},
+			callsService:    true,
+			expectedStatus:  http.StatusOK,
+			expectedMessage: message.UserLoginSuccess,
+			expectedRefresh: "refresh",
+		},
+		{
+			name:            "MFA required",
+			query:           "?token=token",
+			cookie:          magicLinkBinding,
+			result:          service.LoginResult{MFAToken: "mfa"},
+			callsService:    true,
+			expectedStatus:  http.StatusOK,
+			expectedMessage: message.MFARequired,
+		},
+		{
+			name:            "Missing binding cookie",
+			query:           "?token=token",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: message.MagicLinkInvalid,
+		},
+		{
+			name:            "Missing token",
+			cookie:          magicLinkBinding,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: message.MagicLinkInvalid,
+		},
+		{
+			name:            "Invalid or used link",
+			query:           "?token=token",
+			cookie:          magicLinkBinding,
+			consumeErr:      service.ErrInvalidToken,
+			callsService:    true,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: message.MagicLinkInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockMagicLinkService(ctrl)
+			if tt.callsService {
+				mockService.EXPECT().ConsumeLink(gomock.Any(), "token", magicLinkBinding).Return(tt.result, tt.consumeErr)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/auth/magic-link/consume"+tt.query, nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: magicLinkCookie, Value: tt.cookie})
+			}
+			rec := httptest.NewRecorder()
+
+			handler.NewMagicLinkHandler(mockService, magicLinkConfig()).HandleConsume(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			var apiRes handler.Response[*handler.UserLoginResponse]
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&apiRes), "failed to decode response")
+			assert.Equal(t, tt.expectedMessage, apiRes.Message)
+
+			var refresh string
+			if c := findCookie(res, "refresh_token"); c != nil {
+				refresh = c.Value
+			}
+			assert.Equal(t, tt.expectedRefresh, refresh)
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/ferdiebergado/gojeep/internal/pkg/message"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/ferdiebergado/gopherkit/http/response"
+)
+
+// MFAHandler manages the signed-in user's TOTP enrollment. Its routes are mounted behind RequireAuth.
+type MFAHandler struct {
+	service service.MFAService
+}
+
+func NewMFAHandler(mfaService service.MFAService) *MFAHandler {
+	return &MFAHandler{
+		service: mfaService,
+	}
+}
+
+type MFAEnrollResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// MFARecoveryCodesResponse carries a new set of recovery codes, which is only ever shown once.
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code,omitempty" validate:"required"`
+}
+
+func (r *MFACodeRequest) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("code", maskChar),
+	)
+}
+
+func (h *MFAHandler) HandleEnroll(w http.ResponseWriter, r *http.Request) {
+	userID, ok := FromUserContext(r.Context())
+	if !ok {
+		unauthorizedResponse(w, errors.New("missing user in context"), "Unauthorized")
+		return
+	}
+
+	enrollment, err := h.service.Enroll(r.Context(), userID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	res := Response[*MFAEnrollResponse]{
+		Message: message.MFAEnrollStarted,
+		Data: &MFAEnrollResponse{
+			Secret: enrollment.Secret,
+			URI:    enrollment.URI,
+		},
+	}
+
+	response.JSON(w, http.StatusOK, res)
+}
+
+func (h *MFAHandler) HandleConfirm(w http.ResponseWriter, r *http.Request) {
+	userID, ok := FromUserContext(r.Context())
+	if !ok {
+		unauthorizedResponse(w, errors.New("missing user in context"), "Unauthorized")
+		return
+	}
+
+	_, req, _ := FromParamsContext[MFACodeRequest](r.Context())
+	codes, err := h.service.Confirm(r.Context(), userID, req.Code)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	res := Response[*MFARecoveryCodesResponse]{
+		Message: message.MFAEnabled,
+		Data: &MFARecoveryCodesResponse{
+			RecoveryCodes: codes,
+		},
+	}
+
+	response.JSON(w, http.StatusOK, res)
+}
+
+func (h *MFAHandler) HandleRegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	userID, ok := FromUserContext(r.Context())
+	if !ok {
+		unauthorizedResponse(w, errors.New("missing user in context"), "Unauthorized")
+		return
+	}
+
+	_, req, _ := FromParamsContext[MFACodeRequest](r.Context())
+	codes, err := h.service.RegenerateRecoveryCodes(r.Context(), userID, req.Code)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	res := Response[*MFARecoveryCodesResponse]{
+		Message: message.MFARecoveryCodesNew,
+		Data: &MFARecoveryCodesResponse{
+			RecoveryCodes: codes,
+		},
+	}
+
+	response.JSON(w, http.StatusOK, res)
+}
+
+func (h *MFAHandler) HandleDisable(w http.ResponseWriter, r *http.Request) {
+	userID, ok := FromUserContext(r.Context())
+	if !ok {
+		unauthorizedResponse(w, errors.New("missing user in context"), "Unauthorized")
+		return
+	}
+
+	_, req, _ := FromParamsContext[MFACodeRequest](r.Context())
+	if err := h.service.Disable(r.Context(), userID, req.Code); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	res := Response[any]{
+		Message: message.MFADisabled,
+	}
+
+	response.JSON(w, http.StatusOK, res)
+}
+
+func (h *MFAHandler) handleError(w http.ResponseWriter, err error) {
+	var locked *service.AccountLockedError
+	if errors.As(err, &locked) {
+		tooManyRequestsResponse(w, err, message.MFALocked, locked.RetryAfter)
+		return
+	}
+
+	if errors.Is(err, service.ErrInvalidMFACode) {
+		badRequestResponse(w, err, message.MFACodeInvalid)
+		return
+	}
+
+	if errors.Is(err, service.ErrMFAEnabled) {
+		unprocessableResponse(w, err, message.MFAAlreadyEnabled)
+		return
+	}
+
+	if errors.Is(err, service.ErrMFANotEnabled) {
+		unprocessableResponse(w, err, message.MFANotEnabled)
+		return
+	}
+
+	if errors.Is(err, service.ErrUserNotFound) {
+		unauthorizedResponse(w, err, "Unauthorized")
+		return
+	}
+
+	if isContextError(err) {
+		return
+	}
+
+	response.ServerError(w, err)
+}
@@ -0,0 +1,189 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/handler"
+	"github.com/ferdiebergado/gojeep/internal/pkg/message"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/ferdiebergado/gojeep/internal/service/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestMFAHandler_HandleEnroll(t *testing.T) {
+	t.Parallel()
+	enrollment := service.MFAEnrollment{
+		Secret: "JBSWY3DPEHPK3PXP",
+		URI:    "otpauth://totp/gojeep:abc@example.com?secret=JBSWY3DPEHPK3PXP",
+	}
+
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockMFAService(ctrl)
+	mockService.EXPECT().Enroll(gomock.Any(), testUserID).Return(enrollment, nil)
+
+	mfaHandler := handler.NewMFAHandler(mockService)
+	req := withUser(httptest.NewRequest(http.MethodPost, "/users/me/mfa/enroll", nil), testUserID)
+	rec := httptest.NewRecorder()
+
+	mfaHandler.HandleEnroll(rec, req)
+	res := rec.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var apiRes handler.Response[*handler.MFAEnrollResponse]
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&apiRes), "failed to decode response")
+	require.NotNil(t, apiRes.Data)
+	assert.Equal(t, enrollment.Secret, apiRes.Data.Secret)
+	assert.Equal(t, enrollment.URI, apiRes.Data.URI)
+}
+
+func TestMFAHandler_HandleConfirm(t *testing.T) {
+	t.Parallel()
+	const code = "123456"
+	recoveryCodes := []string{"abcd-efgh-ijkl-mnop", "qrst-uvwx-yz23-4567"}
+
+	tests := []struct {
+		name            string
+		code            string
+		expectedStatus  int
+		expectedMessage string
+		mockServiceCall func(mockService *mock.MockMFAService)
+	}{
+		{
+			name:            "MFA enabled",
+			code:            code,
+			expectedStatus:  http.StatusOK,
+			expectedMessage: message.MFAEnabled,
+			mockServiceCall: func(mockService *mock.MockMFAService) {
+				mockService.EXPECT().Confirm(gomock.Any(), testUserID, code).Return(recoveryCodes, nil)
+			},
+		},
+		{
+			name:            "Wrong code",
+			code:            code,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: message.MFACodeInvalid,
+			mockServiceCall: func(mockService *mock.MockMFAService) {
+				mockService.EXPECT().Confirm(gomock.Any(), testUserID, code).Return(nil, service.ErrInvalidMFACode)
+			},
+		},
+		{
+			name:            "Already enabled",
+			code:            code,
+			expectedStatus:  http.StatusUnprocessableEntity,
+			expectedMessage: message.MFAAlreadyEnabled,
+			mockServiceCall: func(mockService *mock.MockMFAService) {
+				mockService.EXPECT().Confirm(gomock.Any(), testUserID, code).Return(nil, service.ErrMFAEnabled)
+			},
+		},
+		{
+			name:            "Missing code",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: message.UserInputInvalid,
+			mockServiceCall: func(_ *mock.MockMFAService) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockMFAService(ctrl)
+			tt.mockServiceCall(mockService)
+
+			mfaHandler := handler.NewMFAHandler(mockService)
+			confirmHandler := handler.ValidateInput[handler.MFACodeRequest](validate)(
+				http.HandlerFunc(mfaHandler.HandleConfirm))
+			confirmHandler = handler.DecodeJSON[handler.MFACodeRequest]()(confirmHandler)
+
+			reqBody, err := json.Marshal(handler.MFACodeRequest{Code: tt.code})
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/users/me/mfa/confirm", bytes.NewReader(reqBody))
+			req.Header.Set(handler.HeaderContentType, handler.MimeJSON)
+			req = withUser(req, testUserID)
+			rec := httptest.NewRecorder()
+
+			confirmHandler.ServeHTTP(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			var apiRes handler.Response[*handler.MFARecoveryCodesResponse]
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&apiRes), "failed to decode response")
+			assert.Equal(t, tt.expectedMessage, apiRes.Message)
+			if tt.expectedStatus == http.StatusOK {
+				require.NotNil(t, apiRes.Data)
+				assert.Equal(t, recoveryCodes, apiRes.Data.RecoveryCodes)
+			}
+		})
+	}
+}
+
+func TestMFAHandler_HandleDisable(t *testing.T) {
+	t.Parallel()
+	const code = "123456"
+
+	tests := []struct {
+		name               string
+		disableErr         error
+		expectedStatus     int
+		expectedMessage    string
+		expectedRetryAfter string
+	}{
+		{
+			name:            "MFA disabled",
+			expectedStatus:  http.StatusOK,
+			expectedMessage: message.MFADisabled,
+		},
+		{
+			name:               "Locked out",
+			disableErr:         &service.AccountLockedError{RetryAfter: time.Minute},
+			expectedStatus:     http.StatusTooManyRequests,
+			expectedMessage:    message.MFALocked,
+			expectedRetryAfter: "60",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockMFAService(ctrl)
+			mockService.EXPECT().Disable(gomock.Any(), testUserID, code).Return(tt.disableErr)
+
+			mfaHandler := handler.NewMFAHandler(mockService)
+			disableHandler := handler.ValidateInput[handler.MFACodeRequest](validate)(
+				http.HandlerFunc(mfaHandler.HandleDisable))
+			disableHandler = handler.DecodeJSON[handler.MFACodeRequest]()(disableHandler)
+
+			reqBody, err := json.Marshal(handler.MFACodeRequest{Code: code})
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/users/me/mfa/disable", bytes.NewReader(reqBody))
+			req.Header.Set(handler.HeaderContentType, handler.MimeJSON)
+			req = withUser(req, testUserID)
+			rec := httptest.NewRecorder()
+
+			disableHandler.ServeHTTP(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+			assert.Equal(t, tt.expectedRetryAfter, res.Header.Get("Retry-After"))
+
+			var apiRes handler.Response[any]
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&apiRes), "failed to decode response")
+			assert.Equal(t, tt.expectedMessage, apiRes.Message)
+		})
+	}
+}
@@ -7,12 +7,15 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/ferdiebergado/gojeep/internal/pkg/message"
 	"github.com/ferdiebergado/gojeep/internal/pkg/security"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/ferdiebergado/gopherkit/http/response"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -67,28 +70,163 @@ func ValidateInput[T any](validate *validator.Validate) func(next http.Handler)
 	}
 }
 
-func RequireAuth(signer security.Signer) func(http.Handler) http.Handler {
+// apiKeyScheme is the Authorization scheme of requests made with an API key.
+const apiKeyScheme = "ApiKey "
+
+// RequireAuth accepts access tokens issued for the given audience, or API keys sent with the ApiKey scheme.
+func RequireAuth(
+	signer security.Signer, revocations service.RevocationService, apiKeys service.APIKeyService, audience string,
+) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			tokenStr, err := extractBearerToken(r.Header.Get("Authorization"))
+			header := r.Header.Get("Authorization")
+			if strings.HasPrefix(header, apiKeyScheme) {
+				authenticateAPIKey(w, r, next, apiKeys, revocations, strings.TrimSpace(header[len(apiKeyScheme):]))
+				return
+			}
+
+			tokenStr, err := extractBearerToken(header)
 			if err != nil || tokenStr == "" {
 				unauthorizedResponse(w, err, "Unauthorized")
 				return
 			}
 
-			sub, err := signer.Verify(tokenStr)
+			claims, err := signer.Verify(tokenStr, security.PurposeAccess, audience)
 			if err != nil {
 				unauthorizedResponse(w, err, "Unauthorized")
 				return
 			}
 
-			userCtx := NewUserContext(r.Context(), sub)
+			revoked, err := revocations.IsTokenRevoked(r.Context(), claims.ID)
+			if err != nil {
+				response.ServerError(w, err)
+				return
+			}
+
+			if revoked {
+				unauthorizedResponse(w, errors.New("token revoked"), "Unauthorized")
+				return
+			}
+
+			revoked, err = revocations.IsUserTokenRevoked(r.Context(), claims.Subject, claims.IssuedAt)
+			if err != nil {
+				response.ServerError(w, err)
+				return
+			}
+
+			if revoked {
+				unauthorizedResponse(w, errors.New("tokens of user revoked"), "Unauthorized")
+				return
+			}
+
+			if rejectSuspended(w, r, revocations, claims.Subject) {
+				return
+			}
+
+			userCtx := NewUserContext(r.Context(), claims.Subject)
 			r = r.WithContext(userCtx)
 			next.ServeHTTP(w, r)
 		})
 	}
 }
 
+func authenticateAPIKey(w http.ResponseWriter, r *http.Request, next http.Handler,
+	apiKeys service.APIKeyService, revocations service.RevocationService, key string,
+) {
+	apiKey, err := apiKeys.Authenticate(r.Context(), key)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAPIKey) {
+			unauthorizedResponse(w, err, "Unauthorized")
+			return
+		}
+
+		if isContextError(err) {
+			return
+		}
+
+		response.ServerError(w, err)
+		return
+	}
+
+	if rejectSuspended(w, r, revocations, apiKey.UserID) {
+		return
+	}
+
+	ctx := NewUserContext(r.Context(), apiKey.UserID)
+	ctx = NewAPIKeyContext(ctx, apiKey.Scopes)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// rejectSuspended responds with 403 and returns true if the user is suspended. Valid credentials of a
+// suspended user are refused until the suspension ends or is lifted.
+func rejectSuspended(
+	w http.ResponseWriter, r *http.Request, revocations service.RevocationService, userID string,
+) bool {
+	suspended, err := revocations.IsUserSuspended(r.Context(), userID)
+	if err != nil {
+		if !isContextError(err) {
+			response.ServerError(w, err)
+		}
+		return true
+	}
+
+	if suspended {
+		forbiddenResponse(w, fmt.Errorf("user %s suspended", userID), message.AccountSuspended)
+		return true
+	}
+	return false
+}
+
+// RequireSession refuses requests made with an API key. It guards the routes that manage the account
+// itself, such as its credentials, sessions and keys, which API keys have no scopes for. It must run after
+// RequireAuth.
+func RequireSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, viaAPIKey := FromAPIKeyContext(r.Context()); viaAPIKey {
+			forbiddenResponse(w, errors.New("api key used on an account route"), message.SessionRequired)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequirePermission lets through only users holding a role that grants the permission. Requests made
+// with a scoped API key also need the permission among the key's scopes. It must run after RequireAuth,
+// which establishes the user.
+func RequirePermission(roles service.RoleService, permission string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := FromUserContext(r.Context())
+			if !ok {
+				unauthorizedResponse(w, errors.New("missing user in context"), "Unauthorized")
+				return
+			}
+
+			allowed, err := roles.HasPermission(r.Context(), userID, permission)
+			if err != nil {
+				if isContextError(err) {
+					return
+				}
+				response.ServerError(w, err)
+				return
+			}
+
+			if !allowed {
+				forbiddenResponse(w, fmt.Errorf("user %s lacks permission %s", userID, permission), message.Forbidden)
+				return
+			}
+
+			if scopes, ok := FromAPIKeyContext(r.Context()); ok && len(scopes) > 0 && !slices.Contains(scopes, permission) {
+				forbiddenResponse(w, fmt.Errorf("api key lacks scope %s", permission), message.Forbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 func extractBearerToken(header string) (string, error) {
 	if header == "" {
 		return "", errors.New("missing Authorization header")
@@ -182,6 +320,12 @@ func LogRequest(next http.Handler) http.Handler {
 	})
 }
 
+// clientContext attaches the requesting device to the request context, for the session a login starts.
+func clientContext(r *http.Request) context.Context {
+	client := service.ClientInfo{UserAgent: r.UserAgent(), IPAddress: getIPAddress(r)}
+	return service.NewClientContext(r.Context(), client)
+}
+
 // getIPAddress extracts the client's IP address from the request.
 func getIPAddress(r *http.Request) string {
 	if ip := r.Header.Get("X-Real-IP"); ip != "" {
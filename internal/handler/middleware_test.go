@@ -10,7 +10,11 @@ import (
 	"time"
 
 	"github.com/ferdiebergado/gojeep/internal/handler"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/pkg/security"
 	"github.com/ferdiebergado/gojeep/internal/pkg/security/mock"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	svcMock "github.com/ferdiebergado/gojeep/internal/service/mock"
 	"go.uber.org/mock/gomock"
 )
 
@@ -34,6 +38,9 @@ func TestRequireAuth(t *testing.T) {
 		authHeader     string
 		signerSub      string
 		signerErr      error
+		revoked        bool
+		userRevoked    bool
+		suspended      bool
 		expectedStatus int
 		expectedBody   string
 	}{
@@ -44,6 +51,30 @@ func TestRequireAuth(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			expectedBody:   "user123",
 		},
+		{
+			name:           "Revoked Token",
+			authHeader:     "Bearer revoked.token.here",
+			signerSub:      "user123",
+			revoked:        true,
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"message":"Unauthorized"}`,
+		},
+		{
+			name:           "Issued Before Account Deletion",
+			authHeader:     "Bearer old.token.here",
+			signerSub:      "user123",
+			userRevoked:    true,
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"message":"Unauthorized"}`,
+		},
+		{
+			name:           "Suspended User",
+			authHeader:     "Bearer suspended.token.here",
+			signerSub:      "user123",
+			suspended:      true,
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"message":"Your account has been suspended."}`,
+		},
 		{
 			name:           "Missing Authorization Header",
 			authHeader:     "",
@@ -82,12 +113,28 @@ func TestRequireAuth(t *testing.T) {
 			ctrl := gomock.NewController(t)
 
 			mockSigner := mock.NewMockSigner(ctrl)
+			mockRevocations := svcMock.NewMockRevocationService(ctrl)
+			mockAPIKeys := svcMock.NewMockAPIKeyService(ctrl)
 
-			if tt.signerErr != nil || tt.signerSub != "" {
-				mockSigner.EXPECT().Verify(strings.ReplaceAll(tt.authHeader, "Bearer ", "")).Return(tt.signerSub, tt.signerErr)
+			const audience = "gojeep"
+			token := strings.ReplaceAll(tt.authHeader, "Bearer ", "")
+			if tt.signerErr != nil {
+				mockSigner.EXPECT().Verify(token, security.PurposeAccess, audience).Return(nil, tt.signerErr)
+			} else if tt.signerSub != "" {
+				issuedAt := time.Now()
+				mockSigner.EXPECT().Verify(token, security.PurposeAccess, audience).
+					Return(&security.Claims{ID: "jti", Subject: tt.signerSub, IssuedAt: issuedAt}, nil)
+				mockRevocations.EXPECT().IsTokenRevoked(gomock.Any(), "jti").Return(tt.revoked, nil)
+				if !tt.revoked {
+					mockRevocations.EXPECT().IsUserTokenRevoked(gomock.Any(), tt.signerSub, issuedAt).
+						Return(tt.userRevoked, nil)
+				}
+				if !tt.revoked && !tt.userRevoked {
+					mockRevocations.EXPECT().IsUserSuspended(gomock.Any(), tt.signerSub).Return(tt.suspended, nil)
+				}
 			}
 
-			handler := handler.RequireAuth(mockSigner)(nextHandler)
+			handler := handler.RequireAuth(mockSigner, mockRevocations, mockAPIKeys, audience)(nextHandler)
 			handler.ServeHTTP(rr, req)
 
 			if rr.Code != tt.expectedStatus {
@@ -100,6 +147,164 @@ func TestRequireAuth(t *testing.T) {
 	}
 }
 
+func TestRequireAuth_APIKey(t *testing.T) {
+	t.Parallel()
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _ := handler.FromUserContext(r.Context())
+		scopes, ok := handler.FromAPIKeyContext(r.Context())
+		if !ok {
+			t.Error("API key context not found")
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(user + " " + strings.Join(scopes, ",")))
+	})
+
+	tests := []struct {
+		name           string
+		authErr        error
+		suspended      bool
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "Valid key", expectedStatus: http.StatusOK, expectedBody: "user123 users:read"},
+		{
+			name:           "Invalid key",
+			authErr:        service.ErrInvalidAPIKey,
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"message":"Unauthorized"}`,
+		},
+		{
+			name:           "Key of a suspended user",
+			suspended:      true,
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"message":"Your account has been suspended."}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockAPIKeys := svcMock.NewMockAPIKeyService(ctrl)
+			mockRevocations := svcMock.NewMockRevocationService(ctrl)
+			key := model.APIKey{UserID: "user123", Scopes: []string{"users:read"}}
+			if tt.authErr != nil {
+				key = model.APIKey{}
+			} else {
+				mockRevocations.EXPECT().IsUserSuspended(gomock.Any(), key.UserID).Return(tt.suspended, nil)
+			}
+			mockAPIKeys.EXPECT().Authenticate(gomock.Any(), "gjp_prefix_secret").Return(key, tt.authErr)
+
+			req := httptest.NewRequest("GET", "/protected", nil)
+			req.Header.Set("Authorization", "ApiKey gjp_prefix_secret")
+			rr := httptest.NewRecorder()
+
+			mw := handler.RequireAuth(mock.NewMockSigner(ctrl), mockRevocations, mockAPIKeys, "gojeep")
+			mw(nextHandler).ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %v, got %v", tt.expectedStatus, rr.Code)
+			}
+			if strings.TrimSpace(rr.Body.String()) != tt.expectedBody {
+				t.Errorf("expected body %q, got %q", tt.expectedBody, strings.TrimSpace(rr.Body.String()))
+			}
+		})
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	t.Parallel()
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name           string
+		userID         string
+		allowed        bool
+		apiKeyScopes   []string
+		expectedStatus int
+	}{
+		{name: "Permission granted", userID: "user123", allowed: true, expectedStatus: http.StatusOK},
+		{name: "Permission missing", userID: "user123", expectedStatus: http.StatusForbidden},
+		{name: "No user", expectedStatus: http.StatusUnauthorized},
+		{
+			name:           "API key in scope",
+			userID:         "user123",
+			allowed:        true,
+			apiKeyScopes:   []string{"users:read"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "API key out of scope",
+			userID:         "user123",
+			allowed:        true,
+			apiKeyScopes:   []string{"roles:read"},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockRoles := svcMock.NewMockRoleService(ctrl)
+			if tt.userID != "" {
+				mockRoles.EXPECT().HasPermission(gomock.Any(), tt.userID, "users:read").Return(tt.allowed, nil)
+			}
+
+			req := httptest.NewRequest("GET", "/admin/users", nil)
+			if tt.userID != "" {
+				req = req.WithContext(handler.NewUserContext(req.Context(), tt.userID))
+			}
+			if tt.apiKeyScopes != nil {
+				req = req.WithContext(handler.NewAPIKeyContext(req.Context(), tt.apiKeyScopes))
+			}
+			rr := httptest.NewRecorder()
+
+			handler.RequirePermission(mockRoles, "users:read")(nextHandler).ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %v, got %v", tt.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
+
+func TestRequireSession(t *testing.T) {
+	t.Parallel()
+	nextHandler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name           string
+		viaAPIKey      bool
+		expectedStatus int
+	}{
+		{name: "Access token", expectedStatus: http.StatusOK},
+		{name: "API key", viaAPIKey: true, expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(http.MethodDelete, "/users/me", nil)
+			req = req.WithContext(handler.NewUserContext(req.Context(), "user123"))
+			if tt.viaAPIKey {
+				req = req.WithContext(handler.NewAPIKeyContext(req.Context(), []string{"users:read"}))
+			}
+			rr := httptest.NewRecorder()
+
+			handler.RequireSession(nextHandler).ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("expected status %v, got %v", tt.expectedStatus, rr.Code)
+			}
+		})
+	}
+}
+
 func TestWriteHeaderOnce(t *testing.T) {
 	rec := httptest.NewRecorder()
 	ctx := context.Background()
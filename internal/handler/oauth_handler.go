@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ferdiebergado/gojeep/internal/config"
+	"github.com/ferdiebergado/gojeep/internal/pkg/message"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/ferdiebergado/gopherkit/http/response"
+)
+
+// oauthStateCookie binds a sign-in to the browser that started it, so a callback cannot be replayed
+// in another browser to sign it in to the attacker's account.
+const oauthStateCookie = "oauth_state"
+
+// OAuthHandler signs users in with external OpenID Connect providers.
+type OAuthHandler struct {
+	service service.OAuthService
+	cfg     *config.Config
+}
+
+func NewOAuthHandler(oauthService service.OAuthService, cfg *config.Config) *OAuthHandler {
+	return &OAuthHandler{
+		service: oauthService,
+		cfg:     cfg,
+	}
+}
+
+// HandleStart redirects the browser to the provider's sign-in page.
+func (h *OAuthHandler) HandleStart(w http.ResponseWriter, r *http.Request) {
+	start, err := h.service.StartLogin(r.Context(), r.PathValue("provider"))
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	h.setStateCookie(w, start.State, h.cfg.OAuth.StateTTL)
+	http.Redirect(w, r, start.URL, http.StatusFound)
+}
+
+// HandleCallback completes the sign-in when the provider redirects back. It answers like HandleUserLogin.
+func (h *OAuthHandler) HandleCallback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	state := query.Get("state")
+
+	cookie, err := r.Cookie(oauthStateCookie)
+	if err != nil || state == "" || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+		badRequestResponse(w, errors.New("oauth state does not match cookie"), message.OAuthFailed)
+		return
+	}
+	h.setStateCookie(w, "", -1)
+
+	// The provider redirects back with an error instead of a code when the user denies the sign-in.
+	if providerErr := query.Get("error"); providerErr != "" {
+		badRequestResponse(w, fmt.Errorf("oauth authorization denied: %s", providerErr), message.OAuthFailed)
+		return
+	}
+
+	params := service.FinishOAuthLoginParams{
+		Provider: r.PathValue("provider"),
+		State:    state,
+		Code:     query.Get("code"),
+	}
+	result, err := h.service.FinishLogin(clientContext(r), params)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	if result.MFAToken != "" {
+		res := Response[*UserLoginResponse]{
+			Message: message.MFARequired,
+			Data: &UserLoginResponse{
+				MFAToken: result.MFAToken,
+			},
+		}
+		response.JSON(w, http.StatusOK, res)
+		return
+	}
+
+	setRefreshCookie(w, h.cfg, result.RefreshToken, h.cfg.Cookie.MaxAge)
+
+	res := Response[*UserLoginResponse]{
+		Message: message.UserLoginSuccess,
+		Data: &UserLoginResponse{
+			AccessToken: result.AccessToken,
+		},
+	}
+
+	response.JSON(w, http.StatusOK, res)
+}
+
+// setStateCookie is SameSite Lax because the callback is a cross-site navigation from the provider.
+func (h *OAuthHandler) setStateCookie(w http.ResponseWriter, state string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthStateCookie,
+		Value:    state,
+		Path:     "/auth/oauth",
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   maxAge,
+	})
+}
+
+func (h *OAuthHandler) handleError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrUnknownProvider) {
+		notFoundResponse(w, err, message.OAuthProviderUnknown)
+		return
+	}
+
+	if errors.Is(err, service.ErrInvalidOAuthState) || errors.Is(err, service.ErrOAuthFailed) {
+		badRequestResponse(w, err, message.OAuthFailed)
+		return
+	}
+
+	if errors.Is(err, service.ErrOAuthEmailUnverified) {
+		unprocessableResponse(w, err, message.OAuthEmailUnverified)
+		return
+	}
+
+	if errors.Is(err, service.ErrUserNotVerified) {
+		unauthorizedResponse(w, err, message.UserUnverified)
+		return
+	}
+
+	if errors.Is(err, service.ErrUserSuspended) {
+		forbiddenResponse(w, err, message.AccountSuspended)
+		return
+	}
+
+	if isContextError(err) {
+		return
+	}
+
+	response.ServerError(w, err)
+}
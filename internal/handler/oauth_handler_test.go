@@ -0,0 +1,216 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ferdiebergado/gojeep/internal/config"
+	"github.com/ferdiebergado/gojeep/internal/handler"
+	"github.com/ferdiebergado/gojeep/internal/pkg/message"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/ferdiebergado/gojeep/internal/service/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	oauthProvider    = "google"
+	oauthState       = "state"
+	oauthStateCookie = "oauth_state"
+)
+
+func oauthConfig() *config.Config {
+	return &config.Config{
+		OAuth:  &config.OAuthOptions{StateTTL: 600},
+		Cookie: &config.CookieOptions{Name: "refresh_token", MaxAge: 3600},
+	}
+}
+
+func TestOAuthHandler_HandleStart(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		provider         string
+		startErr         error
+		expectedStatus   int
+		expectedLocation string
+	}{
+		{
+			name:             "Redirected to provider",
+			provider:         oauthProvider,
+			expectedStatus:   http.StatusFound,
+			expectedLocation: "https://accounts.example.com/authorize?state=state",
+		},
+		{
+			name:           "Unknown provider",
+			provider:       "unknown",
+			startErr:       service.ErrUnknownProvider,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockOAuthService(ctrl)
+			start := service.OAuthStart{URL: tt.expectedLocation, State: oauthState}
+			if tt.startErr != nil {
+				start = service.OAuthStart{}
+			}
+			mockService.EXPECT().StartLogin(gomock.Any(), tt.provider).Return(start, tt.startErr)
+
+			oauthHandler := handler.NewOAuthHandler(mockService, oauthConfig())
+			req := httptest.NewRequest(http.MethodGet, "/auth/oauth/"+tt.provider+"/start", nil)
+			req.SetPathValue("provider", tt.provider)
+			rec := httptest.NewRecorder()
+
+			oauthHandler.HandleStart(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+			if tt.startErr != nil {
+				return
+			}
+
+			assert.Equal(t, tt.expectedLocation, res.Header.Get("Location"))
+			var stateCookie *http.Cookie
+			for _, c := range res.Cookies() {
+				if c.Name == oauthStateCookie {
+					stateCookie = c
+				}
+			}
+			require.NotNil(t, stateCookie, "state cookie not set")
+			assert.Equal(t, oauthState, stateCookie.Value)
+			assert.True(t, stateCookie.HttpOnly)
+			assert.Equal(t, http.SameSiteLaxMode, stateCookie.SameSite)
+			assert.Equal(t, 600, stateCookie.MaxAge)
+		})
+	}
+}
+
+func TestOAuthHandler_HandleCallback(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		query           string
+		cookie          string
+		result          service.LoginResult
+		finishErr       error
+		callsService    bool
+		expectedStatus  int
+		expectedMessage string
+		expectedRefresh string
+	}{
+		{
+			name:            "Signed in",
+			query:           "?state=state&code=code",
+			cookie:          oauthState,
+			result:          service.LoginResult{AccessToken: "access", RefreshToken: "refresh"},
+			callsService:    true,
+			expectedStatus:  http.StatusOK,
+			expectedMessage: message.UserLoginSuccess,
+			expectedRefresh: "refresh",
+		},
+		{
+			name:            "MFA required",
+			query:           "?state=state&code=code",
+			cookie:          oauthState,
+			result:          service.LoginResult{MFAToken: "mfa"},
+			callsService:    true,
+			expectedStatus:  http.StatusOK,
+			expectedMessage: message.MFARequired,
+		},
+		{
+			name:            "Missing state cookie",
+			query:           "?state=state&code=code",
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: message.OAuthFailed,
+		},
+		{
+			name:            "State does not match cookie",
+			query:           "?state=other&code=code",
+			cookie:          oauthState,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: message.OAuthFailed,
+		},
+		{
+			name:            "Denied at provider",
+			query:           "?state=state&error=access_denied",
+			cookie:          oauthState,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: message.OAuthFailed,
+		},
+		{
+			name:            "Provider email unverified",
+			query:           "?state=state&code=code",
+			cookie:          oauthState,
+			finishErr:       service.ErrOAuthEmailUnverified,
+			callsService:    true,
+			expectedStatus:  http.StatusUnprocessableEntity,
+			expectedMessage: message.OAuthEmailUnverified,
+		},
+		{
+			name:            "User unverified",
+			query:           "?state=state&code=code",
+			cookie:          oauthState,
+			finishErr:       service.ErrUserNotVerified,
+			callsService:    true,
+			expectedStatus:  http.StatusUnauthorized,
+			expectedMessage: message.UserUnverified,
+		},
+		{
+			name:            "Expired state",
+			query:           "?state=state&code=code",
+			cookie:          oauthState,
+			finishErr:       service.ErrInvalidOAuthState,
+			callsService:    true,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: message.OAuthFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockOAuthService(ctrl)
+			if tt.callsService {
+				params := service.FinishOAuthLoginParams{Provider: oauthProvider, State: oauthState, Code: "code"}
+				mockService.EXPECT().FinishLogin(gomock.Any(), params).Return(tt.result, tt.finishErr)
+			}
+
+			oauthHandler := handler.NewOAuthHandler(mockService, oauthConfig())
+			req := httptest.NewRequest(http.MethodGet, "/auth/oauth/google/callback"+tt.query, nil)
+			req.SetPathValue("provider", oauthProvider)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: oauthStateCookie, Value: tt.cookie})
+			}
+			rec := httptest.NewRecorder()
+
+			oauthHandler.HandleCallback(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			var apiRes handler.Response[*handler.UserLoginResponse]
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&apiRes), "failed to decode response")
+			assert.Equal(t, tt.expectedMessage, apiRes.Message)
+
+			var refresh string
+			for _, c := range res.Cookies() {
+				if c.Name == "refresh_token" {
+					refresh = c.Value
+				}
+			}
+			assert.Equal(t, tt.expectedRefresh, refresh)
+		})
+	}
+}
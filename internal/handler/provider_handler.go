@@ -0,0 +1,261 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/ferdiebergado/gojeep/internal/pkg/message"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/ferdiebergado/gopherkit/http/response"
+)
+
+// maxTokenRequestSize limits the form body of a token request.
+const maxTokenRequestSize = 1 << 16
+
+// ProviderHandler serves the OpenID provider endpoints other apps sign users in with. Clients send the
+// browser to the frontend page configured as oidc.authorization_endpoint, which calls HandleAuthorize on
+// behalf of the signed-in user and navigates to the URL it answers with. The token and userinfo endpoints
+// are called by the clients and follow RFC 6749.
+type ProviderHandler struct {
+	service service.ProviderService
+}
+
+func NewProviderHandler(providerService service.ProviderService) *ProviderHandler {
+	return &ProviderHandler{service: providerService}
+}
+
+// AuthorizeRequest repeats an authorization request with the user's answer to the consent prompt.
+type AuthorizeRequest struct {
+	ClientID            string `json:"client_id" validate:"required"`
+	RedirectURI         string `json:"redirect_uri" validate:"required"`
+	ResponseType        string `json:"response_type"`
+	Scope               string `json:"scope"`
+	State               string `json:"state"`
+	Nonce               string `json:"nonce"`
+	CodeChallenge       string `json:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method"`
+	Approve             *bool  `json:"approve" validate:"required"`
+}
+
+// AuthorizeResponse is either the URL to send the browser to, or the consent to ask the user for.
+type AuthorizeResponse struct {
+	RedirectURL     string `json:"redirect_url,omitempty"`
+	ConsentRequired bool   `json:"consent_required,omitempty"`
+	Client          string `json:"client,omitempty"`
+	Scope           string `json:"scope,omitempty"`
+}
+
+// HandleAuthorize takes the query of an authorization request. First-party clients get a code right away;
+// other clients need the user's consent, given with HandleConsent.
+func (h *ProviderHandler) HandleAuthorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	params := service.AuthorizeParams{
+		ClientID:            query.Get("client_id"),
+		RedirectURI:         query.Get("redirect_uri"),
+		ResponseType:        query.Get("response_type"),
+		Scope:               query.Get("scope"),
+		State:               query.Get("state"),
+		Nonce:               query.Get("nonce"),
+		CodeChallenge:       query.Get("code_challenge"),
+		CodeChallengeMethod: query.Get("code_challenge_method"),
+	}
+	h.authorize(w, r, params)
+}
+
+// HandleConsent completes an authorization request the user approved or denied.
+func (h *ProviderHandler) HandleConsent(w http.ResponseWriter, r *http.Request) {
+	_, req, _ := FromParamsContext[AuthorizeRequest](r.Context())
+	params := service.AuthorizeParams{
+		ClientID:            req.ClientID,
+		RedirectURI:         req.RedirectURI,
+		ResponseType:        req.ResponseType,
+		Scope:               req.Scope,
+		State:               req.State,
+		Nonce:               req.Nonce,
+		CodeChallenge:       req.CodeChallenge,
+		CodeChallengeMethod: req.CodeChallengeMethod,
+		Consent:             req.Approve,
+	}
+	h.authorize(w, r, params)
+}
+
+func (h *ProviderHandler) authorize(w http.ResponseWriter, r *http.Request, params service.AuthorizeParams) {
+	userID, ok := FromUserContext(r.Context())
+	if !ok {
+		unauthorizedResponse(w, errors.New("missing user in context"), "Unauthorized")
+		return
+	}
+
+	result, err := h.service.Authorize(r.Context(), userID, params)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidClient) || errors.Is(err, service.ErrInvalidRedirectURI) {
+			badRequestResponse(w, err, message.OAuthClientInvalid)
+			return
+		}
+
+		if isContextError(err) {
+			return
+		}
+
+		response.ServerError(w, err)
+		return
+	}
+
+	res := Response[*AuthorizeResponse]{
+		Data: &AuthorizeResponse{
+			RedirectURL:     result.RedirectURL,
+			ConsentRequired: result.ConsentRequired,
+			Client:          result.ClientName,
+			Scope:           result.Scope,
+		},
+	}
+	response.JSON(w, http.StatusOK, res)
+}
+
+// TokenResponse is a successful token response (RFC 6749 section 5.1).
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	IDToken     string `json:"id_token"`
+	Scope       string `json:"scope"`
+}
+
+// OAuthErrorResponse is an error response of the token and userinfo endpoints (RFC 6749 section 5.2).
+type OAuthErrorResponse struct {
+	Error       string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+// HandleToken redeems an authorization code. Clients authenticate with HTTP Basic or with the
+// client_secret form field; public clients send only client_id.
+func (h *ProviderHandler) HandleToken(w http.ResponseWriter, r *http.Request) {
+	// Token responses carry credentials and must not be cached.
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxTokenRequestSize)
+	if err := r.ParseForm(); err != nil {
+		oauthError(w, http.StatusBadRequest, err, "invalid_request")
+		return
+	}
+
+	clientID, clientSecret, err := clientCredentials(r)
+	if err != nil {
+		oauthError(w, http.StatusBadRequest, err, "invalid_request")
+		return
+	}
+
+	params := service.ExchangeCodeParams{
+		GrantType:    r.PostForm.Get("grant_type"),
+		Code:         r.PostForm.Get("code"),
+		RedirectURI:  r.PostForm.Get("redirect_uri"),
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		CodeVerifier: r.PostForm.Get("code_verifier"),
+	}
+	result, err := h.service.Exchange(r.Context(), params)
+	if err != nil {
+		h.handleTokenError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, TokenResponse{
+		AccessToken: result.AccessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   result.ExpiresIn,
+		IDToken:     result.IDToken,
+		Scope:       result.Scope,
+	})
+}
+
+func (h *ProviderHandler) handleTokenError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrInvalidClient) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="gojeep"`)
+		oauthError(w, http.StatusUnauthorized, err, "invalid_client")
+		return
+	}
+
+	if errors.Is(err, service.ErrInvalidGrant) {
+		oauthError(w, http.StatusBadRequest, err, "invalid_grant")
+		return
+	}
+
+	if errors.Is(err, service.ErrUnsupportedGrantType) {
+		oauthError(w, http.StatusBadRequest, err, "unsupported_grant_type")
+		return
+	}
+
+	if isContextError(err) {
+		return
+	}
+
+	response.ServerError(w, err)
+}
+
+// UserInfoResponse holds the claims about the user released by the access token's scope.
+type UserInfoResponse struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified *bool  `json:"email_verified,omitempty"`
+	Name          string `json:"name,omitempty"`
+}
+
+// HandleUserInfo returns the claims about the user an access token issued by HandleToken belongs to.
+func (h *ProviderHandler) HandleUserInfo(w http.ResponseWriter, r *http.Request) {
+	accessToken, err := extractBearerToken(r.Header.Get("Authorization"))
+	if err != nil || accessToken == "" {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		oauthError(w, http.StatusUnauthorized, err, "invalid_request")
+		return
+	}
+
+	info, err := h.service.UserInfo(r.Context(), accessToken)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidToken) {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			oauthError(w, http.StatusUnauthorized, err, "invalid_token")
+			return
+		}
+
+		if isContextError(err) {
+			return
+		}
+
+		response.ServerError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, UserInfoResponse{
+		Subject:       info.Subject,
+		Email:         info.Email,
+		EmailVerified: info.EmailVerified,
+		Name:          info.Name,
+	})
+}
+
+// clientCredentials reads the client from HTTP Basic authentication, whose parts are form-encoded
+// (RFC 6749 section 2.3.1), or else from the form.
+func clientCredentials(r *http.Request) (clientID, clientSecret string, err error) {
+	id, secret, ok := r.BasicAuth()
+	if !ok {
+		return r.PostForm.Get("client_id"), r.PostForm.Get("client_secret"), nil
+	}
+
+	if clientID, err = url.QueryUnescape(id); err != nil {
+		return "", "", err
+	}
+
+	if clientSecret, err = url.QueryUnescape(secret); err != nil {
+		return "", "", err
+	}
+
+	return clientID, clientSecret, nil
+}
+
+func oauthError(w http.ResponseWriter, status int, err error, code string) {
+	slog.Error("Client error", "reason", err)
+	response.JSON(w, status, OAuthErrorResponse{Error: code})
+}
@@ -0,0 +1,308 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ferdiebergado/gojeep/internal/handler"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/ferdiebergado/gojeep/internal/service/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	providerClientID    = "app"
+	providerRedirectURI = "https://app.example.com/callback"
+)
+
+func TestProviderHandler_HandleAuthorize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		userID         string
+		result         service.AuthorizeResult
+		authorizeErr   error
+		expectedStatus int
+	}{
+		{
+			name:           "First-party client redirected",
+			userID:         testUserID,
+			result:         service.AuthorizeResult{RedirectURL: providerRedirectURI + "?code=code&state=state"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "Consent required",
+			userID: testUserID,
+			result: service.AuthorizeResult{
+				ConsentRequired: true, ClientName: "App", Scope: "openid email",
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Unknown client",
+			userID:         testUserID,
+			authorizeErr:   service.ErrInvalidClient,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Redirect URI not registered",
+			userID:         testUserID,
+			authorizeErr:   service.ErrInvalidRedirectURI,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Not signed in",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockProviderService(ctrl)
+			query := url.Values{
+				"client_id":     {providerClientID},
+				"redirect_uri":  {providerRedirectURI},
+				"response_type": {"code"},
+				"scope":         {"openid email"},
+				"state":         {"state"},
+			}
+			if tt.userID != "" {
+				params := service.AuthorizeParams{
+					ClientID:     providerClientID,
+					RedirectURI:  providerRedirectURI,
+					ResponseType: "code",
+					Scope:        "openid email",
+					State:        "state",
+				}
+				mockService.EXPECT().Authorize(gomock.Any(), tt.userID, params).Return(tt.result, tt.authorizeErr)
+			}
+
+			providerHandler := handler.NewProviderHandler(mockService)
+			req := withUser(httptest.NewRequest(http.MethodGet, "/oauth/authorize?"+query.Encode(), nil), tt.userID)
+			rec := httptest.NewRecorder()
+
+			providerHandler.HandleAuthorize(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var body handler.Response[handler.AuthorizeResponse]
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+			assert.Equal(t, tt.result.RedirectURL, body.Data.RedirectURL)
+			assert.Equal(t, tt.result.ConsentRequired, body.Data.ConsentRequired)
+			assert.Equal(t, tt.result.ClientName, body.Data.Client)
+		})
+	}
+}
+
+func TestProviderHandler_HandleConsent(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockProviderService(ctrl)
+	approve := false
+	redirectURL := providerRedirectURI + "?error=access_denied&state=state"
+	mockService.EXPECT().Authorize(gomock.Any(), testUserID, service.AuthorizeParams{
+		ClientID:    providerClientID,
+		RedirectURI: providerRedirectURI,
+		State:       "state",
+		Consent:     &approve,
+	}).Return(service.AuthorizeResult{RedirectURL: redirectURL}, nil)
+
+	providerHandler := handler.NewProviderHandler(mockService)
+	params := handler.AuthorizeRequest{
+		ClientID:    providerClientID,
+		RedirectURI: providerRedirectURI,
+		State:       "state",
+		Approve:     &approve,
+	}
+	req := withUser(httptest.NewRequest(http.MethodPost, "/oauth/authorize", nil), testUserID)
+	req = req.WithContext(handler.NewParamsContext(req.Context(), params))
+	rec := httptest.NewRecorder()
+
+	providerHandler.HandleConsent(rec, req)
+	res := rec.Result()
+	defer res.Body.Close()
+
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	var body handler.Response[handler.AuthorizeResponse]
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+	assert.Equal(t, redirectURL, body.Data.RedirectURL)
+}
+
+func TestProviderHandler_HandleToken(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		basicAuth      bool
+		exchangeErr    error
+		expectedStatus int
+		expectedError  string
+	}{
+		{
+			name:           "Secret in form",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Secret in basic auth",
+			basicAuth:      true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid client",
+			exchangeErr:    service.ErrInvalidClient,
+			expectedStatus: http.StatusUnauthorized,
+			expectedError:  "invalid_client",
+		},
+		{
+			name:           "Invalid grant",
+			exchangeErr:    service.ErrInvalidGrant,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "invalid_grant",
+		},
+		{
+			name:           "Unsupported grant type",
+			exchangeErr:    service.ErrUnsupportedGrantType,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "unsupported_grant_type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockProviderService(ctrl)
+			result := service.TokenResult{
+				AccessToken: "access", IDToken: "id", Scope: "openid", ExpiresIn: 3600,
+			}
+			if tt.exchangeErr != nil {
+				result = service.TokenResult{}
+			}
+			mockService.EXPECT().Exchange(gomock.Any(), service.ExchangeCodeParams{
+				GrantType:    "authorization_code",
+				Code:         "code",
+				RedirectURI:  providerRedirectURI,
+				ClientID:     providerClientID,
+				ClientSecret: "s3cret+",
+				CodeVerifier: "verifier",
+			}).Return(result, tt.exchangeErr)
+
+			form := url.Values{
+				"grant_type":    {"authorization_code"},
+				"code":          {"code"},
+				"redirect_uri":  {providerRedirectURI},
+				"code_verifier": {"verifier"},
+			}
+			if !tt.basicAuth {
+				form.Set("client_id", providerClientID)
+				form.Set("client_secret", "s3cret+")
+			}
+
+			providerHandler := handler.NewProviderHandler(mockService)
+			req := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tt.basicAuth {
+				req.SetBasicAuth(url.QueryEscape(providerClientID), url.QueryEscape("s3cret+"))
+			}
+			rec := httptest.NewRecorder()
+
+			providerHandler.HandleToken(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+			assert.Equal(t, "no-store", res.Header.Get("Cache-Control"))
+			if tt.expectedError != "" {
+				var body handler.OAuthErrorResponse
+				require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+				assert.Equal(t, tt.expectedError, body.Error)
+				return
+			}
+
+			var body handler.TokenResponse
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+			assert.Equal(t, "access", body.AccessToken)
+			assert.Equal(t, "Bearer", body.TokenType)
+			assert.Equal(t, "id", body.IDToken)
+			assert.Equal(t, 3600, body.ExpiresIn)
+		})
+	}
+}
+
+func TestProviderHandler_HandleUserInfo(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		authorization  string
+		userInfoErr    error
+		expectedStatus int
+	}{
+		{
+			name:           "Valid token",
+			authorization:  "Bearer access",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid token",
+			authorization:  "Bearer access",
+			userInfoErr:    service.ErrInvalidToken,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Missing token",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockProviderService(ctrl)
+			if tt.authorization != "" {
+				info := service.UserInfo{Subject: testUserID, Email: "abc@example.com"}
+				if tt.userInfoErr != nil {
+					info = service.UserInfo{}
+				}
+				mockService.EXPECT().UserInfo(gomock.Any(), "access").Return(info, tt.userInfoErr)
+			}
+
+			providerHandler := handler.NewProviderHandler(mockService)
+			req := httptest.NewRequest(http.MethodGet, "/userinfo", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+
+			providerHandler.HandleUserInfo(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+			if tt.expectedStatus != http.StatusOK {
+				assert.Contains(t, res.Header.Get("WWW-Authenticate"), "Bearer")
+				return
+			}
+
+			var body handler.UserInfoResponse
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+			assert.Equal(t, testUserID, body.Subject)
+			assert.Equal(t, "abc@example.com", body.Email)
+		})
+	}
+}
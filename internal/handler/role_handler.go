@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ferdiebergado/gojeep/internal/pkg/message"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/ferdiebergado/gopherkit/http/response"
+)
+
+// Permissions checked by RequirePermission. They are seeded by the migrations and granted through roles.
+const (
+	PermissionUsersRead  = "users:read"
+	PermissionUsersWrite = "users:write"
+	PermissionRolesRead  = "roles:read"
+	PermissionRolesWrite = "roles:write"
+)
+
+// isPermission reports whether p is one of the permissions above.
+func isPermission(p string) bool {
+	switch p {
+	case PermissionUsersRead, PermissionUsersWrite, PermissionRolesRead, PermissionRolesWrite:
+		return true
+	}
+	return false
+}
+
+// RoleHandler lets administrators manage the roles of users. Its routes are mounted behind RequireAuth
+// and RequirePermission.
+type RoleHandler struct {
+	service service.RoleService
+}
+
+func NewRoleHandler(roleService service.RoleService) *RoleHandler {
+	return &RoleHandler{service: roleService}
+}
+
+type UserRolesResponse struct {
+	UserID string   `json:"user_id"`
+	Roles  []string `json:"roles"`
+}
+
+func (h *RoleHandler) HandleListUserRoles(w http.ResponseWriter, r *http.Request) {
+	userID, err := pathID(r)
+	if err != nil {
+		notFoundResponse(w, err, message.UserProfileNotFound)
+		return
+	}
+
+	roles, err := h.service.UserRoles(r.Context(), userID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	res := Response[*UserRolesResponse]{
+		Data: &UserRolesResponse{UserID: userID, Roles: roles},
+	}
+	response.JSON(w, http.StatusOK, res)
+}
+
+func (h *RoleHandler) HandleAssignRole(w http.ResponseWriter, r *http.Request) {
+	userID, err := pathID(r)
+	if err != nil {
+		notFoundResponse(w, err, message.UserProfileNotFound)
+		return
+	}
+
+	if err := h.service.AssignRole(r.Context(), userID, r.PathValue("role")); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, Response[any]{Message: message.RoleAssigned})
+}
+
+func (h *RoleHandler) HandleRevokeRole(w http.ResponseWriter, r *http.Request) {
+	userID, err := pathID(r)
+	if err != nil {
+		notFoundResponse(w, err, message.UserProfileNotFound)
+		return
+	}
+
+	if err := h.service.RevokeRole(r.Context(), userID, r.PathValue("role")); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, Response[any]{Message: message.RoleRevoked})
+}
+
+func (h *RoleHandler) handleError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrUserNotFound) {
+		notFoundResponse(w, err, message.UserProfileNotFound)
+		return
+	}
+
+	if errors.Is(err, service.ErrUnknownRole) {
+		notFoundResponse(w, err, message.RoleUnknown)
+		return
+	}
+
+	if errors.Is(err, service.ErrRoleNotAssigned) {
+		notFoundResponse(w, err, message.RoleNotAssigned)
+		return
+	}
+
+	if isContextError(err) {
+		return
+	}
+
+	response.ServerError(w, err)
+}
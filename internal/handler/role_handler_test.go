@@ -0,0 +1,119 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ferdiebergado/gojeep/internal/handler"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/ferdiebergado/gojeep/internal/service/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRoleHandler_HandleListUserRoles(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockRoleService(ctrl)
+	mockService.EXPECT().UserRoles(gomock.Any(), testUserID).Return([]string{"admin"}, nil)
+
+	roleHandler := handler.NewRoleHandler(mockService)
+	req := httptest.NewRequest(http.MethodGet, "/admin/users/"+testUserID+"/roles", nil)
+	req.SetPathValue("id", testUserID)
+	rec := httptest.NewRecorder()
+
+	roleHandler.HandleListUserRoles(rec, req)
+	res := rec.Result()
+	defer res.Body.Close()
+
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	var body handler.Response[handler.UserRolesResponse]
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+	assert.Equal(t, []string{"admin"}, body.Data.Roles)
+}
+
+func TestRoleHandler_HandleAssignRole(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		userID         string
+		assignErr      error
+		expectedStatus int
+	}{
+		{name: "Assigned", userID: testUserID, expectedStatus: http.StatusOK},
+		{
+			name:           "Unknown role",
+			userID:         testUserID,
+			assignErr:      service.ErrUnknownRole,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Unknown user",
+			userID:         testUserID,
+			assignErr:      service.ErrUserNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{name: "Malformed user id", userID: "1", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockRoleService(ctrl)
+			if tt.userID == testUserID {
+				mockService.EXPECT().AssignRole(gomock.Any(), testUserID, "admin").Return(tt.assignErr)
+			}
+
+			roleHandler := handler.NewRoleHandler(mockService)
+			req := httptest.NewRequest(http.MethodPut, "/admin/users/"+tt.userID+"/roles/admin", nil)
+			req.SetPathValue("id", tt.userID)
+			req.SetPathValue("role", "admin")
+			rec := httptest.NewRecorder()
+
+			roleHandler.HandleAssignRole(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+		})
+	}
+}
+
+func TestRoleHandler_HandleRevokeRole(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		revokeErr      error
+		expectedStatus int
+	}{
+		{name: "Revoked", expectedStatus: http.StatusOK},
+		{name: "Not assigned", revokeErr: service.ErrRoleNotAssigned, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockRoleService(ctrl)
+			mockService.EXPECT().RevokeRole(gomock.Any(), testUserID, "admin").Return(tt.revokeErr)
+
+			roleHandler := handler.NewRoleHandler(mockService)
+			req := httptest.NewRequest(http.MethodDelete, "/admin/users/"+testUserID+"/roles/admin", nil)
+			req.SetPathValue("id", testUserID)
+			req.SetPathValue("role", "admin")
+			rec := httptest.NewRecorder()
+
+			roleHandler.HandleRevokeRole(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+		})
+	}
+}
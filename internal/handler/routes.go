@@ -7,14 +7,92 @@ import (
 
 func MountRoutes(r router.Router, h *Handler, v *validator.Validate) {
 	r.Get("/health", h.Base.HandleHealth)
+	r.Get("/.well-known/jwks.json", h.WellKnown.HandleJWKS)
+	r.Get("/exports/download", h.Export.HandleDownload)
 	r.Group("/auth", func(gr router.Router) router.Router {
 		gr.Post("/register", h.Auth.HandleUserRegister,
 			DecodeJSON[RegisterUserRequest](), ValidateInput[RegisterUserRequest](v))
 		gr.Get("/verify", h.Auth.VerifyEmail)
+		gr.Post("/verify/resend", h.Auth.HandleResendVerification,
+			DecodeJSON[ResendVerificationRequest](), ValidateInput[ResendVerificationRequest](v))
 		gr.Post("/login", h.Auth.HandleUserLogin,
 			DecodeJSON[UserLoginRequest](), ValidateInput[UserLoginRequest](v))
 		gr.Post("/refresh", h.Auth.HandleRefreshToken)
 		gr.Post("/logout", h.Auth.HandleLogout)
+		gr.Post("/forgot-password", h.Auth.HandleForgotPassword,
+			DecodeJSON[ForgotPasswordRequest](), ValidateInput[ForgotPasswordRequest](v))
+		gr.Post("/reset-password", h.Auth.HandleResetPassword,
+			DecodeJSON[ResetPasswordRequest](), ValidateInput[ResetPasswordRequest](v))
+		gr.Get("/email/confirm", h.Auth.HandleConfirmEmailChange)
+		gr.Post("/mfa/verify", h.Auth.HandleVerifyMFA,
+			DecodeJSON[VerifyMFARequest](), ValidateInput[VerifyMFARequest](v))
+		gr.Post("/webauthn/register/options", h.WebAuthn.HandleBeginRegistration, h.Authenticate,
+			RequireSession)
+		gr.Post("/webauthn/register/finish", h.WebAuthn.HandleFinishRegistration, h.Authenticate,
+			RequireSession, DecodeJSON[PasskeyRegistrationRequest](), ValidateInput[PasskeyRegistrationRequest](v))
+		gr.Post("/webauthn/login/options", h.WebAuthn.HandleBeginLogin)
+		gr.Post("/webauthn/login/finish", h.WebAuthn.HandleFinishLogin,
+			DecodeJSON[PasskeyLoginRequest](), ValidateInput[PasskeyLoginRequest](v))
+		gr.Get("/oauth/{provider}/start", h.OAuth.HandleStart)
+		gr.Get("/oauth/{provider}/callback", h.OAuth.HandleCallback)
+		gr.Post("/magic-link", h.MagicLink.HandleRequest,
+			DecodeJSON[MagicLinkRequest](), ValidateInput[MagicLinkRequest](v))
+		gr.Get("/magic-link/consume", h.MagicLink.HandleConsume)
+		return gr
+	})
+	if h.OIDC {
+		mountProviderRoutes(r, h, v)
+	}
+	r.Group("/users", func(gr router.Router) router.Router {
+		gr.Get("/me", h.User.HandleGetCurrentUser)
+		gr.Patch("/me", h.User.HandleUpdateCurrentUser, RequireSession,
+			DecodeJSON[UpdateUserRequest](), ValidateInput[UpdateUserRequest](v))
+		gr.Delete("/me", h.User.HandleDeleteCurrentUser, RequireSession)
+		gr.Post("/me/password", h.Auth.HandleChangePassword, RequireSession,
+			DecodeJSON[ChangePasswordRequest](), ValidateInput[ChangePasswordRequest](v))
+		gr.Post("/me/email", h.Auth.HandleChangeEmail, RequireSession,
+			DecodeJSON[ChangeEmailRequest](), ValidateInput[ChangeEmailRequest](v))
+		gr.Post("/me/mfa/enroll", h.MFA.HandleEnroll, RequireSession)
+		gr.Post("/me/mfa/confirm", h.MFA.HandleConfirm, RequireSession,
+			DecodeJSON[MFACodeRequest](), ValidateInput[MFACodeRequest](v))
+		gr.Post("/me/mfa/disable", h.MFA.HandleDisable, RequireSession,
+			DecodeJSON[MFACodeRequest](), ValidateInput[MFACodeRequest](v))
+		gr.Post("/me/mfa/recovery-codes", h.MFA.HandleRegenerateRecoveryCodes, RequireSession,
+			DecodeJSON[MFACodeRequest](), ValidateInput[MFACodeRequest](v))
+		gr.Get("/me/api-keys", h.APIKey.HandleList, RequireSession)
+		gr.Post("/me/api-keys", h.APIKey.HandleCreate, RequireSession,
+			DecodeJSON[CreateAPIKeyRequest](), ValidateInput[CreateAPIKeyRequest](v))
+		gr.Delete("/me/api-keys/{id}", h.APIKey.HandleRevoke, RequireSession)
+		gr.Get("/me/sessions", h.Session.HandleList, RequireSession)
+		gr.Delete("/me/sessions/{id}", h.Session.HandleRevoke, RequireSession)
+		gr.Post("/me/export", h.Export.HandleRequest, RequireSession)
+		return gr
+	}, h.Authenticate)
+	r.Group("/admin", func(gr router.Router) router.Router {
+		gr.Get("/users", h.Admin.HandleListUsers, h.Authorize(PermissionUsersRead))
+		gr.Get("/users/{id}", h.Admin.HandleGetUser, h.Authorize(PermissionUsersRead))
+		gr.Post("/users/{id}/verify", h.Admin.HandleVerifyUser, h.Authorize(PermissionUsersWrite))
+		gr.Post("/users/{id}/suspend", h.Admin.HandleSuspendUser, h.Authorize(PermissionUsersWrite),
+			DecodeJSON[SuspendUserRequest](), ValidateInput[SuspendUserRequest](v))
+		gr.Post("/users/{id}/unsuspend", h.Admin.HandleUnsuspendUser, h.Authorize(PermissionUsersWrite))
+		gr.Post("/users/{id}/logout", h.Admin.HandleSignOutUser, h.Authorize(PermissionUsersWrite))
+		gr.Get("/users/{id}/roles", h.Role.HandleListUserRoles, h.Authorize(PermissionRolesRead))
+		gr.Put("/users/{id}/roles/{role}", h.Role.HandleAssignRole, h.Authorize(PermissionRolesWrite))
+		gr.Delete("/users/{id}/roles/{role}", h.Role.HandleRevokeRole, h.Authorize(PermissionRolesWrite))
+		return gr
+	}, h.Authenticate)
+}
+
+// mountProviderRoutes mounts the endpoints of the OpenID provider.
+func mountProviderRoutes(r router.Router, h *Handler, v *validator.Validate) {
+	r.Get("/.well-known/openid-configuration", h.WellKnown.HandleOpenIDConfiguration)
+	r.Get("/userinfo", h.Provider.HandleUserInfo)
+	r.Post("/userinfo", h.Provider.HandleUserInfo)
+	r.Group("/oauth", func(gr router.Router) router.Router {
+		gr.Get("/authorize", h.Provider.HandleAuthorize, h.Authenticate, RequireSession)
+		gr.Post("/authorize", h.Provider.HandleConsent, h.Authenticate, RequireSession,
+			DecodeJSON[AuthorizeRequest](), ValidateInput[AuthorizeRequest](v))
+		gr.Post("/token", h.Provider.HandleToken)
 		return gr
 	})
 }
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/pkg/message"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/ferdiebergado/gopherkit/http/response"
+)
+
+// SessionHandler shows where the signed-in user is logged in and signs devices out. Its routes are
+// mounted behind RequireAuth.
+type SessionHandler struct {
+	service service.SessionService
+}
+
+func NewSessionHandler(sessionService service.SessionService) *SessionHandler {
+	return &SessionHandler{service: sessionService}
+}
+
+type SessionResponse struct {
+	ID              string     `json:"id"`
+	UserAgent       string     `json:"user_agent"`
+	IPAddress       string     `json:"ip_address"`
+	CreatedAt       time.Time  `json:"created_at"`
+	LastRefreshedAt *time.Time `json:"last_refreshed_at,omitempty"`
+}
+
+func newSessionResponse(session model.Session) *SessionResponse {
+	return &SessionResponse{
+		ID:              session.ID,
+		UserAgent:       session.UserAgent,
+		IPAddress:       session.IPAddress,
+		CreatedAt:       session.CreatedAt,
+		LastRefreshedAt: session.LastRefreshedAt,
+	}
+}
+
+func (h *SessionHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	userID, ok := FromUserContext(r.Context())
+	if !ok {
+		unauthorizedResponse(w, errors.New("missing user in context"), "Unauthorized")
+		return
+	}
+
+	sessions, err := h.service.ListSessions(r.Context(), userID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	data := make([]*SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		data = append(data, newSessionResponse(session))
+	}
+
+	response.JSON(w, http.StatusOK, Response[[]*SessionResponse]{Data: data})
+}
+
+func (h *SessionHandler) HandleRevoke(w http.ResponseWriter, r *http.Request) {
+	userID, ok := FromUserContext(r.Context())
+	if !ok {
+		unauthorizedResponse(w, errors.New("missing user in context"), "Unauthorized")
+		return
+	}
+
+	// Session ids are token family ids, random bytes in URL-safe base64 rather than UUIDs.
+	sessionID := r.PathValue("id")
+	if _, err := base64.URLEncoding.DecodeString(sessionID); err != nil {
+		notFoundResponse(w, err, message.SessionNotFound)
+		return
+	}
+
+	if err := h.service.RevokeSession(r.Context(), userID, sessionID); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, Response[any]{Message: message.SessionRevoked})
+}
+
+func (h *SessionHandler) handleError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrSessionNotFound) {
+		notFoundResponse(w, err, message.SessionNotFound)
+		return
+	}
+
+	if isContextError(err) {
+		return
+	}
+
+	response.ServerError(w, err)
+}
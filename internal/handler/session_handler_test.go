@@ -0,0 +1,84 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/handler"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/ferdiebergado/gojeep/internal/service/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSessionHandler_HandleList(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockSessionService(ctrl)
+	mockService.EXPECT().ListSessions(gomock.Any(), testUserID).Return([]model.Session{
+		{ID: "family", UserID: testUserID, UserAgent: "curl/8.0", IPAddress: "192.0.2.1", CreatedAt: time.Now()},
+	}, nil)
+
+	req := withUser(httptest.NewRequest(http.MethodGet, "/users/me/sessions", nil), testUserID)
+	rec := httptest.NewRecorder()
+
+	handler.NewSessionHandler(mockService).HandleList(rec, req)
+	res := rec.Result()
+	defer res.Body.Close()
+
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	var body handler.Response[[]handler.SessionResponse]
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+	require.Len(t, body.Data, 1)
+	assert.Equal(t, "family", body.Data[0].ID)
+	assert.Equal(t, "curl/8.0", body.Data[0].UserAgent)
+	assert.Equal(t, "192.0.2.1", body.Data[0].IPAddress)
+}
+
+func TestSessionHandler_HandleRevoke(t *testing.T) {
+	t.Parallel()
+
+	const sessionID = "ZmFtaWx5"
+
+	tests := []struct {
+		name           string
+		sessionID      string
+		revokeErr      error
+		expectedStatus int
+	}{
+		{name: "Revoked", sessionID: sessionID, expectedStatus: http.StatusOK},
+		{
+			name:           "Unknown session",
+			sessionID:      sessionID,
+			revokeErr:      service.ErrSessionNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{name: "Malformed id", sessionID: "family!", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockSessionService(ctrl)
+			if tt.sessionID == sessionID {
+				mockService.EXPECT().RevokeSession(gomock.Any(), testUserID, sessionID).Return(tt.revokeErr)
+			}
+
+			req := withUser(httptest.NewRequest(http.MethodDelete, "/users/me/sessions/"+tt.sessionID, nil), testUserID)
+			req.SetPathValue("id", tt.sessionID)
+			rec := httptest.NewRecorder()
+
+			handler.NewSessionHandler(mockService).HandleRevoke(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/pkg/message"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/ferdiebergado/gopherkit/http/response"
+)
+
+// UserHandler serves the signed-in user's own account. Its routes are mounted behind RequireAuth.
+type UserHandler struct {
+	service service.UserService
+}
+
+func NewUserHandler(userService service.UserService) *UserHandler {
+	return &UserHandler{
+		service: userService,
+	}
+}
+
+type UserResponse struct {
+	ID         string     `json:"id"`
+	Email      string     `json:"email"`
+	Name       string     `json:"name"`
+	VerifiedAt *time.Time `json:"verified_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func newUserResponse(user model.User) *UserResponse {
+	return &UserResponse{
+		ID:         user.ID,
+		Email:      user.Email,
+		Name:       user.Name,
+		VerifiedAt: user.VerifiedAt,
+		CreatedAt:  user.CreatedAt,
+		UpdatedAt:  user.UpdatedAt,
+	}
+}
+
+func (h *UserHandler) HandleGetCurrentUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := FromUserContext(r.Context())
+	if !ok {
+		unauthorizedResponse(w, errors.New("missing user in context"), "Unauthorized")
+		return
+	}
+
+	user, err := h.service.GetUser(r.Context(), userID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	res := Response[*UserResponse]{
+		Data: newUserResponse(user),
+	}
+
+	response.JSON(w, http.StatusOK, res)
+}
+
+type UpdateUserRequest struct {
+	Name *string `json:"name,omitempty" validate:"omitempty,max=255"`
+}
+
+func (h *UserHandler) HandleUpdateCurrentUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := FromUserContext(r.Context())
+	if !ok {
+		unauthorizedResponse(w, errors.New("missing user in context"), "Unauthorized")
+		return
+	}
+
+	_, req, _ := FromParamsContext[UpdateUserRequest](r.Context())
+	user, err := h.service.UpdateUser(r.Context(), userID, service.UpdateUserParams{Name: req.Name})
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	res := Response[*UserResponse]{
+		Message: message.UserProfileUpdated,
+		Data:    newUserResponse(user),
+	}
+
+	response.JSON(w, http.StatusOK, res)
+}
+
+func (h *UserHandler) HandleDeleteCurrentUser(w http.ResponseWriter, r *http.Request) {
+	userID, ok := FromUserContext(r.Context())
+	if !ok {
+		unauthorizedResponse(w, errors.New("missing user in context"), "Unauthorized")
+		return
+	}
+
+	if err := h.service.DeleteUser(r.Context(), userID); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	res := Response[any]{
+		Message: message.UserDeleted,
+	}
+
+	response.JSON(w, http.StatusOK, res)
+}
+
+func (h *UserHandler) handleError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrUserNotFound) {
+		notFoundResponse(w, err, message.UserProfileNotFound)
+		return
+	}
+
+	if isContextError(err) {
+		return
+	}
+
+	response.ServerError(w, err)
+}
@@ -0,0 +1,149 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ferdiebergado/gojeep/internal/handler"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/pkg/message"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/ferdiebergado/gojeep/internal/service/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const testUserID = "0b5e6f5c-5d2a-4c51-9f1e-3c2b8f0e7a41"
+
+func withUser(req *http.Request, userID string) *http.Request {
+	if userID == "" {
+		return req
+	}
+	return req.WithContext(handler.NewUserContext(req.Context(), userID))
+}
+
+func TestUserHandler_HandleGetCurrentUser(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		userID          string
+		expectedStatus  int
+		expectedMessage string
+		mockServiceCall func(mockService *mock.MockUserService)
+	}{
+		{
+			name:           "Signed-in user",
+			userID:         testUserID,
+			expectedStatus: http.StatusOK,
+			mockServiceCall: func(mockService *mock.MockUserService) {
+				mockService.EXPECT().GetUser(gomock.Any(), testUserID).
+					Return(model.User{Model: model.Model{ID: testUserID}, Email: testEmail}, nil)
+			},
+		},
+		{
+			name:            "User no longer exists",
+			userID:          testUserID,
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: message.UserProfileNotFound,
+			mockServiceCall: func(mockService *mock.MockUserService) {
+				mockService.EXPECT().GetUser(gomock.Any(), testUserID).Return(model.User{}, service.ErrUserNotFound)
+			},
+		},
+		{
+			name:            "Missing user context",
+			expectedStatus:  http.StatusUnauthorized,
+			expectedMessage: "Unauthorized",
+			mockServiceCall: func(_ *mock.MockUserService) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockUserService(ctrl)
+			tt.mockServiceCall(mockService)
+
+			userHandler := handler.NewUserHandler(mockService)
+			req := withUser(httptest.NewRequest(http.MethodGet, "/users/me", nil), tt.userID)
+			rec := httptest.NewRecorder()
+
+			userHandler.HandleGetCurrentUser(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			var apiRes handler.Response[*handler.UserResponse]
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&apiRes), "failed to decode response")
+			assert.Equal(t, tt.expectedMessage, apiRes.Message)
+			if tt.expectedStatus == http.StatusOK {
+				require.NotNil(t, apiRes.Data)
+				assert.Equal(t, testUserID, apiRes.Data.ID)
+				assert.Equal(t, testEmail, apiRes.Data.Email)
+			}
+		})
+	}
+}
+
+func TestUserHandler_HandleUpdateCurrentUser(t *testing.T) {
+	t.Parallel()
+	name := "New Name"
+
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockUserService(ctrl)
+	mockService.EXPECT().UpdateUser(gomock.Any(), testUserID, service.UpdateUserParams{Name: &name}).
+		Return(model.User{Model: model.Model{ID: testUserID}, Email: testEmail, Name: name}, nil)
+
+	userHandler := handler.NewUserHandler(mockService)
+	updateHandler := handler.ValidateInput[handler.UpdateUserRequest](validate)(
+		http.HandlerFunc(userHandler.HandleUpdateCurrentUser))
+	updateHandler = handler.DecodeJSON[handler.UpdateUserRequest]()(updateHandler)
+
+	reqBody, err := json.Marshal(handler.UpdateUserRequest{Name: &name})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPatch, "/users/me", bytes.NewReader(reqBody))
+	req.Header.Set(handler.HeaderContentType, handler.MimeJSON)
+	req = withUser(req, testUserID)
+	rec := httptest.NewRecorder()
+
+	updateHandler.ServeHTTP(rec, req)
+	res := rec.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var apiRes handler.Response[*handler.UserResponse]
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&apiRes), "failed to decode response")
+	assert.Equal(t, message.UserProfileUpdated, apiRes.Message)
+	require.NotNil(t, apiRes.Data)
+	assert.Equal(t, name, apiRes.Data.Name)
+}
+
+func TestUserHandler_HandleDeleteCurrentUser(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockUserService(ctrl)
+	mockService.EXPECT().DeleteUser(gomock.Any(), testUserID).Return(nil)
+
+	userHandler := handler.NewUserHandler(mockService)
+	req := withUser(httptest.NewRequest(http.MethodDelete, "/users/me", nil), testUserID)
+	rec := httptest.NewRecorder()
+
+	userHandler.HandleDeleteCurrentUser(rec, req)
+	res := rec.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var apiRes handler.Response[any]
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&apiRes), "failed to decode response")
+	assert.Equal(t, message.UserDeleted, apiRes.Message)
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ferdiebergado/gojeep/internal/config"
+	"github.com/ferdiebergado/gojeep/internal/pkg/message"
+	"github.com/ferdiebergado/gojeep/internal/pkg/webauthn"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/ferdiebergado/gopherkit/http/response"
+)
+
+// WebAuthnHandler runs the passkey ceremonies. Registration needs a signed-in user; login does not.
+type WebAuthnHandler struct {
+	service service.WebAuthnService
+	cfg     *config.Config
+}
+
+func NewWebAuthnHandler(webAuthnService service.WebAuthnService, cfg *config.Config) *WebAuthnHandler {
+	return &WebAuthnHandler{
+		service: webAuthnService,
+		cfg:     cfg,
+	}
+}
+
+// PasskeyRegistrationRequest is the PublicKeyCredential from navigator.credentials.create(), serialized
+// with toJSON(). Fields the server does not use are accepted and ignored.
+type PasskeyRegistrationRequest struct {
+	ID                      string             `json:"id" validate:"required"`
+	RawID                   string             `json:"rawId"`
+	Type                    string             `json:"type" validate:"required,eq=public-key"`
+	AuthenticatorAttachment string             `json:"authenticatorAttachment,omitempty"`
+	ClientExtensionResults  map[string]any     `json:"clientExtensionResults,omitempty"`
+	Response                PasskeyAttestation `json:"response"`
+}
+
+type PasskeyAttestation struct {
+	ClientDataJSON     string   `json:"clientDataJSON" validate:"required"`
+	AttestationObject  string   `json:"attestationObject" validate:"required"`
+	AuthenticatorData  string   `json:"authenticatorData,omitempty"`
+	Transports         []string `json:"transports,omitempty"`
+	PublicKey          string   `json:"publicKey,omitempty"`
+	PublicKeyAlgorithm int      `json:"publicKeyAlgorithm,omitempty"`
+}
+
+// PasskeyLoginRequest is the PublicKeyCredential from navigator.credentials.get(), serialized with toJSON().
+type PasskeyLoginRequest struct {
+	ID                      string           `json:"id" validate:"required"`
+	RawID                   string           `json:"rawId"`
+	Type                    string           `json:"type" validate:"required,eq=public-key"`
+	AuthenticatorAttachment string           `json:"authenticatorAttachment,omitempty"`
+	ClientExtensionResults  map[string]any   `json:"clientExtensionResults,omitempty"`
+	Response                PasskeyAssertion `json:"response"`
+}
+
+type PasskeyAssertion struct {
+	ClientDataJSON    string `json:"clientDataJSON" validate:"required"`
+	AuthenticatorData string `json:"authenticatorData" validate:"required"`
+	Signature         string `json:"signature" validate:"required"`
+	UserHandle        string `json:"userHandle,omitempty"`
+}
+
+func (h *WebAuthnHandler) HandleBeginRegistration(w http.ResponseWriter, r *http.Request) {
+	userID, ok := FromUserContext(r.Context())
+	if !ok {
+		unauthorizedResponse(w, errors.New("missing user in context"), "Unauthorized")
+		return
+	}
+
+	opts, err := h.service.BeginRegistration(r.Context(), userID)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	res := Response[*webauthn.CreationOptions]{
+		Data: &opts,
+	}
+
+	response.JSON(w, http.StatusOK, res)
+}
+
+func (h *WebAuthnHandler) HandleFinishRegistration(w http.ResponseWriter, r *http.Request) {
+	userID, ok := FromUserContext(r.Context())
+	if !ok {
+		unauthorizedResponse(w, errors.New("missing user in context"), "Unauthorized")
+		return
+	}
+
+	_, req, _ := FromParamsContext[PasskeyRegistrationRequest](r.Context())
+	credential := webauthn.RegistrationResponse{
+		ID:   req.ID,
+		Type: req.Type,
+		Response: webauthn.AttestationResponse{
+			ClientDataJSON:    req.Response.ClientDataJSON,
+			AttestationObject: req.Response.AttestationObject,
+		},
+	}
+	if err := h.service.FinishRegistration(r.Context(), userID, credential); err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	res := Response[any]{
+		Message: message.PasskeyRegistered,
+	}
+
+	response.JSON(w, http.StatusCreated, res)
+}
+
+func (h *WebAuthnHandler) HandleBeginLogin(w http.ResponseWriter, r *http.Request) {
+	opts, err := h.service.BeginLogin(r.Context())
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	res := Response[*webauthn.RequestOptions]{
+		Data: &opts,
+	}
+
+	response.JSON(w, http.StatusOK, res)
+}
+
+// HandleFinishLogin signs the user in with a passkey. It answers like HandleUserLogin.
+func (h *WebAuthnHandler) HandleFinishLogin(w http.ResponseWriter, r *http.Request) {
+	_, req, _ := FromParamsContext[PasskeyLoginRequest](r.Context())
+	assertion := webauthn.LoginResponse{
+		ID:   req.ID,
+		Type: req.Type,
+		Response: webauthn.AssertionResponse{
+			ClientDataJSON:    req.Response.ClientDataJSON,
+			AuthenticatorData: req.Response.AuthenticatorData,
+			Signature:         req.Response.Signature,
+			UserHandle:        req.Response.UserHandle,
+		},
+	}
+	accessToken, refreshToken, err := h.service.FinishLogin(clientContext(r), assertion)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPasskey) {
+			unauthorizedResponse(w, err, message.PasskeyInvalid)
+			return
+		}
+
+		h.handleError(w, err)
+		return
+	}
+
+	setRefreshCookie(w, h.cfg, refreshToken, h.cfg.Cookie.MaxAge)
+
+	res := Response[*UserLoginResponse]{
+		Message: message.UserLoginSuccess,
+		Data: &UserLoginResponse{
+			AccessToken: accessToken,
+		},
+	}
+
+	response.JSON(w, http.StatusOK, res)
+}
+
+func (h *WebAuthnHandler) handleError(w http.ResponseWriter, err error) {
+	if errors.Is(err, service.ErrInvalidPasskey) {
+		badRequestResponse(w, err, message.PasskeyInvalid)
+		return
+	}
+
+	if errors.Is(err, service.ErrUserNotFound) {
+		unauthorizedResponse(w, err, "Unauthorized")
+		return
+	}
+
+	if errors.Is(err, service.ErrUserSuspended) {
+		forbiddenResponse(w, err, message.AccountSuspended)
+		return
+	}
+
+	if isContextError(err) {
+		return
+	}
+
+	response.ServerError(w, err)
+}
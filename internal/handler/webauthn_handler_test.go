@@ -0,0 +1,158 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ferdiebergado/gojeep/internal/config"
+	"github.com/ferdiebergado/gojeep/internal/handler"
+	"github.com/ferdiebergado/gojeep/internal/pkg/message"
+	"github.com/ferdiebergado/gojeep/internal/pkg/webauthn"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/ferdiebergado/gojeep/internal/service/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// passkeyLoginBody is a credential as serialized by PublicKeyCredential.toJSON() in a browser.
+const passkeyLoginBody = `{
+	"id": "Y3JlZA",
+	"rawId": "Y3JlZA",
+	"type": "public-key",
+	"authenticatorAttachment": "platform",
+	"clientExtensionResults": {},
+	"response": {
+		"clientDataJSON": "Y2xpZW50",
+		"authenticatorData": "YXV0aA",
+		"signature": "c2ln",
+		"userHandle": "MQ"
+	}
+}`
+
+func TestWebAuthnHandler_HandleBeginRegistration(t *testing.T) {
+	t.Parallel()
+	opts := webauthn.CreationOptions{
+		Challenge: "challenge",
+		User:      webauthn.UserEntity{ID: "MQ", Name: testEmail, DisplayName: testEmail},
+	}
+
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockWebAuthnService(ctrl)
+	mockService.EXPECT().BeginRegistration(gomock.Any(), testUserID).Return(opts, nil)
+
+	webAuthnHandler := handler.NewWebAuthnHandler(mockService, &config.Config{})
+	req := withUser(httptest.NewRequest(http.MethodPost, "/auth/webauthn/register/options", nil), testUserID)
+	rec := httptest.NewRecorder()
+
+	webAuthnHandler.HandleBeginRegistration(rec, req)
+	res := rec.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var apiRes handler.Response[*webauthn.CreationOptions]
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&apiRes), "failed to decode response")
+	require.NotNil(t, apiRes.Data)
+	assert.Equal(t, opts.Challenge, apiRes.Data.Challenge)
+	assert.Equal(t, opts.User, apiRes.Data.User)
+}
+
+func TestWebAuthnHandler_HandleFinishLogin(t *testing.T) {
+	t.Parallel()
+	const cookieName = "refresh_token"
+
+	tests := []struct {
+		name            string
+		body            string
+		expectedStatus  int
+		expectedMessage string
+		expectedCookie  string
+		mockServiceCall func(mockService *mock.MockWebAuthnService)
+	}{
+		{
+			name:            "Signed in",
+			body:            passkeyLoginBody,
+			expectedStatus:  http.StatusOK,
+			expectedMessage: message.UserLoginSuccess,
+			expectedCookie:  "refresh_token_value",
+			mockServiceCall: func(mockService *mock.MockWebAuthnService) {
+				assertion := webauthn.LoginResponse{
+					ID:   "Y3JlZA",
+					Type: "public-key",
+					Response: webauthn.AssertionResponse{
+						ClientDataJSON:    "Y2xpZW50",
+						AuthenticatorData: "YXV0aA",
+						Signature:         "c2ln",
+						UserHandle:        "MQ",
+					},
+				}
+				mockService.EXPECT().FinishLogin(gomock.Any(), assertion).
+					Return("access_token", "refresh_token_value", nil)
+			},
+		},
+		{
+			name:            "Invalid passkey",
+			body:            passkeyLoginBody,
+			expectedStatus:  http.StatusUnauthorized,
+			expectedMessage: message.PasskeyInvalid,
+			mockServiceCall: func(mockService *mock.MockWebAuthnService) {
+				mockService.EXPECT().FinishLogin(gomock.Any(), gomock.Any()).
+					Return("", "", service.ErrInvalidPasskey)
+			},
+		},
+		{
+			name:            "Missing signature",
+			body:            `{"id": "Y3JlZA", "type": "public-key", "response": {"clientDataJSON": "Y2xpZW50"}}`,
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: message.UserInputInvalid,
+			mockServiceCall: func(_ *mock.MockWebAuthnService) {},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockService := mock.NewMockWebAuthnService(ctrl)
+			tt.mockServiceCall(mockService)
+
+			cfg := &config.Config{
+				Cookie: &config.CookieOptions{
+					Name:   cookieName,
+					MaxAge: 60,
+				},
+			}
+			webAuthnHandler := handler.NewWebAuthnHandler(mockService, cfg)
+			finishHandler := handler.ValidateInput[handler.PasskeyLoginRequest](validate)(
+				http.HandlerFunc(webAuthnHandler.HandleFinishLogin))
+			finishHandler = handler.DecodeJSON[handler.PasskeyLoginRequest]()(finishHandler)
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/webauthn/login/finish", strings.NewReader(tt.body))
+			req.Header.Set(handler.HeaderContentType, handler.MimeJSON)
+			rec := httptest.NewRecorder()
+
+			finishHandler.ServeHTTP(rec, req)
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			var apiRes handler.Response[*handler.UserLoginResponse]
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&apiRes), "failed to decode response")
+			assert.Equal(t, tt.expectedMessage, apiRes.Message)
+
+			if tt.expectedCookie != "" {
+				cookies := res.Cookies()
+				require.Len(t, cookies, 1)
+				assert.Equal(t, cookieName, cookies[0].Name)
+				assert.Equal(t, tt.expectedCookie, cookies[0].Value)
+				require.NotNil(t, apiRes.Data)
+				assert.Equal(t, "access_token", apiRes.Data.AccessToken)
+			}
+		})
+	}
+}
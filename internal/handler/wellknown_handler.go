@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/ferdiebergado/gojeep/internal/pkg/security"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/ferdiebergado/gopherkit/http/response"
+)
+
+// jwksMaxAge lets verifiers cache the key set while still picking up a rotation within the hour.
+const jwksMaxAge = "public, max-age=3600"
+
+type WellKnownHandler struct {
+	signer                security.Signer
+	issuer                string
+	authorizationEndpoint string
+}
+
+// NewWellKnownHandler publishes the keys of the signer. The authorization endpoint is the frontend page
+// that signs users in for OpenID clients; it is only advertised by the provider's discovery document.
+func NewWellKnownHandler(signer security.Signer, issuer, authorizationEndpoint string) *WellKnownHandler {
+	return &WellKnownHandler{signer: signer, issuer: issuer, authorizationEndpoint: authorizationEndpoint}
+}
+
+// HandleJWKS publishes the public keys that access tokens can be verified with.
+func (h *WellKnownHandler) HandleJWKS(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Cache-Control", jwksMaxAge)
+	response.JSON(w, http.StatusOK, h.signer.JWKS())
+}
+
+// OpenIDConfiguration is the discovery document of the OpenID provider (OpenID Connect Discovery 1.0).
+type OpenIDConfiguration struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+}
+
+// HandleOpenIDConfiguration publishes where and how clients sign users in through this server. Only
+// algorithms the signer can sign ID tokens with are advertised.
+func (h *WellKnownHandler) HandleOpenIDConfiguration(w http.ResponseWriter, _ *http.Request) {
+	algs := []string{}
+	if alg, err := h.signer.IDTokenAlgorithm(); err == nil {
+		algs = append(algs, alg)
+	}
+
+	w.Header().Set("Cache-Control", jwksMaxAge)
+	response.JSON(w, http.StatusOK, OpenIDConfiguration{
+		Issuer:                           h.issuer,
+		AuthorizationEndpoint:            h.authorizationEndpoint,
+		TokenEndpoint:                    h.issuer + "/oauth/token",
+		UserInfoEndpoint:                 h.issuer + "/userinfo",
+		JWKSURI:                          h.issuer + "/.well-known/jwks.json",
+		ResponseTypesSupported:           []string{"code"},
+		GrantTypesSupported:              []string{"authorization_code"},
+		SubjectTypesSupported:            []string{"public"},
+		IDTokenSigningAlgValuesSupported: algs,
+		ScopesSupported:                  []string{service.ScopeOpenID, service.ScopeProfile, service.ScopeEmail},
+		ClaimsSupported: []string{
+			"sub", "iss", "aud", "exp", "iat", "nonce", "email", "email_verified", "name",
+		},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+	})
+}
@@ -0,0 +1,87 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ferdiebergado/gojeep/internal/handler"
+	"github.com/ferdiebergado/gojeep/internal/pkg/security"
+	"github.com/ferdiebergado/gojeep/internal/pkg/security/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWellKnownHandler_HandleJWKS(t *testing.T) {
+	t.Parallel()
+	jwks := security.JWKSet{Keys: []security.JWK{
+		{KeyType: "OKP", KeyID: "key-1", Use: "sig", Algorithm: "EdDSA", Curve: "Ed25519", X: "x"},
+	}}
+
+	ctrl := gomock.NewController(t)
+	mockSigner := mock.NewMockSigner(ctrl)
+	mockSigner.EXPECT().JWKS().Return(jwks)
+
+	wellKnownHandler := handler.NewWellKnownHandler(mockSigner, "https://gojeep.test", "")
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	rec := httptest.NewRecorder()
+
+	wellKnownHandler.HandleJWKS(rec, req)
+	res := rec.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.NotEmpty(t, res.Header.Get("Cache-Control"))
+
+	var got security.JWKSet
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+	assert.Equal(t, jwks, got)
+}
+
+func TestWellKnownHandler_HandleOpenIDConfiguration(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mockSigner := mock.NewMockSigner(ctrl)
+	mockSigner.EXPECT().IDTokenAlgorithm().Return("ES256", nil)
+
+	wellKnownHandler := handler.NewWellKnownHandler(mockSigner, "https://gojeep.test", "https://app.gojeep.test/authorize")
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/openid-configuration", nil)
+	rec := httptest.NewRecorder()
+
+	wellKnownHandler.HandleOpenIDConfiguration(rec, req)
+	res := rec.Result()
+	defer res.Body.Close()
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var got handler.OpenIDConfiguration
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+	assert.Equal(t, "https://gojeep.test", got.Issuer)
+	assert.Equal(t, "https://app.gojeep.test/authorize", got.AuthorizationEndpoint)
+	assert.Equal(t, "https://gojeep.test/oauth/token", got.TokenEndpoint)
+	assert.Equal(t, "https://gojeep.test/.well-known/jwks.json", got.JWKSURI)
+	assert.Equal(t, []string{"ES256"}, got.IDTokenSigningAlgValuesSupported)
+	assert.Equal(t, []string{"S256"}, got.CodeChallengeMethodsSupported)
+}
+
+// A server key cannot sign ID tokens, so no algorithm is advertised for them.
+func TestWellKnownHandler_HandleOpenIDConfiguration_ServerKey(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mockSigner := mock.NewMockSigner(ctrl)
+	mockSigner.EXPECT().IDTokenAlgorithm().Return("", security.ErrNoPublicKey)
+
+	wellKnownHandler := handler.NewWellKnownHandler(mockSigner, "https://gojeep.test", "https://app.gojeep.test/authorize")
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/openid-configuration", nil)
+	rec := httptest.NewRecorder()
+
+	wellKnownHandler.HandleOpenIDConfiguration(rec, req)
+	res := rec.Result()
+	defer res.Body.Close()
+
+	var got handler.OpenIDConfiguration
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&got))
+	assert.Empty(t, got.IDTokenSigningAlgValuesSupported)
+}
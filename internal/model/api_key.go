@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// APIKey is a long-lived credential a user creates for scripts and CI jobs. Prefix is the public part of
+// the key used to look it up; only the hash of the whole key is stored. Scopes, when set, limit the
+// permissions the key can exercise to those listed.
+type APIKey struct {
+	ID         string
+	UserID     string
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// AuditEvent records a security relevant action on an account.
+type AuditEvent struct {
+	ID        string
+	UserID    string
+	Event     string
+	CreatedAt time.Time
+}
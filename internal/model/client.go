@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// Client is an app registered to sign users in through gojeep as its OpenID provider. Public clients,
+// such as single-page apps, have no secret and rely on PKCE alone. First-party clients skip consent.
+type Client struct {
+	ID           string
+	Name         string
+	SecretHash   string
+	RedirectURIs []string
+	FirstParty   bool
+	CreatedAt    time.Time
+}
+
+// AuthorizationCode is a code issued to a client at the authorization endpoint, to be redeemed once at
+// the token endpoint.
+type AuthorizationCode struct {
+	ClientID      string
+	UserID        string
+	RedirectURI   string
+	Scope         string
+	Nonce         string
+	CodeChallenge string
+}
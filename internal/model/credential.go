@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// Credential is a WebAuthn public key credential (passkey). ID is the base64url credential ID and
+// PublicKey the COSE_Key registered by the authenticator.
+type Credential struct {
+	ID         string
+	UserID     string
+	PublicKey  []byte
+	SignCount  uint32
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+}
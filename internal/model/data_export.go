@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// DataExport is a ZIP archive of the personal data held on a user, kept until ExpiresAt for the user to
+// download.
+type DataExport struct {
+	ID        string
+	UserID    string
+	Archive   []byte
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
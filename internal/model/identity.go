@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// Identity links a user to an account at an external OpenID provider. Subject is the provider's
+// stable identifier of the account, and Email the address the provider reported when it was linked.
+type Identity struct {
+	ID        string
+	UserID    string
+	Provider  string
+	Subject   string
+	Email     string
+	CreatedAt time.Time
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// MFA is a user's TOTP enrollment. Secret is encrypted; MFA is only enforced once ConfirmedAt is set.
+type MFA struct {
+	UserID       string
+	Secret       string
+	LastUsedStep int64
+	ConfirmedAt  *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// RecoveryCode is a one-time code that stands in for a TOTP code. Only its hash is stored.
+type RecoveryCode struct {
+	ID        string
+	UserID    string
+	CodeHash  string
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// Session is a signed-in device. Its ID is the family of the refresh tokens it was issued, so revoking
+// the session revokes them.
+type Session struct {
+	ID              string
+	UserID          string
+	UserAgent       string
+	IPAddress       string
+	CreatedAt       time.Time
+	LastRefreshedAt *time.Time
+}
@@ -7,6 +7,35 @@ import (
 type User struct {
 	Model
 	Email        string
+	Name         string
 	PasswordHash string
 	VerifiedAt   *time.Time
+	Suspension
+	DeletedAt *time.Time
+}
+
+// Suspension records an administrator blocking a user. SuspendedAt is nil if the user was never
+// suspended or the suspension was lifted; a nil SuspendedUntil suspends the user until it is lifted.
+type Suspension struct {
+	SuspendedAt      *time.Time
+	SuspendedUntil   *time.Time
+	SuspendedBy      *string
+	SuspensionReason string
+}
+
+// Suspended reports whether the suspension is in force at the given time.
+func (s Suspension) Suspended(now time.Time) bool {
+	return s.SuspendedAt != nil && (s.SuspendedUntil == nil || now.Before(*s.SuspendedUntil))
+}
+
+// UserStatus is what is checked of a user on every authenticated request.
+type UserStatus struct {
+	TokensRevokedAt *time.Time
+	Suspension
+}
+
+// AcceptsToken reports whether a token issued at issuedAt postdates the last revocation of the user's
+// credentials. JWT times have whole seconds, so the revocation time is truncated.
+func (s UserStatus) AcceptsToken(issuedAt time.Time) bool {
+	return s.TokensRevokedAt == nil || !issuedAt.Before(s.TokensRevokedAt.Truncate(time.Second))
 }
@@ -1,14 +1,64 @@
 package message
 
 const (
-	JSONDecodeFailure = "failed to decode json"
-	TokenInvalid      = "Invalid token."
-	UserExists        = "A user with this email already exists."
-	UserInputInvalid  = "Invalid input."
-	UserLoginSuccess  = "Login successful!"
-	UserNotFound      = "Invalid username or password."
-	UserRegSuccess    = "A link to activate your account has been emailed to the address provided."
-	UserUnverified    = "Please verify your email."
-	UserVerifySuccess = "Verification successful!"
-	UserLogoutSuccess = "Logout successful."
+	AccountLocked          = "Too many failed login attempts. Please try again later."
+	AccountSuspended       = "Your account has been suspended."
+	APIKeyCreated          = "Copy the API key now. It will not be shown again."
+	APIKeyNotFound         = "API key not found."
+	APIKeyRevoked          = "The API key has been revoked."
+	APIKeyScopeInvalid     = "API key scopes must be permissions."
+	DataExportInvalid      = "This download link is invalid or has expired. Please request a new export."
+	DataExportPending      = "A data export was requested recently. Please use the link we emailed you or try again later."
+	DataExportRequested    = "Your data export is being prepared. We will email you a download link when it is ready."
+	EmailChangeRequested   = "A confirmation link has been sent to the new email address."
+	EmailChanged           = "Your email address has been changed."
+	Forbidden              = "You do not have permission to do this."
+	JSONDecodeFailure      = "failed to decode json"
+	MagicLinkInvalid       = "This login link is invalid or has expired. Please request a new one in this browser."
+	MagicLinkRequested     = "If an account with that email exists, a login link has been sent."
+	MFAAlreadyEnabled      = "Two-factor authentication is already enabled."
+	MFACodeInvalid         = "Invalid authentication code."
+	MFADisabled            = "Two-factor authentication has been disabled."
+	MFAEnabled             = "Two-factor authentication has been enabled."
+	MFAEnrollStarted       = "Add the secret to your authenticator app, then confirm with a code."
+	MFALocked              = "Too many invalid authentication codes. Please try again later."
+	MFANotEnabled          = "Two-factor authentication is not enabled."
+	MFARecoveryCodesNew    = "Save these recovery codes in a safe place. They will not be shown again."
+	MFARequired            = "Enter the code from your authenticator app."
+	OAuthClientInvalid     = "Unknown client or redirect URI."
+	OAuthEmailUnverified   = "The provider has not verified your email. Please log in with your password."
+	OAuthFailed            = "Sign-in with the provider failed. Please try again."
+	OAuthProviderUnknown   = "Unknown sign-in provider."
+	PasskeyInvalid         = "Passkey verification failed."
+	PasskeyRegistered      = "Your passkey has been registered."
+	PasswordChanged        = "Your password has been changed. Your other sessions have been signed out."
+	PasswordIncorrect      = "Current password is incorrect."
+	PasswordResetRequested = "If an account with that email exists, a password reset link has been sent."
+	PasswordResetSuccess   = "Your password has been reset. Please log in with your new password."
+	RoleAssigned           = "The role has been assigned."
+	RoleNotAssigned        = "The user does not have this role."
+	RoleRevoked            = "The role has been revoked."
+	RoleUnknown            = "Unknown role."
+	SessionNotFound        = "Session not found."
+	SessionRevoked         = "The session has been signed out."
+	SessionRequired        = "This action needs a signed-in session. API keys cannot be used."
+	SuspendSelf            = "You cannot suspend your own account."
+	SuspensionExpired      = "The end of the suspension must be in the future."
+	TokenInvalid           = "Invalid token."
+	UserDeleted            = "Your account will be deleted. Log in again during the grace period to cancel."
+	UserExists             = "A user with this email already exists."
+	UserInputInvalid       = "Invalid input."
+	UserLoginSuccess       = "Login successful!"
+	UserMarkedVerified     = "The user has been marked as verified."
+	UserNotFound           = "Invalid username or password."
+	UserProfileNotFound    = "User not found."
+	UserProfileUpdated     = "Your profile has been updated."
+	UserRegSuccess         = "A link to activate your account has been emailed to the address provided."
+	UserSignedOut          = "The user has been signed out everywhere."
+	UserSuspended          = "The user has been suspended and signed out."
+	UserUnsuspended        = "The suspension has been lifted."
+	UserUnverified         = "Please verify your email."
+	UserVerifySuccess      = "Verification successful!"
+	UserLogoutSuccess      = "Logout successful."
+	VerificationResent     = "If an unverified account with that email exists, a new verification link has been sent."
 )
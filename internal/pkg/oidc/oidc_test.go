@@ -0,0 +1,168 @@
+package oidc_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/pkg/oidc"
+	"github.com/ferdiebergado/gojeep/internal/pkg/oidc/oidctest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testClientID     = "gojeep"
+	testClientSecret = "secret"
+	testRedirectURL  = "http://localhost:8888/auth/oauth/stub/callback"
+	testNonce        = "nonce"
+)
+
+func newTestClient(t *testing.T) (*oidc.Client, *oidctest.Provider) {
+	t.Helper()
+	provider, err := oidctest.NewProvider(testClientID, testClientSecret)
+	require.NoError(t, err)
+	t.Cleanup(provider.Close)
+
+	provider.SetIdentity(oidctest.Identity{
+		Subject:       "248289761001",
+		Email:         "jane@example.com",
+		EmailVerified: true,
+		Name:          "Jane Doe",
+	})
+
+	client := oidc.NewClient(oidc.Config{
+		Issuer:       provider.Issuer(),
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  testRedirectURL,
+		Scopes:       []string{"openid", "email"},
+	}, &http.Client{Timeout: 5 * time.Second})
+
+	return client, provider
+}
+
+func TestClient_SignIn(t *testing.T) {
+	t.Parallel()
+	client, provider := newTestClient(t)
+	ctx := context.Background()
+
+	verifier, err := oidc.NewCodeVerifier()
+	require.NoError(t, err)
+	assert.Len(t, verifier, 43)
+
+	authURL, err := client.AuthCodeURL(ctx, "state", testNonce, oidc.CodeChallenge(verifier))
+	require.NoError(t, err)
+
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, testRedirectURL, u.Query().Get("redirect_uri"))
+	assert.Equal(t, "openid email", u.Query().Get("scope"))
+
+	code, state, err := provider.Authorize(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "state", state)
+
+	idToken, err := client.Exchange(ctx, code, verifier)
+	require.NoError(t, err)
+
+	claims, err := client.VerifyIDToken(ctx, idToken, testNonce)
+	require.NoError(t, err)
+	assert.Equal(t, "248289761001", claims.Subject)
+	assert.Equal(t, "jane@example.com", claims.Email)
+	assert.True(t, bool(claims.EmailVerified))
+	assert.Equal(t, "Jane Doe", claims.Name)
+}
+
+func TestClient_Exchange_WrongVerifier(t *testing.T) {
+	t.Parallel()
+	client, provider := newTestClient(t)
+	ctx := context.Background()
+
+	verifier, err := oidc.NewCodeVerifier()
+	require.NoError(t, err)
+	authURL, err := client.AuthCodeURL(ctx, "state", testNonce, oidc.CodeChallenge(verifier))
+	require.NoError(t, err)
+	code, _, err := provider.Authorize(authURL)
+	require.NoError(t, err)
+
+	other, err := oidc.NewCodeVerifier()
+	require.NoError(t, err)
+
+	_, err = client.Exchange(ctx, code, other)
+	require.ErrorIs(t, err, oidc.ErrProvider)
+}
+
+func TestClient_VerifyIDToken_WrongNonce(t *testing.T) {
+	t.Parallel()
+	client, provider := newTestClient(t)
+	ctx := context.Background()
+
+	verifier, err := oidc.NewCodeVerifier()
+	require.NoError(t, err)
+	authURL, err := client.AuthCodeURL(ctx, "state", testNonce, oidc.CodeChallenge(verifier))
+	require.NoError(t, err)
+	code, _, err := provider.Authorize(authURL)
+	require.NoError(t, err)
+	idToken, err := client.Exchange(ctx, code, verifier)
+	require.NoError(t, err)
+
+	_, err = client.VerifyIDToken(ctx, idToken, "other")
+	require.ErrorIs(t, err, oidc.ErrInvalidToken)
+}
+
+func TestClient_VerifyIDToken_WrongAudience(t *testing.T) {
+	t.Parallel()
+	client, provider := newTestClient(t)
+	ctx := context.Background()
+
+	verifier, err := oidc.NewCodeVerifier()
+	require.NoError(t, err)
+	authURL, err := client.AuthCodeURL(ctx, "state", testNonce, oidc.CodeChallenge(verifier))
+	require.NoError(t, err)
+	code, _, err := provider.Authorize(authURL)
+	require.NoError(t, err)
+	idToken, err := client.Exchange(ctx, code, verifier)
+	require.NoError(t, err)
+
+	// A token issued to another client of the same provider must be rejected.
+	other := oidc.NewClient(oidc.Config{
+		Issuer:   provider.Issuer(),
+		ClientID: "other",
+	}, http.DefaultClient)
+
+	_, err = other.VerifyIDToken(ctx, idToken, testNonce)
+	require.ErrorIs(t, err, oidc.ErrInvalidToken)
+}
+
+func TestBool_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		data    string
+		want    bool
+		wantErr bool
+	}{
+		{name: "Boolean", data: "true", want: true},
+		{name: "String", data: `"true"`, want: true},
+		{name: "False", data: "false"},
+		{name: "Invalid", data: `"yes"`, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var b oidc.Bool
+			err := b.UnmarshalJSON([]byte(tc.data))
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, bool(b))
+		})
+	}
+}
@@ -54,26 +54,41 @@ func (h *Argon2Hasher) Hash(plain string) (string, error) {
 	return encoded, nil
 }
 
-// Verify implements Hasher.
-func (h *Argon2Hasher) Verify(plain string, hashed string) (bool, error) {
+// argon2Params are the cost parameters encoded in a hash.
+type argon2Params struct {
+	memory     uint32
+	iterations uint32
+	threads    uint8
+}
+
+// parseArgon2Hash splits an encoded hash into its parameters, salt and key, still base64-encoded.
+func parseArgon2Hash(hashed string) (params argon2Params, salt, key string, err error) {
 	parts := strings.Split(hashed, "$")
 	if len(parts) != 6 || parts[1] != "argon2id" {
-		return false, fmt.Errorf("invalid hash format")
+		return argon2Params{}, "", "", fmt.Errorf("invalid hash format")
 	}
 
-	var memory, time uint32
-	var threads uint8
-	_, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads)
+	_, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.iterations, &params.threads)
+	if err != nil {
+		return argon2Params{}, "", "", err
+	}
+
+	return params, parts[4], parts[5], nil
+}
+
+// Verify implements Hasher.
+func (h *Argon2Hasher) Verify(plain string, hashed string) (bool, error) {
+	params, encodedSalt, encodedHash, err := parseArgon2Hash(hashed)
 	if err != nil {
 		return false, err
 	}
 
-	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	salt, err := base64.RawStdEncoding.DecodeString(encodedSalt)
 	if err != nil {
 		return false, fmt.Errorf("base64 decode salt: %w", err)
 	}
 
-	actualHash, err := base64.RawStdEncoding.DecodeString(parts[5])
+	actualHash, err := base64.RawStdEncoding.DecodeString(encodedHash)
 	if err != nil {
 		return false, fmt.Errorf("base64 decode hash: %w", err)
 	}
@@ -83,9 +98,20 @@ func (h *Argon2Hasher) Verify(plain string, hashed string) (bool, error) {
 		return false, fmt.Errorf("hash length %d exceeds uint32:", hashLen)
 	}
 
-	computedHash := argon2.IDKey([]byte(plain+h.pepper), salt, time, memory, threads, uint32(hashLen))
+	computedHash := argon2.IDKey([]byte(plain+h.pepper), salt, params.iterations, params.memory, params.threads,
+		uint32(hashLen))
 	if subtle.ConstantTimeCompare(computedHash, actualHash) == 1 {
 		return true, nil
 	}
 	return false, nil
 }
+
+// NeedsRehash implements Hasher. Hashes it cannot parse need one too, though Verify rejects them first.
+func (h *Argon2Hasher) NeedsRehash(hashed string) bool {
+	params, _, _, err := parseArgon2Hash(hashed)
+	if err != nil {
+		return true
+	}
+
+	return params != argon2Params{memory: h.memory, iterations: h.iterations, threads: h.threads}
+}
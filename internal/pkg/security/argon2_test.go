@@ -52,3 +52,41 @@ func TestArgon2Hasher_Verify(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, isValid)
 }
+
+func TestArgon2Hasher_NeedsRehash(t *testing.T) {
+	t.Parallel()
+	cfg := &config.Argon2Options{
+		Memory:     65536,
+		Iterations: 3,
+		Threads:    2,
+		SaltLength: 16,
+		KeyLength:  32,
+	}
+	hasher := security.NewArgon2Hasher(cfg, "pepper")
+
+	hashed, err := hasher.Hash("securepassword")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stronger := *cfg
+	stronger.Iterations = 4
+
+	tests := []struct {
+		name   string
+		hasher *security.Argon2Hasher
+		hashed string
+		want   bool
+	}{
+		{name: "Current parameters", hasher: hasher, hashed: hashed},
+		{name: "Parameters raised", hasher: security.NewArgon2Hasher(&stronger, "pepper"), hashed: hashed, want: true},
+		{name: "Malformed hash", hasher: hasher, hashed: "not a hash", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.hasher.NeedsRehash(tt.hashed))
+		})
+	}
+}
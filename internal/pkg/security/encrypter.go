@@ -0,0 +1,82 @@
+//go:generate mockgen -destination=mock/encrypter_mock.go -package=mock . Encrypter
+package security
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+const encryptionKeyLen = 32
+
+var ErrCiphertext = errors.New("malformed ciphertext")
+
+// Encrypter protects secrets stored at rest, such as TOTP seeds.
+type Encrypter interface {
+	Encrypt(plaintext []byte) (string, error)
+	Decrypt(ciphertext string) ([]byte, error)
+}
+
+type aesEncrypter struct {
+	aead cipher.AEAD
+}
+
+var _ Encrypter = (*aesEncrypter)(nil)
+
+// NewAESEncrypter derives an AES-256-GCM key from the server key with HKDF-SHA256. The info string
+// separates the keys of different uses so that one derived key never decrypts another use's data.
+func NewAESEncrypter(serverKey, info string) (Encrypter, error) {
+	key := make([]byte, encryptionKeyLen)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, []byte(serverKey), nil, []byte(info)), key); err != nil {
+		return nil, fmt.Errorf("derive encryption key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("new aes cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("new gcm: %w", err)
+	}
+
+	return &aesEncrypter{aead: aead}, nil
+}
+
+// Encrypt returns the base64 encoded nonce followed by the sealed plaintext.
+func (e *aesEncrypter) Encrypt(plaintext []byte) (string, error) {
+	nonce := make([]byte, e.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generate nonce: %w", err)
+	}
+
+	sealed := e.aead.Seal(nonce, nonce, plaintext, nil)
+	return base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+func (e *aesEncrypter) Decrypt(ciphertext string) ([]byte, error) {
+	sealed, err := base64.RawStdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return nil, ErrCiphertext
+	}
+
+	nonceSize := e.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, ErrCiphertext
+	}
+
+	plaintext, err := e.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("open ciphertext: %w", err)
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,41 @@
+package security_test
+
+import (
+	"testing"
+
+	"github.com/ferdiebergado/gojeep/internal/pkg/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAESEncrypterRoundTrip(t *testing.T) {
+	t.Parallel()
+	encrypter, err := security.NewAESEncrypter("server-key", "totp")
+	require.NoError(t, err)
+
+	plaintext := []byte("secret seed")
+	ciphertext, err := encrypter.Encrypt(plaintext)
+	require.NoError(t, err)
+	assert.NotContains(t, ciphertext, string(plaintext))
+
+	decrypted, err := encrypter.Decrypt(ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, decrypted)
+}
+
+func TestAESEncrypterSeparatesKeys(t *testing.T) {
+	t.Parallel()
+	totp, err := security.NewAESEncrypter("server-key", "totp")
+	require.NoError(t, err)
+	other, err := security.NewAESEncrypter("server-key", "other")
+	require.NoError(t, err)
+
+	ciphertext, err := totp.Encrypt([]byte("secret seed"))
+	require.NoError(t, err)
+
+	_, err = other.Decrypt(ciphertext)
+	assert.Error(t, err)
+
+	_, err = totp.Decrypt("not base64!")
+	assert.ErrorIs(t, err, security.ErrCiphertext)
+}
@@ -4,4 +4,7 @@ package security
 type Hasher interface {
 	Hash(plain string) (string, error)
 	Verify(plain, hashed string) (bool, error)
+	// NeedsRehash reports whether the hash was made with other parameters than the hasher's, so it should
+	// be replaced by a fresh hash once the plain text is at hand.
+	NeedsRehash(hashed string) bool
 }
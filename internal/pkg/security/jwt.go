@@ -2,70 +2,307 @@
 package security
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/ferdiebergado/gojeep/internal/config"
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// Purpose is what a token was issued for. A token is only accepted where its purpose is expected.
+type Purpose string
+
+const (
+	PurposeAccess  Purpose = "access"
+	PurposeRefresh Purpose = "refresh"
+	PurposeVerify  Purpose = "verify"
+	PurposeReset   Purpose = "reset"
+	// PurposeEmailChange confirms a new email address. The pending address is stored server-side under the jti.
+	PurposeEmailChange Purpose = "email_change"
+	// PurposeMFA is the challenge of a login that still needs a second factor.
+	PurposeMFA Purpose = "mfa"
+	// PurposeMagicLink signs a user in from an emailed link. The jti is bound to the requesting browser.
+	PurposeMagicLink Purpose = "magic_link"
+	// PurposeDataExport authorizes the download of a personal data export. The jti is the export's ID.
+	PurposeDataExport Purpose = "data_export"
+	// PurposeOAuthAccess is an access token issued to an OAuth client, limited to its granted scope.
+	PurposeOAuthAccess Purpose = "oauth_access"
+)
+
+var (
+	ErrTokenPurpose = errors.New("token has the wrong purpose")
+	ErrNoPublicKey  = errors.New("signing key has no public key")
+)
+
 type Signer interface {
-	Sign(subject string, audience []string, duration time.Duration) (string, error)
-	Verify(tokenString string) (string, error)
+	Sign(purpose Purpose, subject string, audience []string, duration time.Duration) (string, error)
+	SignWithID(purpose Purpose, id, subject string, audience []string, duration time.Duration) (string, error)
+	SignWithScope(purpose Purpose, subject string, audience []string, scope string, duration time.Duration) (string, error)
+	SignWithRoles(
+		purpose Purpose, subject string, audience []string, roles []string, duration time.Duration,
+	) (string, error)
+	SignIDToken(claims IDTokenClaims, duration time.Duration) (string, error)
+	Verify(tokenString string, purpose Purpose, audience string) (*Claims, error)
+	IDTokenAlgorithm() (string, error)
+	JWKS() JWKSet
+}
+
+// Claims holds the registered claims of a verified token, the scope of tokens issued to OAuth clients and
+// the roles of the user an access token was issued to.
+type Claims struct {
+	ID        string
+	Subject   string
+	Scope     string
+	Roles     []string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+type tokenClaims struct {
+	Purpose Purpose  `json:"purpose"`
+	Scope   string   `json:"scope,omitempty"`
+	Roles   []string `json:"roles,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// IDTokenClaims describe the user an OpenID Connect ID token is issued for. Email and Name are only set
+// when the client was granted the scope that releases them.
+type IDTokenClaims struct {
+	Subject       string
+	ClientID      string
+	Nonce         string
+	Email         string
+	EmailVerified *bool
+	Name          string
+}
+
+type idTokenClaims struct {
+	Nonce         string `json:"nonce,omitempty"`
+	Email         string `json:"email,omitempty"`
+	EmailVerified *bool  `json:"email_verified,omitempty"`
+	Name          string `json:"name,omitempty"`
+	jwt.RegisteredClaims
 }
 
 type signer struct {
-	method jwt.SigningMethod
-	key    string
-	jtiLen uint32
-	issuer string
+	current *signingKey
+	keys    map[string]*signingKey
+	jtiLen  uint32
+	issuer  string
 }
 
 var _ Signer = (*signer)(nil)
 
-func NewSigner(cfg *config.Config) Signer {
-	return &signer{
-		method: jwt.SigningMethodHS256,
-		key:    cfg.Server.Key,
-		jtiLen: cfg.JWT.JTILen,
-		issuer: cfg.JWT.Issuer,
+// NewSigner signs with the configured private key, or with the server key using HS256 if there is none.
+// Tokens are verified with the signing key and any additional verification keys, looked up by kid.
+func NewSigner(cfg *config.Config) (Signer, error) {
+	current := newHMACKey(cfg.Server.Key)
+	if opts := cfg.JWT.SigningKey; opts != nil {
+		key, err := loadPrivateKey(opts.ID, opts.File)
+		if err != nil {
+			return nil, err
+		}
+		current = key
+	}
+
+	s := &signer{
+		current: current,
+		keys:    map[string]*signingKey{current.id: current},
+		jtiLen:  cfg.JWT.JTILen,
+		issuer:  cfg.JWT.Issuer,
+	}
+
+	for _, opts := range cfg.JWT.VerificationKeys {
+		key, err := loadPublicKey(opts.ID, opts.File)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := s.keys[key.id]; exists {
+			return nil, fmt.Errorf("duplicate key id %s", key.id)
+		}
+		s.keys[key.id] = key
 	}
+
+	return s, nil
 }
 
-func (s *signer) Sign(subject string, audience []string, duration time.Duration) (string, error) {
+func (s *signer) Sign(purpose Purpose, subject string, audience []string, duration time.Duration) (string, error) {
 	id, err := GenerateRandomBytesEncoded(s.jtiLen)
 	if err != nil {
 		return "", err
 	}
 
-	now := time.Now()
+	return s.SignWithID(purpose, id, subject, audience, duration)
+}
+
+// SignWithID signs a token using the given id as its jti, for tokens that are tracked server-side.
+func (s *signer) SignWithID(
+	purpose Purpose, id, subject string, audience []string, duration time.Duration,
+) (string, error) {
+	return s.sign(s.claims(purpose, id, subject, audience, duration))
+}
 
-	claims := &jwt.RegisteredClaims{
-		ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
-		IssuedAt:  jwt.NewNumericDate(now),
-		NotBefore: jwt.NewNumericDate(now),
-		Issuer:    s.issuer,
-		Subject:   subject,
-		ID:        id,
-		Audience:  audience,
+func (s *signer) claims(
+	purpose Purpose, id, subject string, audience []string, duration time.Duration,
+) *tokenClaims {
+	now := time.Now()
+	return &tokenClaims{
+		Purpose: purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    s.issuer,
+			Subject:   subject,
+			ID:        id,
+			Audience:  audience,
+		},
 	}
+}
+
+// SignWithScope signs a token that grants only the given space-separated scope.
+func (s *signer) SignWithScope(
+	purpose Purpose, subject string, audience []string, scope string, duration time.Duration,
+) (string, error) {
+	return s.signWithExtra(purpose, subject, audience, duration, tokenClaims{Scope: scope})
+}
 
-	token := jwt.NewWithClaims(s.method, claims)
-	return token.SignedString([]byte(s.key))
+// SignWithRoles signs a token carrying the roles of its subject.
+func (s *signer) SignWithRoles(
+	purpose Purpose, subject string, audience []string, roles []string, duration time.Duration,
+) (string, error) {
+	return s.signWithExtra(purpose, subject, audience, duration, tokenClaims{Roles: roles})
 }
 
-func (s *signer) Verify(tokenString string) (string, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, func(_ *jwt.Token) (any, error) {
-		return []byte(s.key), nil
-	}, jwt.WithValidMethods([]string{s.method.Alg()}))
+// signWithExtra signs a token with a new jti, adding the scope and roles of extra to its claims.
+func (s *signer) signWithExtra(
+	purpose Purpose, subject string, audience []string, duration time.Duration, extra tokenClaims,
+) (string, error) {
+	id, err := GenerateRandomBytesEncoded(s.jtiLen)
 	if err != nil {
 		return "", err
 	}
 
-	claims, ok := token.Claims.(*jwt.RegisteredClaims)
+	claims := s.claims(purpose, id, subject, audience, duration)
+	claims.Scope = extra.Scope
+	claims.Roles = extra.Roles
+
+	return s.sign(claims)
+}
+
+// sign signs the claims with the current key, naming it in the kid header.
+func (s *signer) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.current.method, claims)
+	token.Header["kid"] = s.current.id
+	return token.SignedString(s.current.private)
+}
+
+// SignIDToken signs an OpenID Connect ID token for a client. Clients verify it with the published JWK set,
+// so it needs an asymmetric signing key.
+func (s *signer) SignIDToken(params IDTokenClaims, duration time.Duration) (string, error) {
+	if _, err := s.IDTokenAlgorithm(); err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	claims := &idTokenClaims{
+		Nonce:         params.Nonce,
+		Email:         params.Email,
+		EmailVerified: params.EmailVerified,
+		Name:          params.Name,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    s.issuer,
+			Subject:   params.Subject,
+			Audience:  []string{params.ClientID},
+		},
+	}
+
+	return s.sign(claims)
+}
+
+// IDTokenAlgorithm is the JWS algorithm ID tokens are signed with, such as RS256. It returns
+// ErrNoPublicKey if the signing key is the server key, which cannot sign them.
+func (s *signer) IDTokenAlgorithm() (string, error) {
+	if _, ok := s.current.jwk(); !ok {
+		return "", ErrNoPublicKey
+	}
+	return s.current.method.Alg(), nil
+}
+
+// Verify checks the token's signature, expiry and issuer, and that it was issued for the given purpose and audience.
+func (s *signer) Verify(tokenString string, purpose Purpose, audience string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &tokenClaims{}, s.verificationKey,
+		jwt.WithIssuer(s.issuer), jwt.WithAudience(audience))
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*tokenClaims)
 	if !ok {
-		return "", fmt.Errorf("token claims is not a RegisteredClaims: %T", token.Claims)
+		return nil, fmt.Errorf("token claims is not a tokenClaims: %T", token.Claims)
+	}
+
+	if claims.Purpose != purpose {
+		return nil, ErrTokenPurpose
+	}
+
+	parsed := &Claims{
+		ID:      claims.ID,
+		Subject: claims.Subject,
+		Scope:   claims.Scope,
+		Roles:   claims.Roles,
+	}
+	if claims.IssuedAt != nil {
+		parsed.IssuedAt = claims.IssuedAt.Time
+	}
+	if claims.ExpiresAt != nil {
+		parsed.ExpiresAt = claims.ExpiresAt.Time
+	}
+
+	return parsed, nil
+}
+
+// verificationKey finds the key named by the token's kid. Tokens without a kid predate key rotation
+// and are checked against the signing key.
+func (s *signer) verificationKey(token *jwt.Token) (any, error) {
+	key := s.current
+	if kid, ok := token.Header["kid"].(string); ok {
+		if key, ok = s.keys[kid]; !ok {
+			return nil, ErrUnknownKey
+		}
+	}
+
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s for key %s", token.Method.Alg(), key.id)
+	}
+
+	return key.public, nil
+}
+
+// JWKS returns the public verification keys, the signing key first.
+func (s *signer) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	if jwk, ok := s.current.jwk(); ok {
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	ids := make([]string, 0, len(s.keys))
+	for id := range s.keys {
+		if id != s.current.id {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	for _, id := range ids {
+		if jwk, ok := s.keys[id].jwk(); ok {
+			set.Keys = append(set.Keys, jwk)
+		}
 	}
 
-	return claims.Subject, nil
+	return set
 }
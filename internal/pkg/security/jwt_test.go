@@ -1,12 +1,28 @@
 package security_test
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/ferdiebergado/gojeep/internal/config"
 	"github.com/ferdiebergado/gojeep/internal/pkg/security"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -16,6 +32,49 @@ const (
 
 var audience = []string{aud}
 
+func newSigner(t *testing.T, cfg *config.Config) security.Signer {
+	t.Helper()
+	signer, err := security.NewSigner(cfg)
+	require.NoError(t, err)
+	return signer
+}
+
+// writeKey writes a PKCS #8 PEM private key to a temporary file and returns its path.
+func writeKey(t *testing.T, key crypto.Signer) string {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "key.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+// writePublicKey writes a PKIX PEM public key to a temporary file and returns its path.
+func writePublicKey(t *testing.T, key crypto.PublicKey) string {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "key.pub")
+	data := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func keyConfig(signing *config.JWTKeyOptions, verification ...config.JWTKeyOptions) *config.Config {
+	return &config.Config{
+		Server: &config.ServerConfig{},
+		JWT: &config.JWTOptions{
+			JTILen:           32,
+			Issuer:           "test",
+			SigningKey:       signing,
+			VerificationKeys: verification,
+		},
+	}
+}
+
 func TestJWTSignAndVerify(t *testing.T) {
 	t.Parallel()
 	cfg := &config.Config{
@@ -25,16 +84,17 @@ func TestJWTSignAndVerify(t *testing.T) {
 			Issuer: "test",
 		},
 	}
-	jwtHandler := security.NewSigner(cfg)
+	jwtHandler := newSigner(t, cfg)
 
 	subject := testUser
 	ttl := 24 * time.Hour
-	tokenString, err := jwtHandler.Sign(subject, audience, ttl)
+	tokenString, err := jwtHandler.Sign(security.PurposeAccess, subject, audience, ttl)
 	assert.NoError(t, err)
 
-	verifiedSubject, err := jwtHandler.Verify(tokenString)
+	claims, err := jwtHandler.Verify(tokenString, security.PurposeAccess, aud)
 	assert.NoError(t, err)
-	assert.Equal(t, subject, verifiedSubject)
+	assert.Equal(t, subject, claims.Subject)
+	assert.WithinDuration(t, time.Now(), claims.IssuedAt, time.Minute)
 }
 
 func TestJWTVerifyInvalidToken(t *testing.T) {
@@ -46,10 +106,10 @@ func TestJWTVerifyInvalidToken(t *testing.T) {
 			Issuer: "test",
 		},
 	}
-	jwtHandler := security.NewSigner(cfg)
+	jwtHandler := newSigner(t, cfg)
 
 	invalidToken := "invalid-token"
-	_, err := jwtHandler.Verify(invalidToken)
+	_, err := jwtHandler.Verify(invalidToken, security.PurposeAccess, aud)
 	assert.Error(t, err)
 }
 
@@ -62,16 +122,16 @@ func TestJWTVerifyModifiedToken(t *testing.T) {
 			Issuer: "test",
 		},
 	}
-	jwtHandler := security.NewSigner(cfg)
+	jwtHandler := newSigner(t, cfg)
 
 	subject := testUser
 	ttl := 24 * time.Hour
 
-	tokenString, err := jwtHandler.Sign(subject, audience, ttl)
+	tokenString, err := jwtHandler.Sign(security.PurposeAccess, subject, audience, ttl)
 	assert.NoError(t, err)
 
 	modifiedToken := tokenString + "modified"
-	_, err = jwtHandler.Verify(modifiedToken)
+	_, err = jwtHandler.Verify(modifiedToken, security.PurposeAccess, aud)
 	assert.Error(t, err)
 }
 
@@ -84,15 +144,15 @@ func TestJWTVerifyExpiredToken(t *testing.T) {
 			Issuer: "test",
 		},
 	}
-	jwtHandler := security.NewSigner(cfg)
+	jwtHandler := newSigner(t, cfg)
 
 	subject := testUser
 	ttl := -1 * time.Hour
 
-	tokenString, err := jwtHandler.Sign(subject, audience, ttl)
+	tokenString, err := jwtHandler.Sign(security.PurposeAccess, subject, audience, ttl)
 	assert.NoError(t, err)
 
-	_, err = jwtHandler.Verify(tokenString)
+	_, err = jwtHandler.Verify(tokenString, security.PurposeAccess, aud)
 	assert.Error(t, err)
 }
 
@@ -107,12 +167,12 @@ func TestJWTVerifyWrongSigningKey(t *testing.T) {
 			Issuer: "test",
 		},
 	}
-	jwtHandler := security.NewSigner(cfg)
+	jwtHandler := newSigner(t, cfg)
 
 	subject := testUser
 	ttl := 24 * time.Hour
 
-	tokenString, err := jwtHandler.Sign(subject, audience, ttl)
+	tokenString, err := jwtHandler.Sign(security.PurposeAccess, subject, audience, ttl)
 	assert.NoError(t, err)
 
 	wrongCfg := &config.Config{
@@ -124,8 +184,276 @@ func TestJWTVerifyWrongSigningKey(t *testing.T) {
 			Issuer: "test",
 		},
 	}
-	wrongJwtHandler := security.NewSigner(wrongCfg)
+	wrongJwtHandler := newSigner(t, wrongCfg)
+
+	_, err = wrongJwtHandler.Verify(tokenString, security.PurposeAccess, aud)
+	assert.Error(t, err)
+}
+
+func TestJWTSignWithIDAndVerify(t *testing.T) {
+	t.Parallel()
+	cfg := &config.Config{
+		Server: &config.ServerConfig{},
+		JWT: &config.JWTOptions{
+			JTILen: 32,
+			Issuer: "test",
+		},
+	}
+	jwtHandler := newSigner(t, cfg)
+
+	const jti = "token-id"
+	ttl := time.Hour
+	tokenString, err := jwtHandler.SignWithID(security.PurposeRefresh, jti, testUser, audience, ttl)
+	assert.NoError(t, err)
+
+	claims, err := jwtHandler.Verify(tokenString, security.PurposeRefresh, aud)
+	assert.NoError(t, err)
+	assert.Equal(t, jti, claims.ID)
+	assert.Equal(t, testUser, claims.Subject)
+	assert.WithinDuration(t, time.Now().Add(ttl), claims.ExpiresAt, time.Minute)
+}
+
+func TestJWTVerifyWrongPurpose(t *testing.T) {
+	t.Parallel()
+	cfg := &config.Config{
+		Server: &config.ServerConfig{},
+		JWT: &config.JWTOptions{
+			JTILen: 32,
+			Issuer: "test",
+		},
+	}
+	jwtHandler := newSigner(t, cfg)
+
+	tokenString, err := jwtHandler.Sign(security.PurposeVerify, testUser, audience, time.Hour)
+	assert.NoError(t, err)
+
+	_, err = jwtHandler.Verify(tokenString, security.PurposeAccess, aud)
+	assert.ErrorIs(t, err, security.ErrTokenPurpose)
+}
+
+func TestJWTVerifyWrongAudience(t *testing.T) {
+	t.Parallel()
+	cfg := &config.Config{
+		Server: &config.ServerConfig{},
+		JWT: &config.JWTOptions{
+			JTILen: 32,
+			Issuer: "test",
+		},
+	}
+	jwtHandler := newSigner(t, cfg)
+
+	tokenString, err := jwtHandler.Sign(security.PurposeAccess, testUser, audience, time.Hour)
+	assert.NoError(t, err)
+
+	_, err = jwtHandler.Verify(tokenString, security.PurposeAccess, "localhost/other")
+	assert.Error(t, err)
+}
+
+func TestJWTAsymmetricKeys(t *testing.T) {
+	t.Parallel()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name    string
+		key     crypto.Signer
+		wantAlg string
+		wantKty string
+	}{
+		{name: "RS256", key: rsaKey, wantAlg: "RS256", wantKty: "RSA"},
+		{name: "ES256", key: ecKey, wantAlg: "ES256", wantKty: "EC"},
+		{name: "EdDSA", key: edKey, wantAlg: "EdDSA", wantKty: "OKP"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := keyConfig(&config.JWTKeyOptions{ID: "key-1", File: writeKey(t, tc.key)})
+			jwtHandler := newSigner(t, cfg)
+
+			tokenString, err := jwtHandler.Sign(security.PurposeAccess, testUser, audience, time.Hour)
+			require.NoError(t, err)
+
+			header := decodeHeader(t, tokenString)
+			assert.Equal(t, tc.wantAlg, header["alg"])
+			assert.Equal(t, "key-1", header["kid"])
+
+			claims, err := jwtHandler.Verify(tokenString, security.PurposeAccess, aud)
+			require.NoError(t, err)
+			assert.Equal(t, testUser, claims.Subject)
+
+			jwks := jwtHandler.JWKS()
+			require.Len(t, jwks.Keys, 1)
+			assert.Equal(t, "key-1", jwks.Keys[0].KeyID)
+			assert.Equal(t, tc.wantAlg, jwks.Keys[0].Algorithm)
+			assert.Equal(t, tc.wantKty, jwks.Keys[0].KeyType)
+
+			pub, err := jwks.Keys[0].PublicKey()
+			require.NoError(t, err)
+			assert.True(t, pub.(interface{ Equal(crypto.PublicKey) bool }).Equal(tc.key.Public()))
+		})
+	}
+}
+
+func TestJWTKeyRotation(t *testing.T) {
+	t.Parallel()
+	oldKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	oldSigner := newSigner(t, keyConfig(&config.JWTKeyOptions{ID: "old", File: writeKey(t, oldKey)}))
+	oldToken, err := oldSigner.Sign(security.PurposeAccess, testUser, audience, time.Hour)
+	require.NoError(t, err)
+
+	rotated := newSigner(t, keyConfig(
+		&config.JWTKeyOptions{ID: "new", File: writeKey(t, newKey)},
+		config.JWTKeyOptions{ID: "old", File: writePublicKey(t, oldKey.Public())},
+	))
+
+	claims, err := rotated.Verify(oldToken, security.PurposeAccess, aud)
+	require.NoError(t, err)
+	assert.Equal(t, testUser, claims.Subject)
+
+	newToken, err := rotated.Sign(security.PurposeAccess, testUser, audience, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "new", decodeHeader(t, newToken)["kid"])
+
+	_, err = oldSigner.Verify(newToken, security.PurposeAccess, aud)
+	assert.ErrorIs(t, err, security.ErrUnknownKey)
+
+	jwks := rotated.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "new", jwks.Keys[0].KeyID)
+	assert.Equal(t, "old", jwks.Keys[1].KeyID)
+}
+
+func TestJWTDefaultKeyIDIsThumbprint(t *testing.T) {
+	t.Parallel()
+	// RFC 7638 section 3.1 example key.
+	const (
+		n = "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZC" +
+			"iFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8K" +
+			"JZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_" +
+			"xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw"
+		e    = "AQAB"
+		want = "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"
+	)
+
+	modulus, err := base64.RawURLEncoding.DecodeString(n)
+	require.NoError(t, err)
+	pub := &rsa.PublicKey{N: new(big.Int).SetBytes(modulus), E: 65537}
+
+	jwtHandler := newSigner(t, keyConfig(nil, config.JWTKeyOptions{File: writePublicKey(t, pub)}))
 
-	_, err = wrongJwtHandler.Verify(tokenString)
+	jwks := jwtHandler.JWKS()
+	require.Len(t, jwks.Keys, 1)
+	assert.Equal(t, want, jwks.Keys[0].KeyID)
+	assert.Equal(t, e, jwks.Keys[0].E)
+}
+
+func TestJWTRejectsHMACWithAsymmetricKey(t *testing.T) {
+	t.Parallel()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	hmacSigner := newSigner(t, keyConfig(nil))
+	tokenString, err := hmacSigner.Sign(security.PurposeAccess, testUser, audience, time.Hour)
+	require.NoError(t, err)
+
+	jwtHandler := newSigner(t, keyConfig(&config.JWTKeyOptions{File: writeKey(t, key)}))
+	_, err = jwtHandler.Verify(tokenString, security.PurposeAccess, aud)
 	assert.Error(t, err)
+	assert.Empty(t, hmacSigner.JWKS().Keys)
+}
+
+func decodeHeader(t *testing.T, tokenString string) map[string]any {
+	t.Helper()
+	segment, _, _ := strings.Cut(tokenString, ".")
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	require.NoError(t, err)
+
+	var header map[string]any
+	require.NoError(t, json.Unmarshal(data, &header))
+	return header
+}
+
+func TestJWTSignWithScope(t *testing.T) {
+	t.Parallel()
+	jwtHandler := newSigner(t, keyConfig(nil))
+
+	tokenString, err := jwtHandler.SignWithScope(security.PurposeOAuthAccess, testUser, audience, "openid email",
+		time.Hour)
+	require.NoError(t, err)
+
+	claims, err := jwtHandler.Verify(tokenString, security.PurposeOAuthAccess, aud)
+	require.NoError(t, err)
+	assert.Equal(t, "openid email", claims.Scope)
+
+	// A scoped token is not an access token to the API.
+	_, err = jwtHandler.Verify(tokenString, security.PurposeAccess, aud)
+	assert.ErrorIs(t, err, security.ErrTokenPurpose)
+}
+
+func TestJWTSignWithRoles(t *testing.T) {
+	t.Parallel()
+	jwtHandler := newSigner(t, keyConfig(nil))
+
+	tokenString, err := jwtHandler.SignWithRoles(security.PurposeAccess, testUser, audience, []string{"admin"},
+		time.Hour)
+	require.NoError(t, err)
+
+	claims, err := jwtHandler.Verify(tokenString, security.PurposeAccess, aud)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admin"}, claims.Roles)
+}
+
+func TestJWTSignIDToken(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Asymmetric key", func(t *testing.T) {
+		t.Parallel()
+		ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		jwtHandler := newSigner(t, keyConfig(&config.JWTKeyOptions{ID: "key-1", File: writeKey(t, ecKey)}))
+		alg, err := jwtHandler.IDTokenAlgorithm()
+		require.NoError(t, err)
+		assert.Equal(t, "ES256", alg)
+
+		verified := true
+		tokenString, err := jwtHandler.SignIDToken(security.IDTokenClaims{
+			Subject:       testUser,
+			ClientID:      "client",
+			Nonce:         "nonce",
+			Email:         "user@example.com",
+			EmailVerified: &verified,
+		}, time.Minute)
+		require.NoError(t, err)
+
+		var claims jwt.MapClaims
+		_, err = jwt.ParseWithClaims(tokenString, &claims, func(*jwt.Token) (any, error) {
+			return ecKey.Public(), nil
+		}, jwt.WithIssuer("test"), jwt.WithAudience("client"), jwt.WithExpirationRequired())
+		require.NoError(t, err)
+		assert.Equal(t, testUser, claims["sub"])
+		assert.Equal(t, "nonce", claims["nonce"])
+		assert.Equal(t, "user@example.com", claims["email"])
+		assert.Equal(t, true, claims["email_verified"])
+		assert.NotContains(t, claims, "name")
+		assert.NotContains(t, claims, "purpose")
+	})
+
+	t.Run("Server key", func(t *testing.T) {
+		t.Parallel()
+		jwtHandler := newSigner(t, keyConfig(nil))
+		_, err := jwtHandler.IDTokenAlgorithm()
+		require.ErrorIs(t, err, security.ErrNoPublicKey)
+
+		_, err = jwtHandler.SignIDToken(security.IDTokenClaims{Subject: testUser, ClientID: "client"}, time.Minute)
+		assert.ErrorIs(t, err, security.ErrNoPublicKey)
+	})
 }
@@ -13,6 +13,7 @@ import (
 	reflect "reflect"
 	time "time"
 
+	security "github.com/ferdiebergado/gojeep/internal/pkg/security"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -40,32 +41,121 @@ func (m *MockSigner) EXPECT() *MockSignerMockRecorder {
 	return m.recorder
 }
 
+// IDTokenAlgorithm mocks base method.
+func (m *MockSigner) IDTokenAlgorithm() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IDTokenAlgorithm")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IDTokenAlgorithm indicates an expected call of IDTokenAlgorithm.
+func (mr *MockSignerMockRecorder) IDTokenAlgorithm() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IDTokenAlgorithm", reflect.TypeOf((*MockSigner)(nil).IDTokenAlgorithm))
+}
+
+// JWKS mocks base method.
+func (m *MockSigner) JWKS() security.JWKSet {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JWKS")
+	ret0, _ := ret[0].(security.JWKSet)
+	return ret0
+}
+
+// JWKS indicates an expected call of JWKS.
+func (mr *MockSignerMockRecorder) JWKS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JWKS", reflect.TypeOf((*MockSigner)(nil).JWKS))
+}
+
 // Sign mocks base method.
-func (m *MockSigner) Sign(subject string, audience []string, duration time.Duration) (string, error) {
+func (m *MockSigner) Sign(purpose security.Purpose, subject string, audience []string, duration time.Duration) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Sign", subject, audience, duration)
+	ret := m.ctrl.Call(m, "Sign", purpose, subject, audience, duration)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Sign indicates an expected call of Sign.
-func (mr *MockSignerMockRecorder) Sign(subject, audience, duration any) *gomock.Call {
+func (mr *MockSignerMockRecorder) Sign(purpose, subject, audience, duration any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sign", reflect.TypeOf((*MockSigner)(nil).Sign), subject, audience, duration)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sign", reflect.TypeOf((*MockSigner)(nil).Sign), purpose, subject, audience, duration)
 }
 
-// Verify mocks base method.
-func (m *MockSigner) Verify(tokenString string) (string, error) {
+// SignIDToken mocks base method.
+func (m *MockSigner) SignIDToken(claims security.IDTokenClaims, duration time.Duration) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignIDToken", claims, duration)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignIDToken indicates an expected call of SignIDToken.
+func (mr *MockSignerMockRecorder) SignIDToken(claims, duration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignIDToken", reflect.TypeOf((*MockSigner)(nil).SignIDToken), claims, duration)
+}
+
+// SignWithID mocks base method.
+func (m *MockSigner) SignWithID(purpose security.Purpose, id, subject string, audience []string, duration time.Duration) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignWithID", purpose, id, subject, audience, duration)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignWithID indicates an expected call of SignWithID.
+func (mr *MockSignerMockRecorder) SignWithID(purpose, id, subject, audience, duration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignWithID", reflect.TypeOf((*MockSigner)(nil).SignWithID), purpose, id, subject, audience, duration)
+}
+
+// SignWithRoles mocks base method.
+func (m *MockSigner) SignWithRoles(purpose security.Purpose, subject string, audience, roles []string, duration time.Duration) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SignWithRoles", purpose, subject, audience, roles, duration)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SignWithRoles indicates an expected call of SignWithRoles.
+func (mr *MockSignerMockRecorder) SignWithRoles(purpose, subject, audience, roles, duration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignWithRoles", reflect.TypeOf((*MockSigner)(nil).SignWithRoles), purpose, subject, audience, roles, duration)
+}
+
+// SignWithScope mocks base method.
+func (m *MockSigner) SignWithScope(purpose security.Purpose, subject string, audience []string, scope string, duration time.Duration) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Verify", tokenString)
+	ret := m.ctrl.Call(m, "SignWithScope", purpose, subject, audience, scope, duration)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// SignWithScope indicates an expected call of SignWithScope.
+func (mr *MockSignerMockRecorder) SignWithScope(purpose, subject, audience, scope, duration any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SignWithScope", reflect.TypeOf((*MockSigner)(nil).SignWithScope), purpose, subject, audience, scope, duration)
+}
+
+// Verify mocks base method.
+func (m *MockSigner) Verify(tokenString string, purpose security.Purpose, audience string) (*security.Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", tokenString, purpose, audience)
+	ret0, _ := ret[0].(*security.Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // Verify indicates an expected call of Verify.
-func (mr *MockSignerMockRecorder) Verify(tokenString any) *gomock.Call {
+func (mr *MockSignerMockRecorder) Verify(tokenString, purpose, audience any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockSigner)(nil).Verify), tokenString)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockSigner)(nil).Verify), tokenString, purpose, audience)
 }
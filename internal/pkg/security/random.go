@@ -2,7 +2,9 @@ package security
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 )
 
 func GenerateRandomBytes(length uint32) ([]byte, error) {
@@ -25,3 +27,10 @@ func GenerateRandomBytesEncoded(length uint32) (string, error) {
 
 	return base64.URLEncoding.EncodeToString(key), nil
 }
+
+// HashToken returns the hex SHA-256 of a random token, so the token itself need not be stored. Random
+// tokens have enough entropy that they need no salt or slow hash, unlike passwords.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,39 @@
+package security
+
+import (
+	"encoding/base32"
+	"strings"
+)
+
+const (
+	// recoveryCodeLen is the number of random bytes in a recovery code, 80 bits.
+	recoveryCodeLen = 10
+	// recoveryGroupLen is the number of characters between the dashes of a displayed code.
+	recoveryGroupLen = 4
+)
+
+// GenerateRecoveryCode returns a random one-time code formatted for display, like "abcd-efgh-ijkl-mnop".
+func GenerateRecoveryCode() (string, error) {
+	b, err := GenerateRandomBytes(recoveryCodeLen)
+	if err != nil {
+		return "", err
+	}
+
+	code := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))
+
+	var sb strings.Builder
+	for i := 0; i < len(code); i += recoveryGroupLen {
+		if i > 0 {
+			sb.WriteByte('-')
+		}
+		sb.WriteString(code[i : i+recoveryGroupLen])
+	}
+	return sb.String(), nil
+}
+
+// NormalizeRecoveryCode strips the formatting of a recovery code typed by a user, so it can be compared
+// with the normalized code that was hashed.
+func NormalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
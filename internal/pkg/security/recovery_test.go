@@ -0,0 +1,26 @@
+package security_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/ferdiebergado/gojeep/internal/pkg/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateRecoveryCode(t *testing.T) {
+	t.Parallel()
+	code, err := security.GenerateRecoveryCode()
+	require.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^[a-z2-7]{4}(-[a-z2-7]{4}){3}$`), code)
+
+	other, err := security.GenerateRecoveryCode()
+	require.NoError(t, err)
+	assert.NotEqual(t, code, other)
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "abcdefghijklmnop", security.NormalizeRecoveryCode("ABCD-efgh ijkl-MNOP"))
+}
@@ -0,0 +1,63 @@
+package security_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/pkg/security"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfc6238Secret is the SHA1 seed of the RFC 6238 appendix B test vectors.
+var rfc6238Secret = []byte("12345678901234567890")
+
+func TestTOTPCodeRFC6238Vectors(t *testing.T) {
+	t.Parallel()
+	// The RFC lists 8 digit codes; the 6 digit codes are their last six digits.
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for unix, want := range vectors {
+		assert.Equal(t, want, security.TOTPCode(rfc6238Secret, time.Unix(unix, 0)), "time %d", unix)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	t.Parallel()
+	secret, err := security.GenerateTOTPSecret()
+	require.NoError(t, err)
+
+	now := time.Now()
+	step, ok := security.ValidateTOTP(secret, security.TOTPCode(secret, now), now)
+	assert.True(t, ok)
+	assert.Equal(t, now.Unix()/30, step)
+
+	_, ok = security.ValidateTOTP(secret, security.TOTPCode(secret, now.Add(-30*time.Second)), now)
+	assert.True(t, ok, "previous period is accepted for clock skew")
+
+	_, ok = security.ValidateTOTP(secret, security.TOTPCode(secret, now.Add(-2*time.Minute)), now)
+	assert.False(t, ok, "old code is rejected")
+
+	_, ok = security.ValidateTOTP(secret, "12345", now)
+	assert.False(t, ok, "short code is rejected")
+}
+
+func TestTOTPURI(t *testing.T) {
+	t.Parallel()
+	uri, err := url.Parse(security.TOTPURI("gojeep", "abc@example.com", rfc6238Secret))
+	require.NoError(t, err)
+
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/gojeep:abc@example.com", uri.Path)
+	assert.Equal(t, security.EncodeTOTPSecret(rfc6238Secret), uri.Query().Get("secret"))
+	assert.Equal(t, "gojeep", uri.Query().Get("issuer"))
+}
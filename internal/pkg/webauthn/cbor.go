@@ -0,0 +1,185 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// CBOR major types (RFC 8949 section 3.1).
+const (
+	cborUint = iota
+	cborNegInt
+	cborBytes
+	cborText
+	cborArray
+	cborMap
+	cborTag
+	cborSimple
+)
+
+// CBOR simple values and argument sizes.
+const (
+	cborFalse     = 20
+	cborTrue      = 21
+	cborNull      = 22
+	cborUndefined = 23
+	cborArg1      = 24
+	cborArg2      = 25
+	cborArg4      = 26
+	cborArg8      = 27
+)
+
+// cborMaxDepth bounds the nesting of decoded items. WebAuthn structures are at most a few levels deep.
+const cborMaxDepth = 16
+
+var errCBORTruncated = errors.New("cbor: unexpected end of data")
+
+// decodeCBOR decodes the first data item of data and returns it along with the bytes that follow it.
+// Only the definite-length subset that authenticators emit (CTAP2 canonical CBOR) is supported.
+// Integers decode to int64, byte strings to []byte, text to string, arrays to []any and maps to
+// map[any]any with int64 or string keys.
+func decodeCBOR(data []byte) (any, []byte, error) {
+	return decodeCBORItem(data, 0)
+}
+
+func decodeCBORItem(data []byte, depth int) (any, []byte, error) {
+	if depth > cborMaxDepth {
+		return nil, nil, errors.New("cbor: nesting too deep")
+	}
+
+	if len(data) == 0 {
+		return nil, nil, errCBORTruncated
+	}
+
+	major, info := data[0]>>5, data[0]&0x1f
+	arg, rest, err := cborArgument(info, data[1:])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch major {
+	case cborUint:
+		if arg > 1<<63-1 {
+			return nil, nil, errors.New("cbor: integer overflows int64")
+		}
+		return int64(arg), rest, nil
+	case cborNegInt:
+		if arg > 1<<63-1 {
+			return nil, nil, errors.New("cbor: integer overflows int64")
+		}
+		return -1 - int64(arg), rest, nil
+	case cborBytes, cborText:
+		if arg > uint64(len(rest)) {
+			return nil, nil, errCBORTruncated
+		}
+		b := rest[:arg]
+		if major == cborText {
+			return string(b), rest[arg:], nil
+		}
+		return append([]byte(nil), b...), rest[arg:], nil
+	case cborArray:
+		return decodeCBORArray(arg, rest, depth)
+	case cborMap:
+		return decodeCBORMap(arg, rest, depth)
+	case cborTag:
+		return decodeCBORItem(rest, depth+1)
+	default:
+		return decodeCBORSimple(info, rest)
+	}
+}
+
+// cborArgument reads the argument of an item head. Indefinite lengths are rejected.
+func cborArgument(info byte, data []byte) (uint64, []byte, error) {
+	var size int
+	switch {
+	case info < cborArg1:
+		return uint64(info), data, nil
+	case info == cborArg1:
+		size = 1
+	case info == cborArg2:
+		size = 2
+	case info == cborArg4:
+		size = 4
+	case info == cborArg8:
+		size = 8
+	default:
+		return 0, nil, fmt.Errorf("cbor: unsupported additional information %d", info)
+	}
+
+	if len(data) < size {
+		return 0, nil, errCBORTruncated
+	}
+
+	var arg uint64
+	switch size {
+	case 1:
+		arg = uint64(data[0])
+	case 2:
+		arg = uint64(binary.BigEndian.Uint16(data))
+	case 4:
+		arg = uint64(binary.BigEndian.Uint32(data))
+	default:
+		arg = binary.BigEndian.Uint64(data)
+	}
+	return arg, data[size:], nil
+}
+
+func decodeCBORArray(n uint64, data []byte, depth int) (any, []byte, error) {
+	// Every item takes at least one byte, which bounds the allocation by the input size.
+	if n > uint64(len(data)) {
+		return nil, nil, errCBORTruncated
+	}
+
+	items := make([]any, 0, n)
+	for range n {
+		item, rest, err := decodeCBORItem(data, depth+1)
+		if err != nil {
+			return nil, nil, err
+		}
+		items = append(items, item)
+		data = rest
+	}
+	return items, data, nil
+}
+
+func decodeCBORMap(n uint64, data []byte, depth int) (any, []byte, error) {
+	if n > uint64(len(data)) {
+		return nil, nil, errCBORTruncated
+	}
+
+	m := make(map[any]any, n)
+	for range n {
+		key, rest, err := decodeCBORItem(data, depth+1)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch key.(type) {
+		case int64, string:
+		default:
+			return nil, nil, fmt.Errorf("cbor: unsupported map key type %T", key)
+		}
+
+		value, rest, err := decodeCBORItem(rest, depth+1)
+		if err != nil {
+			return nil, nil, err
+		}
+		m[key] = value
+		data = rest
+	}
+	return m, data, nil
+}
+
+func decodeCBORSimple(info byte, data []byte) (any, []byte, error) {
+	switch info {
+	case cborFalse:
+		return false, data, nil
+	case cborTrue:
+		return true, data, nil
+	case cborNull, cborUndefined:
+		return nil, data, nil
+	default:
+		return nil, nil, fmt.Errorf("cbor: unsupported simple value %d", info)
+	}
+}
@@ -0,0 +1,63 @@
+package webauthn
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+)
+
+// COSE key parameters (RFC 9053) of the only supported algorithm, ES256.
+const (
+	coseKeyType  = 1
+	coseAlg      = 3
+	coseCurve    = -1
+	coseX        = -2
+	coseY        = -3
+	coseKtyEC2   = 2
+	coseCrvP256  = 1
+	AlgES256     = -7
+	p256CoordLen = 32
+)
+
+// parsePublicKey decodes a COSE_Key and returns its ECDSA P-256 public key.
+func parsePublicKey(coseKey []byte) (*ecdsa.PublicKey, error) {
+	item, rest, err := decodeCBOR(coseKey)
+	if err != nil {
+		return nil, fmt.Errorf("decode public key: %w", err)
+	}
+
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%d trailing bytes after public key", len(rest))
+	}
+
+	key, ok := item.(map[any]any)
+	if !ok {
+		return nil, fmt.Errorf("public key is a %T, not a map", item)
+	}
+
+	if key[int64(coseKeyType)] != int64(coseKtyEC2) || key[int64(coseAlg)] != int64(AlgES256) ||
+		key[int64(coseCurve)] != int64(coseCrvP256) {
+		return nil, fmt.Errorf("unsupported public key: kty %v, alg %v, crv %v",
+			key[int64(coseKeyType)], key[int64(coseAlg)], key[int64(coseCurve)])
+	}
+
+	x, okX := key[int64(coseX)].([]byte)
+	y, okY := key[int64(coseY)].([]byte)
+	if !okX || !okY || len(x) != p256CoordLen || len(y) != p256CoordLen {
+		return nil, fmt.Errorf("malformed public key coordinates")
+	}
+
+	// ecdh rejects points that are not on the curve.
+	point := append(append([]byte{4}, x...), y...)
+	if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+
+	return &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}, nil
+}
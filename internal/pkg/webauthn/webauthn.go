@@ -0,0 +1,427 @@
+// Package webauthn implements the relying party side of WebAuthn (https://www.w3.org/TR/webauthn-3/)
+// for passkeys: ES256 credentials with "none" attestation and required user verification.
+package webauthn
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/pkg/security"
+)
+
+const (
+	challengeLen = 32
+
+	typePublicKey   = "public-key"
+	ceremonyCreate  = "webauthn.create"
+	ceremonyGet     = "webauthn.get"
+	attestationNone = "none"
+	requirement     = "required"
+)
+
+// Authenticator data flags.
+const (
+	flagUserPresent  = 0x01
+	flagUserVerified = 0x04
+	flagAttested     = 0x40
+)
+
+// Sizes of the fixed authenticator data fields.
+const (
+	rpIDHashLen  = 32
+	flagsLen     = 1
+	signCountLen = 4
+	aaguidLen    = 16
+	credIDLenLen = 2
+	authDataLen  = rpIDHashLen + flagsLen + signCountLen
+)
+
+var (
+	ErrInvalidResponse = errors.New("invalid webauthn response")
+	// ErrSignCount means the signature counter went backwards, a sign that the authenticator was cloned.
+	ErrSignCount = errors.New("webauthn signature counter did not increase")
+)
+
+// RelyingParty identifies the site credentials are scoped to. Origin is the web origin of the pages
+// that run the ceremonies, such as https://example.com.
+type RelyingParty struct {
+	ID     string
+	Name   string
+	Origin string
+}
+
+type RPEntity struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// UserEntity describes the account a credential is created for. ID is the base64url user handle.
+type UserEntity struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+type CredentialParameter struct {
+	Type string `json:"type"`
+	Alg  int    `json:"alg"`
+}
+
+// CredentialDescriptor names a credential by its base64url ID.
+type CredentialDescriptor struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+type AuthenticatorSelection struct {
+	ResidentKey        string `json:"residentKey"`
+	RequireResidentKey bool   `json:"requireResidentKey"`
+	UserVerification   string `json:"userVerification"`
+}
+
+// CreationOptions is the JSON form of PublicKeyCredentialCreationOptions, passed to
+// navigator.credentials.create() after decoding the base64url fields.
+type CreationOptions struct {
+	Challenge              string                 `json:"challenge"`
+	RP                     RPEntity               `json:"rp"`
+	User                   UserEntity             `json:"user"`
+	PubKeyCredParams       []CredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                  `json:"timeout"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                 `json:"attestation"`
+}
+
+// RequestOptions is the JSON form of PublicKeyCredentialRequestOptions. No credentials are listed, so
+// the authenticator offers the user's discoverable credentials (passkeys) for the site.
+type RequestOptions struct {
+	Challenge        string `json:"challenge"`
+	Timeout          int64  `json:"timeout"`
+	RPID             string `json:"rpId"`
+	UserVerification string `json:"userVerification"`
+}
+
+type AttestationResponse struct {
+	ClientDataJSON    string `json:"clientDataJSON"`
+	AttestationObject string `json:"attestationObject"`
+}
+
+// RegistrationResponse is the JSON form of the PublicKeyCredential returned by navigator.credentials.create().
+// Binary fields are base64url encoded.
+type RegistrationResponse struct {
+	ID       string              `json:"id"`
+	Type     string              `json:"type"`
+	Response AttestationResponse `json:"response"`
+}
+
+type AssertionResponse struct {
+	ClientDataJSON    string `json:"clientDataJSON"`
+	AuthenticatorData string `json:"authenticatorData"`
+	Signature         string `json:"signature"`
+	UserHandle        string `json:"userHandle,omitempty"`
+}
+
+// LoginResponse is the JSON form of the PublicKeyCredential returned by navigator.credentials.get().
+type LoginResponse struct {
+	ID       string            `json:"id"`
+	Type     string            `json:"type"`
+	Response AssertionResponse `json:"response"`
+}
+
+// Credential is a verified new credential. PublicKey is the COSE_Key the authenticator returned.
+type Credential struct {
+	ID        string
+	PublicKey []byte
+	SignCount uint32
+}
+
+type clientData struct {
+	Type        string `json:"type"`
+	Challenge   string `json:"challenge"`
+	Origin      string `json:"origin"`
+	CrossOrigin bool   `json:"crossOrigin"`
+}
+
+type authenticatorData struct {
+	raw          []byte
+	rpIDHash     []byte
+	flags        byte
+	signCount    uint32
+	credentialID []byte
+	publicKey    []byte
+}
+
+// NewChallenge returns a random base64url challenge.
+func NewChallenge() (string, error) {
+	b, err := security.GenerateRandomBytes(challengeLen)
+	if err != nil {
+		return "", err
+	}
+	return encode(b), nil
+}
+
+// CreationOptions returns the registration options of a new credential for user. The credentials the user
+// already has are excluded, so an authenticator is not registered twice.
+func (rp RelyingParty) CreationOptions(challenge string, user UserEntity, exclude []string,
+	timeout time.Duration) CreationOptions {
+	excluded := make([]CredentialDescriptor, 0, len(exclude))
+	for _, id := range exclude {
+		excluded = append(excluded, CredentialDescriptor{Type: typePublicKey, ID: id})
+	}
+
+	return CreationOptions{
+		Challenge:          challenge,
+		RP:                 RPEntity{ID: rp.ID, Name: rp.Name},
+		User:               user,
+		PubKeyCredParams:   []CredentialParameter{{Type: typePublicKey, Alg: AlgES256}},
+		Timeout:            timeout.Milliseconds(),
+		ExcludeCredentials: excluded,
+		AuthenticatorSelection: AuthenticatorSelection{
+			ResidentKey:        requirement,
+			RequireResidentKey: true,
+			UserVerification:   requirement,
+		},
+		Attestation: attestationNone,
+	}
+}
+
+func (rp RelyingParty) RequestOptions(challenge string, timeout time.Duration) RequestOptions {
+	return RequestOptions{
+		Challenge:        challenge,
+		Timeout:          timeout.Milliseconds(),
+		RPID:             rp.ID,
+		UserVerification: requirement,
+	}
+}
+
+// ResponseChallenge returns the challenge a response was made for, so the caller can look it up before
+// verifying the response.
+func ResponseChallenge(clientDataJSON string) (string, error) {
+	data, _, err := parseClientData(clientDataJSON)
+	if err != nil {
+		return "", err
+	}
+	return data.Challenge, nil
+}
+
+// VerifyRegistration checks a registration response against the challenge it was issued for and
+// returns the new credential.
+func (rp RelyingParty) VerifyRegistration(res RegistrationResponse, challenge string) (Credential, error) {
+	if _, err := rp.verifyClientData(res.Response.ClientDataJSON, ceremonyCreate, challenge); err != nil {
+		return Credential{}, err
+	}
+
+	rawAttestation, err := decode(res.Response.AttestationObject)
+	if err != nil {
+		return Credential{}, fmt.Errorf("%w: attestation object: %w", ErrInvalidResponse, err)
+	}
+
+	authData, err := parseAttestationObject(rawAttestation)
+	if err != nil {
+		return Credential{}, err
+	}
+
+	if err := rp.verifyAuthData(authData); err != nil {
+		return Credential{}, err
+	}
+
+	if authData.flags&flagAttested == 0 {
+		return Credential{}, fmt.Errorf("%w: no attested credential data", ErrInvalidResponse)
+	}
+
+	id := encode(authData.credentialID)
+	if res.ID != id {
+		return Credential{}, fmt.Errorf("%w: credential id mismatch", ErrInvalidResponse)
+	}
+
+	if _, err := parsePublicKey(authData.publicKey); err != nil {
+		return Credential{}, fmt.Errorf("%w: %w", ErrInvalidResponse, err)
+	}
+
+	cred := Credential{
+		ID:        id,
+		PublicKey: authData.publicKey,
+		SignCount: authData.signCount,
+	}
+	return cred, nil
+}
+
+// VerifyLogin checks an assertion made with a stored credential and returns the new signature counter.
+func (rp RelyingParty) VerifyLogin(res LoginResponse, challenge string, publicKey []byte,
+	signCount uint32) (uint32, error) {
+	rawClientData, err := rp.verifyClientData(res.Response.ClientDataJSON, ceremonyGet, challenge)
+	if err != nil {
+		return 0, err
+	}
+
+	rawAuthData, err := decode(res.Response.AuthenticatorData)
+	if err != nil {
+		return 0, fmt.Errorf("%w: authenticator data: %w", ErrInvalidResponse, err)
+	}
+
+	authData, err := parseAuthData(rawAuthData)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := rp.verifyAuthData(authData); err != nil {
+		return 0, err
+	}
+
+	key, err := parsePublicKey(publicKey)
+	if err != nil {
+		return 0, fmt.Errorf("stored public key: %w", err)
+	}
+
+	sig, err := decode(res.Response.Signature)
+	if err != nil {
+		return 0, fmt.Errorf("%w: signature: %w", ErrInvalidResponse, err)
+	}
+
+	// The signature covers the authenticator data followed by the hash of the client data.
+	clientDataHash := sha256.Sum256(rawClientData)
+	signed := sha256.New()
+	signed.Write(authData.raw)
+	signed.Write(clientDataHash[:])
+	if !ecdsa.VerifyASN1(key, signed.Sum(nil), sig) {
+		return 0, fmt.Errorf("%w: bad signature", ErrInvalidResponse)
+	}
+
+	// Authenticators without a counter always report zero.
+	if (authData.signCount != 0 || signCount != 0) && authData.signCount <= signCount {
+		return 0, ErrSignCount
+	}
+
+	return authData.signCount, nil
+}
+
+// verifyClientData checks the client data of a ceremony and returns it raw, as signed by the authenticator.
+func (rp RelyingParty) verifyClientData(clientDataJSON, ceremony, challenge string) ([]byte, error) {
+	data, raw, err := parseClientData(clientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.Type != ceremony {
+		return nil, fmt.Errorf("%w: type %q", ErrInvalidResponse, data.Type)
+	}
+
+	if data.Challenge != challenge {
+		return nil, fmt.Errorf("%w: challenge mismatch", ErrInvalidResponse)
+	}
+
+	if data.Origin != rp.Origin || data.CrossOrigin {
+		return nil, fmt.Errorf("%w: origin %q", ErrInvalidResponse, data.Origin)
+	}
+
+	return raw, nil
+}
+
+func (rp RelyingParty) verifyAuthData(authData authenticatorData) error {
+	rpIDHash := sha256.Sum256([]byte(rp.ID))
+	if !bytes.Equal(authData.rpIDHash, rpIDHash[:]) {
+		return fmt.Errorf("%w: relying party id mismatch", ErrInvalidResponse)
+	}
+
+	if authData.flags&flagUserPresent == 0 || authData.flags&flagUserVerified == 0 {
+		return fmt.Errorf("%w: user not verified", ErrInvalidResponse)
+	}
+
+	return nil
+}
+
+func parseClientData(clientDataJSON string) (clientData, []byte, error) {
+	raw, err := decode(clientDataJSON)
+	if err != nil {
+		return clientData{}, nil, fmt.Errorf("%w: client data: %w", ErrInvalidResponse, err)
+	}
+
+	var data clientData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return clientData{}, nil, fmt.Errorf("%w: client data: %w", ErrInvalidResponse, err)
+	}
+
+	return data, raw, nil
+}
+
+// parseAttestationObject returns the authenticator data of an attestation object with "none" attestation.
+func parseAttestationObject(raw []byte) (authenticatorData, error) {
+	item, _, err := decodeCBOR(raw)
+	if err != nil {
+		return authenticatorData{}, fmt.Errorf("%w: attestation object: %w", ErrInvalidResponse, err)
+	}
+
+	obj, ok := item.(map[any]any)
+	if !ok {
+		return authenticatorData{}, fmt.Errorf("%w: attestation object is not a map", ErrInvalidResponse)
+	}
+
+	if format := obj["fmt"]; format != attestationNone {
+		return authenticatorData{}, fmt.Errorf("%w: unsupported attestation format %v", ErrInvalidResponse, format)
+	}
+
+	rawAuthData, ok := obj["authData"].([]byte)
+	if !ok {
+		return authenticatorData{}, fmt.Errorf("%w: missing authenticator data", ErrInvalidResponse)
+	}
+
+	return parseAuthData(rawAuthData)
+}
+
+func parseAuthData(raw []byte) (authenticatorData, error) {
+	if len(raw) < authDataLen {
+		return authenticatorData{}, fmt.Errorf("%w: authenticator data too short", ErrInvalidResponse)
+	}
+
+	authData := authenticatorData{
+		raw:       raw,
+		rpIDHash:  raw[:rpIDHashLen],
+		flags:     raw[rpIDHashLen],
+		signCount: binary.BigEndian.Uint32(raw[rpIDHashLen+flagsLen : authDataLen]),
+	}
+
+	if authData.flags&flagAttested == 0 {
+		return authData, nil
+	}
+
+	// Attested credential data: AAGUID, credential ID length, credential ID, then the COSE public key.
+	rest := raw[authDataLen:]
+	if len(rest) < aaguidLen+credIDLenLen {
+		return authenticatorData{}, fmt.Errorf("%w: attested credential data too short", ErrInvalidResponse)
+	}
+
+	rest = rest[aaguidLen:]
+	idLen := int(binary.BigEndian.Uint16(rest))
+	rest = rest[credIDLenLen:]
+	if len(rest) < idLen {
+		return authenticatorData{}, fmt.Errorf("%w: credential id too short", ErrInvalidResponse)
+	}
+	authData.credentialID = rest[:idLen]
+	rest = rest[idLen:]
+
+	// The key is followed by extension data, if any, so its length is only known after decoding it.
+	_, after, err := decodeCBOR(rest)
+	if err != nil {
+		return authenticatorData{}, fmt.Errorf("%w: credential public key: %w", ErrInvalidResponse, err)
+	}
+	authData.publicKey = rest[:len(rest)-len(after)]
+
+	return authData, nil
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decode accepts base64url with or without padding, as browsers and libraries differ.
+func decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
@@ -0,0 +1,200 @@
+package webauthn_test
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/pkg/webauthn"
+	"github.com/ferdiebergado/gojeep/internal/pkg/webauthn/webauthntest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testOrigin = "https://example.com"
+	testRPID   = "example.com"
+)
+
+func newRP() webauthn.RelyingParty {
+	return webauthn.RelyingParty{ID: testRPID, Name: "Example", Origin: testOrigin}
+}
+
+// register runs a registration ceremony and returns the authenticator and its verified credential.
+func register(t *testing.T, rp webauthn.RelyingParty) (*webauthntest.Authenticator, webauthn.Credential) {
+	t.Helper()
+	authenticator, err := webauthntest.NewAuthenticator(testOrigin)
+	require.NoError(t, err)
+
+	challenge, err := webauthn.NewChallenge()
+	require.NoError(t, err)
+
+	user := webauthn.UserEntity{ID: "dXNlcg", Name: "abc@example.com", DisplayName: "Abc"}
+	res, err := authenticator.Register(rp.CreationOptions(challenge, user, nil, time.Minute))
+	require.NoError(t, err)
+
+	got, err := webauthn.ResponseChallenge(res.Response.ClientDataJSON)
+	require.NoError(t, err)
+	assert.Equal(t, challenge, got)
+
+	cred, err := rp.VerifyRegistration(res, challenge)
+	require.NoError(t, err)
+	return authenticator, cred
+}
+
+func TestRegistrationAndLogin(t *testing.T) {
+	t.Parallel()
+	rp := newRP()
+	authenticator, cred := register(t, rp)
+	assert.Equal(t, authenticator.CredentialID(), cred.ID)
+	assert.Equal(t, uint32(0), cred.SignCount)
+
+	signCount := cred.SignCount
+	for range 2 {
+		challenge, err := webauthn.NewChallenge()
+		require.NoError(t, err)
+
+		res, err := authenticator.Login(rp.RequestOptions(challenge, time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, "dXNlcg", res.Response.UserHandle)
+
+		newCount, err := rp.VerifyLogin(res, challenge, cred.PublicKey, signCount)
+		require.NoError(t, err)
+		assert.Greater(t, newCount, signCount)
+		signCount = newCount
+	}
+}
+
+func TestCreationOptions(t *testing.T) {
+	t.Parallel()
+	opts := newRP().CreationOptions("challenge", webauthn.UserEntity{ID: "id"}, []string{"cred"}, time.Minute)
+	assert.Equal(t, "none", opts.Attestation)
+	assert.Equal(t, int64(60000), opts.Timeout)
+	assert.Equal(t, []webauthn.CredentialParameter{{Type: "public-key", Alg: webauthn.AlgES256}}, opts.PubKeyCredParams)
+	assert.Equal(t, []webauthn.CredentialDescriptor{{Type: "public-key", ID: "cred"}}, opts.ExcludeCredentials)
+	assert.True(t, opts.AuthenticatorSelection.RequireResidentKey)
+}
+
+func TestVerifyRegistrationRejects(t *testing.T) {
+	t.Parallel()
+	rp := newRP()
+	user := webauthn.UserEntity{ID: "dXNlcg", Name: "abc@example.com"}
+
+	testCases := []struct {
+		name   string
+		origin string
+		rpID   string
+		tamper func(res *webauthn.RegistrationResponse)
+		verify string
+	}{
+		{name: "Wrong origin", origin: "https://evil.example", rpID: testRPID},
+		{name: "Wrong relying party", origin: testOrigin, rpID: "evil.example"},
+		{name: "Other challenge", origin: testOrigin, rpID: testRPID, verify: "other"},
+		{
+			name: "Unsupported attestation", origin: testOrigin, rpID: testRPID,
+			tamper: func(res *webauthn.RegistrationResponse) {
+				res.Response.AttestationObject = encode(webauthntest.EncodeCBOR(map[any]any{
+					"fmt":      "packed",
+					"attStmt":  map[any]any{},
+					"authData": []byte{},
+				}))
+			},
+		},
+		{
+			name: "Truncated attestation object", origin: testOrigin, rpID: testRPID,
+			tamper: func(res *webauthn.RegistrationResponse) {
+				res.Response.AttestationObject = res.Response.AttestationObject[:40]
+			},
+		},
+		{
+			name: "Credential id mismatch", origin: testOrigin, rpID: testRPID,
+			tamper: func(res *webauthn.RegistrationResponse) {
+				res.ID = "AAAA"
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			authenticator, err := webauthntest.NewAuthenticator(tc.origin)
+			require.NoError(t, err)
+
+			challenge, err := webauthn.NewChallenge()
+			require.NoError(t, err)
+
+			opts := rp.CreationOptions(challenge, user, nil, time.Minute)
+			opts.RP.ID = tc.rpID
+			res, err := authenticator.Register(opts)
+			require.NoError(t, err)
+			if tc.tamper != nil {
+				tc.tamper(&res)
+			}
+
+			verify := challenge
+			if tc.verify != "" {
+				verify = tc.verify
+			}
+			_, err = rp.VerifyRegistration(res, verify)
+			assert.ErrorIs(t, err, webauthn.ErrInvalidResponse)
+		})
+	}
+}
+
+func TestVerifyLoginRejects(t *testing.T) {
+	t.Parallel()
+	rp := newRP()
+
+	t.Run("Bad signature", func(t *testing.T) {
+		t.Parallel()
+		authenticator, _ := register(t, rp)
+		_, other := register(t, rp)
+
+		challenge, err := webauthn.NewChallenge()
+		require.NoError(t, err)
+		res, err := authenticator.Login(rp.RequestOptions(challenge, time.Minute))
+		require.NoError(t, err)
+
+		// The signature does not verify with another credential's key.
+		_, err = rp.VerifyLogin(res, challenge, other.PublicKey, 0)
+		assert.ErrorIs(t, err, webauthn.ErrInvalidResponse)
+	})
+
+	t.Run("Registration response replayed as login", func(t *testing.T) {
+		t.Parallel()
+		authenticator, err := webauthntest.NewAuthenticator(testOrigin)
+		require.NoError(t, err)
+
+		challenge, err := webauthn.NewChallenge()
+		require.NoError(t, err)
+		reg, err := authenticator.Register(rp.CreationOptions(challenge, webauthn.UserEntity{}, nil, time.Minute))
+		require.NoError(t, err)
+		cred, err := rp.VerifyRegistration(reg, challenge)
+		require.NoError(t, err)
+
+		res := webauthn.LoginResponse{ID: reg.ID, Response: webauthn.AssertionResponse{
+			ClientDataJSON: reg.Response.ClientDataJSON,
+		}}
+		_, err = rp.VerifyLogin(res, challenge, cred.PublicKey, 0)
+		assert.ErrorIs(t, err, webauthn.ErrInvalidResponse)
+	})
+}
+
+func TestVerifyLoginSignCountRegression(t *testing.T) {
+	t.Parallel()
+	rp := newRP()
+	authenticator, cred := register(t, rp)
+
+	challenge, err := webauthn.NewChallenge()
+	require.NoError(t, err)
+	res, err := authenticator.Login(rp.RequestOptions(challenge, time.Minute))
+	require.NoError(t, err)
+
+	// The stored counter is ahead of the authenticator's, as when a cloned authenticator was used first.
+	_, err = rp.VerifyLogin(res, challenge, cred.PublicKey, 5)
+	assert.ErrorIs(t, err, webauthn.ErrSignCount)
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
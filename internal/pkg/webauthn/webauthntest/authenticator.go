@@ -0,0 +1,158 @@
+// Package webauthntest provides a software authenticator for testing WebAuthn ceremonies without
+// hardware or a browser.
+package webauthntest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ferdiebergado/gojeep/internal/pkg/webauthn"
+)
+
+const (
+	credentialIDLen = 16
+	flagsRegister   = 0x45 // user present, user verified, attested credential data
+	flagsLogin      = 0x05 // user present, user verified
+)
+
+// Authenticator holds one ES256 passkey and acts as the browser too: it produces the client data of
+// the given Origin.
+type Authenticator struct {
+	Origin       string
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	userHandle   string
+	signCount    uint32
+}
+
+func NewAuthenticator(origin string) (*Authenticator, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	id := make([]byte, credentialIDLen)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	return &Authenticator{Origin: origin, key: key, credentialID: id}, nil
+}
+
+// CredentialID returns the base64url ID of the passkey.
+func (a *Authenticator) CredentialID() string {
+	return encode(a.credentialID)
+}
+
+// UserHandle returns the user handle stored at registration.
+func (a *Authenticator) UserHandle() string {
+	return a.userHandle
+}
+
+// Register creates the passkey for the given options, like navigator.credentials.create().
+func (a *Authenticator) Register(opts webauthn.CreationOptions) (webauthn.RegistrationResponse, error) {
+	clientData, err := a.clientData("webauthn.create", opts.Challenge)
+	if err != nil {
+		return webauthn.RegistrationResponse{}, err
+	}
+
+	a.userHandle = opts.User.ID
+
+	authData := a.authData(opts.RP.ID, flagsRegister)
+	authData = append(authData, make([]byte, 16)...) // zero AAGUID, as with "none" attestation
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.credentialID)))
+	authData = append(authData, a.credentialID...)
+	authData = append(authData, a.coseKey()...)
+
+	attestation := map[any]any{
+		"fmt":      "none",
+		"attStmt":  map[any]any{},
+		"authData": authData,
+	}
+
+	res := webauthn.RegistrationResponse{
+		ID:   a.CredentialID(),
+		Type: "public-key",
+		Response: webauthn.AttestationResponse{
+			ClientDataJSON:    encode(clientData),
+			AttestationObject: encode(EncodeCBOR(attestation)),
+		},
+	}
+	return res, nil
+}
+
+// Login signs an assertion for the given options, like navigator.credentials.get(). Each call increases
+// the signature counter.
+func (a *Authenticator) Login(opts webauthn.RequestOptions) (webauthn.LoginResponse, error) {
+	clientData, err := a.clientData("webauthn.get", opts.Challenge)
+	if err != nil {
+		return webauthn.LoginResponse{}, err
+	}
+
+	a.signCount++
+	authData := a.authData(opts.RPID, flagsLogin)
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(authData, clientDataHash[:]...))
+	sig, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		return webauthn.LoginResponse{}, err
+	}
+
+	res := webauthn.LoginResponse{
+		ID:   a.CredentialID(),
+		Type: "public-key",
+		Response: webauthn.AssertionResponse{
+			ClientDataJSON:    encode(clientData),
+			AuthenticatorData: encode(authData),
+			Signature:         encode(sig),
+			UserHandle:        a.userHandle,
+		},
+	}
+	return res, nil
+}
+
+func (a *Authenticator) clientData(ceremony, challenge string) ([]byte, error) {
+	data, err := json.Marshal(map[string]any{
+		"type":        ceremony,
+		"challenge":   challenge,
+		"origin":      a.Origin,
+		"crossOrigin": false,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal client data: %w", err)
+	}
+	return data, nil
+}
+
+func (a *Authenticator) authData(rpID string, flags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	authData := append([]byte{}, rpIDHash[:]...)
+	authData = append(authData, flags)
+	return binary.BigEndian.AppendUint32(authData, a.signCount)
+}
+
+func (a *Authenticator) coseKey() []byte {
+	pub, err := a.key.PublicKey.ECDH()
+	if err != nil {
+		panic(err) // a generated P-256 key always converts
+	}
+	point := pub.Bytes()
+	return EncodeCBOR(map[any]any{
+		int64(1):  int64(2),  // kty: EC2
+		int64(3):  int64(-7), // alg: ES256
+		int64(-1): int64(1),  // crv: P-256
+		int64(-2): point[1:33],
+		int64(-3): point[33:],
+	})
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,60 @@
+package webauthntest
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// EncodeCBOR encodes int64, []byte, string, bool, nil, []any and map[any]any values as CBOR.
+// It panics on other types, which are a bug in the test.
+func EncodeCBOR(v any) []byte {
+	switch v := v.(type) {
+	case int64:
+		if v < 0 {
+			return cborHead(1, uint64(-1-v))
+		}
+		return cborHead(0, uint64(v))
+	case []byte:
+		return append(cborHead(2, uint64(len(v))), v...)
+	case string:
+		return append(cborHead(3, uint64(len(v))), v...)
+	case []any:
+		out := cborHead(4, uint64(len(v)))
+		for _, item := range v {
+			out = append(out, EncodeCBOR(item)...)
+		}
+		return out
+	case map[any]any:
+		out := cborHead(5, uint64(len(v)))
+		for key, value := range v {
+			out = append(out, EncodeCBOR(key)...)
+			out = append(out, EncodeCBOR(value)...)
+		}
+		return out
+	case bool:
+		if v {
+			return []byte{0xf5}
+		}
+		return []byte{0xf4}
+	case nil:
+		return []byte{0xf6}
+	default:
+		panic(fmt.Sprintf("webauthntest: cannot encode %T as CBOR", v))
+	}
+}
+
+func cborHead(major byte, arg uint64) []byte {
+	major <<= 5
+	switch {
+	case arg < 24:
+		return []byte{major | byte(arg)}
+	case arg <= 0xff:
+		return []byte{major | 24, byte(arg)}
+	case arg <= 0xffff:
+		return binary.BigEndian.AppendUint16([]byte{major | 25}, uint16(arg))
+	case arg <= 0xffffffff:
+		return binary.BigEndian.AppendUint32([]byte{major | 26}, uint32(arg))
+	default:
+		return binary.BigEndian.AppendUint64([]byte{major | 27}, arg)
+	}
+}
@@ -0,0 +1,150 @@
+//go:generate mockgen -destination=mock/api_key_repo_mock.go -package=mock . APIKeyRepository
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/model"
+)
+
+// APIKeyRepository stores the API keys users create for machine clients.
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, params CreateAPIKeyParams) (model.APIKey, error)
+	ListAPIKeys(ctx context.Context, userID string) ([]model.APIKey, error)
+	FindAPIKeyByPrefix(ctx context.Context, prefix string) (model.APIKey, error)
+	TouchAPIKey(ctx context.Context, id string) error
+	DeleteAPIKey(ctx context.Context, userID, id string) error
+}
+
+type apiKeyRepo struct {
+	db *sql.DB
+}
+
+var _ APIKeyRepository = (*apiKeyRepo)(nil)
+
+func NewAPIKeyRepository(db *sql.DB) APIKeyRepository {
+	return &apiKeyRepo{db: db}
+}
+
+type CreateAPIKeyParams struct {
+	UserID    string
+	Name      string
+	Prefix    string
+	KeyHash   string
+	Scopes    []string
+	ExpiresAt *time.Time
+}
+
+const QueryAPIKeyCreate = `
+INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, created_at
+`
+
+func (r *apiKeyRepo) CreateAPIKey(ctx context.Context, params CreateAPIKeyParams) (model.APIKey, error) {
+	scopes, err := json.Marshal(params.Scopes)
+	if err != nil {
+		return model.APIKey{}, fmt.Errorf("encode scopes: %w", err)
+	}
+
+	key := model.APIKey{
+		UserID:    params.UserID,
+		Name:      params.Name,
+		Prefix:    params.Prefix,
+		KeyHash:   params.KeyHash,
+		Scopes:    params.Scopes,
+		ExpiresAt: params.ExpiresAt,
+	}
+	if err := r.db.QueryRowContext(ctx, QueryAPIKeyCreate, params.UserID, params.Name, params.Prefix,
+		params.KeyHash, scopes, params.ExpiresAt).Scan(&key.ID, &key.CreatedAt); err != nil {
+		return model.APIKey{}, err
+	}
+
+	return key, nil
+}
+
+const QueryAPIKeyList = `
+SELECT id, user_id, name, prefix, key_hash, scopes, expires_at, last_used_at, created_at FROM api_keys
+WHERE user_id = $1
+ORDER BY created_at DESC
+`
+
+func (r *apiKeyRepo) ListAPIKeys(ctx context.Context, userID string) ([]model.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx, QueryAPIKeyList, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []model.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+const QueryAPIKeyFindByPrefix = `
+SELECT id, user_id, name, prefix, key_hash, scopes, expires_at, last_used_at, created_at FROM api_keys
+WHERE prefix = $1
+LIMIT 1
+`
+
+func (r *apiKeyRepo) FindAPIKeyByPrefix(ctx context.Context, prefix string) (model.APIKey, error) {
+	return scanAPIKey(r.db.QueryRowContext(ctx, QueryAPIKeyFindByPrefix, prefix))
+}
+
+const QueryAPIKeyTouch = "UPDATE api_keys SET last_used_at = NOW() WHERE id = $1"
+
+// TouchAPIKey records that the key was just used.
+func (r *apiKeyRepo) TouchAPIKey(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, QueryAPIKeyTouch, id)
+	return err
+}
+
+const QueryAPIKeyDelete = "DELETE FROM api_keys WHERE id = $1 AND user_id = $2"
+
+// DeleteAPIKey revokes one of the user's keys. It returns sql.ErrNoRows if the user has no such key.
+func (r *apiKeyRepo) DeleteAPIKey(ctx context.Context, userID, id string) error {
+	res, err := r.db.ExecContext(ctx, QueryAPIKeyDelete, id, userID)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAPIKey(row rowScanner) (model.APIKey, error) {
+	var key model.APIKey
+	var scopes []byte
+	if err := row.Scan(&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.KeyHash, &scopes, &key.ExpiresAt,
+		&key.LastUsedAt, &key.CreatedAt); err != nil {
+		return model.APIKey{}, err
+	}
+
+	if err := json.Unmarshal(scopes, &key.Scopes); err != nil {
+		return model.APIKey{}, fmt.Errorf("decode scopes of api key %s: %w", key.ID, err)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,122 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	apiKeyID     = "4b9c9f2e-0d5a-4a52-9a4e-2a6f0f0e9b1d"
+	apiKeyPrefix = "0a1b2c3d4e5f"
+	apiKeyUserID = "1"
+)
+
+func apiKeyCols() []string {
+	return []string{id, "user_id", "name", "prefix", "key_hash", "scopes", "expires_at", "last_used_at", createdAt}
+}
+
+func TestAPIKeyRepo_CreateAPIKey(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(repository.QueryAPIKeyCreate).
+		WithArgs(apiKeyUserID, "ci", apiKeyPrefix, "hash", []byte(`["users:read"]`), nil).
+		WillReturnRows(sqlmock.NewRows([]string{id, createdAt}).AddRow(apiKeyID, now))
+
+	repo := repository.NewAPIKeyRepository(db)
+	key, err := repo.CreateAPIKey(context.Background(), repository.CreateAPIKeyParams{
+		UserID:  apiKeyUserID,
+		Name:    "ci",
+		Prefix:  apiKeyPrefix,
+		KeyHash: "hash",
+		Scopes:  []string{"users:read"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, apiKeyID, key.ID)
+	assert.Equal(t, now, key.CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyRepo_ListAPIKeys(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	lastUsed := time.Now()
+	mock.ExpectQuery(repository.QueryAPIKeyList).
+		WithArgs(apiKeyUserID).
+		WillReturnRows(sqlmock.NewRows(apiKeyCols()).
+			AddRow(apiKeyID, apiKeyUserID, "ci", apiKeyPrefix, "hash", []byte(`[]`), nil, lastUsed, time.Now()))
+
+	repo := repository.NewAPIKeyRepository(db)
+	keys, err := repo.ListAPIKeys(context.Background(), apiKeyUserID)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, "ci", keys[0].Name)
+	assert.Empty(t, keys[0].Scopes)
+	assert.Nil(t, keys[0].ExpiresAt)
+	require.NotNil(t, keys[0].LastUsedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyRepo_FindAPIKeyByPrefix(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(repository.QueryAPIKeyFindByPrefix).
+		WithArgs(apiKeyPrefix).
+		WillReturnRows(sqlmock.NewRows(apiKeyCols()).
+			AddRow(apiKeyID, apiKeyUserID, "ci", apiKeyPrefix, "hash", []byte(`["users:read"]`), nil, nil,
+				time.Now()))
+
+	repo := repository.NewAPIKeyRepository(db)
+	key, err := repo.FindAPIKeyByPrefix(context.Background(), apiKeyPrefix)
+	require.NoError(t, err)
+	assert.Equal(t, "hash", key.KeyHash)
+	assert.Equal(t, []string{"users:read"}, key.Scopes)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyRepo_DeleteAPIKey(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{name: "Deleted", affected: 1},
+		{name: "Not the user's key", wantErr: sql.ErrNoRows},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			db, mock, err := sqlmock.New(sqlmockOpts)
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectExec(repository.QueryAPIKeyDelete).
+				WithArgs(apiKeyID, apiKeyUserID).
+				WillReturnResult(sqlmock.NewResult(0, tc.affected))
+
+			repo := repository.NewAPIKeyRepository(db)
+			err = repo.DeleteAPIKey(context.Background(), apiKeyUserID, apiKeyID)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
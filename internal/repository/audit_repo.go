@@ -0,0 +1,84 @@
+//go:generate mockgen -destination=mock/audit_repo_mock.go -package=mock . AuditRepository
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/model"
+)
+
+type AuditRepository interface {
+	CreateAuditEvent(ctx context.Context, userID, event string) error
+	CreateAuditEventByActor(ctx context.Context, userID, actorID, event string) error
+	ListAuditEvents(ctx context.Context, userID string) ([]model.AuditEvent, error)
+	FindLastAuditEventTime(ctx context.Context, userID, event string) (time.Time, error)
+}
+
+type auditRepo struct {
+	db *sql.DB
+}
+
+var _ AuditRepository = (*auditRepo)(nil)
+
+func NewAuditRepository(db *sql.DB) AuditRepository {
+	return &auditRepo{db: db}
+}
+
+const QueryAuditEventCreate = "INSERT INTO audit_events (user_id, event) VALUES ($1, $2)"
+
+func (r *auditRepo) CreateAuditEvent(ctx context.Context, userID, event string) error {
+	_, err := r.db.ExecContext(ctx, QueryAuditEventCreate, userID, event)
+	return err
+}
+
+const QueryAuditEventCreateByActor = "INSERT INTO audit_events (user_id, actor_id, event) VALUES ($1, $2, $3)"
+
+// CreateAuditEventByActor records an event on the user's account that another user, an administrator,
+// caused.
+func (r *auditRepo) CreateAuditEventByActor(ctx context.Context, userID, actorID, event string) error {
+	_, err := r.db.ExecContext(ctx, QueryAuditEventCreateByActor, userID, actorID, event)
+	return err
+}
+
+const QueryAuditEventList = `
+SELECT id, user_id, event, created_at FROM audit_events
+WHERE user_id = $1
+ORDER BY created_at
+`
+
+func (r *auditRepo) ListAuditEvents(ctx context.Context, userID string) ([]model.AuditEvent, error) {
+	rows, err := r.db.QueryContext(ctx, QueryAuditEventList, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []model.AuditEvent{}
+	for rows.Next() {
+		var event model.AuditEvent
+		if err := rows.Scan(&event.ID, &event.UserID, &event.Event, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+const QueryAuditEventFindLast = `
+SELECT created_at FROM audit_events
+WHERE user_id = $1 AND event = $2
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+// FindLastAuditEventTime returns when the event was last recorded for the user, or sql.ErrNoRows if never.
+func (r *auditRepo) FindLastAuditEventTime(ctx context.Context, userID, event string) (time.Time, error) {
+	var createdAt time.Time
+	if err := r.db.QueryRowContext(ctx, QueryAuditEventFindLast, userID, event).Scan(&createdAt); err != nil {
+		return time.Time{}, err
+	}
+	return createdAt, nil
+}
@@ -0,0 +1,79 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditRepo_CreateAuditEvent(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(repository.QueryAuditEventCreate).
+		WithArgs("1", "mfa_recovery_code_used").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := repository.NewAuditRepository(db)
+	assert.NoError(t, repo.CreateAuditEvent(context.Background(), "1", "mfa_recovery_code_used"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuditRepo_CreateAuditEventByActor(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(repository.QueryAuditEventCreateByActor).
+		WithArgs("1", "2", "user_suspended").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := repository.NewAuditRepository(db)
+	assert.NoError(t, repo.CreateAuditEventByActor(context.Background(), "1", "2", "user_suspended"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuditRepo_ListAuditEvents(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(repository.QueryAuditEventList).
+		WithArgs("1").
+		WillReturnRows(sqlmock.NewRows([]string{id, "user_id", "event", createdAt}).
+			AddRow("event-1", "1", "mfa_enabled", time.Now()))
+
+	repo := repository.NewAuditRepository(db)
+	events, err := repo.ListAuditEvents(context.Background(), "1")
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "mfa_enabled", events[0].Event)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuditRepo_FindLastAuditEventTime(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	requestedAt := time.Now()
+	mock.ExpectQuery(repository.QueryAuditEventFindLast).
+		WithArgs("1", "data_export_requested").
+		WillReturnRows(sqlmock.NewRows([]string{createdAt}).AddRow(requestedAt))
+
+	repo := repository.NewAuditRepository(db)
+	got, err := repo.FindLastAuditEventTime(context.Background(), "1", "data_export_requested")
+	require.NoError(t, err)
+	assert.Equal(t, requestedAt, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,101 @@
+//go:generate mockgen -destination=mock/client_repo_mock.go -package=mock . ClientRepository
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/model"
+)
+
+// ClientRepository stores the apps that sign users in through gojeep and the authorization codes
+// issued to them.
+type ClientRepository interface {
+	FindClient(ctx context.Context, clientID string) (model.Client, error)
+	SaveAuthorizationCode(ctx context.Context, params SaveAuthorizationCodeParams) error
+	ConsumeAuthorizationCode(ctx context.Context, codeHash string) (model.AuthorizationCode, error)
+	PurgeAuthorizationCodes(ctx context.Context) (int64, error)
+}
+
+type clientRepo struct {
+	db *sql.DB
+}
+
+var _ ClientRepository = (*clientRepo)(nil)
+
+func NewClientRepository(db *sql.DB) ClientRepository {
+	return &clientRepo{db: db}
+}
+
+const QueryClientFind = `
+SELECT id, name, COALESCE(secret_hash, ''), redirect_uris, first_party, created_at FROM oauth_clients
+WHERE id = $1
+LIMIT 1
+`
+
+func (r *clientRepo) FindClient(ctx context.Context, clientID string) (model.Client, error) {
+	var client model.Client
+	var redirectURIs []byte
+	if err := r.db.QueryRowContext(ctx, QueryClientFind, clientID).
+		Scan(&client.ID, &client.Name, &client.SecretHash, &redirectURIs, &client.FirstParty,
+			&client.CreatedAt); err != nil {
+		return model.Client{}, err
+	}
+
+	if err := json.Unmarshal(redirectURIs, &client.RedirectURIs); err != nil {
+		return model.Client{}, fmt.Errorf("decode redirect uris of client %s: %w", clientID, err)
+	}
+
+	return client, nil
+}
+
+// SaveAuthorizationCodeParams describe an issued code. Only the hash of the code is stored.
+type SaveAuthorizationCodeParams struct {
+	CodeHash string
+	model.AuthorizationCode
+	ExpiresAt time.Time
+}
+
+const QueryAuthorizationCodeSave = `
+INSERT INTO authorization_codes
+	(code_hash, client_id, user_id, redirect_uri, scope, nonce, code_challenge, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`
+
+func (r *clientRepo) SaveAuthorizationCode(ctx context.Context, params SaveAuthorizationCodeParams) error {
+	_, err := r.db.ExecContext(ctx, QueryAuthorizationCodeSave, params.CodeHash, params.ClientID, params.UserID,
+		params.RedirectURI, params.Scope, params.Nonce, params.CodeChallenge, params.ExpiresAt)
+	return err
+}
+
+const QueryAuthorizationCodeConsume = `
+DELETE FROM authorization_codes
+WHERE code_hash = $1 AND expires_at > NOW()
+RETURNING client_id, user_id, redirect_uri, scope, nonce, code_challenge
+`
+
+// ConsumeAuthorizationCode deletes an unexpired code and returns what it was issued for.
+// It returns sql.ErrNoRows if the code is unknown, expired or already redeemed.
+func (r *clientRepo) ConsumeAuthorizationCode(ctx context.Context, codeHash string) (model.AuthorizationCode,
+	error) {
+	var code model.AuthorizationCode
+	if err := r.db.QueryRowContext(ctx, QueryAuthorizationCodeConsume, codeHash).
+		Scan(&code.ClientID, &code.UserID, &code.RedirectURI, &code.Scope, &code.Nonce,
+			&code.CodeChallenge); err != nil {
+		return model.AuthorizationCode{}, err
+	}
+	return code, nil
+}
+
+const QueryAuthorizationCodePurge = "DELETE FROM authorization_codes WHERE expires_at <= NOW()"
+
+func (r *clientRepo) PurgeAuthorizationCodes(ctx context.Context) (int64, error) {
+	res, err := r.db.ExecContext(ctx, QueryAuthorizationCodePurge)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
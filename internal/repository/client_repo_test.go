@@ -0,0 +1,126 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	clientID = "intranet"
+	codeHash = "5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8"
+)
+
+func TestClientRepo_FindClient(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(repository.QueryClientFind).
+		WithArgs(clientID).
+		WillReturnRows(sqlmock.NewRows([]string{id, "name", "secret_hash", "redirect_uris", "first_party", createdAt}).
+			AddRow(clientID, "Intranet", "hash", []byte(`["https://intranet.example.com/callback"]`), true,
+				time.Now()))
+
+	repo := repository.NewClientRepository(db)
+	client, err := repo.FindClient(context.Background(), clientID)
+	require.NoError(t, err)
+	assert.Equal(t, "Intranet", client.Name)
+	assert.Equal(t, []string{"https://intranet.example.com/callback"}, client.RedirectURIs)
+	assert.True(t, client.FirstParty)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClientRepo_SaveAuthorizationCode(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	params := repository.SaveAuthorizationCodeParams{
+		CodeHash: codeHash,
+		AuthorizationCode: model.AuthorizationCode{
+			ClientID:      clientID,
+			UserID:        "1",
+			RedirectURI:   "https://intranet.example.com/callback",
+			Scope:         "openid",
+			Nonce:         "nonce",
+			CodeChallenge: "challenge",
+		},
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	mock.ExpectExec(repository.QueryAuthorizationCodeSave).
+		WithArgs(params.CodeHash, params.ClientID, params.UserID, params.RedirectURI, params.Scope, params.Nonce,
+			params.CodeChallenge, params.ExpiresAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := repository.NewClientRepository(db)
+	assert.NoError(t, repo.SaveAuthorizationCode(context.Background(), params))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestClientRepo_ConsumeAuthorizationCode(t *testing.T) {
+	t.Parallel()
+
+	columns := []string{"client_id", "user_id", "redirect_uri", "scope", "nonce", "code_challenge"}
+	testCases := []struct {
+		name    string
+		rows    *sqlmock.Rows
+		wantErr error
+	}{
+		{
+			name: "Issued",
+			rows: sqlmock.NewRows(columns).
+				AddRow(clientID, "1", "https://intranet.example.com/callback", "openid", "nonce", "challenge"),
+		},
+		{name: "Unknown, expired or redeemed", rows: sqlmock.NewRows(columns), wantErr: sql.ErrNoRows},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			db, mock, err := sqlmock.New(sqlmockOpts)
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectQuery(repository.QueryAuthorizationCodeConsume).
+				WithArgs(codeHash).
+				WillReturnRows(tc.rows)
+
+			repo := repository.NewClientRepository(db)
+			code, err := repo.ConsumeAuthorizationCode(context.Background(), codeHash)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, clientID, code.ClientID)
+				assert.Equal(t, "challenge", code.CodeChallenge)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestClientRepo_PurgeAuthorizationCodes(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(repository.QueryAuthorizationCodePurge).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	repo := repository.NewClientRepository(db)
+	purged, err := repo.PurgeAuthorizationCodes(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
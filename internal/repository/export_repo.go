@@ -0,0 +1,96 @@
+//go:generate mockgen -destination=mock/export_repo_mock.go -package=mock . ExportRepository
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/model"
+)
+
+// ExportRepository stores the personal data archives built for users until they are downloaded.
+type ExportRepository interface {
+	CreateExport(ctx context.Context, params CreateExportParams) (model.DataExport, error)
+	FindExport(ctx context.Context, userID, id string) (model.DataExport, error)
+	FindActiveExportExpiry(ctx context.Context, userID string) (time.Time, error)
+	PurgeExports(ctx context.Context) (int64, error)
+}
+
+type exportRepo struct {
+	db *sql.DB
+}
+
+var _ ExportRepository = (*exportRepo)(nil)
+
+func NewExportRepository(db *sql.DB) ExportRepository {
+	return &exportRepo{db: db}
+}
+
+type CreateExportParams struct {
+	UserID    string
+	Archive   []byte
+	ExpiresAt time.Time
+}
+
+const QueryExportCreate = `
+INSERT INTO data_exports (user_id, archive, expires_at)
+VALUES ($1, $2, $3)
+RETURNING id, created_at
+`
+
+func (r *exportRepo) CreateExport(ctx context.Context, params CreateExportParams) (model.DataExport, error) {
+	export := model.DataExport{
+		UserID:    params.UserID,
+		Archive:   params.Archive,
+		ExpiresAt: params.ExpiresAt,
+	}
+	if err := r.db.QueryRowContext(ctx, QueryExportCreate, params.UserID, params.Archive, params.ExpiresAt).
+		Scan(&export.ID, &export.CreatedAt); err != nil {
+		return model.DataExport{}, err
+	}
+	return export, nil
+}
+
+const QueryExportFind = `
+SELECT e.id, e.user_id, e.archive, e.expires_at, e.created_at FROM data_exports e
+JOIN users u ON u.id = e.user_id
+WHERE e.id = $1 AND e.user_id = $2 AND e.expires_at > NOW() AND u.deleted_at IS NULL
+`
+
+// FindExport returns one of the user's exports. An expired export, or one of a deleted user, is not found.
+func (r *exportRepo) FindExport(ctx context.Context, userID, id string) (model.DataExport, error) {
+	var export model.DataExport
+	if err := r.db.QueryRowContext(ctx, QueryExportFind, id, userID).
+		Scan(&export.ID, &export.UserID, &export.Archive, &export.ExpiresAt, &export.CreatedAt); err != nil {
+		return model.DataExport{}, err
+	}
+	return export, nil
+}
+
+const QueryExportFindActiveExpiry = `
+SELECT expires_at FROM data_exports
+WHERE user_id = $1 AND expires_at > NOW()
+ORDER BY expires_at DESC
+LIMIT 1
+`
+
+// FindActiveExportExpiry returns when the user's last unexpired export expires, or sql.ErrNoRows if the user
+// has none.
+func (r *exportRepo) FindActiveExportExpiry(ctx context.Context, userID string) (time.Time, error) {
+	var expiresAt time.Time
+	if err := r.db.QueryRowContext(ctx, QueryExportFindActiveExpiry, userID).Scan(&expiresAt); err != nil {
+		return time.Time{}, err
+	}
+	return expiresAt, nil
+}
+
+const QueryExportPurge = "DELETE FROM data_exports WHERE expires_at < NOW()"
+
+func (r *exportRepo) PurgeExports(ctx context.Context) (int64, error) {
+	res, err := r.db.ExecContext(ctx, QueryExportPurge)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,113 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	exportID     = "export-1"
+	exportUserID = "1"
+)
+
+func TestExportRepo_CreateExport(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	archive := []byte("zip")
+	expiresAt := time.Now().Add(time.Hour)
+	mock.ExpectQuery(repository.QueryExportCreate).
+		WithArgs(exportUserID, archive, expiresAt).
+		WillReturnRows(sqlmock.NewRows([]string{id, createdAt}).AddRow(exportID, time.Now()))
+
+	repo := repository.NewExportRepository(db)
+	export, err := repo.CreateExport(context.Background(), repository.CreateExportParams{
+		UserID:    exportUserID,
+		Archive:   archive,
+		ExpiresAt: expiresAt,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, exportID, export.ID)
+	assert.Equal(t, archive, export.Archive)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExportRepo_FindActiveExportExpiry(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	expiresAt := time.Now().Add(time.Hour)
+	mock.ExpectQuery(repository.QueryExportFindActiveExpiry).
+		WithArgs(exportUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"expires_at"}).AddRow(expiresAt))
+
+	repo := repository.NewExportRepository(db)
+	got, err := repo.FindActiveExportExpiry(context.Background(), exportUserID)
+	require.NoError(t, err)
+	assert.Equal(t, expiresAt, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExportRepo_FindExport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Found", func(t *testing.T) {
+		t.Parallel()
+		db, mock, err := sqlmock.New(sqlmockOpts)
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(repository.QueryExportFind).
+			WithArgs(exportID, exportUserID).
+			WillReturnRows(sqlmock.NewRows([]string{id, "user_id", "archive", "expires_at", createdAt}).
+				AddRow(exportID, exportUserID, []byte("zip"), time.Now().Add(time.Hour), time.Now()))
+
+		repo := repository.NewExportRepository(db)
+		export, err := repo.FindExport(context.Background(), exportUserID, exportID)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("zip"), export.Archive)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Expired or another user's", func(t *testing.T) {
+		t.Parallel()
+		db, mock, err := sqlmock.New(sqlmockOpts)
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(repository.QueryExportFind).
+			WithArgs(exportID, exportUserID).
+			WillReturnError(sql.ErrNoRows)
+
+		repo := repository.NewExportRepository(db)
+		_, err = repo.FindExport(context.Background(), exportUserID, exportID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestExportRepo_PurgeExports(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(repository.QueryExportPurge).WillReturnResult(sqlmock.NewResult(0, 2))
+
+	repo := repository.NewExportRepository(db)
+	purged, err := repo.PurgeExports(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
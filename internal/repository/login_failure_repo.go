@@ -0,0 +1,94 @@
+//go:generate mockgen -destination=mock/login_failure_repo_mock.go -package=mock . LoginFailureRepository
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// LoginFailureRepository counts failed logins per key, such as an account or an IP address. The counters
+// are kept in the database so every instance of the server sees the same lockouts.
+type LoginFailureRepository interface {
+	RecordLoginFailure(ctx context.Context, key string, window time.Duration) (int, error)
+	LockLogin(ctx context.Context, key string, until time.Time) error
+	FindLoginLock(ctx context.Context, accountKey, ipKey string) (time.Time, error)
+	ClearLoginFailures(ctx context.Context, key string) error
+	PurgeLoginFailures(ctx context.Context, window time.Duration) (int64, error)
+}
+
+type loginFailureRepo struct {
+	db *sql.DB
+}
+
+var _ LoginFailureRepository = (*loginFailureRepo)(nil)
+
+func NewLoginFailureRepository(db *sql.DB) LoginFailureRepository {
+	return &loginFailureRepo{db: db}
+}
+
+const QueryLoginFailureRecord = `
+INSERT INTO login_failures (key, failures, last_failed_at)
+VALUES ($1, 1, NOW())
+ON CONFLICT (key) DO UPDATE
+SET failures = CASE
+		WHEN login_failures.last_failed_at < NOW() - make_interval(secs => $2) THEN 1
+		ELSE login_failures.failures + 1
+	END,
+	last_failed_at = NOW()
+RETURNING failures
+`
+
+// RecordLoginFailure adds a failure to the key's counter and returns the new count. The count starts
+// over when the previous failure is older than window.
+func (r *loginFailureRepo) RecordLoginFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	var failures int
+	if err := r.db.QueryRowContext(ctx, QueryLoginFailureRecord, key, window.Seconds()).Scan(&failures); err != nil {
+		return 0, err
+	}
+	return failures, nil
+}
+
+const QueryLoginFailureLock = "UPDATE login_failures SET locked_until = $2 WHERE key = $1"
+
+func (r *loginFailureRepo) LockLogin(ctx context.Context, key string, until time.Time) error {
+	_, err := r.db.ExecContext(ctx, QueryLoginFailureLock, key, until)
+	return err
+}
+
+const QueryLoginFailureFindLock = `
+SELECT MAX(locked_until) FROM login_failures
+WHERE key IN ($1, $2) AND locked_until > NOW()
+`
+
+// FindLoginLock returns the latest time the account or the IP address is locked until, or the zero time
+// if neither is locked.
+func (r *loginFailureRepo) FindLoginLock(ctx context.Context, accountKey, ipKey string) (time.Time, error) {
+	var until sql.NullTime
+	if err := r.db.QueryRowContext(ctx, QueryLoginFailureFindLock, accountKey, ipKey).Scan(&until); err != nil {
+		return time.Time{}, err
+	}
+	return until.Time, nil
+}
+
+const QueryLoginFailureClear = "DELETE FROM login_failures WHERE key = $1"
+
+func (r *loginFailureRepo) ClearLoginFailures(ctx context.Context, key string) error {
+	_, err := r.db.ExecContext(ctx, QueryLoginFailureClear, key)
+	return err
+}
+
+const QueryLoginFailurePurge = `
+DELETE FROM login_failures
+WHERE last_failed_at < NOW() - make_interval(secs => $1)
+AND (locked_until IS NULL OR locked_until <= NOW())
+`
+
+// PurgeLoginFailures deletes counters that would start over anyway and are not locked.
+func (r *loginFailureRepo) PurgeLoginFailures(ctx context.Context, window time.Duration) (int64, error) {
+	res, err := r.db.ExecContext(ctx, QueryLoginFailurePurge, window.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,117 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	accountKey = "account:abc@example.com"
+	ipKey      = "ip:127.0.0.1"
+)
+
+func TestLoginFailureRepo_RecordLoginFailure(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(repository.QueryLoginFailureRecord).
+		WithArgs(accountKey, float64(3600)).
+		WillReturnRows(sqlmock.NewRows([]string{"failures"}).AddRow(3))
+
+	repo := repository.NewLoginFailureRepository(db)
+	failures, err := repo.RecordLoginFailure(context.Background(), accountKey, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 3, failures)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginFailureRepo_LockLogin(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	until := time.Now().Add(time.Minute)
+	mock.ExpectExec(repository.QueryLoginFailureLock).
+		WithArgs(accountKey, until).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := repository.NewLoginFailureRepository(db)
+	err = repo.LockLogin(context.Background(), accountKey, until)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginFailureRepo_FindLoginLock(t *testing.T) {
+	t.Parallel()
+	until := time.Now().Add(time.Minute)
+
+	testCases := []struct {
+		name        string
+		lockedUntil any
+		want        time.Time
+	}{
+		{name: "Locked", lockedUntil: until, want: until},
+		{name: "Not locked", lockedUntil: nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			db, mock, err := sqlmock.New(sqlmockOpts)
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectQuery(repository.QueryLoginFailureFindLock).
+				WithArgs(accountKey, ipKey).
+				WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(tc.lockedUntil))
+
+			repo := repository.NewLoginFailureRepository(db)
+			got, err := repo.FindLoginLock(context.Background(), accountKey, ipKey)
+			require.NoError(t, err)
+			assert.True(t, tc.want.Equal(got))
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestLoginFailureRepo_ClearLoginFailures(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(repository.QueryLoginFailureClear).
+		WithArgs(accountKey).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := repository.NewLoginFailureRepository(db)
+	err = repo.ClearLoginFailures(context.Background(), accountKey)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLoginFailureRepo_PurgeLoginFailures(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(repository.QueryLoginFailurePurge).
+		WithArgs(float64(3600)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	repo := repository.NewLoginFailureRepository(db)
+	purged, err := repo.PurgeLoginFailures(context.Background(), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,203 @@
+//go:generate mockgen -destination=mock/mfa_repo_mock.go -package=mock . MFARepository
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ferdiebergado/gojeep/internal/model"
+)
+
+type MFARepository interface {
+	SaveMFASecret(ctx context.Context, userID, secret string) error
+	FindMFA(ctx context.Context, userID string) (model.MFA, error)
+	ConfirmMFA(ctx context.Context, userID string) error
+	UseMFAStep(ctx context.Context, userID string, step int64) error
+	DeleteMFA(ctx context.Context, userID string) error
+	ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error
+	FindUnusedRecoveryCodes(ctx context.Context, userID string) ([]model.RecoveryCode, error)
+	UseRecoveryCode(ctx context.Context, id string) error
+}
+
+type mfaRepo struct {
+	db *sql.DB
+}
+
+var _ MFARepository = (*mfaRepo)(nil)
+
+func NewMFARepository(db *sql.DB) MFARepository {
+	return &mfaRepo{db: db}
+}
+
+const QueryMFASaveSecret = `
+INSERT INTO user_mfa (user_id, secret)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET secret = EXCLUDED.secret, last_used_step = 0, updated_at = NOW()
+WHERE user_mfa.confirmed_at IS NULL
+`
+
+// SaveMFASecret starts or restarts an enrollment. It returns sql.ErrNoRows if MFA is already confirmed,
+// so an enabled secret is never silently replaced.
+func (r *mfaRepo) SaveMFASecret(ctx context.Context, userID, secret string) error {
+	res, err := r.db.ExecContext(ctx, QueryMFASaveSecret, userID, secret)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+const QueryMFAFind = `
+SELECT user_id, secret, last_used_step, confirmed_at, created_at, updated_at FROM user_mfa
+WHERE user_id = $1
+LIMIT 1
+`
+
+func (r *mfaRepo) FindMFA(ctx context.Context, userID string) (model.MFA, error) {
+	var mfa model.MFA
+	if err := r.db.QueryRowContext(ctx, QueryMFAFind, userID).
+		Scan(&mfa.UserID, &mfa.Secret, &mfa.LastUsedStep, &mfa.ConfirmedAt, &mfa.CreatedAt, &mfa.UpdatedAt); err != nil {
+		return model.MFA{}, err
+	}
+	return mfa, nil
+}
+
+const QueryMFAConfirm = `
+UPDATE user_mfa
+SET confirmed_at = NOW(), updated_at = NOW()
+WHERE user_id = $1 AND confirmed_at IS NULL
+`
+
+func (r *mfaRepo) ConfirmMFA(ctx context.Context, userID string) error {
+	_, err := r.db.ExecContext(ctx, QueryMFAConfirm, userID)
+	return err
+}
+
+const QueryMFAUseStep = `
+UPDATE user_mfa
+SET last_used_step = $2, updated_at = NOW()
+WHERE user_id = $1 AND last_used_step < $2
+`
+
+// UseMFAStep records the time step of an accepted code. It returns sql.ErrNoRows if that step or a
+// later one was already used, so a code cannot be replayed.
+func (r *mfaRepo) UseMFAStep(ctx context.Context, userID string, step int64) error {
+	res, err := r.db.ExecContext(ctx, QueryMFAUseStep, userID, step)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+const QueryMFADelete = "DELETE FROM user_mfa WHERE user_id = $1"
+
+func (r *mfaRepo) DeleteMFA(ctx context.Context, userID string) error {
+	_, err := r.db.ExecContext(ctx, QueryMFADelete, userID)
+	return err
+}
+
+const (
+	QueryRecoveryCodesDelete = "DELETE FROM mfa_recovery_codes WHERE user_id = $1"
+	QueryRecoveryCodeCreate  = "INSERT INTO mfa_recovery_codes (user_id, code_hash) VALUES ($1, $2)"
+)
+
+// ReplaceRecoveryCodes swaps the user's recovery codes for a new set in one transaction, so the old
+// codes stop working exactly when the new ones start.
+func (r *mfaRepo) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, tx.Rollback())
+		}
+	}()
+
+	if _, err = tx.ExecContext(ctx, QueryRecoveryCodesDelete, userID); err != nil {
+		return fmt.Errorf("delete recovery codes: %w", err)
+	}
+
+	for _, hash := range codeHashes {
+		if _, err = tx.ExecContext(ctx, QueryRecoveryCodeCreate, userID, hash); err != nil {
+			return fmt.Errorf("create recovery code: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+const QueryRecoveryCodesFindUnused = `
+SELECT id, user_id, code_hash, used_at, created_at FROM mfa_recovery_codes
+WHERE user_id = $1 AND used_at IS NULL
+`
+
+func (r *mfaRepo) FindUnusedRecoveryCodes(ctx context.Context, userID string) ([]model.RecoveryCode, error) {
+	rows, err := r.db.QueryContext(ctx, QueryRecoveryCodesFindUnused, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var codes []model.RecoveryCode
+	for rows.Next() {
+		var code model.RecoveryCode
+		if err := rows.Scan(&code.ID, &code.UserID, &code.CodeHash, &code.UsedAt, &code.CreatedAt); err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+const QueryRecoveryCodeUse = `
+UPDATE mfa_recovery_codes
+SET used_at = NOW()
+WHERE id = $1 AND used_at IS NULL
+`
+
+// UseRecoveryCode marks a code as used. It returns sql.ErrNoRows if the code was already used.
+func (r *mfaRepo) UseRecoveryCode(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, QueryRecoveryCodeUse, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
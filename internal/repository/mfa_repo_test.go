@@ -0,0 +1,243 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	mfaUserID = "1"
+	mfaSecret = "encrypted"
+)
+
+func TestMFARepo_SaveMFASecret(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{name: "Saved", affected: 1},
+		{name: "Already confirmed", affected: 0, wantErr: sql.ErrNoRows},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			db, mock, err := sqlmock.New(sqlmockOpts)
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectExec(repository.QueryMFASaveSecret).
+				WithArgs(mfaUserID, mfaSecret).
+				WillReturnResult(sqlmock.NewResult(0, tc.affected))
+
+			repo := repository.NewMFARepository(db)
+			err = repo.SaveMFASecret(context.Background(), mfaUserID, mfaSecret)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMFARepo_FindMFA(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(repository.QueryMFAFind).
+		WithArgs(mfaUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "secret", "last_used_step", "confirmed_at", createdAt, updatedAt}).
+			AddRow(mfaUserID, mfaSecret, 42, &now, now, now))
+
+	repo := repository.NewMFARepository(db)
+	mfa, err := repo.FindMFA(context.Background(), mfaUserID)
+	require.NoError(t, err)
+	assert.Equal(t, mfaSecret, mfa.Secret)
+	assert.Equal(t, int64(42), mfa.LastUsedStep)
+	assert.NotNil(t, mfa.ConfirmedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMFARepo_ConfirmMFA(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(repository.QueryMFAConfirm).
+		WithArgs(mfaUserID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := repository.NewMFARepository(db)
+	assert.NoError(t, repo.ConfirmMFA(context.Background(), mfaUserID))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMFARepo_UseMFAStep(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{name: "New step", affected: 1},
+		{name: "Replayed step", affected: 0, wantErr: sql.ErrNoRows},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			db, mock, err := sqlmock.New(sqlmockOpts)
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectExec(repository.QueryMFAUseStep).
+				WithArgs(mfaUserID, int64(100)).
+				WillReturnResult(sqlmock.NewResult(0, tc.affected))
+
+			repo := repository.NewMFARepository(db)
+			err = repo.UseMFAStep(context.Background(), mfaUserID, 100)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestMFARepo_DeleteMFA(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(repository.QueryMFADelete).
+		WithArgs(mfaUserID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := repository.NewMFARepository(db)
+	assert.NoError(t, repo.DeleteMFA(context.Background(), mfaUserID))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMFARepo_ReplaceRecoveryCodes(t *testing.T) {
+	t.Parallel()
+	hashes := []string{"hash1", "hash2"}
+
+	t.Run("Replaced", func(t *testing.T) {
+		t.Parallel()
+		db, mock, err := sqlmock.New(sqlmockOpts)
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(repository.QueryRecoveryCodesDelete).
+			WithArgs(mfaUserID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		for _, hash := range hashes {
+			mock.ExpectExec(repository.QueryRecoveryCodeCreate).
+				WithArgs(mfaUserID, hash).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		mock.ExpectCommit()
+
+		repo := repository.NewMFARepository(db)
+		assert.NoError(t, repo.ReplaceRecoveryCodes(context.Background(), mfaUserID, hashes))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Rolled back on failure", func(t *testing.T) {
+		t.Parallel()
+		db, mock, err := sqlmock.New(sqlmockOpts)
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(repository.QueryRecoveryCodesDelete).
+			WithArgs(mfaUserID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(repository.QueryRecoveryCodeCreate).
+			WithArgs(mfaUserID, hashes[0]).
+			WillReturnError(sql.ErrConnDone)
+		mock.ExpectRollback()
+
+		repo := repository.NewMFARepository(db)
+		err = repo.ReplaceRecoveryCodes(context.Background(), mfaUserID, hashes)
+		assert.ErrorIs(t, err, sql.ErrConnDone)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMFARepo_FindUnusedRecoveryCodes(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(repository.QueryRecoveryCodesFindUnused).
+		WithArgs(mfaUserID).
+		WillReturnRows(sqlmock.NewRows([]string{id, "user_id", "code_hash", "used_at", createdAt}).
+			AddRow("a", mfaUserID, "hash1", nil, now).
+			AddRow("b", mfaUserID, "hash2", nil, now))
+
+	repo := repository.NewMFARepository(db)
+	codes, err := repo.FindUnusedRecoveryCodes(context.Background(), mfaUserID)
+	require.NoError(t, err)
+	require.Len(t, codes, 2)
+	assert.Equal(t, "hash2", codes[1].CodeHash)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMFARepo_UseRecoveryCode(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{name: "Unused code", affected: 1},
+		{name: "Used code", affected: 0, wantErr: sql.ErrNoRows},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			db, mock, err := sqlmock.New(sqlmockOpts)
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectExec(repository.QueryRecoveryCodeUse).
+				WithArgs("a").
+				WillReturnResult(sqlmock.NewResult(0, tc.affected))
+
+			repo := repository.NewMFARepository(db)
+			err = repo.UseRecoveryCode(context.Background(), "a")
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/gojeep/internal/repository (interfaces: ClientRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mock/client_repo_mock.go -package=mock . ClientRepository
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/ferdiebergado/gojeep/internal/model"
+	repository "github.com/ferdiebergado/gojeep/internal/repository"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockClientRepository is a mock of ClientRepository interface.
+type MockClientRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockClientRepositoryMockRecorder is the mock recorder for MockClientRepository.
+type MockClientRepositoryMockRecorder struct {
+	mock *MockClientRepository
+}
+
+// NewMockClientRepository creates a new mock instance.
+func NewMockClientRepository(ctrl *gomock.Controller) *MockClientRepository {
+	mock := &MockClientRepository{ctrl: ctrl}
+	mock.recorder = &MockClientRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClientRepository) EXPECT() *MockClientRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ConsumeAuthorizationCode mocks base method.
+func (m *MockClientRepository) ConsumeAuthorizationCode(ctx context.Context, codeHash string) (model.AuthorizationCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeAuthorizationCode", ctx, codeHash)
+	ret0, _ := ret[0].(model.AuthorizationCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeAuthorizationCode indicates an expected call of ConsumeAuthorizationCode.
+func (mr *MockClientRepositoryMockRecorder) ConsumeAuthorizationCode(ctx, codeHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeAuthorizationCode", reflect.TypeOf((*MockClientRepository)(nil).ConsumeAuthorizationCode), ctx, codeHash)
+}
+
+// FindClient mocks base method.
+func (m *MockClientRepository) FindClient(ctx context.Context, clientID string) (model.Client, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindClient", ctx, clientID)
+	ret0, _ := ret[0].(model.Client)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindClient indicates an expected call of FindClient.
+func (mr *MockClientRepositoryMockRecorder) FindClient(ctx, clientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindClient", reflect.TypeOf((*MockClientRepository)(nil).FindClient), ctx, clientID)
+}
+
+// PurgeAuthorizationCodes mocks base method.
+func (m *MockClientRepository) PurgeAuthorizationCodes(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeAuthorizationCodes", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeAuthorizationCodes indicates an expected call of PurgeAuthorizationCodes.
+func (mr *MockClientRepositoryMockRecorder) PurgeAuthorizationCodes(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeAuthorizationCodes", reflect.TypeOf((*MockClientRepository)(nil).PurgeAuthorizationCodes), ctx)
+}
+
+// SaveAuthorizationCode mocks base method.
+func (m *MockClientRepository) SaveAuthorizationCode(ctx context.Context, params repository.SaveAuthorizationCodeParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAuthorizationCode", ctx, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveAuthorizationCode indicates an expected call of SaveAuthorizationCode.
+func (mr *MockClientRepositoryMockRecorder) SaveAuthorizationCode(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAuthorizationCode", reflect.TypeOf((*MockClientRepository)(nil).SaveAuthorizationCode), ctx, params)
+}
@@ -0,0 +1,104 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/gojeep/internal/repository (interfaces: ExportRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mock/export_repo_mock.go -package=mock . ExportRepository
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/ferdiebergado/gojeep/internal/model"
+	repository "github.com/ferdiebergado/gojeep/internal/repository"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockExportRepository is a mock of ExportRepository interface.
+type MockExportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExportRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockExportRepositoryMockRecorder is the mock recorder for MockExportRepository.
+type MockExportRepositoryMockRecorder struct {
+	mock *MockExportRepository
+}
+
+// NewMockExportRepository creates a new mock instance.
+func NewMockExportRepository(ctrl *gomock.Controller) *MockExportRepository {
+	mock := &MockExportRepository{ctrl: ctrl}
+	mock.recorder = &MockExportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExportRepository) EXPECT() *MockExportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateExport mocks base method.
+func (m *MockExportRepository) CreateExport(ctx context.Context, params repository.CreateExportParams) (model.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExport", ctx, params)
+	ret0, _ := ret[0].(model.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateExport indicates an expected call of CreateExport.
+func (mr *MockExportRepositoryMockRecorder) CreateExport(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExport", reflect.TypeOf((*MockExportRepository)(nil).CreateExport), ctx, params)
+}
+
+// FindActiveExportExpiry mocks base method.
+func (m *MockExportRepository) FindActiveExportExpiry(ctx context.Context, userID string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindActiveExportExpiry", ctx, userID)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindActiveExportExpiry indicates an expected call of FindActiveExportExpiry.
+func (mr *MockExportRepositoryMockRecorder) FindActiveExportExpiry(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindActiveExportExpiry", reflect.TypeOf((*MockExportRepository)(nil).FindActiveExportExpiry), ctx, userID)
+}
+
+// FindExport mocks base method.
+func (m *MockExportRepository) FindExport(ctx context.Context, userID, id string) (model.DataExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindExport", ctx, userID, id)
+	ret0, _ := ret[0].(model.DataExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindExport indicates an expected call of FindExport.
+func (mr *MockExportRepositoryMockRecorder) FindExport(ctx, userID, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindExport", reflect.TypeOf((*MockExportRepository)(nil).FindExport), ctx, userID, id)
+}
+
+// PurgeExports mocks base method.
+func (m *MockExportRepository) PurgeExports(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExports", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExports indicates an expected call of PurgeExports.
+func (mr *MockExportRepositoryMockRecorder) PurgeExports(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExports", reflect.TypeOf((*MockExportRepository)(nil).PurgeExports), ctx)
+}
@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/gojeep/internal/repository (interfaces: LoginFailureRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mock/login_failure_repo_mock.go -package=mock . LoginFailureRepository
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLoginFailureRepository is a mock of LoginFailureRepository interface.
+type MockLoginFailureRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginFailureRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockLoginFailureRepositoryMockRecorder is the mock recorder for MockLoginFailureRepository.
+type MockLoginFailureRepositoryMockRecorder struct {
+	mock *MockLoginFailureRepository
+}
+
+// NewMockLoginFailureRepository creates a new mock instance.
+func NewMockLoginFailureRepository(ctrl *gomock.Controller) *MockLoginFailureRepository {
+	mock := &MockLoginFailureRepository{ctrl: ctrl}
+	mock.recorder = &MockLoginFailureRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginFailureRepository) EXPECT() *MockLoginFailureRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClearLoginFailures mocks base method.
+func (m *MockLoginFailureRepository) ClearLoginFailures(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearLoginFailures", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearLoginFailures indicates an expected call of ClearLoginFailures.
+func (mr *MockLoginFailureRepositoryMockRecorder) ClearLoginFailures(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearLoginFailures", reflect.TypeOf((*MockLoginFailureRepository)(nil).ClearLoginFailures), ctx, key)
+}
+
+// FindLoginLock mocks base method.
+func (m *MockLoginFailureRepository) FindLoginLock(ctx context.Context, accountKey, ipKey string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLoginLock", ctx, accountKey, ipKey)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLoginLock indicates an expected call of FindLoginLock.
+func (mr *MockLoginFailureRepositoryMockRecorder) FindLoginLock(ctx, accountKey, ipKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLoginLock", reflect.TypeOf((*MockLoginFailureRepository)(nil).FindLoginLock), ctx, accountKey, ipKey)
+}
+
+// LockLogin mocks base method.
+func (m *MockLoginFailureRepository) LockLogin(ctx context.Context, key string, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockLogin", ctx, key, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockLogin indicates an expected call of LockLogin.
+func (mr *MockLoginFailureRepositoryMockRecorder) LockLogin(ctx, key, until any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockLogin", reflect.TypeOf((*MockLoginFailureRepository)(nil).LockLogin), ctx, key, until)
+}
+
+// PurgeLoginFailures mocks base method.
+func (m *MockLoginFailureRepository) PurgeLoginFailures(ctx context.Context, window time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeLoginFailures", ctx, window)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeLoginFailures indicates an expected call of PurgeLoginFailures.
+func (mr *MockLoginFailureRepositoryMockRecorder) PurgeLoginFailures(ctx, window any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeLoginFailures", reflect.TypeOf((*MockLoginFailureRepository)(nil).PurgeLoginFailures), ctx, window)
+}
+
+// RecordLoginFailure mocks base method.
+func (m *MockLoginFailureRepository) RecordLoginFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordLoginFailure", ctx, key, window)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordLoginFailure indicates an expected call of RecordLoginFailure.
+func (mr *MockLoginFailureRepositoryMockRecorder) RecordLoginFailure(ctx, key, window any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLoginFailure", reflect.TypeOf((*MockLoginFailureRepository)(nil).RecordLoginFailure), ctx, key, window)
+}
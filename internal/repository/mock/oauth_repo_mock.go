@@ -0,0 +1,146 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/gojeep/internal/repository (interfaces: OAuthRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mock/oauth_repo_mock.go -package=mock . OAuthRepository
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/ferdiebergado/gojeep/internal/model"
+	repository "github.com/ferdiebergado/gojeep/internal/repository"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockOAuthRepository is a mock of OAuthRepository interface.
+type MockOAuthRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOAuthRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockOAuthRepositoryMockRecorder is the mock recorder for MockOAuthRepository.
+type MockOAuthRepositoryMockRecorder struct {
+	mock *MockOAuthRepository
+}
+
+// NewMockOAuthRepository creates a new mock instance.
+func NewMockOAuthRepository(ctrl *gomock.Controller) *MockOAuthRepository {
+	mock := &MockOAuthRepository{ctrl: ctrl}
+	mock.recorder = &MockOAuthRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOAuthRepository) EXPECT() *MockOAuthRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ConsumeState mocks base method.
+func (m *MockOAuthRepository) ConsumeState(ctx context.Context, state, provider string) (repository.OAuthState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeState", ctx, state, provider)
+	ret0, _ := ret[0].(repository.OAuthState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeState indicates an expected call of ConsumeState.
+func (mr *MockOAuthRepositoryMockRecorder) ConsumeState(ctx, state, provider any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeState", reflect.TypeOf((*MockOAuthRepository)(nil).ConsumeState), ctx, state, provider)
+}
+
+// CreateIdentity mocks base method.
+func (m *MockOAuthRepository) CreateIdentity(ctx context.Context, identity model.Identity) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIdentity", ctx, identity)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateIdentity indicates an expected call of CreateIdentity.
+func (mr *MockOAuthRepositoryMockRecorder) CreateIdentity(ctx, identity any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIdentity", reflect.TypeOf((*MockOAuthRepository)(nil).CreateIdentity), ctx, identity)
+}
+
+// CreateIdentityUser mocks base method.
+func (m *MockOAuthRepository) CreateIdentityUser(ctx context.Context, params repository.CreateIdentityUserParams) (model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIdentityUser", ctx, params)
+	ret0, _ := ret[0].(model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIdentityUser indicates an expected call of CreateIdentityUser.
+func (mr *MockOAuthRepositoryMockRecorder) CreateIdentityUser(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIdentityUser", reflect.TypeOf((*MockOAuthRepository)(nil).CreateIdentityUser), ctx, params)
+}
+
+// FindIdentity mocks base method.
+func (m *MockOAuthRepository) FindIdentity(ctx context.Context, provider, subject string) (model.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindIdentity", ctx, provider, subject)
+	ret0, _ := ret[0].(model.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindIdentity indicates an expected call of FindIdentity.
+func (mr *MockOAuthRepositoryMockRecorder) FindIdentity(ctx, provider, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindIdentity", reflect.TypeOf((*MockOAuthRepository)(nil).FindIdentity), ctx, provider, subject)
+}
+
+// ListIdentities mocks base method.
+func (m *MockOAuthRepository) ListIdentities(ctx context.Context, userID string) ([]model.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIdentities", ctx, userID)
+	ret0, _ := ret[0].([]model.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIdentities indicates an expected call of ListIdentities.
+func (mr *MockOAuthRepositoryMockRecorder) ListIdentities(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIdentities", reflect.TypeOf((*MockOAuthRepository)(nil).ListIdentities), ctx, userID)
+}
+
+// PurgeStates mocks base method.
+func (m *MockOAuthRepository) PurgeStates(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeStates", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeStates indicates an expected call of PurgeStates.
+func (mr *MockOAuthRepositoryMockRecorder) PurgeStates(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeStates", reflect.TypeOf((*MockOAuthRepository)(nil).PurgeStates), ctx)
+}
+
+// SaveState mocks base method.
+func (m *MockOAuthRepository) SaveState(ctx context.Context, params repository.SaveStateParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveState", ctx, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveState indicates an expected call of SaveState.
+func (mr *MockOAuthRepositoryMockRecorder) SaveState(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveState", reflect.TypeOf((*MockOAuthRepository)(nil).SaveState), ctx, params)
+}
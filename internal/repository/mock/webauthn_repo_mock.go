@@ -0,0 +1,145 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/gojeep/internal/repository (interfaces: WebAuthnRepository)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mock/webauthn_repo_mock.go -package=mock . WebAuthnRepository
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/ferdiebergado/gojeep/internal/model"
+	repository "github.com/ferdiebergado/gojeep/internal/repository"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWebAuthnRepository is a mock of WebAuthnRepository interface.
+type MockWebAuthnRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebAuthnRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockWebAuthnRepositoryMockRecorder is the mock recorder for MockWebAuthnRepository.
+type MockWebAuthnRepositoryMockRecorder struct {
+	mock *MockWebAuthnRepository
+}
+
+// NewMockWebAuthnRepository creates a new mock instance.
+func NewMockWebAuthnRepository(ctrl *gomock.Controller) *MockWebAuthnRepository {
+	mock := &MockWebAuthnRepository{ctrl: ctrl}
+	mock.recorder = &MockWebAuthnRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebAuthnRepository) EXPECT() *MockWebAuthnRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ConsumeChallenge mocks base method.
+func (m *MockWebAuthnRepository) ConsumeChallenge(ctx context.Context, challenge, ceremony string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeChallenge", ctx, challenge, ceremony)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeChallenge indicates an expected call of ConsumeChallenge.
+func (mr *MockWebAuthnRepositoryMockRecorder) ConsumeChallenge(ctx, challenge, ceremony any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeChallenge", reflect.TypeOf((*MockWebAuthnRepository)(nil).ConsumeChallenge), ctx, challenge, ceremony)
+}
+
+// CreateCredential mocks base method.
+func (m *MockWebAuthnRepository) CreateCredential(ctx context.Context, cred model.Credential) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCredential", ctx, cred)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCredential indicates an expected call of CreateCredential.
+func (mr *MockWebAuthnRepositoryMockRecorder) CreateCredential(ctx, cred any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCredential", reflect.TypeOf((*MockWebAuthnRepository)(nil).CreateCredential), ctx, cred)
+}
+
+// FindCredential mocks base method.
+func (m *MockWebAuthnRepository) FindCredential(ctx context.Context, id string) (model.Credential, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindCredential", ctx, id)
+	ret0, _ := ret[0].(model.Credential)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindCredential indicates an expected call of FindCredential.
+func (mr *MockWebAuthnRepositoryMockRecorder) FindCredential(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCredential", reflect.TypeOf((*MockWebAuthnRepository)(nil).FindCredential), ctx, id)
+}
+
+// ListCredentialIDs mocks base method.
+func (m *MockWebAuthnRepository) ListCredentialIDs(ctx context.Context, userID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCredentialIDs", ctx, userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCredentialIDs indicates an expected call of ListCredentialIDs.
+func (mr *MockWebAuthnRepositoryMockRecorder) ListCredentialIDs(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCredentialIDs", reflect.TypeOf((*MockWebAuthnRepository)(nil).ListCredentialIDs), ctx, userID)
+}
+
+// PurgeChallenges mocks base method.
+func (m *MockWebAuthnRepository) PurgeChallenges(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeChallenges", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeChallenges indicates an expected call of PurgeChallenges.
+func (mr *MockWebAuthnRepositoryMockRecorder) PurgeChallenges(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeChallenges", reflect.TypeOf((*MockWebAuthnRepository)(nil).PurgeChallenges), ctx)
+}
+
+// SaveChallenge mocks base method.
+func (m *MockWebAuthnRepository) SaveChallenge(ctx context.Context, params repository.SaveChallengeParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveChallenge", ctx, params)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveChallenge indicates an expected call of SaveChallenge.
+func (mr *MockWebAuthnRepositoryMockRecorder) SaveChallenge(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveChallenge", reflect.TypeOf((*MockWebAuthnRepository)(nil).SaveChallenge), ctx, params)
+}
+
+// UpdateCredentialSignCount mocks base method.
+func (m *MockWebAuthnRepository) UpdateCredentialSignCount(ctx context.Context, id string, signCount uint32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCredentialSignCount", ctx, id, signCount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCredentialSignCount indicates an expected call of UpdateCredentialSignCount.
+func (mr *MockWebAuthnRepositoryMockRecorder) UpdateCredentialSignCount(ctx, id, signCount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCredentialSignCount", reflect.TypeOf((*MockWebAuthnRepository)(nil).UpdateCredentialSignCount), ctx, id, signCount)
+}
@@ -0,0 +1,205 @@
+//go:generate mockgen -destination=mock/oauth_repo_mock.go -package=mock . OAuthRepository
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// OAuthRepository stores the identities users sign in with at external providers and the state of
+// sign-ins in progress.
+type OAuthRepository interface {
+	SaveState(ctx context.Context, params SaveStateParams) error
+	ConsumeState(ctx context.Context, state, provider string) (OAuthState, error)
+	PurgeStates(ctx context.Context) (int64, error)
+	FindIdentity(ctx context.Context, provider, subject string) (model.Identity, error)
+	ListIdentities(ctx context.Context, userID string) ([]model.Identity, error)
+	CreateIdentity(ctx context.Context, identity model.Identity) error
+	CreateIdentityUser(ctx context.Context, params CreateIdentityUserParams) (model.User, error)
+}
+
+var ErrIdentityExists = errors.New("identity already linked")
+
+type oauthRepo struct {
+	db *sql.DB
+}
+
+var _ OAuthRepository = (*oauthRepo)(nil)
+
+func NewOAuthRepository(db *sql.DB) OAuthRepository {
+	return &oauthRepo{db: db}
+}
+
+// OAuthState is what the server keeps of a sign-in between sending the user to the provider and
+// the callback.
+type OAuthState struct {
+	Nonce        string
+	CodeVerifier string
+}
+
+type SaveStateParams struct {
+	State        string
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+}
+
+const QueryOAuthStateSave = `
+INSERT INTO oauth_states (state, provider, nonce, code_verifier, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+func (r *oauthRepo) SaveState(ctx context.Context, params SaveStateParams) error {
+	_, err := r.db.ExecContext(ctx, QueryOAuthStateSave, params.State, params.Provider, params.Nonce,
+		params.CodeVerifier, params.ExpiresAt)
+	return err
+}
+
+const QueryOAuthStateConsume = `
+DELETE FROM oauth_states
+WHERE state = $1 AND provider = $2 AND expires_at > NOW()
+RETURNING nonce, code_verifier
+`
+
+// ConsumeState deletes an unexpired state issued for the provider and returns it.
+// It returns sql.ErrNoRows if the state is unknown, expired or already used.
+func (r *oauthRepo) ConsumeState(ctx context.Context, state, provider string) (OAuthState, error) {
+	var s OAuthState
+	if err := r.db.QueryRowContext(ctx, QueryOAuthStateConsume, state, provider).
+		Scan(&s.Nonce, &s.CodeVerifier); err != nil {
+		return OAuthState{}, err
+	}
+	return s, nil
+}
+
+const QueryOAuthStatePurge = "DELETE FROM oauth_states WHERE expires_at <= NOW()"
+
+func (r *oauthRepo) PurgeStates(ctx context.Context) (int64, error) {
+	res, err := r.db.ExecContext(ctx, QueryOAuthStatePurge)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+const QueryIdentityFind = `
+SELECT id, user_id, provider, subject, email, created_at FROM identities
+WHERE provider = $1 AND subject = $2
+LIMIT 1
+`
+
+func (r *oauthRepo) FindIdentity(ctx context.Context, provider, subject string) (model.Identity, error) {
+	var identity model.Identity
+	if err := r.db.QueryRowContext(ctx, QueryIdentityFind, provider, subject).
+		Scan(&identity.ID, &identity.UserID, &identity.Provider, &identity.Subject, &identity.Email,
+			&identity.CreatedAt); err != nil {
+		return model.Identity{}, err
+	}
+	return identity, nil
+}
+
+const QueryIdentityList = `
+SELECT id, user_id, provider, subject, email, created_at FROM identities
+WHERE user_id = $1
+ORDER BY created_at
+`
+
+func (r *oauthRepo) ListIdentities(ctx context.Context, userID string) ([]model.Identity, error) {
+	rows, err := r.db.QueryContext(ctx, QueryIdentityList, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	identities := []model.Identity{}
+	for rows.Next() {
+		var identity model.Identity
+		if err := rows.Scan(&identity.ID, &identity.UserID, &identity.Provider, &identity.Subject, &identity.Email,
+			&identity.CreatedAt); err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity)
+	}
+
+	return identities, rows.Err()
+}
+
+const QueryIdentityCreate = `
+INSERT INTO identities (user_id, provider, subject, email)
+VALUES ($1, $2, $3, $4)
+`
+
+// CreateIdentity links an identity to an existing user. It returns ErrIdentityExists if the provider
+// account is already linked.
+func (r *oauthRepo) CreateIdentity(ctx context.Context, identity model.Identity) error {
+	_, err := r.db.ExecContext(ctx, QueryIdentityCreate, identity.UserID, identity.Provider, identity.Subject,
+		identity.Email)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return ErrIdentityExists
+		}
+		return err
+	}
+	return nil
+}
+
+// CreateIdentityUserParams describes a user signing up through a provider. Verified marks the email
+// as verified when the provider asserts it.
+type CreateIdentityUserParams struct {
+	Email        string
+	Name         string
+	PasswordHash string
+	Verified     bool
+	Provider     string
+	Subject      string
+}
+
+const QueryIdentityUserCreate = `
+INSERT INTO users (email, name, password_hash, verified_at)
+VALUES ($1, $2, $3, CASE WHEN $4 THEN NOW() END)
+RETURNING id, email, name, created_at, updated_at, verified_at
+`
+
+// CreateIdentityUser creates a user and links the identity in one transaction, so no user is left
+// without the identity that signed them up. It returns ErrEmailTaken if a user has the email.
+func (r *oauthRepo) CreateIdentityUser(ctx context.Context, params CreateIdentityUserParams) (user model.User,
+	err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.User{}, err
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, tx.Rollback())
+		}
+	}()
+
+	if err = tx.QueryRowContext(ctx, QueryIdentityUserCreate, params.Email, params.Name, params.PasswordHash,
+		params.Verified).
+		Scan(&user.ID, &user.Email, &user.Name, &user.CreatedAt, &user.UpdatedAt, &user.VerifiedAt); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return model.User{}, ErrEmailTaken
+		}
+		return model.User{}, fmt.Errorf("create user: %w", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, QueryIdentityCreate, user.ID, params.Provider, params.Subject,
+		params.Email); err != nil {
+		return model.User{}, fmt.Errorf("create identity: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return model.User{}, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,230 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	oauthProvider = "google"
+	oauthSubject  = "248289761001"
+	oauthUserID   = "1"
+	oauthEmail    = "jane@example.com"
+	oauthState    = "state"
+)
+
+func TestOAuthRepo_SaveState(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	params := repository.SaveStateParams{
+		State:        oauthState,
+		Provider:     oauthProvider,
+		Nonce:        "nonce",
+		CodeVerifier: "verifier",
+		ExpiresAt:    time.Now().Add(time.Minute),
+	}
+	mock.ExpectExec(repository.QueryOAuthStateSave).
+		WithArgs(params.State, params.Provider, params.Nonce, params.CodeVerifier, params.ExpiresAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := repository.NewOAuthRepository(db)
+	assert.NoError(t, repo.SaveState(context.Background(), params))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOAuthRepo_ConsumeState(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		rows    *sqlmock.Rows
+		wantErr error
+	}{
+		{name: "Issued", rows: sqlmock.NewRows([]string{"nonce", "code_verifier"}).AddRow("nonce", "verifier")},
+		{name: "Unknown or expired", rows: sqlmock.NewRows([]string{"nonce", "code_verifier"}), wantErr: sql.ErrNoRows},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			db, mock, err := sqlmock.New(sqlmockOpts)
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectQuery(repository.QueryOAuthStateConsume).
+				WithArgs(oauthState, oauthProvider).
+				WillReturnRows(tc.rows)
+
+			repo := repository.NewOAuthRepository(db)
+			state, err := repo.ConsumeState(context.Background(), oauthState, oauthProvider)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, repository.OAuthState{Nonce: "nonce", CodeVerifier: "verifier"}, state)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestOAuthRepo_PurgeStates(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(repository.QueryOAuthStatePurge).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	repo := repository.NewOAuthRepository(db)
+	purged, err := repo.PurgeStates(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOAuthRepo_FindIdentity(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(repository.QueryIdentityFind).
+		WithArgs(oauthProvider, oauthSubject).
+		WillReturnRows(sqlmock.NewRows([]string{id, "user_id", "provider", "subject", "email", createdAt}).
+			AddRow("a", oauthUserID, oauthProvider, oauthSubject, oauthEmail, time.Now()))
+
+	repo := repository.NewOAuthRepository(db)
+	identity, err := repo.FindIdentity(context.Background(), oauthProvider, oauthSubject)
+	require.NoError(t, err)
+	assert.Equal(t, oauthUserID, identity.UserID)
+	assert.Equal(t, oauthEmail, identity.Email)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOAuthRepo_ListIdentities(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(repository.QueryIdentityList).
+		WithArgs(oauthUserID).
+		WillReturnRows(sqlmock.NewRows([]string{id, "user_id", "provider", "subject", "email", createdAt}).
+			AddRow("a", oauthUserID, oauthProvider, oauthSubject, oauthEmail, time.Now()))
+
+	repo := repository.NewOAuthRepository(db)
+	identities, err := repo.ListIdentities(context.Background(), oauthUserID)
+	require.NoError(t, err)
+	require.Len(t, identities, 1)
+	assert.Equal(t, oauthProvider, identities[0].Provider)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOAuthRepo_CreateIdentity(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		execErr error
+		wantErr error
+	}{
+		{name: "Linked"},
+		{name: "Already linked", execErr: &pgconn.PgError{Code: "23505"}, wantErr: repository.ErrIdentityExists},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			db, mock, err := sqlmock.New(sqlmockOpts)
+			require.NoError(t, err)
+			defer db.Close()
+
+			identity := model.Identity{UserID: oauthUserID, Provider: oauthProvider, Subject: oauthSubject, Email: oauthEmail}
+			exec := mock.ExpectExec(repository.QueryIdentityCreate).
+				WithArgs(identity.UserID, identity.Provider, identity.Subject, identity.Email)
+			if tc.execErr != nil {
+				exec.WillReturnError(tc.execErr)
+			} else {
+				exec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			repo := repository.NewOAuthRepository(db)
+			err = repo.CreateIdentity(context.Background(), identity)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestOAuthRepo_CreateIdentityUser(t *testing.T) {
+	t.Parallel()
+	params := repository.CreateIdentityUserParams{
+		Email:        oauthEmail,
+		Name:         "Jane Doe",
+		PasswordHash: "hash",
+		Verified:     true,
+		Provider:     oauthProvider,
+		Subject:      oauthSubject,
+	}
+
+	t.Run("Created", func(t *testing.T) {
+		t.Parallel()
+		db, mock, err := sqlmock.New(sqlmockOpts)
+		require.NoError(t, err)
+		defer db.Close()
+
+		now := time.Now()
+		mock.ExpectBegin()
+		mock.ExpectQuery(repository.QueryIdentityUserCreate).
+			WithArgs(params.Email, params.Name, params.PasswordHash, params.Verified).
+			WillReturnRows(sqlmock.NewRows([]string{id, "email", "name", createdAt, updatedAt, "verified_at"}).
+				AddRow(oauthUserID, params.Email, params.Name, now, now, now))
+		mock.ExpectExec(repository.QueryIdentityCreate).
+			WithArgs(oauthUserID, params.Provider, params.Subject, params.Email).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		repo := repository.NewOAuthRepository(db)
+		user, err := repo.CreateIdentityUser(context.Background(), params)
+		require.NoError(t, err)
+		assert.Equal(t, oauthUserID, user.ID)
+		assert.NotNil(t, user.VerifiedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Email taken", func(t *testing.T) {
+		t.Parallel()
+		db, mock, err := sqlmock.New(sqlmockOpts)
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(repository.QueryIdentityUserCreate).
+			WithArgs(params.Email, params.Name, params.PasswordHash, params.Verified).
+			WillReturnError(&pgconn.PgError{Code: "23505"})
+		mock.ExpectRollback()
+
+		repo := repository.NewOAuthRepository(db)
+		_, err = repo.CreateIdentityUser(context.Background(), params)
+		assert.ErrorIs(t, err, repository.ErrEmailTaken)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,133 @@
+//go:generate mockgen -destination=mock/refresh_token_repo_mock.go -package=mock . RefreshTokenRepository
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/model"
+)
+
+type RefreshTokenRepository interface {
+	CreateRefreshToken(ctx context.Context, params CreateRefreshTokenParams) error
+	FindRefreshToken(ctx context.Context, id string) (model.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, id string) error
+	RevokeRefreshTokenFamily(ctx context.Context, familyID string) error
+	RevokeUserRefreshTokens(ctx context.Context, userID string) error
+	RevokeOtherRefreshTokens(ctx context.Context, userID, familyID string) error
+	PurgeRefreshTokens(ctx context.Context) (int64, error)
+}
+
+type refreshTokenRepo struct {
+	db *sql.DB
+}
+
+var _ RefreshTokenRepository = (*refreshTokenRepo)(nil)
+
+func NewRefreshTokenRepository(db *sql.DB) RefreshTokenRepository {
+	return &refreshTokenRepo{db: db}
+}
+
+type CreateRefreshTokenParams struct {
+	ID        string
+	UserID    string
+	FamilyID  string
+	ExpiresAt time.Time
+}
+
+const QueryRefreshTokenCreate = `
+INSERT INTO refresh_tokens (id, user_id, family_id, expires_at)
+VALUES ($1, $2, $3, $4)
+`
+
+func (r *refreshTokenRepo) CreateRefreshToken(ctx context.Context, params CreateRefreshTokenParams) error {
+	_, err := r.db.ExecContext(ctx, QueryRefreshTokenCreate, params.ID, params.UserID, params.FamilyID, params.ExpiresAt)
+	return err
+}
+
+const QueryRefreshTokenFind = `
+SELECT id, user_id, family_id, expires_at, rotated_at, revoked_at, created_at FROM refresh_tokens
+WHERE id = $1
+LIMIT 1
+`
+
+func (r *refreshTokenRepo) FindRefreshToken(ctx context.Context, id string) (model.RefreshToken, error) {
+	var token model.RefreshToken
+	if err := r.db.QueryRowContext(ctx, QueryRefreshTokenFind, id).
+		Scan(&token.ID, &token.UserID, &token.FamilyID, &token.ExpiresAt, &token.RotatedAt, &token.RevokedAt,
+			&token.CreatedAt); err != nil {
+		return model.RefreshToken{}, err
+	}
+	return token, nil
+}
+
+const QueryRefreshTokenRotate = `
+UPDATE refresh_tokens
+SET rotated_at = NOW()
+WHERE id = $1 AND rotated_at IS NULL AND revoked_at IS NULL
+`
+
+// RotateRefreshToken marks the token as used. It returns sql.ErrNoRows when the token
+// was already rotated or revoked, which happens when two requests race with the same token.
+func (r *refreshTokenRepo) RotateRefreshToken(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, QueryRefreshTokenRotate, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+const QueryRefreshTokenRevokeFamily = `
+UPDATE refresh_tokens
+SET revoked_at = NOW()
+WHERE family_id = $1 AND revoked_at IS NULL
+`
+
+func (r *refreshTokenRepo) RevokeRefreshTokenFamily(ctx context.Context, familyID string) error {
+	_, err := r.db.ExecContext(ctx, QueryRefreshTokenRevokeFamily, familyID)
+	return err
+}
+
+const QueryRefreshTokenRevokeUser = `
+UPDATE refresh_tokens
+SET revoked_at = NOW()
+WHERE user_id = $1 AND revoked_at IS NULL
+`
+
+func (r *refreshTokenRepo) RevokeUserRefreshTokens(ctx context.Context, userID string) error {
+	_, err := r.db.ExecContext(ctx, QueryRefreshTokenRevokeUser, userID)
+	return err
+}
+
+const QueryRefreshTokenRevokeOthers = `
+UPDATE refresh_tokens
+SET revoked_at = NOW()
+WHERE user_id = $1 AND family_id <> $2 AND revoked_at IS NULL
+`
+
+// RevokeOtherRefreshTokens revokes every session of the user except the given token family.
+func (r *refreshTokenRepo) RevokeOtherRefreshTokens(ctx context.Context, userID, familyID string) error {
+	_, err := r.db.ExecContext(ctx, QueryRefreshTokenRevokeOthers, userID, familyID)
+	return err
+}
+
+const QueryRefreshTokenPurge = "DELETE FROM refresh_tokens WHERE expires_at < NOW()"
+
+func (r *refreshTokenRepo) PurgeRefreshTokens(ctx context.Context) (int64, error) {
+	res, err := r.db.ExecContext(ctx, QueryRefreshTokenPurge)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
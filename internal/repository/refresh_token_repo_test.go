@@ -0,0 +1,201 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	tokenID  = "jti"
+	familyID = "family"
+	ownerID  = "1"
+)
+
+func TestRefreshTokenRepo_CreateRefreshToken(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	expiresAt := time.Now().Add(time.Hour)
+	mock.ExpectExec(repository.QueryRefreshTokenCreate).
+		WithArgs(tokenID, ownerID, familyID, expiresAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	repo := repository.NewRefreshTokenRepository(db)
+	err = repo.CreateRefreshToken(context.Background(), repository.CreateRefreshTokenParams{
+		ID:        tokenID,
+		UserID:    ownerID,
+		FamilyID:  familyID,
+		ExpiresAt: expiresAt,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshTokenRepo_FindRefreshToken(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	rtCols := []string{"id", "user_id", "family_id", "expires_at", "rotated_at", "revoked_at", "created_at"}
+
+	tests := []struct {
+		name      string
+		mockSetup func(sqlmock.Sqlmock)
+		wantErr   error
+	}{
+		{
+			name: "Token exists",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(repository.QueryRefreshTokenFind).
+					WithArgs(tokenID).
+					WillReturnRows(sqlmock.NewRows(rtCols).
+						AddRow(tokenID, ownerID, familyID, now, nil, nil, now))
+			},
+		},
+		{
+			name: "Token not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(repository.QueryRefreshTokenFind).
+					WithArgs(tokenID).
+					WillReturnRows(sqlmock.NewRows(rtCols))
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			db, mock, err := sqlmock.New(sqlmockOpts)
+			require.NoError(t, err)
+			defer db.Close()
+
+			tt.mockSetup(mock)
+
+			repo := repository.NewRefreshTokenRepository(db)
+			token, err := repo.FindRefreshToken(context.Background(), tokenID)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, model.RefreshToken{}, token)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, familyID, token.FamilyID)
+				assert.Nil(t, token.RotatedAt)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRefreshTokenRepo_RotateRefreshToken(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		affected int64
+		execErr  error
+		wantErr  error
+	}{
+		{name: "Rotated", affected: 1},
+		{name: "Already rotated", affected: 0, wantErr: sql.ErrNoRows},
+		{name: "Database error", execErr: errors.New("update failed"), wantErr: errors.New("update failed")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			db, mock, err := sqlmock.New(sqlmockOpts)
+			require.NoError(t, err)
+			defer db.Close()
+
+			exec := mock.ExpectExec(repository.QueryRefreshTokenRotate).WithArgs(tokenID)
+			if tt.execErr != nil {
+				exec.WillReturnError(tt.execErr)
+			} else {
+				exec.WillReturnResult(sqlmock.NewResult(0, tt.affected))
+			}
+
+			repo := repository.NewRefreshTokenRepository(db)
+			err = repo.RotateRefreshToken(context.Background(), tokenID)
+			if tt.wantErr != nil {
+				assert.ErrorContains(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRefreshTokenRepo_RevokeRefreshTokenFamily(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(repository.QueryRefreshTokenRevokeFamily).
+		WithArgs(familyID).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	repo := repository.NewRefreshTokenRepository(db)
+	err = repo.RevokeRefreshTokenFamily(context.Background(), familyID)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshTokenRepo_RevokeUserRefreshTokens(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(repository.QueryRefreshTokenRevokeUser).
+		WithArgs(ownerID).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	repo := repository.NewRefreshTokenRepository(db)
+	err = repo.RevokeUserRefreshTokens(context.Background(), ownerID)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshTokenRepo_RevokeOtherRefreshTokens(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(repository.QueryRefreshTokenRevokeOthers).
+		WithArgs(ownerID, familyID).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	repo := repository.NewRefreshTokenRepository(db)
+	err = repo.RevokeOtherRefreshTokens(context.Background(), ownerID, familyID)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRefreshTokenRepo_PurgeRefreshTokens(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(repository.QueryRefreshTokenPurge).
+		WillReturnResult(sqlmock.NewResult(0, 5))
+
+	repo := repository.NewRefreshTokenRepository(db)
+	purged, err := repo.PurgeRefreshTokens(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
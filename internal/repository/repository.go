@@ -3,13 +3,39 @@ package repository
 import "database/sql"
 
 type Repository struct {
-	Base BaseRepository
-	User UserRepository
+	Base         BaseRepository
+	User         UserRepository
+	RefreshToken RefreshTokenRepository
+	RevokedToken RevokedTokenRepository
+	Token        TokenRepository
+	MFA          MFARepository
+	Audit        AuditRepository
+	WebAuthn     WebAuthnRepository
+	LoginFailure LoginFailureRepository
+	OAuth        OAuthRepository
+	Client       ClientRepository
+	Role         RoleRepository
+	APIKey       APIKeyRepository
+	Session      SessionRepository
+	Export       ExportRepository
 }
 
 func NewRepository(db *sql.DB) *Repository {
 	return &Repository{
-		Base: NewBaseRepository(db),
-		User: NewUserRepository(db),
+		Base:         NewBaseRepository(db),
+		User:         NewUserRepository(db),
+		RefreshToken: NewRefreshTokenRepository(db),
+		RevokedToken: NewRevokedTokenRepository(db),
+		Token:        NewTokenRepository(db),
+		MFA:          NewMFARepository(db),
+		Audit:        NewAuditRepository(db),
+		WebAuthn:     NewWebAuthnRepository(db),
+		LoginFailure: NewLoginFailureRepository(db),
+		OAuth:        NewOAuthRepository(db),
+		Client:       NewClientRepository(db),
+		Role:         NewRoleRepository(db),
+		APIKey:       NewAPIKeyRepository(db),
+		Session:      NewSessionRepository(db),
+		Export:       NewExportRepository(db),
 	}
 }
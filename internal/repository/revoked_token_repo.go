@@ -0,0 +1,61 @@
+//go:generate mockgen -destination=mock/revoked_token_repo_mock.go -package=mock . RevokedTokenRepository
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type RevokedTokenRepository interface {
+	RevokeToken(ctx context.Context, params RevokeTokenParams) error
+	IsTokenRevoked(ctx context.Context, id string) (bool, error)
+	PurgeRevokedTokens(ctx context.Context) (int64, error)
+}
+
+type revokedTokenRepo struct {
+	db *sql.DB
+}
+
+var _ RevokedTokenRepository = (*revokedTokenRepo)(nil)
+
+func NewRevokedTokenRepository(db *sql.DB) RevokedTokenRepository {
+	return &revokedTokenRepo{db: db}
+}
+
+type RevokeTokenParams struct {
+	ID        string
+	UserID    string
+	ExpiresAt time.Time
+}
+
+const QueryRevokedTokenCreate = `
+INSERT INTO revoked_tokens (id, user_id, expires_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (id) DO NOTHING
+`
+
+func (r *revokedTokenRepo) RevokeToken(ctx context.Context, params RevokeTokenParams) error {
+	_, err := r.db.ExecContext(ctx, QueryRevokedTokenCreate, params.ID, params.UserID, params.ExpiresAt)
+	return err
+}
+
+const QueryRevokedTokenExists = "SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE id = $1)"
+
+func (r *revokedTokenRepo) IsTokenRevoked(ctx context.Context, id string) (bool, error) {
+	var revoked bool
+	if err := r.db.QueryRowContext(ctx, QueryRevokedTokenExists, id).Scan(&revoked); err != nil {
+		return false, err
+	}
+	return revoked, nil
+}
+
+const QueryRevokedTokenPurge = "DELETE FROM revoked_tokens WHERE expires_at < NOW()"
+
+func (r *revokedTokenRepo) PurgeRevokedTokens(ctx context.Context) (int64, error) {
+	res, err := r.db.ExecContext(ctx, QueryRevokedTokenPurge)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
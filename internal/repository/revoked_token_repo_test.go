@@ -0,0 +1,69 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevokedTokenRepo_RevokeToken(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	expiresAt := time.Now().Add(time.Hour)
+	mock.ExpectExec(repository.QueryRevokedTokenCreate).
+		WithArgs(tokenID, ownerID, expiresAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	repo := repository.NewRevokedTokenRepository(db)
+	err = repo.RevokeToken(context.Background(), repository.RevokeTokenParams{
+		ID:        tokenID,
+		UserID:    ownerID,
+		ExpiresAt: expiresAt,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRevokedTokenRepo_IsTokenRevoked(t *testing.T) {
+	t.Parallel()
+
+	for _, revoked := range []bool{true, false} {
+		db, mock, err := sqlmock.New(sqlmockOpts)
+		require.NoError(t, err)
+
+		mock.ExpectQuery(repository.QueryRevokedTokenExists).
+			WithArgs(tokenID).
+			WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(revoked))
+
+		repo := repository.NewRevokedTokenRepository(db)
+		got, err := repo.IsTokenRevoked(context.Background(), tokenID)
+		assert.NoError(t, err)
+		assert.Equal(t, revoked, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		db.Close()
+	}
+}
+
+func TestRevokedTokenRepo_PurgeRevokedTokens(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(repository.QueryRevokedTokenPurge).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	repo := repository.NewRevokedTokenRepository(db)
+	purged, err := repo.PurgeRevokedTokens(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
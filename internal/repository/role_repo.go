@@ -0,0 +1,107 @@
+//go:generate mockgen -destination=mock/role_repo_mock.go -package=mock . RoleRepository
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// foreignKeyViolation is the SQLSTATE of an insert referencing a row that does not exist.
+const foreignKeyViolation = "23503"
+
+var ErrUnknownRole = errors.New("unknown role")
+
+// RoleRepository stores the roles assigned to users. Roles grant permissions, which are seeded by the
+// migrations along with the roles themselves.
+type RoleRepository interface {
+	FindUserRoles(ctx context.Context, userID string) ([]string, error)
+	HasPermission(ctx context.Context, userID, permission string) (bool, error)
+	AssignRole(ctx context.Context, userID, role string) error
+	RevokeRole(ctx context.Context, userID, role string) error
+}
+
+type roleRepo struct {
+	db *sql.DB
+}
+
+var _ RoleRepository = (*roleRepo)(nil)
+
+func NewRoleRepository(db *sql.DB) RoleRepository {
+	return &roleRepo{db: db}
+}
+
+const QueryUserRolesFind = "SELECT role FROM user_roles WHERE user_id = $1 ORDER BY role"
+
+func (r *roleRepo) FindUserRoles(ctx context.Context, userID string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, QueryUserRolesFind, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	roles := []string{}
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return nil, err
+		}
+		roles = append(roles, role)
+	}
+
+	return roles, rows.Err()
+}
+
+const QueryPermissionCheck = `
+SELECT EXISTS (
+	SELECT 1 FROM user_roles ur
+	JOIN role_permissions rp ON rp.role = ur.role
+	WHERE ur.user_id = $1 AND rp.permission = $2
+)
+`
+
+// HasPermission reports whether any of the user's roles grants the permission.
+func (r *roleRepo) HasPermission(ctx context.Context, userID, permission string) (bool, error) {
+	var ok bool
+	if err := r.db.QueryRowContext(ctx, QueryPermissionCheck, userID, permission).Scan(&ok); err != nil {
+		return false, err
+	}
+	return ok, nil
+}
+
+const QueryUserRoleAssign = "INSERT INTO user_roles (user_id, role) VALUES ($1, $2) ON CONFLICT DO NOTHING"
+
+// AssignRole gives the user a role. Assigning a role the user already has does nothing.
+func (r *roleRepo) AssignRole(ctx context.Context, userID, role string) error {
+	_, err := r.db.ExecContext(ctx, QueryUserRoleAssign, userID, role)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation && pgErr.ConstraintName == "user_roles_role_fkey" {
+			return ErrUnknownRole
+		}
+		return err
+	}
+	return nil
+}
+
+const QueryUserRoleRevoke = "DELETE FROM user_roles WHERE user_id = $1 AND role = $2"
+
+// RevokeRole takes a role away from the user, returning sql.ErrNoRows if the user did not have it.
+func (r *roleRepo) RevokeRole(ctx context.Context, userID, role string) error {
+	res, err := r.db.ExecContext(ctx, QueryUserRoleRevoke, userID, role)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
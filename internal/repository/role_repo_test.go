@@ -0,0 +1,123 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	roleUserID = "1"
+	roleAdmin  = "admin"
+)
+
+func TestRoleRepo_FindUserRoles(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(repository.QueryUserRolesFind).
+		WithArgs(roleUserID).
+		WillReturnRows(sqlmock.NewRows([]string{"role"}).AddRow(roleAdmin).AddRow("support"))
+
+	repo := repository.NewRoleRepository(db)
+	roles, err := repo.FindUserRoles(context.Background(), roleUserID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{roleAdmin, "support"}, roles)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRoleRepo_HasPermission(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(repository.QueryPermissionCheck).
+		WithArgs(roleUserID, "users:read").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	repo := repository.NewRoleRepository(db)
+	ok, err := repo.HasPermission(context.Background(), roleUserID, "users:read")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRoleRepo_AssignRole(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		role    string
+		execErr error
+		wantErr error
+	}{
+		{name: "Assigned", role: roleAdmin},
+		{
+			name:    "Unknown role",
+			role:    "owner",
+			execErr: &pgconn.PgError{Code: "23503", ConstraintName: "user_roles_role_fkey"},
+			wantErr: repository.ErrUnknownRole,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			db, mock, err := sqlmock.New(sqlmockOpts)
+			require.NoError(t, err)
+			defer db.Close()
+
+			exec := mock.ExpectExec(repository.QueryUserRoleAssign).WithArgs(roleUserID, tc.role)
+			if tc.execErr != nil {
+				exec.WillReturnError(tc.execErr)
+			} else {
+				exec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			repo := repository.NewRoleRepository(db)
+			err = repo.AssignRole(context.Background(), roleUserID, tc.role)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestRoleRepo_RevokeRole(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{name: "Revoked", affected: 1},
+		{name: "Role not assigned", wantErr: sql.ErrNoRows},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			db, mock, err := sqlmock.New(sqlmockOpts)
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectExec(repository.QueryUserRoleRevoke).
+				WithArgs(roleUserID, roleAdmin).
+				WillReturnResult(sqlmock.NewResult(0, tc.affected))
+
+			repo := repository.NewRoleRepository(db)
+			err = repo.RevokeRole(context.Background(), roleUserID, roleAdmin)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,157 @@
+//go:generate mockgen -destination=mock/session_repo_mock.go -package=mock . SessionRepository
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ferdiebergado/gojeep/internal/model"
+)
+
+// SessionRepository stores where users are signed in. A session lives as long as its refresh token family.
+type SessionRepository interface {
+	CreateSession(ctx context.Context, session model.Session) error
+	TouchSession(ctx context.Context, id string) error
+	ListSessions(ctx context.Context, userID string) ([]model.Session, error)
+	ListAllSessions(ctx context.Context, userID string) ([]model.Session, error)
+	RevokeSession(ctx context.Context, userID, id string) error
+	PurgeSessions(ctx context.Context) (int64, error)
+}
+
+type sessionRepo struct {
+	db *sql.DB
+}
+
+var _ SessionRepository = (*sessionRepo)(nil)
+
+func NewSessionRepository(db *sql.DB) SessionRepository {
+	return &sessionRepo{db: db}
+}
+
+const QuerySessionCreate = `
+INSERT INTO sessions (id, user_id, user_agent, ip_address)
+VALUES ($1, $2, $3, $4)
+`
+
+func (r *sessionRepo) CreateSession(ctx context.Context, session model.Session) error {
+	_, err := r.db.ExecContext(ctx, QuerySessionCreate, session.ID, session.UserID, session.UserAgent,
+		session.IPAddress)
+	return err
+}
+
+const QuerySessionTouch = "UPDATE sessions SET last_refreshed_at = NOW() WHERE id = $1"
+
+// TouchSession records that the session's refresh token was just used.
+func (r *sessionRepo) TouchSession(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, QuerySessionTouch, id)
+	return err
+}
+
+// QuerySessionList lists the sessions that still hold a usable refresh token. Sessions signed out in any
+// way, whether by logout, password change or token reuse, have none left.
+const QuerySessionList = `
+SELECT s.id, s.user_id, s.user_agent, s.ip_address, s.created_at, s.last_refreshed_at FROM sessions s
+WHERE s.user_id = $1 AND EXISTS (
+	SELECT 1 FROM refresh_tokens rt
+	WHERE rt.family_id = s.id AND rt.rotated_at IS NULL AND rt.revoked_at IS NULL AND rt.expires_at > NOW()
+)
+ORDER BY COALESCE(s.last_refreshed_at, s.created_at) DESC
+`
+
+func (r *sessionRepo) ListSessions(ctx context.Context, userID string) ([]model.Session, error) {
+	return r.listSessions(ctx, QuerySessionList, userID)
+}
+
+// QuerySessionListAll lists every session still stored, including the signed out ones not purged yet.
+const QuerySessionListAll = `
+SELECT id, user_id, user_agent, ip_address, created_at, last_refreshed_at FROM sessions
+WHERE user_id = $1
+ORDER BY created_at
+`
+
+func (r *sessionRepo) ListAllSessions(ctx context.Context, userID string) ([]model.Session, error) {
+	return r.listSessions(ctx, QuerySessionListAll, userID)
+}
+
+func (r *sessionRepo) listSessions(ctx context.Context, query, userID string) ([]model.Session, error) {
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []model.Session{}
+	for rows.Next() {
+		var session model.Session
+		if err := rows.Scan(&session.ID, &session.UserID, &session.UserAgent, &session.IPAddress,
+			&session.CreatedAt, &session.LastRefreshedAt); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+const (
+	QuerySessionDelete = "DELETE FROM sessions WHERE id = $1 AND user_id = $2"
+	QuerySessionRevoke = `
+UPDATE refresh_tokens
+SET revoked_at = NOW()
+WHERE family_id = $1 AND revoked_at IS NULL
+`
+)
+
+// RevokeSession deletes one of the user's sessions and revokes its refresh tokens. It returns
+// sql.ErrNoRows if the user has no such session.
+func (r *sessionRepo) RevokeSession(ctx context.Context, userID, id string) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, tx.Rollback())
+		}
+	}()
+
+	res, err := tx.ExecContext(ctx, QuerySessionDelete, id, userID)
+	if err != nil {
+		return fmt.Errorf("delete session: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+
+	if _, err = tx.ExecContext(ctx, QuerySessionRevoke, id); err != nil {
+		return fmt.Errorf("revoke refresh tokens: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// QuerySessionPurge deletes sessions left without a usable refresh token. Sessions just created are
+// spared until their first refresh token has been saved.
+const QuerySessionPurge = `
+DELETE FROM sessions s
+WHERE s.created_at < NOW() - INTERVAL '1 minute' AND NOT EXISTS (
+	SELECT 1 FROM refresh_tokens rt
+	WHERE rt.family_id = s.id AND rt.revoked_at IS NULL AND rt.expires_at > NOW()
+)
+`
+
+func (r *sessionRepo) PurgeSessions(ctx context.Context) (int64, error) {
+	res, err := r.db.ExecContext(ctx, QuerySessionPurge)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,121 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	sessionID     = "family"
+	sessionUserID = "1"
+)
+
+func TestSessionRepo_CreateSession(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(repository.QuerySessionCreate).
+		WithArgs(sessionID, sessionUserID, "curl/8.0", "192.0.2.1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := repository.NewSessionRepository(db)
+	err = repo.CreateSession(context.Background(), model.Session{
+		ID: sessionID, UserID: sessionUserID, UserAgent: "curl/8.0", IPAddress: "192.0.2.1",
+	})
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepo_ListSessions(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	refreshed := time.Now()
+	mock.ExpectQuery(repository.QuerySessionList).
+		WithArgs(sessionUserID).
+		WillReturnRows(sqlmock.NewRows([]string{id, "user_id", "user_agent", "ip_address", createdAt,
+			"last_refreshed_at"}).
+			AddRow(sessionID, sessionUserID, "curl/8.0", "192.0.2.1", time.Now(), refreshed))
+
+	repo := repository.NewSessionRepository(db)
+	sessions, err := repo.ListSessions(context.Background(), sessionUserID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "curl/8.0", sessions[0].UserAgent)
+	require.NotNil(t, sessions[0].LastRefreshedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepo_ListAllSessions(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(repository.QuerySessionListAll).
+		WithArgs(sessionUserID).
+		WillReturnRows(sqlmock.NewRows([]string{id, "user_id", "user_agent", "ip_address", createdAt,
+			"last_refreshed_at"}).
+			AddRow(sessionID, sessionUserID, "curl/8.0", "192.0.2.1", time.Now(), nil))
+
+	repo := repository.NewSessionRepository(db)
+	sessions, err := repo.ListAllSessions(context.Background(), sessionUserID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Nil(t, sessions[0].LastRefreshedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSessionRepo_RevokeSession(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Revoked", func(t *testing.T) {
+		t.Parallel()
+		db, mock, err := sqlmock.New(sqlmockOpts)
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(repository.QuerySessionDelete).
+			WithArgs(sessionID, sessionUserID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(repository.QuerySessionRevoke).
+			WithArgs(sessionID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		repo := repository.NewSessionRepository(db)
+		require.NoError(t, repo.RevokeSession(context.Background(), sessionUserID, sessionID))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not the user's session", func(t *testing.T) {
+		t.Parallel()
+		db, mock, err := sqlmock.New(sqlmockOpts)
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(repository.QuerySessionDelete).
+			WithArgs(sessionID, sessionUserID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		repo := repository.NewSessionRepository(db)
+		err = repo.RevokeSession(context.Background(), sessionUserID, sessionID)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
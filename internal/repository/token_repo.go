@@ -0,0 +1,83 @@
+//go:generate mockgen -destination=mock/token_repo_mock.go -package=mock . TokenRepository
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// TokenRepository stores single-use tokens, such as password reset tokens, until they are consumed.
+type TokenRepository interface {
+	SaveToken(ctx context.Context, params SaveTokenParams) error
+	ConsumeToken(ctx context.Context, id, purpose string) (email string, err error)
+	CountTokensSince(ctx context.Context, email, purpose string, since time.Time) (int, error)
+	PurgeTokens(ctx context.Context) (int64, error)
+}
+
+type tokenRepo struct {
+	db *sql.DB
+}
+
+var _ TokenRepository = (*tokenRepo)(nil)
+
+func NewTokenRepository(db *sql.DB) TokenRepository {
+	return &tokenRepo{db: db}
+}
+
+type SaveTokenParams struct {
+	ID        string
+	Email     string
+	Purpose   string
+	ExpiresAt time.Time
+}
+
+const QueryTokenSave = `
+INSERT INTO tokens (id, email, purpose, ttl)
+VALUES ($1, $2, $3, $4)
+`
+
+func (r *tokenRepo) SaveToken(ctx context.Context, params SaveTokenParams) error {
+	_, err := r.db.ExecContext(ctx, QueryTokenSave, params.ID, params.Email, params.Purpose, params.ExpiresAt)
+	return err
+}
+
+const QueryTokenConsume = `
+DELETE FROM tokens
+WHERE id = $1 AND purpose = $2 AND ttl > NOW()
+RETURNING email
+`
+
+// ConsumeToken deletes an unexpired token and returns the email it was issued for.
+// It returns sql.ErrNoRows when the token does not exist, has expired or was already used.
+func (r *tokenRepo) ConsumeToken(ctx context.Context, id, purpose string) (string, error) {
+	var email string
+	if err := r.db.QueryRowContext(ctx, QueryTokenConsume, id, purpose).Scan(&email); err != nil {
+		return "", err
+	}
+	return email, nil
+}
+
+const QueryTokenCountSince = `
+SELECT COUNT(*) FROM tokens
+WHERE email = $1 AND purpose = $2 AND created_at > $3
+`
+
+// CountTokensSince returns how many tokens were issued to email for purpose after since.
+func (r *tokenRepo) CountTokensSince(ctx context.Context, email, purpose string, since time.Time) (int, error) {
+	var count int
+	if err := r.db.QueryRowContext(ctx, QueryTokenCountSince, email, purpose, since).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+const QueryTokenPurge = "DELETE FROM tokens WHERE ttl < NOW()"
+
+func (r *tokenRepo) PurgeTokens(ctx context.Context) (int64, error) {
+	res, err := r.db.ExecContext(ctx, QueryTokenPurge)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
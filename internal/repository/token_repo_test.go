@@ -0,0 +1,119 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	tokenEmail   = "abc@example.com"
+	tokenPurpose = "password_reset"
+)
+
+func TestTokenRepo_SaveToken(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	expiresAt := time.Now().Add(time.Hour)
+	mock.ExpectExec(repository.QueryTokenSave).
+		WithArgs(tokenID, tokenEmail, tokenPurpose, expiresAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	repo := repository.NewTokenRepository(db)
+	err = repo.SaveToken(context.Background(), repository.SaveTokenParams{
+		ID:        tokenID,
+		Email:     tokenEmail,
+		Purpose:   tokenPurpose,
+		ExpiresAt: expiresAt,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTokenRepo_ConsumeToken(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		rows      *sqlmock.Rows
+		wantEmail string
+		wantErr   error
+	}{
+		{
+			name:      "Unused token",
+			rows:      sqlmock.NewRows([]string{"email"}).AddRow(tokenEmail),
+			wantEmail: tokenEmail,
+		},
+		{
+			name:    "Used or expired token",
+			rows:    sqlmock.NewRows([]string{"email"}),
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			db, mock, err := sqlmock.New(sqlmockOpts)
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectQuery(repository.QueryTokenConsume).
+				WithArgs(tokenID, tokenPurpose).
+				WillReturnRows(tt.rows)
+
+			repo := repository.NewTokenRepository(db)
+			email, err := repo.ConsumeToken(context.Background(), tokenID, tokenPurpose)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantEmail, email)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestTokenRepo_CountTokensSince(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	since := time.Now().Add(-time.Minute)
+	mock.ExpectQuery(repository.QueryTokenCountSince).
+		WithArgs(tokenEmail, tokenPurpose, since).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	repo := repository.NewTokenRepository(db)
+	count, err := repo.CountTokensSince(context.Background(), tokenEmail, tokenPurpose, since)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTokenRepo_PurgeTokens(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(repository.QueryTokenPurge).
+		WillReturnResult(sqlmock.NewResult(0, 7))
+
+	repo := repository.NewTokenRepository(db)
+	purged, err := repo.PurgeTokens(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -4,15 +4,37 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// uniqueViolation is the Postgres error code for a unique constraint violation.
+const uniqueViolation = "23505"
+
+var ErrEmailTaken = errors.New("email already in use")
+
 type UserRepository interface {
 	CreateUser(ctx context.Context, params CreateUserParams) (model.User, error)
 	FindUserByEmail(ctx context.Context, email string) (model.User, error)
+	FindUserByID(ctx context.Context, userID string) (model.User, error)
+	UpdateUser(ctx context.Context, userID string, params UpdateUserParams) (model.User, error)
+	DeleteUser(ctx context.Context, userID string) error
+	FindDeletedUserByEmail(ctx context.Context, email string) (model.User, error)
+	RestoreUser(ctx context.Context, userID string) error
+	PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (int64, error)
+	FindUserStatus(ctx context.Context, userID string) (model.UserStatus, error)
 	VerifyUser(ctx context.Context, userID string) error
-	ListUsers(ctx context.Context) ([]model.User, error)
+	UpdatePassword(ctx context.Context, userID, passwordHash string) error
+	UpdateEmail(ctx context.Context, userID, email string) error
+	ListUsers(ctx context.Context, params ListUsersParams) ([]model.User, error)
+	SuspendUser(ctx context.Context, userID string, params SuspendUserParams) error
+	UnsuspendUser(ctx context.Context, userID string) error
+	RevokeUserTokens(ctx context.Context, userID string) error
 }
 
 type userRepo struct {
@@ -36,10 +58,15 @@ VALUES ($1, $2)
 RETURNING id, email, created_at, updated_at
 `
 
+// CreateUser inserts a new user. It returns ErrEmailTaken if a user, even one pending deletion, has the email.
 func (r *userRepo) CreateUser(ctx context.Context, params CreateUserParams) (model.User, error) {
 	var user model.User
 	if err := r.db.QueryRowContext(ctx, QueryUserCreate, params.Email, params.PasswordHash).
 		Scan(&user.ID, &user.Email, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return model.User{}, ErrEmailTaken
+		}
 		return model.User{}, err
 	}
 	return user, nil
@@ -47,7 +74,7 @@ func (r *userRepo) CreateUser(ctx context.Context, params CreateUserParams) (mod
 
 const QueryUserFindByEmail = `
 SELECT id, email, password_hash, created_at, updated_at, verified_at FROM users
-WHERE email = $1
+WHERE email = $1 AND deleted_at IS NULL
 LIMIT 1
 `
 
@@ -60,10 +87,171 @@ func (r *userRepo) FindUserByEmail(ctx context.Context, email string) (model.Use
 	return user, nil
 }
 
+const QueryUserFindByID = `
+SELECT id, email, name, password_hash, created_at, updated_at, verified_at,
+	suspended_at, suspended_until, suspended_by, suspension_reason
+FROM users
+WHERE id = $1 AND deleted_at IS NULL
+LIMIT 1
+`
+
+func (r *userRepo) FindUserByID(ctx context.Context, userID string) (model.User, error) {
+	var user model.User
+	if err := r.db.QueryRowContext(ctx, QueryUserFindByID, userID).
+		Scan(&user.ID, &user.Email, &user.Name, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt,
+			&user.VerifiedAt, &user.SuspendedAt, &user.SuspendedUntil, &user.SuspendedBy,
+			&user.SuspensionReason); err != nil {
+		return model.User{}, err
+	}
+	return user, nil
+}
+
+// UpdateUserParams holds the profile fields to change. Nil fields are left as they are.
+type UpdateUserParams struct {
+	Name *string
+}
+
+const QueryUserUpdate = `
+UPDATE users
+SET name = COALESCE($2, name), updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, email, name, created_at, updated_at, verified_at
+`
+
+func (r *userRepo) UpdateUser(ctx context.Context, userID string, params UpdateUserParams) (model.User, error) {
+	var user model.User
+	if err := r.db.QueryRowContext(ctx, QueryUserUpdate, userID, params.Name).
+		Scan(&user.ID, &user.Email, &user.Name, &user.CreatedAt, &user.UpdatedAt, &user.VerifiedAt); err != nil {
+		return model.User{}, err
+	}
+	return user, nil
+}
+
+const (
+	QueryUserDelete = `
+UPDATE users
+SET deleted_at = NOW(), tokens_revoked_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+`
+	QueryUserDeleteAPIKeys = "DELETE FROM api_keys WHERE user_id = $1"
+)
+
+// DeleteUser marks the user as deleted and cuts off every credential issued so far: refresh tokens are
+// revoked, API keys are dropped and access tokens issued before now are rejected. The row itself is
+// kept until PurgeDeletedUsers removes it.
+func (r *userRepo) DeleteUser(ctx context.Context, userID string) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, tx.Rollback())
+		}
+	}()
+
+	res, err := tx.ExecContext(ctx, QueryUserDelete, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if _, err = tx.ExecContext(ctx, QueryRefreshTokenRevokeUser, userID); err != nil {
+		return err
+	}
+
+	if _, err = tx.ExecContext(ctx, QueryUserDeleteAPIKeys, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// QueryUserFindDeletedByEmail is the only lookup of deleted users, so a login can restore the account. A
+// deleted user has no status, so the suspension is returned with the user.
+const QueryUserFindDeletedByEmail = `
+SELECT id, email, password_hash, created_at, updated_at, verified_at, deleted_at,
+	suspended_at, suspended_until, suspended_by, suspension_reason FROM users
+WHERE email = $1 AND deleted_at IS NOT NULL
+LIMIT 1
+`
+
+func (r *userRepo) FindDeletedUserByEmail(ctx context.Context, email string) (model.User, error) {
+	var user model.User
+	if err := r.db.QueryRowContext(ctx, QueryUserFindDeletedByEmail, email).
+		Scan(&user.ID, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt, &user.VerifiedAt,
+			&user.DeletedAt, &user.SuspendedAt, &user.SuspendedUntil, &user.SuspendedBy,
+			&user.SuspensionReason); err != nil {
+		return model.User{}, err
+	}
+	return user, nil
+}
+
+const QueryUserRestore = `
+UPDATE users
+SET deleted_at = NULL, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NOT NULL
+`
+
+// RestoreUser cancels the deletion of a user. Credentials revoked by DeleteUser stay revoked.
+func (r *userRepo) RestoreUser(ctx context.Context, userID string) error {
+	res, err := r.db.ExecContext(ctx, QueryUserRestore, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+const QueryUserPurgeDeleted = "DELETE FROM users WHERE deleted_at < $1"
+
+// PurgeDeletedUsers removes the users deleted before the given time, along with everything they own.
+func (r *userRepo) PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, QueryUserPurgeDeleted, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+const QueryUserFindStatus = `
+SELECT tokens_revoked_at, suspended_at, suspended_until, suspended_by, suspension_reason FROM users
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+// FindUserStatus returns what decides whether the user's credentials are honoured. A deleted user has
+// no status, so sql.ErrNoRows is returned.
+func (r *userRepo) FindUserStatus(ctx context.Context, userID string) (model.UserStatus, error) {
+	var status model.UserStatus
+	if err := r.db.QueryRowContext(ctx, QueryUserFindStatus, userID).
+		Scan(&status.TokensRevokedAt, &status.SuspendedAt, &status.SuspendedUntil, &status.SuspendedBy,
+			&status.SuspensionReason); err != nil {
+		return model.UserStatus{}, err
+	}
+	return status, nil
+}
+
 const QueryUserVerify = `
 UPDATE users
 SET verified_at = NOW()
-WHERE id = $1
+WHERE id = $1 AND deleted_at IS NULL
 `
 
 func (r *userRepo) VerifyUser(ctx context.Context, userID string) error {
@@ -76,10 +264,135 @@ func (r *userRepo) VerifyUser(ctx context.Context, userID string) error {
 	return nil
 }
 
-const QueryUserList = "SELECT id, email, verified_at, created_at, updated_at FROM users"
+const QueryUserUpdatePassword = `
+UPDATE users
+SET password_hash = $2, updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (r *userRepo) UpdatePassword(ctx context.Context, userID, passwordHash string) error {
+	res, err := r.db.ExecContext(ctx, QueryUserUpdatePassword, userID, passwordHash)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+const QueryUserUpdateEmail = `
+UPDATE users
+SET email = $2, verified_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+// UpdateEmail sets a new, already verified, email address. It returns ErrEmailTaken if another user has it.
+func (r *userRepo) UpdateEmail(ctx context.Context, userID, email string) error {
+	res, err := r.db.ExecContext(ctx, QueryUserUpdateEmail, userID, email)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return ErrEmailTaken
+		}
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// UserSort is a column the user list can be ordered by. The id breaks ties, so the order is total and
+// pages can resume after any user.
+type UserSort string
+
+const (
+	UserSortCreatedAt UserSort = "created_at"
+	UserSortEmail     UserSort = "email"
+)
+
+// UserCursor is the last user of the previous page. Only the sort column and the id are compared.
+type UserCursor struct {
+	ID        string
+	CreatedAt time.Time
+	Email     string
+}
+
+// ListUsersParams filters and pages the user list. Nil and empty filters match every user.
+type ListUsersParams struct {
+	Verified      *bool
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	EmailPrefix   string
+	Sort          UserSort
+	Desc          bool
+	After         *UserCursor
+	Limit         int
+}
+
+// QueryUserList is formatted by UserListQuery with the sort column, the comparison that skips the users
+// of earlier pages and the sort direction.
+const QueryUserList = `
+SELECT id, email, name, verified_at, suspended_at, created_at, updated_at FROM users
+WHERE deleted_at IS NULL
+AND ($1::boolean IS NULL OR (verified_at IS NOT NULL) = $1)
+AND ($2::timestamptz IS NULL OR created_at >= $2)
+AND ($3::timestamptz IS NULL OR created_at < $3)
+AND ($4 = '' OR email LIKE $4 || '%%')
+AND ($5::uuid IS NULL OR (%[1]s, id) %[2]s ($6, $5))
+ORDER BY %[1]s %[3]s, id %[3]s
+LIMIT $7
+`
 
-func (r *userRepo) ListUsers(ctx context.Context) ([]model.User, error) {
-	rows, err := r.db.QueryContext(ctx, QueryUserList)
+// UserListQuery returns the list query for a sort order. The column comes from UserSort, never from
+// user input.
+func UserListQuery(sort UserSort, desc bool) string {
+	column := string(UserSortCreatedAt)
+	if sort == UserSortEmail {
+		column = string(UserSortEmail)
+	}
+
+	op, dir := ">", "ASC"
+	if desc {
+		op, dir = "<", "DESC"
+	}
+
+	return fmt.Sprintf(QueryUserList, column, op, dir)
+}
+
+// escapeLike escapes the LIKE wildcards in s, so it matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// ListUsers returns a page of the users matching the filters, in the requested order.
+func (r *userRepo) ListUsers(ctx context.Context, params ListUsersParams) ([]model.User, error) {
+	var afterID, afterKey any
+	if params.After != nil {
+		afterID, afterKey = params.After.ID, params.After.CreatedAt
+		if params.Sort == UserSortEmail {
+			afterKey = params.After.Email
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, UserListQuery(params.Sort, params.Desc),
+		params.Verified, params.CreatedAfter, params.CreatedBefore, escapeLike(params.EmailPrefix),
+		afterID, afterKey, params.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -88,7 +401,8 @@ func (r *userRepo) ListUsers(ctx context.Context) ([]model.User, error) {
 	var users []model.User
 	for rows.Next() {
 		var user model.User
-		if err := rows.Scan(&user.ID, &user.Email, &user.VerifiedAt, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		if err := rows.Scan(&user.ID, &user.Email, &user.Name, &user.VerifiedAt, &user.SuspendedAt,
+			&user.CreatedAt, &user.UpdatedAt); err != nil {
 			return nil, err
 		}
 		users = append(users, user)
@@ -100,3 +414,94 @@ func (r *userRepo) ListUsers(ctx context.Context) ([]model.User, error) {
 
 	return users, nil
 }
+
+// SuspendUserParams describe a suspension. A nil Until suspends the user until the suspension is lifted.
+type SuspendUserParams struct {
+	Reason  string
+	Until   *time.Time
+	ActorID string
+}
+
+const (
+	QueryUserSuspend = `
+UPDATE users
+SET suspended_at = NOW(), suspended_until = $2, suspended_by = $3, suspension_reason = $4,
+	tokens_revoked_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+`
+	QueryUserUnsuspend = `
+UPDATE users
+SET suspended_at = NULL, suspended_until = NULL, suspended_by = NULL, suspension_reason = '',
+	updated_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+`
+	QueryUserRevokeTokens = `
+UPDATE users
+SET tokens_revoked_at = NOW()
+WHERE id = $1 AND deleted_at IS NULL
+`
+)
+
+// SuspendUser marks the user as suspended and signs them out everywhere, as RevokeUserTokens does.
+func (r *userRepo) SuspendUser(ctx context.Context, userID string, params SuspendUserParams) error {
+	return r.signOut(ctx, QueryUserSuspend, userID, params.Until, params.ActorID, params.Reason)
+}
+
+// UnsuspendUser lifts a suspension. Credentials revoked by SuspendUser stay revoked.
+func (r *userRepo) UnsuspendUser(ctx context.Context, userID string) error {
+	res, err := r.db.ExecContext(ctx, QueryUserUnsuspend, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// RevokeUserTokens signs the user out everywhere: refresh tokens are revoked and access tokens issued
+// before now are rejected. API keys are left alone.
+func (r *userRepo) RevokeUserTokens(ctx context.Context, userID string) error {
+	return r.signOut(ctx, QueryUserRevokeTokens, userID)
+}
+
+// signOut runs the query updating the user, whose first argument is the user id, then revokes their
+// refresh tokens in the same transaction.
+func (r *userRepo) signOut(ctx context.Context, query, userID string, args ...any) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, tx.Rollback())
+		}
+	}()
+
+	res, err := tx.ExecContext(ctx, query, append([]any{userID}, args...)...)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if _, err = tx.ExecContext(ctx, QueryRefreshTokenRevokeUser, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
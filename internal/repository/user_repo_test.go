@@ -2,6 +2,8 @@ package repository_test
 
 import (
 	"context"
+	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"testing"
 	"time"
@@ -9,6 +11,7 @@ import (
 	sqlmock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/ferdiebergado/gojeep/internal/model"
 	"github.com/ferdiebergado/gojeep/internal/repository"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -53,6 +56,7 @@ func TestUserRepo_CreateUser(t *testing.T) {
 		mockSetup     func()
 		expectedEmail string
 		expectErr     bool
+		wantErr       error
 	}{
 		{
 			name: "Successful user creation",
@@ -82,6 +86,20 @@ func TestUserRepo_CreateUser(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			name: "Email taken",
+			params: repository.CreateUserParams{
+				Email:        email1,
+				PasswordHash: passwordHash,
+			},
+			mockSetup: func() {
+				mock.ExpectQuery(repository.QueryUserCreate).
+					WithArgs(email1, passwordHash).
+					WillReturnError(&pgconn.PgError{Code: "23505"})
+			},
+			expectErr: true,
+			wantErr:   repository.ErrEmailTaken,
+		},
 		{
 			name: "Invalid row scan",
 			params: repository.CreateUserParams{
@@ -105,6 +123,9 @@ func TestUserRepo_CreateUser(t *testing.T) {
 			newUser, err := repo.CreateUser(context.Background(), tt.params)
 			if tt.expectErr {
 				assert.Error(t, err)
+				if tt.wantErr != nil {
+					assert.ErrorIs(t, err, tt.wantErr)
+				}
 				assert.Equal(t, model.User{}, newUser)
 			} else {
 				assert.NoError(t, err)
@@ -221,10 +242,55 @@ func TestUserRepo_VerifyUser(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestUserRepo_UpdatePassword(t *testing.T) {
+	t.Parallel()
+	const (
+		userID  = "1"
+		newHash = "new_hash"
+	)
+
+	tests := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{name: "Updated", affected: 1},
+		{name: "Deleted or unknown user", wantErr: sql.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			db, mock, err := sqlmock.New(sqlmockOpts)
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectExec(repository.QueryUserUpdatePassword).
+				WithArgs(userID, newHash).
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+
+			repo := repository.NewUserRepository(db)
+			err = repo.UpdatePassword(context.Background(), userID, newHash)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
 func TestUserRepo_ListUsers(t *testing.T) {
 	t.Parallel()
 
+	listCols := []string{id, email, "name", verifiedAt, "suspended_at", createdAt, updatedAt}
+	now := time.Now()
+	verified := true
+	after := &repository.UserCursor{ID: "1", CreatedAt: now, Email: "abc@example.com"}
+
 	type args struct {
+		params    repository.ListUsersParams
 		mockSetup func(sqlmock.Sqlmock)
 	}
 	tests := []struct {
@@ -236,21 +302,36 @@ func TestUserRepo_ListUsers(t *testing.T) {
 		{
 			name: "success - one user",
 			args: args{
+				params: repository.ListUsersParams{Sort: repository.UserSortCreatedAt, Limit: 10},
 				mockSetup: func(mock sqlmock.Sqlmock) {
-					rows := sqlmock.NewRows([]string{"id", "email", "verified_at", "created_at", "updated_at"}).
-						AddRow("1", "abc@example.com", time.Now(), time.Now(), time.Now())
-					mock.ExpectQuery(repository.QueryUserList).WillReturnRows(rows)
+					rows := sqlmock.NewRows(listCols).
+						AddRow("1", "abc@example.com", "Abc", now, nil, now, now)
+					mock.ExpectQuery(repository.UserListQuery(repository.UserSortCreatedAt, false)).
+						WithArgs(nil, nil, nil, "", nil, nil, 10).
+						WillReturnRows(rows)
 				},
 			},
 			wantErr: false,
 			wantLen: 1,
 		},
 		{
-			name: "success - no users",
+			name: "success - filtered page after a cursor",
 			args: args{
+				params: repository.ListUsersParams{
+					Verified:      &verified,
+					CreatedAfter:  &now,
+					CreatedBefore: &now,
+					EmailPrefix:   "a_b%",
+					Sort:          repository.UserSortEmail,
+					Desc:          true,
+					After:         after,
+					Limit:         10,
+				},
 				mockSetup: func(mock sqlmock.Sqlmock) {
-					rows := sqlmock.NewRows([]string{"id", "email", "verified_at", "created_at", "updated_at"})
-					mock.ExpectQuery(repository.QueryUserList).WillReturnRows(rows)
+					rows := sqlmock.NewRows(listCols)
+					mock.ExpectQuery(repository.UserListQuery(repository.UserSortEmail, true)).
+						WithArgs(&verified, &now, &now, `a\_b\%`, after.ID, after.Email, 10).
+						WillReturnRows(rows)
 				},
 			},
 			wantErr: false,
@@ -259,8 +340,10 @@ func TestUserRepo_ListUsers(t *testing.T) {
 		{
 			name: "query error",
 			args: args{
+				params: repository.ListUsersParams{Sort: repository.UserSortCreatedAt, Limit: 10},
 				mockSetup: func(mock sqlmock.Sqlmock) {
-					mock.ExpectQuery(repository.QueryUserList).WillReturnError(errors.New("query failed"))
+					mock.ExpectQuery(repository.UserListQuery(repository.UserSortCreatedAt, false)).
+						WillReturnError(errors.New("query failed"))
 				},
 			},
 			wantErr: true,
@@ -269,10 +352,12 @@ func TestUserRepo_ListUsers(t *testing.T) {
 		{
 			name: "scan error - invalid column type",
 			args: args{
+				params: repository.ListUsersParams{Sort: repository.UserSortCreatedAt, Limit: 10},
 				mockSetup: func(mock sqlmock.Sqlmock) {
-					rows := sqlmock.NewRows([]string{"id", "email", "verified_at", "created_at", "updated_at"}).
-						AddRow(1, 12345, "invalid_time", "invalid_time", "invalid_time") // email and timestamps wrong
-					mock.ExpectQuery(repository.QueryUserList).WillReturnRows(rows)
+					rows := sqlmock.NewRows(listCols).
+						AddRow(1, 12345, "Abc", "invalid_time", nil, "invalid_time", "invalid_time") // timestamps wrong
+					mock.ExpectQuery(repository.UserListQuery(repository.UserSortCreatedAt, false)).
+						WillReturnRows(rows)
 				},
 			},
 			wantErr: true,
@@ -294,7 +379,7 @@ func TestUserRepo_ListUsers(t *testing.T) {
 
 			tt.args.mockSetup(mock)
 
-			users, err := repo.ListUsers(ctx)
+			users, err := repo.ListUsers(ctx, tt.args.params)
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
@@ -302,6 +387,389 @@ func TestUserRepo_ListUsers(t *testing.T) {
 
 			assert.NoError(t, err)
 			assert.Len(t, users, tt.wantLen)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUserListQuery(t *testing.T) {
+	t.Parallel()
+
+	asc := repository.UserListQuery(repository.UserSortEmail, false)
+	assert.Contains(t, asc, "(email, id) > ($6, $5)")
+	assert.Contains(t, asc, "ORDER BY email ASC, id ASC")
+	assert.Contains(t, asc, "email LIKE $4 || '%')")
+
+	desc := repository.UserListQuery("password_hash", true)
+	assert.Contains(t, desc, "(created_at, id) < ($6, $5)")
+	assert.Contains(t, desc, "ORDER BY created_at DESC, id DESC")
+}
+
+func TestUserRepo_FindUserByID(t *testing.T) {
+	t.Parallel()
+	const userID = "1"
+
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(repository.QueryUserFindByID).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{id, email, "name", passwordHash, createdAt, updatedAt, verifiedAt,
+			"suspended_at", "suspended_until", "suspended_by", "suspension_reason"}).
+			AddRow(userID, "abc@example.com", "Abc", "hashed", now, now, &now, &now, nil, "2", "spam"))
+
+	repo := repository.NewUserRepository(db)
+	user, err := repo.FindUserByID(context.Background(), userID)
+	require.NoError(t, err)
+	assert.Equal(t, userID, user.ID)
+	assert.Equal(t, "Abc", user.Name)
+	assert.Equal(t, "hashed", user.PasswordHash)
+	assert.NotNil(t, user.VerifiedAt)
+	assert.NotNil(t, user.SuspendedAt)
+	assert.Equal(t, "spam", user.SuspensionReason)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepo_UpdateUser(t *testing.T) {
+	t.Parallel()
+	const (
+		userID = "1"
+		name   = "New Name"
+	)
+
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	newName := name
+	mock.ExpectQuery(repository.QueryUserUpdate).
+		WithArgs(userID, &newName).
+		WillReturnRows(sqlmock.NewRows([]string{id, email, "name", createdAt, updatedAt, verifiedAt}).
+			AddRow(userID, "abc@example.com", name, now, now, nil))
+
+	repo := repository.NewUserRepository(db)
+	user, err := repo.UpdateUser(context.Background(), userID, repository.UpdateUserParams{Name: &newName})
+	require.NoError(t, err)
+	assert.Equal(t, name, user.Name)
+	assert.Nil(t, user.VerifiedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepo_DeleteUser(t *testing.T) {
+	t.Parallel()
+	const userID = "1"
+
+	t.Run("Deleted", func(t *testing.T) {
+		t.Parallel()
+		db, mock, err := sqlmock.New(sqlmockOpts)
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(repository.QueryUserDelete).
+			WithArgs(userID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(repository.QueryRefreshTokenRevokeUser).
+			WithArgs(userID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(repository.QueryUserDeleteAPIKeys).
+			WithArgs(userID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		repo := repository.NewUserRepository(db)
+		require.NoError(t, repo.DeleteUser(context.Background(), userID))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		t.Parallel()
+		db, mock, err := sqlmock.New(sqlmockOpts)
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(repository.QueryUserDelete).
+			WithArgs(userID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		repo := repository.NewUserRepository(db)
+		err = repo.DeleteUser(context.Background(), userID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUserRepo_SignOut(t *testing.T) {
+	t.Parallel()
+	const userID = "1"
+
+	until := time.Now().Add(time.Hour)
+	suspension := repository.SuspendUserParams{Reason: "spam", Until: &until, ActorID: "2"}
+
+	tests := []struct {
+		name  string
+		query string
+		args  []driver.Value
+		call  func(repository.UserRepository) error
+	}{
+		{
+			name:  "SuspendUser",
+			query: repository.QueryUserSuspend,
+			args:  []driver.Value{userID, &until, "2", "spam"},
+			call: func(repo repository.UserRepository) error {
+				return repo.SuspendUser(context.Background(), userID, suspension)
+			},
+		},
+		{
+			name:  "RevokeUserTokens",
+			query: repository.QueryUserRevokeTokens,
+			args:  []driver.Value{userID},
+			call: func(repo repository.UserRepository) error {
+				return repo.RevokeUserTokens(context.Background(), userID)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("Signed out", func(t *testing.T) {
+				t.Parallel()
+				db, mock, err := sqlmock.New(sqlmockOpts)
+				require.NoError(t, err)
+				defer db.Close()
+
+				mock.ExpectBegin()
+				mock.ExpectExec(tt.query).
+					WithArgs(tt.args...).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(repository.QueryRefreshTokenRevokeUser).
+					WithArgs(userID).
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+
+				require.NoError(t, tt.call(repository.NewUserRepository(db)))
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+
+			t.Run("Not found", func(t *testing.T) {
+				t.Parallel()
+				db, mock, err := sqlmock.New(sqlmockOpts)
+				require.NoError(t, err)
+				defer db.Close()
+
+				mock.ExpectBegin()
+				mock.ExpectExec(tt.query).
+					WithArgs(tt.args...).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+
+				assert.ErrorIs(t, tt.call(repository.NewUserRepository(db)), sql.ErrNoRows)
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+		})
+	}
+}
+
+func TestUserRepo_UnsuspendUser(t *testing.T) {
+	t.Parallel()
+	const userID = "1"
+
+	tests := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{name: "Unsuspended", affected: 1},
+		{name: "Not found", affected: 0, wantErr: sql.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			db, mock, err := sqlmock.New(sqlmockOpts)
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectExec(repository.QueryUserUnsuspend).
+				WithArgs(userID).
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+
+			repo := repository.NewUserRepository(db)
+			err = repo.UnsuspendUser(context.Background(), userID)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUserRepo_RestoreUser(t *testing.T) {
+	t.Parallel()
+	const userID = "1"
+
+	testCases := []struct {
+		name     string
+		affected int64
+		wantErr  error
+	}{
+		{name: "Restored", affected: 1},
+		{name: "Not deleted", affected: 0, wantErr: sql.ErrNoRows},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			db, mock, err := sqlmock.New(sqlmockOpts)
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectExec(repository.QueryUserRestore).
+				WithArgs(userID).
+				WillReturnResult(sqlmock.NewResult(0, tc.affected))
+
+			repo := repository.NewUserRepository(db)
+			err = repo.RestoreUser(context.Background(), userID)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestUserRepo_PurgeDeletedUsers(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	deletedBefore := time.Now().Add(-time.Hour)
+	mock.ExpectExec(repository.QueryUserPurgeDeleted).
+		WithArgs(deletedBefore).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	repo := repository.NewUserRepository(db)
+	purged, err := repo.PurgeDeletedUsers(context.Background(), deletedBefore)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUserRepo_FindUserStatus(t *testing.T) {
+	t.Parallel()
+	const userID = "1"
+
+	t.Run("Found", func(t *testing.T) {
+		t.Parallel()
+		db, mock, err := sqlmock.New(sqlmockOpts)
+		require.NoError(t, err)
+		defer db.Close()
+
+		now := time.Now()
+		mock.ExpectQuery(repository.QueryUserFindStatus).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows([]string{"tokens_revoked_at", "suspended_at", "suspended_until",
+				"suspended_by", "suspension_reason"}).
+				AddRow(now, now, nil, "2", "spam"))
+
+		repo := repository.NewUserRepository(db)
+		status, err := repo.FindUserStatus(context.Background(), userID)
+		require.NoError(t, err)
+		assert.NotNil(t, status.TokensRevokedAt)
+		assert.NotNil(t, status.SuspendedAt)
+		assert.Nil(t, status.SuspendedUntil)
+		require.NotNil(t, status.SuspendedBy)
+		assert.Equal(t, "2", *status.SuspendedBy)
+		assert.Equal(t, "spam", status.SuspensionReason)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Deleted", func(t *testing.T) {
+		t.Parallel()
+		db, mock, err := sqlmock.New(sqlmockOpts)
+		require.NoError(t, err)
+		defer db.Close()
+
+		mock.ExpectQuery(repository.QueryUserFindStatus).
+			WithArgs(userID).
+			WillReturnError(sql.ErrNoRows)
+
+		repo := repository.NewUserRepository(db)
+		_, err = repo.FindUserStatus(context.Background(), userID)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestUserRepo_UpdateEmail(t *testing.T) {
+	t.Parallel()
+	const (
+		userID   = "1"
+		newEmail = "new@example.com"
+	)
+
+	testCases := []struct {
+		name      string
+		mockSetup func(mock sqlmock.Sqlmock)
+		wantErr   error
+	}{
+		{
+			name: "Updated",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(repository.QueryUserUpdateEmail).
+					WithArgs(userID, newEmail).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Email taken",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(repository.QueryUserUpdateEmail).
+					WithArgs(userID, newEmail).
+					WillReturnError(&pgconn.PgError{Code: "23505"})
+			},
+			wantErr: repository.ErrEmailTaken,
+		},
+		{
+			name: "User not found",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(repository.QueryUserUpdateEmail).
+					WithArgs(userID, newEmail).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			wantErr: sql.ErrNoRows,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			db, mock, err := sqlmock.New(sqlmockOpts)
+			require.NoError(t, err)
+			defer db.Close()
+
+			tc.mockSetup(mock)
+
+			repo := repository.NewUserRepository(db)
+			err = repo.UpdateEmail(context.Background(), userID, newEmail)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
 		})
 	}
 }
@@ -0,0 +1,151 @@
+//go:generate mockgen -destination=mock/webauthn_repo_mock.go -package=mock . WebAuthnRepository
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type WebAuthnRepository interface {
+	CreateCredential(ctx context.Context, cred model.Credential) error
+	FindCredential(ctx context.Context, id string) (model.Credential, error)
+	ListCredentialIDs(ctx context.Context, userID string) ([]string, error)
+	UpdateCredentialSignCount(ctx context.Context, id string, signCount uint32) error
+	SaveChallenge(ctx context.Context, params SaveChallengeParams) error
+	ConsumeChallenge(ctx context.Context, challenge, ceremony string) (userID string, err error)
+	PurgeChallenges(ctx context.Context) (int64, error)
+}
+
+var ErrCredentialExists = errors.New("credential already registered")
+
+type webAuthnRepo struct {
+	db *sql.DB
+}
+
+var _ WebAuthnRepository = (*webAuthnRepo)(nil)
+
+func NewWebAuthnRepository(db *sql.DB) WebAuthnRepository {
+	return &webAuthnRepo{db: db}
+}
+
+const QueryCredentialCreate = `
+INSERT INTO credentials (id, user_id, public_key, sign_count)
+VALUES ($1, $2, $3, $4)
+`
+
+// CreateCredential stores a new credential. It returns ErrCredentialExists if the credential ID is taken.
+func (r *webAuthnRepo) CreateCredential(ctx context.Context, cred model.Credential) error {
+	_, err := r.db.ExecContext(ctx, QueryCredentialCreate, cred.ID, cred.UserID, cred.PublicKey, int64(cred.SignCount))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return ErrCredentialExists
+		}
+		return err
+	}
+	return nil
+}
+
+const QueryCredentialFind = `
+SELECT id, user_id, public_key, sign_count, created_at, last_used_at FROM credentials
+WHERE id = $1
+LIMIT 1
+`
+
+func (r *webAuthnRepo) FindCredential(ctx context.Context, id string) (model.Credential, error) {
+	var cred model.Credential
+	var signCount int64
+	if err := r.db.QueryRowContext(ctx, QueryCredentialFind, id).
+		Scan(&cred.ID, &cred.UserID, &cred.PublicKey, &signCount, &cred.CreatedAt, &cred.LastUsedAt); err != nil {
+		return model.Credential{}, err
+	}
+	cred.SignCount = uint32(signCount) //nolint:gosec // stored from a uint32
+	return cred, nil
+}
+
+const QueryCredentialListIDs = "SELECT id FROM credentials WHERE user_id = $1"
+
+func (r *webAuthnRepo) ListCredentialIDs(ctx context.Context, userID string) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, QueryCredentialListIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+const QueryCredentialUpdateSignCount = `
+UPDATE credentials
+SET sign_count = $2, last_used_at = NOW()
+WHERE id = $1
+`
+
+func (r *webAuthnRepo) UpdateCredentialSignCount(ctx context.Context, id string, signCount uint32) error {
+	_, err := r.db.ExecContext(ctx, QueryCredentialUpdateSignCount, id, int64(signCount))
+	return err
+}
+
+// SaveChallengeParams describes an issued challenge. UserID is empty for login ceremonies, where the
+// user is only known from the credential.
+type SaveChallengeParams struct {
+	Challenge string
+	UserID    string
+	Ceremony  string
+	ExpiresAt time.Time
+}
+
+const QueryChallengeSave = `
+INSERT INTO webauthn_challenges (challenge, user_id, ceremony, expires_at)
+VALUES ($1, NULLIF($2, '')::uuid, $3, $4)
+`
+
+func (r *webAuthnRepo) SaveChallenge(ctx context.Context, params SaveChallengeParams) error {
+	_, err := r.db.ExecContext(ctx, QueryChallengeSave, params.Challenge, params.UserID, params.Ceremony,
+		params.ExpiresAt)
+	return err
+}
+
+const QueryChallengeConsume = `
+DELETE FROM webauthn_challenges
+WHERE challenge = $1 AND ceremony = $2 AND expires_at > NOW()
+RETURNING COALESCE(user_id::text, '')
+`
+
+// ConsumeChallenge deletes an unexpired challenge and returns the user it was issued to, if any.
+// It returns sql.ErrNoRows if the challenge is unknown, expired or already used.
+func (r *webAuthnRepo) ConsumeChallenge(ctx context.Context, challenge, ceremony string) (string, error) {
+	var userID string
+	if err := r.db.QueryRowContext(ctx, QueryChallengeConsume, challenge, ceremony).Scan(&userID); err != nil {
+		return "", err
+	}
+	return userID, nil
+}
+
+const QueryChallengePurge = "DELETE FROM webauthn_challenges WHERE expires_at <= NOW()"
+
+func (r *webAuthnRepo) PurgeChallenges(ctx context.Context) (int64, error) {
+	res, err := r.db.ExecContext(ctx, QueryChallengePurge)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
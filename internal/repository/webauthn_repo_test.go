@@ -0,0 +1,190 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	credentialID     = "Y3JlZGVudGlhbA"
+	webAuthnUserID   = "1"
+	webAuthnCeremony = "login"
+)
+
+func TestWebAuthnRepo_CreateCredential(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		execErr error
+		wantErr error
+	}{
+		{name: "Created"},
+		{name: "Duplicate credential", execErr: &pgconn.PgError{Code: "23505"}, wantErr: repository.ErrCredentialExists},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			db, mock, err := sqlmock.New(sqlmockOpts)
+			require.NoError(t, err)
+			defer db.Close()
+
+			cred := model.Credential{ID: credentialID, UserID: webAuthnUserID, PublicKey: []byte("key"), SignCount: 3}
+			exec := mock.ExpectExec(repository.QueryCredentialCreate).
+				WithArgs(cred.ID, cred.UserID, cred.PublicKey, int64(cred.SignCount))
+			if tc.execErr != nil {
+				exec.WillReturnError(tc.execErr)
+			} else {
+				exec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			repo := repository.NewWebAuthnRepository(db)
+			err = repo.CreateCredential(context.Background(), cred)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWebAuthnRepo_FindCredential(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	mock.ExpectQuery(repository.QueryCredentialFind).
+		WithArgs(credentialID).
+		WillReturnRows(sqlmock.NewRows([]string{id, "user_id", "public_key", "sign_count", createdAt, "last_used_at"}).
+			AddRow(credentialID, webAuthnUserID, []byte("key"), 7, now, nil))
+
+	repo := repository.NewWebAuthnRepository(db)
+	cred, err := repo.FindCredential(context.Background(), credentialID)
+	require.NoError(t, err)
+	assert.Equal(t, webAuthnUserID, cred.UserID)
+	assert.Equal(t, []byte("key"), cred.PublicKey)
+	assert.Equal(t, uint32(7), cred.SignCount)
+	assert.Nil(t, cred.LastUsedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebAuthnRepo_ListCredentialIDs(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery(repository.QueryCredentialListIDs).
+		WithArgs(webAuthnUserID).
+		WillReturnRows(sqlmock.NewRows([]string{id}).AddRow("a").AddRow("b"))
+
+	repo := repository.NewWebAuthnRepository(db)
+	ids, err := repo.ListCredentialIDs(context.Background(), webAuthnUserID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, ids)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebAuthnRepo_UpdateCredentialSignCount(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(repository.QueryCredentialUpdateSignCount).
+		WithArgs(credentialID, int64(8)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := repository.NewWebAuthnRepository(db)
+	err = repo.UpdateCredentialSignCount(context.Background(), credentialID, 8)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebAuthnRepo_SaveChallenge(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	params := repository.SaveChallengeParams{
+		Challenge: "challenge",
+		Ceremony:  webAuthnCeremony,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+	mock.ExpectExec(repository.QueryChallengeSave).
+		WithArgs(params.Challenge, "", params.Ceremony, params.ExpiresAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := repository.NewWebAuthnRepository(db)
+	err = repo.SaveChallenge(context.Background(), params)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWebAuthnRepo_ConsumeChallenge(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		rows    *sqlmock.Rows
+		wantID  string
+		wantErr error
+	}{
+		{name: "Issued to a user", rows: sqlmock.NewRows([]string{"user_id"}).AddRow(webAuthnUserID), wantID: webAuthnUserID},
+		{name: "Unknown or expired", rows: sqlmock.NewRows([]string{"user_id"}), wantErr: sql.ErrNoRows},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			db, mock, err := sqlmock.New(sqlmockOpts)
+			require.NoError(t, err)
+			defer db.Close()
+
+			mock.ExpectQuery(repository.QueryChallengeConsume).
+				WithArgs("challenge", webAuthnCeremony).
+				WillReturnRows(tc.rows)
+
+			repo := repository.NewWebAuthnRepository(db)
+			userID, err := repo.ConsumeChallenge(context.Background(), "challenge", webAuthnCeremony)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.wantID, userID)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestWebAuthnRepo_PurgeChallenges(t *testing.T) {
+	t.Parallel()
+	db, mock, err := sqlmock.New(sqlmockOpts)
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(repository.QueryChallengePurge).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	repo := repository.NewWebAuthnRepository(db)
+	purged, err := repo.PurgeChallenges(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), purged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,298 @@
+//go:generate mockgen -destination=mock/admin_service_mock.go -package=mock . AdminService
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+)
+
+// AdminService lets administrators find users and act on their accounts. The actorID of an action is the
+// administrator taking it, which is recorded with its audit event.
+type AdminService interface {
+	ListUsers(ctx context.Context, params ListUsersParams) (UserPage, error)
+	GetUser(ctx context.Context, userID string) (UserDetail, error)
+	VerifyUser(ctx context.Context, userID, actorID string) error
+	SuspendUser(ctx context.Context, userID string, params SuspendUserParams) error
+	UnsuspendUser(ctx context.Context, userID, actorID string) error
+	SignOutUser(ctx context.Context, userID, actorID string) error
+}
+
+type AdminServiceDeps struct {
+	UserRepo   repository.UserRepository
+	RoleRepo   repository.RoleRepository
+	AuditRepo  repository.AuditRepository
+	Revocation RevocationService
+}
+
+type adminService struct {
+	userRepo   repository.UserRepository
+	roleRepo   repository.RoleRepository
+	auditRepo  repository.AuditRepository
+	revocation RevocationService
+}
+
+var _ AdminService = (*adminService)(nil)
+
+// Audit events of the actions administrators take on users.
+const (
+	eventUserVerifiedByAdmin = "user_verified_by_admin"
+	eventUserSuspended       = "user_suspended"
+	eventUserUnsuspended     = "user_unsuspended"
+	eventUserSignedOut       = "user_signed_out"
+)
+
+// Page sizes of the user list. A limit of zero gets the default; larger limits are capped.
+const (
+	defaultUserPageSize = 50
+	maxUserPageSize     = 100
+)
+
+var (
+	ErrInvalidUserSort   = errors.New("invalid user sort")
+	ErrInvalidCursor     = errors.New("invalid cursor")
+	ErrSuspendSelf       = errors.New("cannot suspend yourself")
+	ErrSuspensionExpired = errors.New("suspension ends in the past")
+)
+
+func NewAdminService(deps *AdminServiceDeps) AdminService {
+	return &adminService{
+		userRepo:   deps.UserRepo,
+		roleRepo:   deps.RoleRepo,
+		auditRepo:  deps.AuditRepo,
+		revocation: deps.Revocation,
+	}
+}
+
+// ListUsersParams filter and page the user list. Sort is a column, created_at or email, with a leading
+// "-" for descending order; it defaults to the newest users first. Cursor is the NextCursor of the
+// previous page.
+type ListUsersParams struct {
+	Verified      *bool
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	EmailPrefix   string
+	Sort          string
+	Cursor        string
+	Limit         int
+}
+
+// UserPage is a page of users. NextCursor is empty on the last page.
+type UserPage struct {
+	Users      []model.User
+	NextCursor string
+}
+
+// UserDetail is a user with the roles they hold.
+type UserDetail struct {
+	model.User
+	Roles []string
+}
+
+// ListUsers returns a page of the users matching the filters. Pages are keyed on the last user shown, so
+// users created while paging neither shift nor repeat the results.
+func (s *adminService) ListUsers(ctx context.Context, params ListUsersParams) (UserPage, error) {
+	sort, desc, err := parseUserSort(params.Sort)
+	if err != nil {
+		return UserPage{}, err
+	}
+
+	after, err := decodeUserCursor(params.Cursor)
+	if err != nil {
+		return UserPage{}, err
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultUserPageSize
+	}
+	limit = min(limit, maxUserPageSize)
+
+	// One more user than asked for tells whether there is a next page.
+	users, err := s.userRepo.ListUsers(ctx, repository.ListUsersParams{
+		Verified:      params.Verified,
+		CreatedAfter:  params.CreatedAfter,
+		CreatedBefore: params.CreatedBefore,
+		EmailPrefix:   params.EmailPrefix,
+		Sort:          sort,
+		Desc:          desc,
+		After:         after,
+		Limit:         limit + 1,
+	})
+	if err != nil {
+		return UserPage{}, fmt.Errorf("list users: %w", err)
+	}
+
+	if len(users) <= limit {
+		return UserPage{Users: users}, nil
+	}
+
+	users = users[:limit]
+	next, err := encodeUserCursor(users[limit-1])
+	if err != nil {
+		return UserPage{}, err
+	}
+	return UserPage{Users: users, NextCursor: next}, nil
+}
+
+func parseUserSort(sort string) (repository.UserSort, bool, error) {
+	if sort == "" {
+		return repository.UserSortCreatedAt, true, nil
+	}
+
+	column, desc := strings.CutPrefix(sort, "-")
+	switch repository.UserSort(column) {
+	case repository.UserSortCreatedAt, repository.UserSortEmail:
+		return repository.UserSort(column), desc, nil
+	default:
+		return "", false, ErrInvalidUserSort
+	}
+}
+
+// A cursor is the URL-safe encoding of the last user of a page, so it works for any sort.
+func encodeUserCursor(user model.User) (string, error) {
+	cursor := repository.UserCursor{ID: user.ID, CreatedAt: user.CreatedAt, Email: user.Email}
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeUserCursor(cursor string) (*repository.UserCursor, error) {
+	// No cursor means the first page.
+	if cursor == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var after repository.UserCursor
+	if err := json.Unmarshal(data, &after); err != nil || after.ID == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &after, nil
+}
+
+func (s *adminService) GetUser(ctx context.Context, userID string) (UserDetail, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return UserDetail{}, err
+	}
+
+	roles, err := s.roleRepo.FindUserRoles(ctx, userID)
+	if err != nil {
+		return UserDetail{}, fmt.Errorf("find roles of user %s: %w", userID, err)
+	}
+
+	return UserDetail{User: user, Roles: roles}, nil
+}
+
+// VerifyUser marks the user's email as verified, for users who cannot receive the verification email.
+// Users already verified keep their verification time.
+func (s *adminService) VerifyUser(ctx context.Context, userID, actorID string) error {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.VerifiedAt != nil {
+		return nil
+	}
+
+	if err := s.userRepo.VerifyUser(ctx, userID); err != nil {
+		return fmt.Errorf("verify user %s: %w", userID, err)
+	}
+
+	return s.record(ctx, userID, actorID, eventUserVerifiedByAdmin)
+}
+
+// SuspendUserParams describe a suspension. ActorID is the administrator suspending the user. A nil Until
+// suspends the user until the suspension is lifted.
+type SuspendUserParams struct {
+	Reason  string
+	Until   *time.Time
+	ActorID string
+}
+
+// SuspendUser suspends the user and signs them out everywhere. Until it ends or is lifted, the user can
+// neither log in nor use their tokens and API keys.
+func (s *adminService) SuspendUser(ctx context.Context, userID string, params SuspendUserParams) error {
+	if userID == params.ActorID {
+		return ErrSuspendSelf
+	}
+
+	if params.Until != nil && !params.Until.After(time.Now()) {
+		return ErrSuspensionExpired
+	}
+
+	suspension := repository.SuspendUserParams{
+		Reason:  params.Reason,
+		Until:   params.Until,
+		ActorID: params.ActorID,
+	}
+	if err := s.userRepo.SuspendUser(ctx, userID, suspension); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("suspend user %s: %w", userID, err)
+	}
+	s.revocation.ForgetUser(userID)
+
+	return s.record(ctx, userID, params.ActorID, eventUserSuspended)
+}
+
+func (s *adminService) UnsuspendUser(ctx context.Context, userID, actorID string) error {
+	if err := s.userRepo.UnsuspendUser(ctx, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("unsuspend user %s: %w", userID, err)
+	}
+	s.revocation.ForgetUser(userID)
+
+	return s.record(ctx, userID, actorID, eventUserUnsuspended)
+}
+
+// SignOutUser ends every session of the user. Their access tokens are rejected at once and their refresh
+// tokens revoked; API keys keep working.
+func (s *adminService) SignOutUser(ctx context.Context, userID, actorID string) error {
+	if err := s.userRepo.RevokeUserTokens(ctx, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("sign out user %s: %w", userID, err)
+	}
+	s.revocation.ForgetUser(userID)
+
+	return s.record(ctx, userID, actorID, eventUserSignedOut)
+}
+
+func (s *adminService) findUser(ctx context.Context, userID string) (model.User, error) {
+	user, err := s.userRepo.FindUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.User{}, ErrUserNotFound
+		}
+		return model.User{}, fmt.Errorf("find user %s: %w", userID, err)
+	}
+	return user, nil
+}
+
+func (s *adminService) record(ctx context.Context, userID, actorID, event string) error {
+	if err := s.auditRepo.CreateAuditEventByActor(ctx, userID, actorID, event); err != nil {
+		return fmt.Errorf("record %s: %w", event, err)
+	}
+	return nil
+}
@@ -0,0 +1,333 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+	"github.com/ferdiebergado/gojeep/internal/repository/mock"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	svcMock "github.com/ferdiebergado/gojeep/internal/service/mock"
+)
+
+const (
+	adminTargetID = "1"
+	adminActorID  = "2"
+)
+
+type adminMocks struct {
+	users      *mock.MockUserRepository
+	roles      *mock.MockRoleRepository
+	audit      *mock.MockAuditRepository
+	revocation *svcMock.MockRevocationService
+}
+
+func newAdminService(t *testing.T) (service.AdminService, adminMocks) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	m := adminMocks{
+		users:      mock.NewMockUserRepository(ctrl),
+		roles:      mock.NewMockRoleRepository(ctrl),
+		audit:      mock.NewMockAuditRepository(ctrl),
+		revocation: svcMock.NewMockRevocationService(ctrl),
+	}
+	svc := service.NewAdminService(&service.AdminServiceDeps{
+		UserRepo:   m.users,
+		RoleRepo:   m.roles,
+		AuditRepo:  m.audit,
+		Revocation: m.revocation,
+	})
+	return svc, m
+}
+
+func TestAdminService_ListUsers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Defaults to the newest users first", func(t *testing.T) {
+		t.Parallel()
+		svc, m := newAdminService(t)
+		users := []model.User{{Model: model.Model{ID: "1"}}}
+		m.users.EXPECT().ListUsers(gomock.Any(), repository.ListUsersParams{
+			Sort:  repository.UserSortCreatedAt,
+			Desc:  true,
+			Limit: 51,
+		}).Return(users, nil)
+
+		page, err := svc.ListUsers(context.Background(), service.ListUsersParams{})
+		require.NoError(t, err)
+		assert.Equal(t, users, page.Users)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("Pages through the users", func(t *testing.T) {
+		t.Parallel()
+		svc, m := newAdminService(t)
+		created := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+		first := []model.User{
+			{Model: model.Model{ID: "1", CreatedAt: created}, Email: "a@example.com"},
+			{Model: model.Model{ID: "2", CreatedAt: created}, Email: "b@example.com"},
+			{Model: model.Model{ID: "3", CreatedAt: created}, Email: "c@example.com"},
+		}
+		verified := true
+		params := service.ListUsersParams{Verified: &verified, EmailPrefix: "a", Sort: "email", Limit: 2}
+
+		m.users.EXPECT().ListUsers(gomock.Any(), repository.ListUsersParams{
+			Verified:    &verified,
+			EmailPrefix: "a",
+			Sort:        repository.UserSortEmail,
+			Limit:       3,
+		}).Return(first, nil)
+
+		page, err := svc.ListUsers(context.Background(), params)
+		require.NoError(t, err)
+		assert.Equal(t, first[:2], page.Users)
+		require.NotEmpty(t, page.NextCursor)
+
+		m.users.EXPECT().ListUsers(gomock.Any(), repository.ListUsersParams{
+			Verified:    &verified,
+			EmailPrefix: "a",
+			Sort:        repository.UserSortEmail,
+			After:       &repository.UserCursor{ID: "2", CreatedAt: created, Email: "b@example.com"},
+			Limit:       3,
+		}).Return(first[2:], nil)
+
+		params.Cursor = page.NextCursor
+		page, err = svc.ListUsers(context.Background(), params)
+		require.NoError(t, err)
+		assert.Equal(t, first[2:], page.Users)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("Caps the page size", func(t *testing.T) {
+		t.Parallel()
+		svc, m := newAdminService(t)
+		m.users.EXPECT().ListUsers(gomock.Any(), repository.ListUsersParams{
+			Sort:  repository.UserSortCreatedAt,
+			Limit: 101,
+		}).Return(nil, nil)
+
+		_, err := svc.ListUsers(context.Background(), service.ListUsersParams{Sort: "created_at", Limit: 1000})
+		require.NoError(t, err)
+	})
+
+	t.Run("Unknown sort", func(t *testing.T) {
+		t.Parallel()
+		svc, _ := newAdminService(t)
+		_, err := svc.ListUsers(context.Background(), service.ListUsersParams{Sort: "-password_hash"})
+		assert.ErrorIs(t, err, service.ErrInvalidUserSort)
+	})
+
+	t.Run("Malformed cursor", func(t *testing.T) {
+		t.Parallel()
+		svc, _ := newAdminService(t)
+		_, err := svc.ListUsers(context.Background(), service.ListUsersParams{Cursor: "not a cursor"})
+		assert.ErrorIs(t, err, service.ErrInvalidCursor)
+	})
+}
+
+func TestAdminService_GetUser(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Found", func(t *testing.T) {
+		t.Parallel()
+		svc, m := newAdminService(t)
+		user := model.User{Model: model.Model{ID: adminTargetID}, Email: "abc@example.com"}
+		m.users.EXPECT().FindUserByID(gomock.Any(), adminTargetID).Return(user, nil)
+		m.roles.EXPECT().FindUserRoles(gomock.Any(), adminTargetID).Return([]string{"admin"}, nil)
+
+		detail, err := svc.GetUser(context.Background(), adminTargetID)
+		require.NoError(t, err)
+		assert.Equal(t, service.UserDetail{User: user, Roles: []string{"admin"}}, detail)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		t.Parallel()
+		svc, m := newAdminService(t)
+		m.users.EXPECT().FindUserByID(gomock.Any(), adminTargetID).Return(model.User{}, sql.ErrNoRows)
+
+		_, err := svc.GetUser(context.Background(), adminTargetID)
+		assert.ErrorIs(t, err, service.ErrUserNotFound)
+	})
+}
+
+func TestAdminService_VerifyUser(t *testing.T) {
+	t.Parallel()
+	verifiedAt := time.Now()
+
+	tests := []struct {
+		name    string
+		setup   func(m adminMocks)
+		wantErr error
+	}{
+		{
+			name: "Unverified",
+			setup: func(m adminMocks) {
+				m.users.EXPECT().FindUserByID(gomock.Any(), adminTargetID).
+					Return(model.User{Model: model.Model{ID: adminTargetID}}, nil)
+				m.users.EXPECT().VerifyUser(gomock.Any(), adminTargetID).Return(nil)
+				m.audit.EXPECT().CreateAuditEventByActor(gomock.Any(), adminTargetID, adminActorID, "user_verified_by_admin").
+					Return(nil)
+			},
+		},
+		{
+			name: "Already verified",
+			setup: func(m adminMocks) {
+				m.users.EXPECT().FindUserByID(gomock.Any(), adminTargetID).
+					Return(model.User{Model: model.Model{ID: adminTargetID}, VerifiedAt: &verifiedAt}, nil)
+			},
+		},
+		{
+			name: "Not found",
+			setup: func(m adminMocks) {
+				m.users.EXPECT().FindUserByID(gomock.Any(), adminTargetID).Return(model.User{}, sql.ErrNoRows)
+			},
+			wantErr: service.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			svc, m := newAdminService(t)
+			tt.setup(m)
+
+			err := svc.VerifyUser(context.Background(), adminTargetID, adminActorID)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAdminService_AccountActions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		event  string
+		expect func(m adminMocks) *gomock.Call
+		call   func(svc service.AdminService) error
+	}{
+		{
+			name:  "UnsuspendUser",
+			event: "user_unsuspended",
+			expect: func(m adminMocks) *gomock.Call {
+				return m.users.EXPECT().UnsuspendUser(gomock.Any(), adminTargetID)
+			},
+			call: func(svc service.AdminService) error {
+				return svc.UnsuspendUser(context.Background(), adminTargetID, adminActorID)
+			},
+		},
+		{
+			name:  "SignOutUser",
+			event: "user_signed_out",
+			expect: func(m adminMocks) *gomock.Call {
+				return m.users.EXPECT().RevokeUserTokens(gomock.Any(), adminTargetID)
+			},
+			call: func(svc service.AdminService) error {
+				return svc.SignOutUser(context.Background(), adminTargetID, adminActorID)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("Done", func(t *testing.T) {
+				t.Parallel()
+				svc, m := newAdminService(t)
+				tt.expect(m).Return(nil)
+				m.revocation.EXPECT().ForgetUser(adminTargetID)
+				m.audit.EXPECT().CreateAuditEventByActor(gomock.Any(), adminTargetID, adminActorID, tt.event).Return(nil)
+
+				assert.NoError(t, tt.call(svc))
+			})
+
+			t.Run("Not found", func(t *testing.T) {
+				t.Parallel()
+				svc, m := newAdminService(t)
+				tt.expect(m).Return(sql.ErrNoRows)
+
+				assert.ErrorIs(t, tt.call(svc), service.ErrUserNotFound)
+			})
+		})
+	}
+}
+
+func TestAdminService_SuspendUser(t *testing.T) {
+	t.Parallel()
+	until := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+	params := service.SuspendUserParams{Reason: "spam", Until: &until, ActorID: adminActorID}
+
+	tests := []struct {
+		name    string
+		userID  string
+		params  service.SuspendUserParams
+		setup   func(m adminMocks)
+		wantErr error
+	}{
+		{
+			name:   "Suspended",
+			userID: adminTargetID,
+			params: params,
+			setup: func(m adminMocks) {
+				m.users.EXPECT().SuspendUser(gomock.Any(), adminTargetID, repository.SuspendUserParams{
+					Reason:  "spam",
+					Until:   &until,
+					ActorID: adminActorID,
+				}).Return(nil)
+				m.revocation.EXPECT().ForgetUser(adminTargetID)
+				m.audit.EXPECT().CreateAuditEventByActor(gomock.Any(), adminTargetID, adminActorID, "user_suspended").Return(nil)
+			},
+		},
+		{
+			name:   "Not found",
+			userID: adminTargetID,
+			params: params,
+			setup: func(m adminMocks) {
+				m.users.EXPECT().SuspendUser(gomock.Any(), adminTargetID, gomock.Any()).Return(sql.ErrNoRows)
+			},
+			wantErr: service.ErrUserNotFound,
+		},
+		{
+			name:    "Self",
+			userID:  adminActorID,
+			params:  params,
+			setup:   func(adminMocks) {},
+			wantErr: service.ErrSuspendSelf,
+		},
+		{
+			name:    "Ends in the past",
+			userID:  adminTargetID,
+			params:  service.SuspendUserParams{Reason: "spam", Until: &past, ActorID: adminActorID},
+			setup:   func(adminMocks) {},
+			wantErr: service.ErrSuspensionExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			svc, m := newAdminService(t)
+			tt.setup(m)
+
+			err := svc.SuspendUser(context.Background(), tt.userID, tt.params)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
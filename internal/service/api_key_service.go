@@ -0,0 +1,155 @@
+//go:generate mockgen -destination=mock/api_key_service_mock.go -package=mock . APIKeyService
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/pkg/security"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+)
+
+// APIKeyService manages the API keys users create for scripts and CI jobs, and authenticates requests
+// made with them.
+type APIKeyService interface {
+	CreateKey(ctx context.Context, userID string, params CreateAPIKeyParams) (NewAPIKey, error)
+	ListKeys(ctx context.Context, userID string) ([]model.APIKey, error)
+	RevokeKey(ctx context.Context, userID, keyID string) error
+	Authenticate(ctx context.Context, key string) (model.APIKey, error)
+}
+
+type APIKeyServiceDeps struct {
+	Repo repository.APIKeyRepository
+}
+
+type apiKeyService struct {
+	repo repository.APIKeyRepository
+}
+
+var _ APIKeyService = (*apiKeyService)(nil)
+
+// An API key reads gjp_<prefix>_<secret>. The prefix is hex so the separator cannot occur in it.
+const (
+	apiKeyTag       = "gjp"
+	apiKeyPrefixLen = 6
+	apiKeySecretLen = 32
+)
+
+var (
+	ErrInvalidAPIKey  = errors.New("invalid api key")
+	ErrAPIKeyNotFound = errors.New("api key not found")
+)
+
+func NewAPIKeyService(deps *APIKeyServiceDeps) APIKeyService {
+	return &apiKeyService{repo: deps.Repo}
+}
+
+// CreateAPIKeyParams describe a new key. A zero ExpiresIn makes a key that does not expire.
+type CreateAPIKeyParams struct {
+	Name      string
+	Scopes    []string
+	ExpiresIn time.Duration
+}
+
+// NewAPIKey is a key just created. Key is the only time the full key is available.
+type NewAPIKey struct {
+	Key string
+	model.APIKey
+}
+
+func (s *apiKeyService) CreateKey(ctx context.Context, userID string, params CreateAPIKeyParams) (NewAPIKey, error) {
+	prefixBytes, err := security.GenerateRandomBytes(apiKeyPrefixLen)
+	if err != nil {
+		return NewAPIKey{}, fmt.Errorf("generate api key prefix: %w", err)
+	}
+
+	secret, err := security.GenerateRandomBytes(apiKeySecretLen)
+	if err != nil {
+		return NewAPIKey{}, fmt.Errorf("generate api key secret: %w", err)
+	}
+
+	prefix := hex.EncodeToString(prefixBytes)
+	key := apiKeyTag + "_" + prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+
+	var expiresAt *time.Time
+	if params.ExpiresIn > 0 {
+		t := time.Now().Add(params.ExpiresIn)
+		expiresAt = &t
+	}
+
+	scopes := params.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	created, err := s.repo.CreateAPIKey(ctx, repository.CreateAPIKeyParams{
+		UserID:    userID,
+		Name:      params.Name,
+		Prefix:    prefix,
+		KeyHash:   security.HashToken(key),
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return NewAPIKey{}, fmt.Errorf("save api key: %w", err)
+	}
+
+	return NewAPIKey{Key: key, APIKey: created}, nil
+}
+
+func (s *apiKeyService) ListKeys(ctx context.Context, userID string) ([]model.APIKey, error) {
+	keys, err := s.repo.ListAPIKeys(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list api keys of user %s: %w", userID, err)
+	}
+	return keys, nil
+}
+
+func (s *apiKeyService) RevokeKey(ctx context.Context, userID, keyID string) error {
+	if err := s.repo.DeleteAPIKey(ctx, userID, keyID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrAPIKeyNotFound
+		}
+		return fmt.Errorf("delete api key %s: %w", keyID, err)
+	}
+	return nil
+}
+
+// Authenticate returns the stored key matching a presented key and records its use. Unknown, mismatched
+// and expired keys all fail with ErrInvalidAPIKey.
+func (s *apiKeyService) Authenticate(ctx context.Context, key string) (model.APIKey, error) {
+	parts := strings.SplitN(key, "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyTag {
+		return model.APIKey{}, ErrInvalidAPIKey
+	}
+
+	stored, err := s.repo.FindAPIKeyByPrefix(ctx, parts[1])
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.APIKey{}, ErrInvalidAPIKey
+		}
+		return model.APIKey{}, fmt.Errorf("find api key: %w", err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(security.HashToken(key)), []byte(stored.KeyHash)) != 1 {
+		return model.APIKey{}, ErrInvalidAPIKey
+	}
+
+	if stored.ExpiresAt != nil && !stored.ExpiresAt.After(time.Now()) {
+		return model.APIKey{}, ErrInvalidAPIKey
+	}
+
+	if err := s.repo.TouchAPIKey(ctx, stored.ID); err != nil {
+		return model.APIKey{}, fmt.Errorf("record api key use: %w", err)
+	}
+
+	return stored, nil
+}
@@ -0,0 +1,99 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/pkg/security"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+	"github.com/ferdiebergado/gojeep/internal/repository/mock"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const apiKeyUserID = "1"
+
+func TestAPIKeyService_CreateAndAuthenticate(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	repo := mock.NewMockAPIKeyRepository(ctrl)
+	svc := service.NewAPIKeyService(&service.APIKeyServiceDeps{Repo: repo})
+	ctx := context.Background()
+
+	var saved repository.CreateAPIKeyParams
+	repo.EXPECT().CreateAPIKey(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, params repository.CreateAPIKeyParams) (model.APIKey, error) {
+			saved = params
+			return model.APIKey{ID: "key-1", UserID: params.UserID, Prefix: params.Prefix,
+				KeyHash: params.KeyHash, Scopes: params.Scopes, ExpiresAt: params.ExpiresAt}, nil
+		})
+
+	created, err := svc.CreateKey(ctx, apiKeyUserID, service.CreateAPIKeyParams{
+		Name:      "ci",
+		Scopes:    []string{"users:read"},
+		ExpiresIn: 24 * time.Hour,
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(created.Key, "gjp_"+saved.Prefix+"_"))
+	assert.Equal(t, security.HashToken(created.Key), saved.KeyHash)
+	assert.NotContains(t, saved.KeyHash, created.Key)
+	require.NotNil(t, saved.ExpiresAt)
+	assert.InDelta(t, (24 * time.Hour).Seconds(), time.Until(*saved.ExpiresAt).Seconds(), 1)
+
+	repo.EXPECT().FindAPIKeyByPrefix(ctx, saved.Prefix).Return(created.APIKey, nil)
+	repo.EXPECT().TouchAPIKey(ctx, "key-1").Return(nil)
+
+	key, err := svc.Authenticate(ctx, created.Key)
+	require.NoError(t, err)
+	assert.Equal(t, apiKeyUserID, key.UserID)
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	t.Parallel()
+	const key = "gjp_0a1b2c3d4e5f_secret"
+	expired := time.Now().Add(-time.Minute)
+
+	testCases := []struct {
+		name    string
+		key     string
+		stored  model.APIKey
+		findErr error
+	}{
+		{name: "Malformed key", key: "not-a-key"},
+		{name: "Unknown prefix", key: key, findErr: sql.ErrNoRows},
+		{name: "Wrong secret", key: key, stored: model.APIKey{KeyHash: security.HashToken("gjp_0a1b2c3d4e5f_other")}},
+		{name: "Expired", key: key, stored: model.APIKey{KeyHash: security.HashToken(key), ExpiresAt: &expired}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			repo := mock.NewMockAPIKeyRepository(ctrl)
+			if strings.HasPrefix(tc.key, "gjp_") {
+				repo.EXPECT().FindAPIKeyByPrefix(gomock.Any(), "0a1b2c3d4e5f").Return(tc.stored, tc.findErr)
+			}
+
+			svc := service.NewAPIKeyService(&service.APIKeyServiceDeps{Repo: repo})
+			_, err := svc.Authenticate(context.Background(), tc.key)
+			assert.ErrorIs(t, err, service.ErrInvalidAPIKey)
+		})
+	}
+}
+
+func TestAPIKeyService_RevokeKey(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	repo := mock.NewMockAPIKeyRepository(ctrl)
+	repo.EXPECT().DeleteAPIKey(gomock.Any(), apiKeyUserID, "key-1").Return(sql.ErrNoRows)
+
+	svc := service.NewAPIKeyService(&service.APIKeyServiceDeps{Repo: repo})
+	err := svc.RevokeKey(context.Background(), apiKeyUserID, "key-1")
+	assert.ErrorIs(t, err, service.ErrAPIKeyNotFound)
+}
@@ -1,4 +1,4 @@
-//go:generate mockgen -destination=mock/user_service_mock.go -package=mock . UserService
+//go:generate mockgen -destination=mock/auth_service_mock.go -package=mock . AuthService
 package service
 
 import (
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"log/slog"
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/ferdiebergado/gojeep/internal/config"
@@ -20,40 +21,95 @@ import (
 type AuthService interface {
 	RegisterUser(ctx context.Context, params RegisterUserParams) (model.User, error)
 	VerifyUser(ctx context.Context, token string) error
-	LoginUser(ctx context.Context, params LoginUserParams) (accessToken, refreshToken string, err error)
+	ResendVerification(ctx context.Context, email string) error
+	LoginUser(ctx context.Context, params LoginUserParams) (LoginResult, error)
+	VerifyMFA(ctx context.Context, params VerifyMFAParams) (accessToken, refreshToken string, err error)
+	CompleteLogin(ctx context.Context, user model.User) (LoginResult, error)
+	StartSession(ctx context.Context, userID string) (LoginResult, error)
+	RefreshToken(ctx context.Context, token string) (accessToken, refreshToken string, err error)
+	LogoutUser(ctx context.Context, params LogoutUserParams) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, params ResetPasswordParams) error
+	ChangePassword(ctx context.Context, params ChangePasswordParams) error
+	RequestEmailChange(ctx context.Context, params RequestEmailChangeParams) error
+	ConfirmEmailChange(ctx context.Context, token string) error
 }
 
 type AuthServiceDeps struct {
-	Repo   repository.UserRepository
-	Hasher security.Hasher
-	Signer security.Signer
-	Mailer email.Mailer
-	Cfg    *config.Config
+	Repo             repository.UserRepository
+	RefreshTokenRepo repository.RefreshTokenRepository
+	RevokedTokenRepo repository.RevokedTokenRepository
+	TokenRepo        repository.TokenRepository
+	RoleRepo         repository.RoleRepository
+	SessionRepo      repository.SessionRepository
+	MFA              MFAService
+	Lockout          LockoutService
+	Revocation       RevocationService
+	Hasher           security.Hasher
+	Signer           security.Signer
+	Mailer           email.Mailer
+	Cfg              *config.Config
 }
 
 type authService struct {
-	repo   repository.UserRepository
-	hasher security.Hasher
-	signer security.Signer
-	mailer email.Mailer
-	cfg    *config.Config
+	repo             repository.UserRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	revokedTokenRepo repository.RevokedTokenRepository
+	tokenRepo        repository.TokenRepository
+	roleRepo         repository.RoleRepository
+	sessionRepo      repository.SessionRepository
+	mfa              MFAService
+	lockout          LockoutService
+	revocation       RevocationService
+	hasher           security.Hasher
+	signer           security.Signer
+	mailer           email.Mailer
+	cfg              *config.Config
+
+	// dummyHash is verified against when a login names an unknown email, so the login takes as long
+	// as one for a registered email. It is created on first use with the current Argon2 parameters.
+	dummyHash     string
+	dummyHashErr  error
+	dummyHashOnce sync.Once
 }
 
 var _ AuthService = (*authService)(nil)
+
+const (
+	purposeVerification  = "verification"
+	purposePasswordReset = "password_reset"
+	purposeEmailChange   = "email_change"
+	purposeMFAChallenge  = "mfa_challenge"
+)
+
+// dummyPasswordLen is the number of random bytes in the password of the dummy hash.
+const dummyPasswordLen = 16
+
 var (
 	ErrUserNotFound    = errors.New("user not found")
 	ErrUserNotVerified = errors.New("email not verified")
 	ErrUserExists      = errors.New("user already exists")
 	ErrInvalidToken    = errors.New("invalid token")
+	ErrTokenReused     = errors.New("refresh token reused")
+	ErrWrongPassword   = errors.New("current password is incorrect")
+	ErrUserSuspended   = errors.New("user suspended")
 )
 
 func NewAuthService(deps *AuthServiceDeps) AuthService {
 	return &authService{
-		repo:   deps.Repo,
-		hasher: deps.Hasher,
-		mailer: deps.Mailer,
-		signer: deps.Signer,
-		cfg:    deps.Cfg,
+		repo:             deps.Repo,
+		refreshTokenRepo: deps.RefreshTokenRepo,
+		revokedTokenRepo: deps.RevokedTokenRepo,
+		tokenRepo:        deps.TokenRepo,
+		roleRepo:         deps.RoleRepo,
+		sessionRepo:      deps.SessionRepo,
+		mfa:              deps.MFA,
+		lockout:          deps.Lockout,
+		revocation:       deps.Revocation,
+		hasher:           deps.Hasher,
+		mailer:           deps.Mailer,
+		signer:           deps.Signer,
+		cfg:              deps.Cfg,
 	}
 }
 
@@ -66,15 +122,97 @@ func (p *RegisterUserParams) LogValue() slog.Value {
 	return slog.AnyValue(nil)
 }
 
+// LoginUserParams holds the credentials of a login. IPAddress is the client's address, which failed
+// logins are also counted against.
 type LoginUserParams struct {
-	Email    string
-	Password string
+	Email     string
+	Password  string
+	IPAddress string
 }
 
 func (p *LoginUserParams) LogValue() slog.Value {
 	return slog.GroupValue(
 		slog.String("email", "*"),
 		slog.String("password", "*"),
+		slog.String("ip_address", p.IPAddress),
+	)
+}
+
+// LoginResult holds the session tokens, or only MFAToken when the user must still pass VerifyMFA.
+type LoginResult struct {
+	AccessToken  string
+	RefreshToken string
+	MFAToken     string
+}
+
+type VerifyMFAParams struct {
+	Token string
+	Code  string
+}
+
+func (p *VerifyMFAParams) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("token", "*"),
+		slog.String("code", "*"),
+	)
+}
+
+type LogoutUserParams struct {
+	RefreshToken string
+	AccessToken  string
+	AllSessions  bool
+}
+
+func (p *LogoutUserParams) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("refresh_token", "*"),
+		slog.String("access_token", "*"),
+		slog.Bool("all_sessions", p.AllSessions),
+	)
+}
+
+type ResetPasswordParams struct {
+	Token    string
+	Password string
+}
+
+func (p *ResetPasswordParams) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("token", "*"),
+		slog.String("password", "*"),
+	)
+}
+
+// ChangePasswordParams is a password change by a signed-in user. RefreshToken identifies the
+// session to keep; when it is empty or invalid every session is signed out.
+type ChangePasswordParams struct {
+	UserID          string
+	CurrentPassword string
+	NewPassword     string
+	RefreshToken    string
+}
+
+func (p *ChangePasswordParams) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("user_id", p.UserID),
+		slog.String("current_password", "*"),
+		slog.String("new_password", "*"),
+		slog.String("refresh_token", "*"),
+	)
+}
+
+// RequestEmailChangeParams starts an email change by a signed-in user, who must confirm the current password.
+type RequestEmailChangeParams struct {
+	UserID   string
+	NewEmail string
+	Password string
+}
+
+func (p *RequestEmailChangeParams) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("user_id", p.UserID),
+		slog.String("new_email", "*"),
+		slog.String("password", "*"),
 	)
 }
 
@@ -85,26 +223,58 @@ func (s *authService) RegisterUser(ctx context.Context, params RegisterUserParam
 		return model.User{}, err
 	}
 
-	if !reflect.DeepEqual(existing, model.User{}) {
+	exists := !reflect.DeepEqual(existing, model.User{})
+	enumerationSafe := s.cfg.Registration != nil && s.cfg.Registration.EnumerationSafe
+	if exists && !enumerationSafe {
 		return model.User{}, ErrUserExists
 	}
 
+	// In enumeration-safe mode the password is hashed even if the email is taken, so both cases take
+	// equally long.
 	hash, err := s.hasher.Hash(params.Password)
 	if err != nil {
 		return model.User{}, fmt.Errorf("hasher hash: %w", err)
 	}
 
+	if exists {
+		go s.sendAccountExistsEmail(existing)
+		return model.User{}, ErrUserExists
+	}
+
 	user, err := s.repo.CreateUser(ctx, repository.CreateUserParams{Email: email, PasswordHash: hash})
 	if err != nil {
+		// The email belongs to an account in its deletion grace period.
+		if errors.Is(err, repository.ErrEmailTaken) {
+			return model.User{}, ErrUserExists
+		}
 		return model.User{}, fmt.Errorf("create user %s: %w", email, err)
 	}
 
-	go s.sendVerificationEmail(user)
+	go s.sendVerificationEmail(context.WithoutCancel(ctx), user)
 
 	return user, nil
 }
 
-func (s *authService) sendVerificationEmail(user model.User) {
+// sendAccountExistsEmail tells the owner of an email that someone tried to register with it.
+func (s *authService) sendAccountExistsEmail(user model.User) {
+	slog.Info("Sending account exists email...")
+
+	const (
+		title   = "Registration attempt"
+		subject = "Someone tried to register with your email"
+	)
+
+	data := map[string]string{
+		"Title":  title,
+		"Header": subject,
+	}
+	if err := s.mailer.SendHTML([]string{user.Email}, subject, "account_exists", data); err != nil {
+		slog.Error("failed to send email", "reason", err)
+		return
+	}
+}
+
+func (s *authService) sendVerificationEmail(ctx context.Context, user model.User) {
 	slog.Info("Sending verification email...")
 
 	const (
@@ -112,14 +282,32 @@ func (s *authService) sendVerificationEmail(user model.User) {
 		subject = "Verify your email"
 	)
 
+	id, err := security.GenerateRandomBytesEncoded(s.cfg.JWT.JTILen)
+	if err != nil {
+		slog.Error("failed to generate token id", "reason", err)
+		return
+	}
+
 	audience := s.cfg.Server.URL + "/auth/verify"
 	ttl := time.Duration(s.cfg.Email.Options.VerifyTTL) * time.Second
-	token, err := s.signer.Sign(user.ID, []string{audience}, ttl)
+	token, err := s.signer.SignWithID(security.PurposeVerify, id, user.ID, []string{audience}, ttl)
 	if err != nil {
 		slog.Error("failed to generate token", "reason", err)
 		return
 	}
 
+	// The token is recorded so that resend requests can be throttled.
+	params := repository.SaveTokenParams{
+		ID:        id,
+		Email:     user.Email,
+		Purpose:   purposeVerification,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.tokenRepo.SaveToken(ctx, params); err != nil {
+		slog.Error("failed to save token", "reason", err)
+		return
+	}
+
 	data := map[string]string{
 		"Title":  title,
 		"Header": subject,
@@ -132,47 +320,772 @@ func (s *authService) sendVerificationEmail(user model.User) {
 }
 
 func (s *authService) VerifyUser(ctx context.Context, token string) error {
-	userID, err := s.signer.Verify(token)
+	claims, err := s.signer.Verify(token, security.PurposeVerify, s.cfg.Server.URL+"/auth/verify")
 	if err != nil {
 		return ErrInvalidToken
 	}
 
-	return s.repo.VerifyUser(ctx, userID)
+	return s.repo.VerifyUser(ctx, claims.Subject)
 }
 
-func (s *authService) LoginUser(ctx context.Context, params LoginUserParams) (accessToken, refreshToken string, err error) {
-	user, err := s.repo.FindUserByEmail(ctx, params.Email)
+// ResendVerification emails a new verification link to an unverified user, at most once per resend interval.
+// It succeeds whether or not a link was sent so the response does not reveal which emails are registered.
+func (s *authService) ResendVerification(ctx context.Context, email string) error {
+	user, err := s.repo.FindUserByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return "", "", ErrUserNotFound
+			slog.Info("Verification resend requested for unknown email")
+			return nil
 		}
-		return "", "", err
+		return err
 	}
 
-	if user.VerifiedAt == nil {
-		return "", "", ErrUserNotVerified
+	if user.VerifiedAt != nil {
+		slog.Info("Verification resend requested for verified user", "user_id", user.ID)
+		return nil
+	}
+
+	interval := time.Duration(s.cfg.Email.Options.ResendInterval) * time.Second
+	sent, err := s.tokenRepo.CountTokensSince(ctx, email, purposeVerification, time.Now().Add(-interval))
+	if err != nil {
+		return fmt.Errorf("count verification tokens: %w", err)
+	}
+
+	if sent > 0 {
+		slog.Info("Verification resend throttled", "user_id", user.ID)
+		return nil
+	}
+
+	go s.sendVerificationEmail(context.WithoutCancel(ctx), user)
+
+	return nil
+}
+
+func (s *authService) LoginUser(ctx context.Context, params LoginUserParams) (LoginResult, error) {
+	if err := s.lockout.Check(ctx, params.Email, params.IPAddress); err != nil {
+		return LoginResult{}, err
+	}
+
+	user, err := s.findLoginUser(ctx, params.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			if err := s.verifyDummyHash(params.Password); err != nil {
+				return LoginResult{}, err
+			}
+			return LoginResult{}, s.loginFailed(ctx, params)
+		}
+		return LoginResult{}, err
 	}
 
 	ok, err := s.hasher.Verify(params.Password, user.PasswordHash)
+	if err != nil {
+		return LoginResult{}, err
+	}
+
+	if !ok {
+		return LoginResult{}, s.loginFailed(ctx, params)
+	}
+
+	// Only checked once the password is known to be right, so it does not reveal unverified accounts.
+	if user.VerifiedAt == nil {
+		return LoginResult{}, ErrUserNotVerified
+	}
+
+	if err := s.lockout.Reset(ctx, params.Email); err != nil {
+		return LoginResult{}, err
+	}
+
+	if user.DeletedAt != nil {
+		// A deleted user has no status for checkSuspension to find, so the suspension is checked here.
+		if user.Suspended(time.Now()) {
+			return LoginResult{}, ErrUserSuspended
+		}
+	} else {
+		// The password of a deleted account cannot be updated. It is rehashed at the first login after the
+		// account is restored.
+		s.upgradePasswordHash(ctx, user, params.Password)
+	}
+
+	return s.CompleteLogin(ctx, user)
+}
+
+// findLoginUser finds the user logging in, including a deleted user still in the grace period, whose
+// account the login restores.
+func (s *authService) findLoginUser(ctx context.Context, email string) (model.User, error) {
+	user, err := s.repo.FindUserByEmail(ctx, email)
+	if err == nil || !errors.Is(err, sql.ErrNoRows) {
+		return user, err
+	}
+	return s.repo.FindDeletedUserByEmail(ctx, email)
+}
+
+// CompleteLogin signs in a user whose first factor has been checked. It returns an MFA challenge if the
+// user enabled MFA, and starts a session otherwise.
+func (s *authService) CompleteLogin(ctx context.Context, user model.User) (LoginResult, error) {
+	mfaEnabled, err := s.mfa.IsEnabled(ctx, user.ID)
+	if err != nil {
+		return LoginResult{}, err
+	}
+
+	if mfaEnabled {
+		// Checked here too, so a suspended user is not asked for a code first.
+		if err := s.checkSuspension(ctx, user.ID); err != nil {
+			return LoginResult{}, err
+		}
+
+		mfaToken, err := s.issueMFAChallenge(ctx, user)
+		if err != nil {
+			return LoginResult{}, err
+		}
+		return LoginResult{MFAToken: mfaToken}, nil
+	}
+
+	return s.StartSession(ctx, user.ID)
+}
+
+// verifyDummyHash spends the time of a password verification on a login of an unknown email.
+func (s *authService) verifyDummyHash(password string) error {
+	s.dummyHashOnce.Do(func() {
+		secret, err := security.GenerateRandomBytesEncoded(dummyPasswordLen)
+		if err != nil {
+			s.dummyHashErr = fmt.Errorf("generate dummy password: %w", err)
+			return
+		}
+		s.dummyHash, s.dummyHashErr = s.hasher.Hash(secret)
+	})
+	if s.dummyHashErr != nil {
+		return s.dummyHashErr
+	}
+
+	if _, err := s.hasher.Verify(password, s.dummyHash); err != nil {
+		return fmt.Errorf("verify dummy hash: %w", err)
+	}
+	return nil
+}
+
+// loginFailed counts a failed login towards a lockout and returns the error for wrong credentials.
+func (s *authService) loginFailed(ctx context.Context, params LoginUserParams) error {
+	if err := s.lockout.RecordFailure(ctx, params.Email, params.IPAddress); err != nil {
+		return err
+	}
+	return ErrUserNotFound
+}
+
+// VerifyMFA completes a login that returned an MFA challenge. The challenge is single-use, so a wrong
+// code requires logging in again, and wrong codes across challenges lock the user out.
+func (s *authService) VerifyMFA(ctx context.Context,
+	params VerifyMFAParams) (accessToken, refreshToken string, err error) {
+	claims, err := s.signer.Verify(params.Token, security.PurposeMFA, s.cfg.Server.URL+"/auth/mfa/verify")
+	if err != nil {
+		return "", "", ErrInvalidToken
+	}
+
+	if _, err := s.tokenRepo.ConsumeToken(ctx, claims.ID, purposeMFAChallenge); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", "", ErrInvalidToken
+		}
+		return "", "", err
+	}
+
+	if err := s.mfa.VerifyCode(ctx, claims.Subject, params.Code); err != nil {
+		return "", "", err
+	}
+
+	result, err := s.StartSession(ctx, claims.Subject)
 	if err != nil {
 		return "", "", err
 	}
 
+	return result.AccessToken, result.RefreshToken, nil
+}
+
+func (s *authService) issueMFAChallenge(ctx context.Context, user model.User) (string, error) {
+	id, err := security.GenerateRandomBytesEncoded(s.cfg.JWT.JTILen)
+	if err != nil {
+		return "", fmt.Errorf("generate mfa challenge id: %w", err)
+	}
+
+	audience := s.cfg.Server.URL + "/auth/mfa/verify"
+	ttl := time.Duration(s.cfg.MFA.ChallengeTTL) * time.Second
+	token, err := s.signer.SignWithID(security.PurposeMFA, id, user.ID, []string{audience}, ttl)
+	if err != nil {
+		return "", err
+	}
+
+	params := repository.SaveTokenParams{
+		ID:        id,
+		Email:     user.Email,
+		Purpose:   purposeMFAChallenge,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.tokenRepo.SaveToken(ctx, params); err != nil {
+		return "", fmt.Errorf("save mfa challenge: %w", err)
+	}
+
+	return token, nil
+}
+
+// StartSession issues the tokens of a new session, that is a new refresh token family. It is how logins
+// that do not go through LoginUser, such as passkeys, sign the user in. The session is recorded with the
+// device attached to ctx by NewClientContext. Every login ends here, once every factor has been passed, so
+// suspended users are turned away and deleted accounts are restored here.
+func (s *authService) StartSession(ctx context.Context, userID string) (LoginResult, error) {
+	if err := s.admitUser(ctx, userID); err != nil {
+		return LoginResult{}, err
+	}
+
+	familyID, err := security.GenerateRandomBytesEncoded(s.cfg.JWT.JTILen)
+	if err != nil {
+		return LoginResult{}, fmt.Errorf("generate token family: %w", err)
+	}
+
+	client := clientFromContext(ctx)
+	session := model.Session{
+		ID:        familyID,
+		UserID:    userID,
+		UserAgent: client.UserAgent,
+		IPAddress: client.IPAddress,
+	}
+	if err := s.sessionRepo.CreateSession(ctx, session); err != nil {
+		return LoginResult{}, fmt.Errorf("save session: %w", err)
+	}
+
+	access, refresh, err := s.issueTokens(ctx, userID, familyID)
+	if err != nil {
+		return LoginResult{}, err
+	}
+
+	return LoginResult{AccessToken: access, RefreshToken: refresh}, nil
+}
+
+// RefreshToken exchanges a refresh token for a new access token and a rotated refresh token.
+// Presenting a token that was already rotated revokes every token in its family.
+func (s *authService) RefreshToken(ctx context.Context, token string) (accessToken, refreshToken string, err error) {
+	claims, stored, err := s.findRefreshToken(ctx, token)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Suspending a user revokes their refresh tokens; this tells them why.
+	if err := s.checkSuspension(ctx, stored.UserID); err != nil {
+		return "", "", err
+	}
+
+	revoked, err := s.revokedTokenRepo.IsTokenRevoked(ctx, claims.ID)
+	if err != nil {
+		return "", "", err
+	}
+
+	if revoked || stored.RevokedAt != nil {
+		return "", "", ErrInvalidToken
+	}
+
+	if stored.RotatedAt != nil {
+		return "", "", s.revokeFamily(ctx, stored)
+	}
+
+	if err := s.refreshTokenRepo.RotateRefreshToken(ctx, stored.ID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", "", s.revokeFamily(ctx, stored)
+		}
+		return "", "", err
+	}
+
+	if err := s.sessionRepo.TouchSession(ctx, stored.FamilyID); err != nil {
+		return "", "", fmt.Errorf("record session refresh: %w", err)
+	}
+
+	return s.issueTokens(ctx, stored.UserID, stored.FamilyID)
+}
+
+// LogoutUser revokes the refresh token's session, or every session of its owner when AllSessions is set.
+// The access token, if given, is revoked as well so it cannot be used until it expires.
+func (s *authService) LogoutUser(ctx context.Context, params LogoutUserParams) error {
+	claims, stored, err := s.findRefreshToken(ctx, params.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	if params.AllSessions {
+		err = s.refreshTokenRepo.RevokeUserRefreshTokens(ctx, stored.UserID)
+	} else {
+		err = s.refreshTokenRepo.RevokeRefreshTokenFamily(ctx, stored.FamilyID)
+	}
+	if err != nil {
+		return fmt.Errorf("revoke refresh tokens: %w", err)
+	}
+
+	if err := s.revokeToken(ctx, claims); err != nil {
+		return err
+	}
+
+	if params.AccessToken == "" {
+		return nil
+	}
+
+	accessClaims, err := s.signer.Verify(params.AccessToken, security.PurposeAccess, s.cfg.JWT.Issuer)
+	if err != nil || accessClaims.Subject != stored.UserID {
+		slog.Debug("skipping revocation of invalid access token", "reason", err)
+		return nil
+	}
+
+	return s.revokeToken(ctx, accessClaims)
+}
+
+// ForgotPassword emails a password reset link if the address belongs to a user.
+// It succeeds either way so the response does not reveal which emails are registered.
+func (s *authService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.repo.FindUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			slog.Info("Password reset requested for unknown email")
+			return nil
+		}
+		return err
+	}
+
+	go s.sendPasswordResetEmail(context.WithoutCancel(ctx), user)
+
+	return nil
+}
+
+func (s *authService) sendPasswordResetEmail(ctx context.Context, user model.User) {
+	slog.Info("Sending password reset email...")
+
+	const (
+		title   = "Password reset"
+		subject = "Reset your password"
+	)
+
+	id, err := security.GenerateRandomBytesEncoded(s.cfg.JWT.JTILen)
+	if err != nil {
+		slog.Error("failed to generate token id", "reason", err)
+		return
+	}
+
+	audience := s.cfg.Server.URL + "/auth/reset-password"
+	ttl := time.Duration(s.cfg.Email.Options.ResetTTL) * time.Second
+	token, err := s.signer.SignWithID(security.PurposeReset, id, user.ID, []string{audience}, ttl)
+	if err != nil {
+		slog.Error("failed to generate token", "reason", err)
+		return
+	}
+
+	params := repository.SaveTokenParams{
+		ID:        id,
+		Email:     user.Email,
+		Purpose:   purposePasswordReset,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.tokenRepo.SaveToken(ctx, params); err != nil {
+		slog.Error("failed to save token", "reason", err)
+		return
+	}
+
+	data := map[string]string{
+		"Title":  title,
+		"Header": subject,
+		"Link":   audience + "?token=" + token,
+	}
+	if err := s.mailer.SendHTML([]string{user.Email}, subject, "password_reset", data); err != nil {
+		slog.Error("failed to send email", "reason", err)
+		return
+	}
+}
+
+// ResetPassword sets a new password using a single-use reset token and signs the user out of every session.
+func (s *authService) ResetPassword(ctx context.Context, params ResetPasswordParams) error {
+	claims, err := s.signer.Verify(params.Token, security.PurposeReset, s.cfg.Server.URL+"/auth/reset-password")
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	email, err := s.tokenRepo.ConsumeToken(ctx, claims.ID, purposePasswordReset)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidToken
+		}
+		return err
+	}
+
+	user, err := s.repo.FindUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidToken
+		}
+		return err
+	}
+
+	if user.ID != claims.Subject {
+		return ErrInvalidToken
+	}
+
+	hash, err := s.hasher.Hash(params.Password)
+	if err != nil {
+		return fmt.Errorf("hasher hash: %w", err)
+	}
+
+	if err := s.repo.UpdatePassword(ctx, user.ID, hash); err != nil {
+		return fmt.Errorf("update password of user %s: %w", user.ID, err)
+	}
+
+	if err := s.refreshTokenRepo.RevokeUserRefreshTokens(ctx, user.ID); err != nil {
+		return fmt.Errorf("revoke refresh tokens of user %s: %w", user.ID, err)
+	}
+
+	return nil
+}
+
+// ChangePassword replaces the password after checking the current one, signs out every other session
+// and notifies the account address.
+func (s *authService) ChangePassword(ctx context.Context, params ChangePasswordParams) error {
+	user, err := s.repo.FindUserByID(ctx, params.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	ok, err := s.hasher.Verify(params.CurrentPassword, user.PasswordHash)
+	if err != nil {
+		return fmt.Errorf("hasher verify: %w", err)
+	}
+
 	if !ok {
-		return "", "", ErrUserNotFound
+		return ErrWrongPassword
+	}
+
+	hash, err := s.hasher.Hash(params.NewPassword)
+	if err != nil {
+		return fmt.Errorf("hasher hash: %w", err)
+	}
+
+	if err := s.repo.UpdatePassword(ctx, user.ID, hash); err != nil {
+		return fmt.Errorf("update password of user %s: %w", user.ID, err)
+	}
+
+	if err := s.revokeOtherSessions(ctx, user.ID, params.RefreshToken); err != nil {
+		return err
+	}
+
+	go s.sendPasswordChangedEmail(user)
+
+	return nil
+}
+
+// revokeOtherSessions revokes all refresh tokens of the user except the family of the given token.
+func (s *authService) revokeOtherSessions(ctx context.Context, userID, currentToken string) error {
+	if currentToken != "" {
+		_, current, err := s.findRefreshToken(ctx, currentToken)
+		if err == nil && current.UserID == userID {
+			if err := s.refreshTokenRepo.RevokeOtherRefreshTokens(ctx, userID, current.FamilyID); err != nil {
+				return fmt.Errorf("revoke other refresh tokens of user %s: %w", userID, err)
+			}
+			return nil
+		}
+	}
+
+	if err := s.refreshTokenRepo.RevokeUserRefreshTokens(ctx, userID); err != nil {
+		return fmt.Errorf("revoke refresh tokens of user %s: %w", userID, err)
+	}
+	return nil
+}
+
+func (s *authService) sendPasswordChangedEmail(user model.User) {
+	slog.Info("Sending password changed email...")
+
+	const (
+		title   = "Password changed"
+		subject = "Your password was changed"
+	)
+
+	data := map[string]string{
+		"Title":  title,
+		"Header": subject,
+	}
+	if err := s.mailer.SendHTML([]string{user.Email}, subject, "password_changed", data); err != nil {
+		slog.Error("failed to send email", "reason", err)
+		return
+	}
+}
+
+// RequestEmailChange emails a confirmation link to the new address. The email is only changed once
+// the link is followed, see ConfirmEmailChange.
+func (s *authService) RequestEmailChange(ctx context.Context, params RequestEmailChangeParams) error {
+	user, err := s.repo.FindUserByID(ctx, params.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	ok, err := s.hasher.Verify(params.Password, user.PasswordHash)
+	if err != nil {
+		return fmt.Errorf("hasher verify: %w", err)
+	}
+
+	if !ok {
+		return ErrWrongPassword
+	}
+
+	if _, err := s.repo.FindUserByEmail(ctx, params.NewEmail); err == nil {
+		return ErrUserExists
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	go s.sendEmailChangeEmail(context.WithoutCancel(ctx), user, params.NewEmail)
+
+	return nil
+}
+
+func (s *authService) sendEmailChangeEmail(ctx context.Context, user model.User, newEmail string) {
+	slog.Info("Sending email change confirmation...")
+
+	const (
+		title   = "Confirm your new email"
+		subject = "Confirm your new email address"
+	)
+
+	id, err := security.GenerateRandomBytesEncoded(s.cfg.JWT.JTILen)
+	if err != nil {
+		slog.Error("failed to generate token id", "reason", err)
+		return
+	}
+
+	// The token is bound to the user by its subject and to the new address by the stored row.
+	audience := s.cfg.Server.URL + "/auth/email/confirm"
+	ttl := time.Duration(s.cfg.Email.Options.VerifyTTL) * time.Second
+	token, err := s.signer.SignWithID(security.PurposeEmailChange, id, user.ID, []string{audience}, ttl)
+	if err != nil {
+		slog.Error("failed to generate token", "reason", err)
+		return
+	}
+
+	params := repository.SaveTokenParams{
+		ID:        id,
+		Email:     newEmail,
+		Purpose:   purposeEmailChange,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.tokenRepo.SaveToken(ctx, params); err != nil {
+		slog.Error("failed to save token", "reason", err)
+		return
+	}
+
+	data := map[string]string{
+		"Title":  title,
+		"Header": subject,
+		"Link":   audience + "?token=" + token,
+	}
+	if err := s.mailer.SendHTML([]string{newEmail}, subject, "email_change", data); err != nil {
+		slog.Error("failed to send email", "reason", err)
+		return
+	}
+}
+
+// ConfirmEmailChange applies a pending email change and notifies the previous address.
+func (s *authService) ConfirmEmailChange(ctx context.Context, token string) error {
+	claims, err := s.signer.Verify(token, security.PurposeEmailChange, s.cfg.Server.URL+"/auth/email/confirm")
+	if err != nil {
+		return ErrInvalidToken
+	}
+
+	newEmail, err := s.tokenRepo.ConsumeToken(ctx, claims.ID, purposeEmailChange)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidToken
+		}
+		return err
+	}
+
+	user, err := s.repo.FindUserByID(ctx, claims.Subject)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidToken
+		}
+		return err
+	}
+
+	if err := s.repo.UpdateEmail(ctx, user.ID, newEmail); err != nil {
+		if errors.Is(err, repository.ErrEmailTaken) {
+			return ErrUserExists
+		}
+		return fmt.Errorf("update email of user %s: %w", user.ID, err)
+	}
+
+	go s.sendEmailChangedEmail(user.Email)
+
+	return nil
+}
+
+func (s *authService) sendEmailChangedEmail(oldEmail string) {
+	slog.Info("Sending email changed notice...")
+
+	const (
+		title   = "Email changed"
+		subject = "Your email was changed"
+	)
+
+	data := map[string]string{
+		"Title":  title,
+		"Header": subject,
+	}
+	if err := s.mailer.SendHTML([]string{oldEmail}, subject, "email_changed", data); err != nil {
+		slog.Error("failed to send email", "reason", err)
+		return
+	}
+}
+
+func (s *authService) revokeToken(ctx context.Context, claims *security.Claims) error {
+	params := repository.RevokeTokenParams{
+		ID:        claims.ID,
+		UserID:    claims.Subject,
+		ExpiresAt: claims.ExpiresAt,
+	}
+	if err := s.revokedTokenRepo.RevokeToken(ctx, params); err != nil {
+		return fmt.Errorf("revoke token %s: %w", claims.ID, err)
+	}
+	return nil
+}
+
+// findRefreshToken verifies a refresh token and loads its stored record.
+func (s *authService) findRefreshToken(ctx context.Context,
+	token string) (*security.Claims, model.RefreshToken, error) {
+	claims, err := s.signer.Verify(token, security.PurposeRefresh, s.cfg.JWT.Issuer)
+	if err != nil {
+		return nil, model.RefreshToken{}, ErrInvalidToken
 	}
 
+	stored, err := s.refreshTokenRepo.FindRefreshToken(ctx, claims.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.RefreshToken{}, ErrInvalidToken
+		}
+		return nil, model.RefreshToken{}, err
+	}
+
+	if stored.UserID != claims.Subject {
+		return nil, model.RefreshToken{}, ErrInvalidToken
+	}
+
+	return claims, stored, nil
+}
+
+// upgradePasswordHash re-hashes the password if its hash was made with other Argon2 parameters than the
+// configured ones, so raising them migrates users as they log in. A failure is logged and does not stop
+// the login; the old hash still works.
+func (s *authService) upgradePasswordHash(ctx context.Context, user model.User, password string) {
+	if !s.hasher.NeedsRehash(user.PasswordHash) {
+		return
+	}
+
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		slog.Error("failed to rehash password", "user_id", user.ID, "reason", err)
+		return
+	}
+
+	if err := s.repo.UpdatePassword(ctx, user.ID, hash); err != nil {
+		slog.Error("failed to save rehashed password", "user_id", user.ID, "reason", err)
+		return
+	}
+	slog.Info("Password rehashed with the current parameters", "user_id", user.ID)
+}
+
+// checkSuspension returns ErrUserSuspended if the user is suspended. It reads the database rather than
+// the status cache of RevocationService, since logins and refreshes are rare next to other requests.
+func (s *authService) checkSuspension(ctx context.Context, userID string) error {
+	status, err := s.repo.FindUserStatus(ctx, userID)
+	if err != nil {
+		// A deleted user has no status; the checks of their credentials reject them.
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("find status of user %s: %w", userID, err)
+	}
+
+	if status.Suspended(time.Now()) {
+		return ErrUserSuspended
+	}
+	return nil
+}
+
+// admitUser turns away a suspended user. A user without a status is deleted; only a password login, which
+// has checked the suspension of the deleted account, signs in to one, and doing so restores the account.
+func (s *authService) admitUser(ctx context.Context, userID string) error {
+	status, err := s.repo.FindUserStatus(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return s.restoreUser(ctx, userID)
+		}
+		return fmt.Errorf("find status of user %s: %w", userID, err)
+	}
+
+	if status.Suspended(time.Now()) {
+		return ErrUserSuspended
+	}
+	return nil
+}
+
+func (s *authService) restoreUser(ctx context.Context, userID string) error {
+	if err := s.repo.RestoreUser(ctx, userID); err != nil {
+		// The account was purged since the login found it.
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("restore user %s: %w", userID, err)
+	}
+	s.revocation.ForgetUser(userID)
+
+	slog.Info("Deleted account restored by login", "user_id", userID)
+	return nil
+}
+
+func (s *authService) revokeFamily(ctx context.Context, token model.RefreshToken) error {
+	slog.Warn("refresh token reuse detected", "user_id", token.UserID, "family_id", token.FamilyID)
+	if err := s.refreshTokenRepo.RevokeRefreshTokenFamily(ctx, token.FamilyID); err != nil {
+		return fmt.Errorf("revoke token family %s: %w", token.FamilyID, err)
+	}
+	return ErrTokenReused
+}
+
+// issueTokens signs an access token carrying the user's roles and a refresh token in the given family.
+func (s *authService) issueTokens(ctx context.Context, userID, familyID string) (access, refresh string, err error) {
+	roles, err := s.roleRepo.FindUserRoles(ctx, userID)
+	if err != nil {
+		return "", "", fmt.Errorf("find roles of user %s: %w", userID, err)
+	}
+
+	audience := []string{s.cfg.JWT.Issuer}
 	ttl := time.Duration(s.cfg.JWT.Duration) * time.Minute
-	accessToken, err = s.signer.Sign(user.ID, []string{s.cfg.JWT.Issuer}, ttl)
+	access, err = s.signer.SignWithRoles(security.PurposeAccess, userID, audience, roles, ttl)
 	if err != nil {
 		return "", "", err
 	}
 
-	// TODO: add refresh token ttl to config
-	refreshToken, err = s.signer.Sign(user.ID, []string{s.cfg.JWT.Issuer}, time.Hour*24*7)
+	id, err := security.GenerateRandomBytesEncoded(s.cfg.JWT.JTILen)
+	if err != nil {
+		return "", "", fmt.Errorf("generate refresh token id: %w", err)
+	}
+
+	refreshTTL := time.Duration(s.cfg.JWT.RefreshDuration) * time.Minute
+	refresh, err = s.signer.SignWithID(security.PurposeRefresh, id, userID, audience, refreshTTL)
 	if err != nil {
 		return "", "", err
 	}
 
-	return accessToken, refreshToken, nil
+	params := repository.CreateRefreshTokenParams{
+		ID:        id,
+		UserID:    userID,
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(refreshTTL),
+	}
+	if err := s.refreshTokenRepo.CreateRefreshToken(ctx, params); err != nil {
+		return "", "", fmt.Errorf("save refresh token: %w", err)
+	}
+
+	return access, refresh, nil
 }
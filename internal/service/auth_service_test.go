@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"os"
 	"reflect"
 	"sync"
@@ -16,11 +17,14 @@ import (
 	"github.com/ferdiebergado/gojeep/internal/repository/mock"
 	"github.com/ferdiebergado/gojeep/internal/service"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 
 	mailMock "github.com/ferdiebergado/gojeep/internal/pkg/email/mock"
 	"github.com/ferdiebergado/gojeep/internal/pkg/logging"
+	"github.com/ferdiebergado/gojeep/internal/pkg/security"
 	secMock "github.com/ferdiebergado/gojeep/internal/pkg/security/mock"
+	svcMock "github.com/ferdiebergado/gojeep/internal/service/mock"
 )
 
 func TestMain(m *testing.M) {
@@ -43,6 +47,7 @@ func TestUserService_RegisterUser(t *testing.T) {
 
 	ctrl := gomock.NewController(t)
 	mockRepo := mock.NewMockUserRepository(ctrl)
+	mockTokenRepo := mock.NewMockTokenRepository(ctrl)
 	mockHasher := secMock.NewMockHasher(ctrl)
 	mockSigner := secMock.NewMockSigner(ctrl)
 	mockMailer := mailMock.NewMockMailer(ctrl)
@@ -66,6 +71,7 @@ func TestUserService_RegisterUser(t *testing.T) {
 		Server: &config.ServerConfig{
 			URL: "http://localhost:8888",
 		},
+		JWT: &config.JWTOptions{JTILen: 32},
 		Email: &config.SMTPConfig{
 			Options: &config.EmailOptions{
 				VerifyTTL: 300,
@@ -85,10 +91,15 @@ func TestUserService_RegisterUser(t *testing.T) {
 
 	var wg sync.WaitGroup
 	wg.Add(2)
-	mockSigner.EXPECT().Sign(userID, []string{audience}, time.Duration(cfg.Email.Options.VerifyTTL)*time.Second).
-		Do(func(_ string, _ []string, _ time.Duration) {
+	mockSigner.EXPECT().
+		SignWithID(security.PurposeVerify, gomock.Any(), userID, []string{audience},
+			time.Duration(cfg.Email.Options.VerifyTTL)*time.Second).
+		Do(func(_ security.Purpose, _, _ string, _ []string, _ time.Duration) {
 			defer wg.Done()
 		}).Return(token, nil)
+	mockTokenRepo.EXPECT().SaveToken(gomock.Any(), gomock.Cond(func(p repository.SaveTokenParams) bool {
+		return p.Email == testEmail && p.Purpose == "verification"
+	})).Return(nil)
 	mockMailer.EXPECT().SendHTML([]string{testEmail}, subject, tmpl, data).
 		Do(func(_ []string, _, _ string, _ map[string]string) {
 			defer wg.Done()
@@ -97,11 +108,12 @@ func TestUserService_RegisterUser(t *testing.T) {
 	mockRepo.EXPECT().CreateUser(ctx, createParams).Return(user, nil)
 
 	deps := &service.AuthServiceDeps{
-		Repo:   mockRepo,
-		Hasher: mockHasher,
-		Signer: mockSigner,
-		Mailer: mockMailer,
-		Cfg:    cfg,
+		Repo:      mockRepo,
+		TokenRepo: mockTokenRepo,
+		Hasher:    mockHasher,
+		Signer:    mockSigner,
+		Mailer:    mockMailer,
+		Cfg:       cfg,
 	}
 
 	userService := service.NewAuthService(deps)
@@ -116,6 +128,82 @@ func TestUserService_RegisterUser(t *testing.T) {
 	wg.Wait()
 }
 
+func TestUserService_RegisterUser_Exists(t *testing.T) {
+	t.Parallel()
+	const testEmail = "abc@example.com"
+	existing := model.User{Model: model.Model{ID: "1"}, Email: testEmail}
+	regParams := service.RegisterUserParams{Email: testEmail, Password: "test"}
+
+	testCases := []struct {
+		name            string
+		enumerationSafe bool
+	}{
+		{name: "Rejected"},
+		{name: "Enumeration-safe", enumerationSafe: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockRepo := mock.NewMockUserRepository(ctrl)
+			mockHasher := secMock.NewMockHasher(ctrl)
+			mockMailer := mailMock.NewMockMailer(ctrl)
+
+			ctx := context.Background()
+			mockRepo.EXPECT().FindUserByEmail(ctx, testEmail).Return(existing, nil)
+
+			var wg sync.WaitGroup
+			if tc.enumerationSafe {
+				// The password is hashed and the owner is notified, as a new registration would be.
+				wg.Add(1)
+				mockHasher.EXPECT().Hash(regParams.Password).Return("hashed", nil)
+				mockMailer.EXPECT().SendHTML([]string{testEmail}, gomock.Any(), "account_exists", gomock.Any()).
+					Do(func(_ []string, _, _ string, _ map[string]string) {
+						defer wg.Done()
+					})
+			}
+
+			svc := service.NewAuthService(&service.AuthServiceDeps{
+				Repo:   mockRepo,
+				Hasher: mockHasher,
+				Mailer: mockMailer,
+				Cfg: &config.Config{
+					Registration: &config.RegistrationOptions{EnumerationSafe: tc.enumerationSafe},
+				},
+			})
+			_, err := svc.RegisterUser(ctx, regParams)
+			assert.ErrorIs(t, err, service.ErrUserExists)
+
+			wg.Wait()
+		})
+	}
+}
+
+func TestUserService_RegisterUser_PendingDeletion(t *testing.T) {
+	t.Parallel()
+	const testEmail = "abc@example.com"
+	regParams := service.RegisterUserParams{Email: testEmail, Password: "test"}
+
+	ctrl := gomock.NewController(t)
+	mockRepo := mock.NewMockUserRepository(ctrl)
+	mockHasher := secMock.NewMockHasher(ctrl)
+
+	ctx := context.Background()
+	mockRepo.EXPECT().FindUserByEmail(ctx, testEmail).Return(model.User{}, sql.ErrNoRows)
+	mockHasher.EXPECT().Hash(regParams.Password).Return("hashed", nil)
+	mockRepo.EXPECT().CreateUser(ctx, repository.CreateUserParams{Email: testEmail, PasswordHash: "hashed"}).
+		Return(model.User{}, repository.ErrEmailTaken)
+
+	svc := service.NewAuthService(&service.AuthServiceDeps{
+		Repo:   mockRepo,
+		Hasher: mockHasher,
+		Cfg:    &config.Config{},
+	})
+	_, err := svc.RegisterUser(ctx, regParams)
+	assert.ErrorIs(t, err, service.ErrUserExists)
+}
+
 func TestUserService_VerifyUser(t *testing.T) {
 	t.Parallel()
 	const (
@@ -138,7 +226,8 @@ func TestUserService_VerifyUser(t *testing.T) {
 
 	ctx := context.Background()
 	mockRepo.EXPECT().VerifyUser(ctx, id).Return(nil)
-	mockSigner.EXPECT().Verify(token).Return(id, nil)
+	mockSigner.EXPECT().Verify(token, security.PurposeVerify, cfg.Server.URL+"/auth/verify").
+		Return(&security.Claims{ID: "jti", Subject: id}, nil)
 
 	deps := &service.AuthServiceDeps{
 		Repo:   mockRepo,
@@ -163,9 +252,10 @@ func TestUserService_LoginUser(t *testing.T) {
 
 	cfg := &config.Config{
 		Server: &config.ServerConfig{URL: "http://localhost:8888"},
-		JWT:    &config.JWTOptions{Duration: 30},
+		JWT:    &config.JWTOptions{Duration: 30, RefreshDuration: 10080},
+		MFA:    &config.MFAOptions{ChallengeTTL: 300},
 	}
-	loginParams := service.LoginUserParams{Email: testEmail, Password: testPass}
+	loginParams := service.LoginUserParams{Email: testEmail, Password: testPass, IPAddress: "127.0.0.1"}
 	verifiedAt := time.Date(2024, 1, 1, 1, 1, 1, 1, time.UTC)
 	user := model.User{
 		Model:        model.Model{ID: "1"},
@@ -180,7 +270,13 @@ func TestUserService_LoginUser(t *testing.T) {
 		repoErr      error
 		hasherResult bool
 		hasherErr    error
+		mfaEnabled   bool
+		suspended    bool
+		rehash       bool
+		rehashErr    error
+		lockErr      error
 		wantToken    string
+		wantMFAToken string
 		wantErr      error
 	}{
 		{
@@ -189,11 +285,53 @@ func TestUserService_LoginUser(t *testing.T) {
 			hasherResult: true,
 			wantToken:    "mocked_access_token",
 		},
+		{
+			name:         "Success_Rehashed",
+			repoUser:     user,
+			hasherResult: true,
+			rehash:       true,
+			wantToken:    "mocked_access_token",
+		},
+		{
+			name:         "Success_RehashNotSaved",
+			repoUser:     user,
+			hasherResult: true,
+			rehash:       true,
+			rehashErr:    errors.New("database failure"),
+			wantToken:    "mocked_access_token",
+		},
+		{
+			name:         "Success_MFARequired",
+			repoUser:     user,
+			hasherResult: true,
+			mfaEnabled:   true,
+			wantMFAToken: "mocked_mfa_token",
+		},
+		{
+			name:         "Failure_Suspended",
+			repoUser:     user,
+			hasherResult: true,
+			suspended:    true,
+			wantErr:      service.ErrUserSuspended,
+		},
+		{
+			name:         "Failure_SuspendedWithMFA",
+			repoUser:     user,
+			hasherResult: true,
+			mfaEnabled:   true,
+			suspended:    true,
+			wantErr:      service.ErrUserSuspended,
+		},
 		{
 			name:    "Failure_UserNotFound",
-			repoErr: service.ErrUserNotFound,
+			repoErr: sql.ErrNoRows,
 			wantErr: service.ErrUserNotFound,
 		},
+		{
+			name:    "Failure_AccountLocked",
+			lockErr: &service.AccountLockedError{RetryAfter: time.Minute},
+			wantErr: service.ErrAccountLocked,
+		},
 		{
 			name: "Failure_UserUnverified",
 			repoUser: model.User{
@@ -232,44 +370,1174 @@ func TestUserService_LoginUser(t *testing.T) {
 			defer ctrl.Finish()
 
 			mockRepo := mock.NewMockUserRepository(ctrl)
+			mockTokenRepo := mock.NewMockRefreshTokenRepository(ctrl)
+			mockChallengeRepo := mock.NewMockTokenRepository(ctrl)
 			mockHasher := secMock.NewMockHasher(ctrl)
 			mockSigner := secMock.NewMockSigner(ctrl)
+			mockMFA := svcMock.NewMockMFAService(ctrl)
+			mockLockout := svcMock.NewMockLockoutService(ctrl)
+			mockRoleRepo := mock.NewMockRoleRepository(ctrl)
+			mockSessionRepo := mock.NewMockSessionRepository(ctrl)
+
+			ctx := context.Background()
+			mockLockout.EXPECT().Check(ctx, testEmail, loginParams.IPAddress).Return(tc.lockErr)
+
+			if errors.Is(tc.wantErr, service.ErrUserNotFound) {
+				mockLockout.EXPECT().RecordFailure(ctx, testEmail, loginParams.IPAddress).Return(nil)
+			}
+
+			if tc.hasherResult && tc.hasherErr == nil {
+				mockLockout.EXPECT().Reset(ctx, testEmail).Return(nil)
+				mockHasher.EXPECT().NeedsRehash(hashedPass).Return(tc.rehash)
+				if tc.rehash {
+					mockHasher.EXPECT().Hash(testPass).Return("rehashed", nil)
+					mockRepo.EXPECT().UpdatePassword(ctx, tc.repoUser.ID, "rehashed").Return(tc.rehashErr)
+				}
+				mockMFA.EXPECT().IsEnabled(gomock.Any(), tc.repoUser.ID).Return(tc.mfaEnabled, nil)
+
+				var status model.UserStatus
+				if tc.suspended {
+					status.SuspendedAt = &verifiedAt
+				}
+				mockRepo.EXPECT().FindUserStatus(ctx, tc.repoUser.ID).Return(status, nil)
+			}
+
+			if tc.mfaEnabled && !tc.suspended {
+				mockSigner.EXPECT().SignWithID(security.PurposeMFA, gomock.Any(), tc.repoUser.ID,
+					[]string{cfg.Server.URL + "/auth/mfa/verify"}, 5*time.Minute).
+					Return("mocked_mfa_token", nil)
+				mockChallengeRepo.EXPECT().SaveToken(gomock.Any(), gomock.Any()).Return(nil)
+			}
 
 			if !reflect.DeepEqual(tc.repoUser, model.User{}) && tc.repoErr == nil && tc.wantToken != "" {
-				mockSigner.EXPECT().Sign(tc.repoUser.ID, []string{cfg.JWT.Issuer}, 30*time.Minute).
+				mockSessionRepo.EXPECT().CreateSession(ctx, gomock.Cond(func(session model.Session) bool {
+					return session.UserID == tc.repoUser.ID
+				})).Return(nil)
+				mockRoleRepo.EXPECT().FindUserRoles(gomock.Any(), tc.repoUser.ID).Return([]string{"admin"}, nil)
+				mockSigner.EXPECT().SignWithRoles(security.PurposeAccess, tc.repoUser.ID, []string{cfg.JWT.Issuer},
+					[]string{"admin"}, 30*time.Minute).
 					Return("mocked_access_token", nil)
-				mockSigner.EXPECT().Sign(tc.repoUser.ID, []string{cfg.JWT.Issuer}, 7*24*time.Hour).
+				mockSigner.EXPECT().SignWithID(security.PurposeRefresh, gomock.Any(), tc.repoUser.ID, []string{cfg.JWT.Issuer}, 7*24*time.Hour).
 					Return("mocked_refresh_token", nil)
+				mockTokenRepo.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return(nil)
 			}
 
-			ctx := context.Background()
-			mockRepo.EXPECT().
-				FindUserByEmail(ctx, testEmail).
-				Return(tc.repoUser, tc.repoErr)
+			if tc.lockErr == nil {
+				mockRepo.EXPECT().
+					FindUserByEmail(ctx, testEmail).
+					Return(tc.repoUser, tc.repoErr)
+			}
+
+			// An unknown email is checked against a dummy hash, so it takes as long as a known one.
+			if errors.Is(tc.repoErr, sql.ErrNoRows) {
+				mockRepo.EXPECT().FindDeletedUserByEmail(ctx, testEmail).Return(model.User{}, sql.ErrNoRows)
+				mockHasher.EXPECT().Hash(gomock.Any()).Return("dummy_hash", nil)
+				mockHasher.EXPECT().Verify(testPass, "dummy_hash").Return(false, nil)
+			}
 
-			if tc.repoErr == nil {
+			if tc.repoErr == nil && tc.lockErr == nil {
 				mockHasher.EXPECT().
 					Verify(testPass, tc.repoUser.PasswordHash).
 					Return(tc.hasherResult, tc.hasherErr)
 			}
 
 			svc := service.NewAuthService(&service.AuthServiceDeps{
-				Repo:   mockRepo,
-				Hasher: mockHasher,
-				Cfg:    cfg,
-				Signer: mockSigner,
+				Repo:             mockRepo,
+				RefreshTokenRepo: mockTokenRepo,
+				TokenRepo:        mockChallengeRepo,
+				RoleRepo:         mockRoleRepo,
+				SessionRepo:      mockSessionRepo,
+				MFA:              mockMFA,
+				Lockout:          mockLockout,
+				Hasher:           mockHasher,
+				Cfg:              cfg,
+				Signer:           mockSigner,
 			})
 
-			// TODO: add refreshToken to test cases
-			accessToken, _, err := svc.LoginUser(ctx, loginParams)
+			result, err := svc.LoginUser(ctx, loginParams)
 
-			if tc.wantErr != nil {
+			switch {
+			case errors.Is(tc.wantErr, service.ErrUserNotFound) || errors.Is(tc.wantErr, service.ErrAccountLocked) ||
+				errors.Is(tc.wantErr, service.ErrUserSuspended):
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Empty(t, result)
+			case tc.wantErr != nil:
 				assert.ErrorContains(t, err, tc.wantErr.Error())
-				assert.Empty(t, accessToken)
-			} else {
+				assert.Empty(t, result)
+			case tc.wantMFAToken != "":
+				assert.NoError(t, err)
+				assert.Equal(t, service.LoginResult{MFAToken: tc.wantMFAToken}, result)
+			default:
 				assert.NoError(t, err)
-				assert.Equal(t, tc.wantToken, accessToken)
+				assert.Equal(t, tc.wantToken, result.AccessToken)
+				assert.Equal(t, "mocked_refresh_token", result.RefreshToken)
+			}
+		})
+	}
+}
+
+// An unverified account is only reported once the password is right, so it cannot be found by guessing.
+func TestUserService_LoginUser_Unverified(t *testing.T) {
+	t.Parallel()
+	const testEmail = "abc@example.com"
+	user := model.User{Model: model.Model{ID: "1"}, Email: testEmail, PasswordHash: "hashed"}
+
+	testCases := []struct {
+		name        string
+		passwordOK  bool
+		wantErr     error
+		wantFailure bool
+	}{
+		{name: "Right password", passwordOK: true, wantErr: service.ErrUserNotVerified},
+		{name: "Wrong password", wantErr: service.ErrUserNotFound, wantFailure: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockRepo := mock.NewMockUserRepository(ctrl)
+			mockHasher := secMock.NewMockHasher(ctrl)
+			mockLockout := svcMock.NewMockLockoutService(ctrl)
+
+			ctx := context.Background()
+			params := service.LoginUserParams{Email: testEmail, Password: "test"}
+			mockLockout.EXPECT().Check(ctx, testEmail, "").Return(nil)
+			mockRepo.EXPECT().FindUserByEmail(ctx, testEmail).Return(user, nil)
+			mockHasher.EXPECT().Verify(params.Password, user.PasswordHash).Return(tc.passwordOK, nil)
+			if tc.wantFailure {
+				mockLockout.EXPECT().RecordFailure(ctx, testEmail, "").Return(nil)
+			}
+
+			svc := service.NewAuthService(&service.AuthServiceDeps{
+				Repo:    mockRepo,
+				Lockout: mockLockout,
+				Hasher:  mockHasher,
+				Cfg:     &config.Config{},
+			})
+			_, err := svc.LoginUser(ctx, params)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+// A login during the grace period cancels the deletion of the account.
+func TestAuthService_LoginUser_DeletedUser(t *testing.T) {
+	t.Parallel()
+	const testEmail = "abc@example.com"
+	verifiedAt := time.Now()
+	deletedAt := time.Now()
+	user := model.User{
+		Model:        model.Model{ID: "1"},
+		Email:        testEmail,
+		PasswordHash: "hashed",
+		VerifiedAt:   &verifiedAt,
+		DeletedAt:    &deletedAt,
+	}
+	cfg := &config.Config{
+		Server: &config.ServerConfig{URL: "http://localhost:8888"},
+		JWT:    &config.JWTOptions{JTILen: 32},
+		MFA:    &config.MFAOptions{ChallengeTTL: 300},
+	}
+
+	suspendedAt := time.Now()
+
+	testCases := []struct {
+		name       string
+		passwordOK bool
+		suspension model.Suspension
+		wantErr    error
+	}{
+		// The account is only restored by StartSession, once the code has been verified.
+		{name: "Right password asks for the second factor", passwordOK: true},
+		{
+			name:       "Suspended",
+			passwordOK: true,
+			suspension: model.Suspension{SuspendedAt: &suspendedAt},
+			wantErr:    service.ErrUserSuspended,
+		},
+		{name: "Wrong password", wantErr: service.ErrUserNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockRepo := mock.NewMockUserRepository(ctrl)
+			mockHasher := secMock.NewMockHasher(ctrl)
+			mockLockout := svcMock.NewMockLockoutService(ctrl)
+			mockMFA := svcMock.NewMockMFAService(ctrl)
+			mockSigner := secMock.NewMockSigner(ctrl)
+			mockTokenRepo := mock.NewMockTokenRepository(ctrl)
+
+			ctx := context.Background()
+			params := service.LoginUserParams{Email: testEmail, Password: "test"}
+			deleted := user
+			deleted.Suspension = tc.suspension
+			mockLockout.EXPECT().Check(ctx, testEmail, "").Return(nil)
+			mockRepo.EXPECT().FindUserByEmail(ctx, testEmail).Return(model.User{}, sql.ErrNoRows)
+			mockRepo.EXPECT().FindDeletedUserByEmail(ctx, testEmail).Return(deleted, nil)
+			mockHasher.EXPECT().Verify(params.Password, user.PasswordHash).Return(tc.passwordOK, nil)
+			switch {
+			case tc.wantErr == service.ErrUserSuspended:
+				mockLockout.EXPECT().Reset(ctx, testEmail).Return(nil)
+			case tc.passwordOK:
+				mockLockout.EXPECT().Reset(ctx, testEmail).Return(nil)
+				mockMFA.EXPECT().IsEnabled(ctx, user.ID).Return(true, nil)
+				mockRepo.EXPECT().FindUserStatus(ctx, user.ID).Return(model.UserStatus{}, nil)
+				mockSigner.EXPECT().SignWithID(security.PurposeMFA, gomock.Any(), user.ID,
+					[]string{cfg.Server.URL + "/auth/mfa/verify"}, 5*time.Minute).
+					Return("mfa_token", nil)
+				mockTokenRepo.EXPECT().SaveToken(gomock.Any(), gomock.Any()).Return(nil)
+			default:
+				mockLockout.EXPECT().RecordFailure(ctx, testEmail, "").Return(nil)
 			}
+
+			svc := service.NewAuthService(&service.AuthServiceDeps{
+				Repo:      mockRepo,
+				TokenRepo: mockTokenRepo,
+				Lockout:   mockLockout,
+				MFA:       mockMFA,
+				Hasher:    mockHasher,
+				Signer:    mockSigner,
+				Cfg:       cfg,
+			})
+			result, err := svc.LoginUser(ctx, params)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "mfa_token", result.MFAToken)
 		})
 	}
 }
+
+type refreshMocks struct {
+	signer   *secMock.MockSigner
+	repo     *mock.MockRefreshTokenRepository
+	revoked  *mock.MockRevokedTokenRepository
+	roles    *mock.MockRoleRepository
+	sessions *mock.MockSessionRepository
+	users    *mock.MockUserRepository
+}
+
+func TestAuthService_RefreshToken(t *testing.T) {
+	t.Parallel()
+	const (
+		userID       = "1"
+		tokenID      = "jti"
+		familyID     = "family"
+		refreshToken = "refresh_token"
+		issuer       = "gojeep"
+	)
+
+	cfg := &config.Config{
+		JWT: &config.JWTOptions{Duration: 30, RefreshDuration: 60, Issuer: issuer},
+	}
+	claims := &security.Claims{ID: tokenID, Subject: userID}
+	rotatedAt := time.Now()
+	stored := model.RefreshToken{ID: tokenID, UserID: userID, FamilyID: familyID}
+
+	testCases := []struct {
+		name      string
+		setup     func(m refreshMocks)
+		wantToken string
+		wantErr   error
+	}{
+		{
+			name: "Success_Rotated",
+			setup: func(m refreshMocks) {
+				m.signer.EXPECT().Verify(refreshToken, security.PurposeRefresh, issuer).Return(claims, nil)
+				m.repo.EXPECT().FindRefreshToken(gomock.Any(), tokenID).Return(stored, nil)
+				m.users.EXPECT().FindUserStatus(gomock.Any(), userID).Return(model.UserStatus{}, nil)
+				m.revoked.EXPECT().IsTokenRevoked(gomock.Any(), tokenID).Return(false, nil)
+				m.repo.EXPECT().RotateRefreshToken(gomock.Any(), tokenID).Return(nil)
+				m.sessions.EXPECT().TouchSession(gomock.Any(), familyID).Return(nil)
+				m.roles.EXPECT().FindUserRoles(gomock.Any(), userID).Return([]string{}, nil)
+				m.signer.EXPECT().SignWithRoles(security.PurposeAccess, userID, gomock.Any(), []string{}, 30*time.Minute).
+					Return("new_access_token", nil)
+				m.signer.EXPECT().SignWithID(security.PurposeRefresh, gomock.Any(), userID, gomock.Any(), time.Hour).Return("new_refresh_token", nil)
+				m.repo.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Cond(func(p repository.CreateRefreshTokenParams) bool {
+					return p.FamilyID == familyID && p.UserID == userID
+				})).Return(nil)
+			},
+			wantToken: "new_access_token",
+		},
+		{
+			name: "Failure_InvalidSignature",
+			setup: func(m refreshMocks) {
+				m.signer.EXPECT().Verify(refreshToken, security.PurposeRefresh, issuer).Return(nil, errors.New("token is malformed"))
+			},
+			wantErr: service.ErrInvalidToken,
+		},
+		{
+			name: "Failure_UnknownToken",
+			setup: func(m refreshMocks) {
+				m.signer.EXPECT().Verify(refreshToken, security.PurposeRefresh, issuer).Return(claims, nil)
+				m.repo.EXPECT().FindRefreshToken(gomock.Any(), tokenID).Return(model.RefreshToken{}, sql.ErrNoRows)
+			},
+			wantErr: service.ErrInvalidToken,
+		},
+		{
+			name: "Failure_ReusedTokenRevokesFamily",
+			setup: func(m refreshMocks) {
+				reused := stored
+				reused.RotatedAt = &rotatedAt
+				m.signer.EXPECT().Verify(refreshToken, security.PurposeRefresh, issuer).Return(claims, nil)
+				m.repo.EXPECT().FindRefreshToken(gomock.Any(), tokenID).Return(reused, nil)
+				m.users.EXPECT().FindUserStatus(gomock.Any(), userID).Return(model.UserStatus{}, nil)
+				m.revoked.EXPECT().IsTokenRevoked(gomock.Any(), tokenID).Return(false, nil)
+				m.repo.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), familyID).Return(nil)
+			},
+			wantErr: service.ErrTokenReused,
+		},
+		{
+			name: "Failure_ConcurrentRotationRevokesFamily",
+			setup: func(m refreshMocks) {
+				m.signer.EXPECT().Verify(refreshToken, security.PurposeRefresh, issuer).Return(claims, nil)
+				m.repo.EXPECT().FindRefreshToken(gomock.Any(), tokenID).Return(stored, nil)
+				m.users.EXPECT().FindUserStatus(gomock.Any(), userID).Return(model.UserStatus{}, nil)
+				m.revoked.EXPECT().IsTokenRevoked(gomock.Any(), tokenID).Return(false, nil)
+				m.repo.EXPECT().RotateRefreshToken(gomock.Any(), tokenID).Return(sql.ErrNoRows)
+				m.repo.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), familyID).Return(nil)
+			},
+			wantErr: service.ErrTokenReused,
+		},
+		{
+			name: "Failure_Suspended",
+			setup: func(m refreshMocks) {
+				m.signer.EXPECT().Verify(refreshToken, security.PurposeRefresh, issuer).Return(claims, nil)
+				m.repo.EXPECT().FindRefreshToken(gomock.Any(), tokenID).Return(stored, nil)
+				m.users.EXPECT().FindUserStatus(gomock.Any(), userID).
+					Return(model.UserStatus{Suspension: model.Suspension{SuspendedAt: &rotatedAt}}, nil)
+			},
+			wantErr: service.ErrUserSuspended,
+		},
+		{
+			name: "Failure_RevokedJTI",
+			setup: func(m refreshMocks) {
+				m.signer.EXPECT().Verify(refreshToken, security.PurposeRefresh, issuer).Return(claims, nil)
+				m.repo.EXPECT().FindRefreshToken(gomock.Any(), tokenID).Return(stored, nil)
+				m.users.EXPECT().FindUserStatus(gomock.Any(), userID).Return(model.UserStatus{}, nil)
+				m.revoked.EXPECT().IsTokenRevoked(gomock.Any(), tokenID).Return(true, nil)
+			},
+			wantErr: service.ErrInvalidToken,
+		},
+		{
+			name: "Failure_RevokedToken",
+			setup: func(m refreshMocks) {
+				revokedToken := stored
+				revokedToken.RevokedAt = &rotatedAt
+				m.signer.EXPECT().Verify(refreshToken, security.PurposeRefresh, issuer).Return(claims, nil)
+				m.repo.EXPECT().FindRefreshToken(gomock.Any(), tokenID).Return(revokedToken, nil)
+				m.users.EXPECT().FindUserStatus(gomock.Any(), userID).Return(model.UserStatus{}, nil)
+				m.revoked.EXPECT().IsTokenRevoked(gomock.Any(), tokenID).Return(false, nil)
+			},
+			wantErr: service.ErrInvalidToken,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSigner := secMock.NewMockSigner(ctrl)
+			mockTokenRepo := mock.NewMockRefreshTokenRepository(ctrl)
+			mockRevokedRepo := mock.NewMockRevokedTokenRepository(ctrl)
+			m := refreshMocks{
+				signer:   mockSigner,
+				repo:     mockTokenRepo,
+				revoked:  mockRevokedRepo,
+				roles:    mock.NewMockRoleRepository(ctrl),
+				sessions: mock.NewMockSessionRepository(ctrl),
+				users:    mock.NewMockUserRepository(ctrl),
+			}
+			tc.setup(m)
+
+			svc := service.NewAuthService(&service.AuthServiceDeps{
+				Repo:             m.users,
+				RefreshTokenRepo: mockTokenRepo,
+				RevokedTokenRepo: mockRevokedRepo,
+				RoleRepo:         m.roles,
+				SessionRepo:      m.sessions,
+				Signer:           mockSigner,
+				Cfg:              cfg,
+			})
+
+			accessToken, newRefreshToken, err := svc.RefreshToken(context.Background(), refreshToken)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Empty(t, accessToken)
+				assert.Empty(t, newRefreshToken)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantToken, accessToken)
+			assert.Equal(t, "new_refresh_token", newRefreshToken)
+		})
+	}
+}
+
+func TestAuthService_LogoutUser(t *testing.T) {
+	t.Parallel()
+	const (
+		userID       = "1"
+		familyID     = "family"
+		refreshToken = "refresh_token"
+		accessToken  = "access_token"
+		issuer       = "gojeep"
+	)
+
+	cfg := &config.Config{JWT: &config.JWTOptions{Issuer: issuer}}
+
+	refreshClaims := &security.Claims{ID: "refresh_jti", Subject: userID, ExpiresAt: time.Now().Add(time.Hour)}
+	accessClaims := &security.Claims{ID: "access_jti", Subject: userID, ExpiresAt: time.Now().Add(time.Minute)}
+	stored := model.RefreshToken{ID: refreshClaims.ID, UserID: userID, FamilyID: familyID}
+
+	testCases := []struct {
+		name    string
+		params  service.LogoutUserParams
+		setup   func(m refreshMocks)
+		wantErr error
+	}{
+		{
+			name:   "Success_CurrentSession",
+			params: service.LogoutUserParams{RefreshToken: refreshToken, AccessToken: accessToken},
+			setup: func(m refreshMocks) {
+				m.signer.EXPECT().Verify(refreshToken, security.PurposeRefresh, issuer).Return(refreshClaims, nil)
+				m.repo.EXPECT().FindRefreshToken(gomock.Any(), refreshClaims.ID).Return(stored, nil)
+				m.repo.EXPECT().RevokeRefreshTokenFamily(gomock.Any(), familyID).Return(nil)
+				m.revoked.EXPECT().RevokeToken(gomock.Any(), repository.RevokeTokenParams{
+					ID: refreshClaims.ID, UserID: userID, ExpiresAt: refreshClaims.ExpiresAt,
+				}).Return(nil)
+				m.signer.EXPECT().Verify(accessToken, security.PurposeAccess, issuer).Return(accessClaims, nil)
+				m.revoked.EXPECT().RevokeToken(gomock.Any(), repository.RevokeTokenParams{
+					ID: accessClaims.ID, UserID: userID, ExpiresAt: accessClaims.ExpiresAt,
+				}).Return(nil)
+			},
+		},
+		{
+			name:   "Success_AllSessions",
+			params: service.LogoutUserParams{RefreshToken: refreshToken, AllSessions: true},
+			setup: func(m refreshMocks) {
+				m.signer.EXPECT().Verify(refreshToken, security.PurposeRefresh, issuer).Return(refreshClaims, nil)
+				m.repo.EXPECT().FindRefreshToken(gomock.Any(), refreshClaims.ID).Return(stored, nil)
+				m.repo.EXPECT().RevokeUserRefreshTokens(gomock.Any(), userID).Return(nil)
+				m.revoked.EXPECT().RevokeToken(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:   "Failure_InvalidRefreshToken",
+			params: service.LogoutUserParams{RefreshToken: refreshToken},
+			setup: func(m refreshMocks) {
+				m.signer.EXPECT().Verify(refreshToken, security.PurposeRefresh, issuer).Return(nil, errors.New("token is expired"))
+			},
+			wantErr: service.ErrInvalidToken,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockSigner := secMock.NewMockSigner(ctrl)
+			mockTokenRepo := mock.NewMockRefreshTokenRepository(ctrl)
+			mockRevokedRepo := mock.NewMockRevokedTokenRepository(ctrl)
+			tc.setup(refreshMocks{signer: mockSigner, repo: mockTokenRepo, revoked: mockRevokedRepo})
+
+			svc := service.NewAuthService(&service.AuthServiceDeps{
+				RefreshTokenRepo: mockTokenRepo,
+				RevokedTokenRepo: mockRevokedRepo,
+				Signer:           mockSigner,
+				Cfg:              cfg,
+			})
+
+			err := svc.LogoutUser(context.Background(), tc.params)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAuthService_ForgotPassword(t *testing.T) {
+	t.Parallel()
+	const (
+		userID    = "1"
+		testEmail = "abc@example.com"
+		token     = "reset_token"
+	)
+
+	cfg := &config.Config{
+		Server: &config.ServerConfig{URL: "http://localhost:8888"},
+		JWT:    &config.JWTOptions{JTILen: 32},
+		Email:  &config.SMTPConfig{Options: &config.EmailOptions{ResetTTL: 900}},
+	}
+	user := model.User{Model: model.Model{ID: userID}, Email: testEmail}
+
+	t.Run("Known email", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockUserRepository(ctrl)
+		mockTokenRepo := mock.NewMockTokenRepository(ctrl)
+		mockSigner := secMock.NewMockSigner(ctrl)
+		mockMailer := mailMock.NewMockMailer(ctrl)
+
+		audience := cfg.Server.URL + "/auth/reset-password"
+		var wg sync.WaitGroup
+		wg.Add(1)
+		mockRepo.EXPECT().FindUserByEmail(gomock.Any(), testEmail).Return(user, nil)
+		mockSigner.EXPECT().SignWithID(security.PurposeReset, gomock.Any(), userID, []string{audience}, 900*time.Second).Return(token, nil)
+		mockTokenRepo.EXPECT().SaveToken(gomock.Any(), gomock.Cond(func(p repository.SaveTokenParams) bool {
+			return p.Email == testEmail && p.Purpose == "password_reset" && p.ID != ""
+		})).Return(nil)
+		mockMailer.EXPECT().SendHTML([]string{testEmail}, "Reset your password", "password_reset", map[string]string{
+			"Title":  "Password reset",
+			"Header": "Reset your password",
+			"Link":   audience + "?token=" + token,
+		}).Do(func(_ []string, _, _ string, _ map[string]string) {
+			defer wg.Done()
+		})
+
+		svc := service.NewAuthService(&service.AuthServiceDeps{
+			Repo:      mockRepo,
+			TokenRepo: mockTokenRepo,
+			Signer:    mockSigner,
+			Mailer:    mockMailer,
+			Cfg:       cfg,
+		})
+
+		assert.NoError(t, svc.ForgotPassword(context.Background(), testEmail))
+		wg.Wait()
+	})
+
+	t.Run("Unknown email", func(t *testing.T) {
+		t.Parallel()
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockUserRepository(ctrl)
+		mockRepo.EXPECT().FindUserByEmail(gomock.Any(), testEmail).Return(model.User{}, sql.ErrNoRows)
+
+		svc := service.NewAuthService(&service.AuthServiceDeps{Repo: mockRepo, Cfg: cfg})
+
+		assert.NoError(t, svc.ForgotPassword(context.Background(), testEmail))
+	})
+}
+
+func TestAuthService_ResetPassword(t *testing.T) {
+	t.Parallel()
+	const (
+		userID    = "1"
+		testEmail = "abc@example.com"
+		token     = "reset_token"
+		newPass   = "new_password"
+		newHash   = "new_hash"
+		audience  = "http://localhost:8888/auth/reset-password"
+	)
+
+	cfg := &config.Config{Server: &config.ServerConfig{URL: "http://localhost:8888"}}
+
+	claims := &security.Claims{ID: "jti", Subject: userID}
+	user := model.User{Model: model.Model{ID: userID}, Email: testEmail}
+	params := service.ResetPasswordParams{Token: token, Password: newPass}
+
+	testCases := []struct {
+		name    string
+		setup   func(repo *mock.MockUserRepository, tokens *mock.MockTokenRepository, m refreshMocks)
+		wantErr error
+	}{
+		{
+			name: "Success",
+			setup: func(repo *mock.MockUserRepository, tokens *mock.MockTokenRepository, m refreshMocks) {
+				m.signer.EXPECT().Verify(token, security.PurposeReset, audience).Return(claims, nil)
+				tokens.EXPECT().ConsumeToken(gomock.Any(), claims.ID, "password_reset").Return(testEmail, nil)
+				repo.EXPECT().FindUserByEmail(gomock.Any(), testEmail).Return(user, nil)
+				repo.EXPECT().UpdatePassword(gomock.Any(), userID, newHash).Return(nil)
+				m.repo.EXPECT().RevokeUserRefreshTokens(gomock.Any(), userID).Return(nil)
+			},
+		},
+		{
+			name: "Failure_TokenAlreadyUsed",
+			setup: func(_ *mock.MockUserRepository, tokens *mock.MockTokenRepository, m refreshMocks) {
+				m.signer.EXPECT().Verify(token, security.PurposeReset, audience).Return(claims, nil)
+				tokens.EXPECT().ConsumeToken(gomock.Any(), claims.ID, "password_reset").Return("", sql.ErrNoRows)
+			},
+			wantErr: service.ErrInvalidToken,
+		},
+		{
+			name: "Failure_InvalidSignature",
+			setup: func(_ *mock.MockUserRepository, _ *mock.MockTokenRepository, m refreshMocks) {
+				m.signer.EXPECT().Verify(token, security.PurposeReset, audience).Return(nil, errors.New("signature is invalid"))
+			},
+			wantErr: service.ErrInvalidToken,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockRepo := mock.NewMockUserRepository(ctrl)
+			mockTokenRepo := mock.NewMockTokenRepository(ctrl)
+			mockRefreshRepo := mock.NewMockRefreshTokenRepository(ctrl)
+			mockHasher := secMock.NewMockHasher(ctrl)
+			mockSigner := secMock.NewMockSigner(ctrl)
+			mockHasher.EXPECT().Hash(newPass).Return(newHash, nil).AnyTimes()
+			tc.setup(mockRepo, mockTokenRepo, refreshMocks{signer: mockSigner, repo: mockRefreshRepo})
+
+			svc := service.NewAuthService(&service.AuthServiceDeps{
+				Repo:             mockRepo,
+				RefreshTokenRepo: mockRefreshRepo,
+				TokenRepo:        mockTokenRepo,
+				Hasher:           mockHasher,
+				Signer:           mockSigner,
+				Cfg:              cfg,
+			})
+
+			err := svc.ResetPassword(context.Background(), params)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAuthService_ResendVerification(t *testing.T) {
+	t.Parallel()
+	const (
+		userID    = "1"
+		testEmail = "abc@example.com"
+		token     = "verify_token"
+	)
+
+	cfg := &config.Config{
+		Server: &config.ServerConfig{URL: "http://localhost:8888"},
+		JWT:    &config.JWTOptions{JTILen: 32},
+		Email:  &config.SMTPConfig{Options: &config.EmailOptions{VerifyTTL: 300, ResendInterval: 60}},
+	}
+	verifiedAt := time.Now()
+	unverified := model.User{Model: model.Model{ID: userID}, Email: testEmail}
+	verified := model.User{Model: model.Model{ID: userID}, Email: testEmail, VerifiedAt: &verifiedAt}
+
+	testCases := []struct {
+		name     string
+		user     model.User
+		findErr  error
+		sent     int
+		wantSend bool
+	}{
+		{name: "Sends to unverified user", user: unverified, wantSend: true},
+		{name: "Throttled", user: unverified, sent: 1},
+		{name: "Already verified", user: verified},
+		{name: "Unknown email", findErr: sql.ErrNoRows},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockRepo := mock.NewMockUserRepository(ctrl)
+			mockTokenRepo := mock.NewMockTokenRepository(ctrl)
+			mockSigner := secMock.NewMockSigner(ctrl)
+			mockMailer := mailMock.NewMockMailer(ctrl)
+
+			mockRepo.EXPECT().FindUserByEmail(gomock.Any(), testEmail).Return(tc.user, tc.findErr)
+			if tc.findErr == nil && tc.user.VerifiedAt == nil {
+				mockTokenRepo.EXPECT().CountTokensSince(gomock.Any(), testEmail, "verification", gomock.Any()).
+					Return(tc.sent, nil)
+			}
+
+			var wg sync.WaitGroup
+			if tc.wantSend {
+				wg.Add(1)
+				mockSigner.EXPECT().SignWithID(security.PurposeVerify, gomock.Any(), userID, gomock.Any(), 300*time.Second).Return(token, nil)
+				mockTokenRepo.EXPECT().SaveToken(gomock.Any(), gomock.Any()).Return(nil)
+				mockMailer.EXPECT().SendHTML([]string{testEmail}, "Verify your email", "verification", gomock.Any()).
+					Do(func(_ []string, _, _ string, _ map[string]string) {
+						defer wg.Done()
+					})
+			}
+
+			svc := service.NewAuthService(&service.AuthServiceDeps{
+				Repo:      mockRepo,
+				TokenRepo: mockTokenRepo,
+				Signer:    mockSigner,
+				Mailer:    mockMailer,
+				Cfg:       cfg,
+			})
+
+			assert.NoError(t, svc.ResendVerification(context.Background(), testEmail))
+			wg.Wait()
+		})
+	}
+}
+
+func TestAuthService_ChangePassword(t *testing.T) {
+	t.Parallel()
+	const (
+		userID       = "1"
+		testEmail    = "abc@example.com"
+		currentPass  = "current_password"
+		currentHash  = "current_hash"
+		newPass      = "new_password"
+		newHash      = "new_hash"
+		refreshToken = "refresh_token"
+		familyID     = "family"
+		issuer       = "gojeep"
+	)
+
+	cfg := &config.Config{JWT: &config.JWTOptions{Issuer: issuer}}
+	user := model.User{Model: model.Model{ID: userID}, Email: testEmail, PasswordHash: currentHash}
+	claims := &security.Claims{ID: "jti", Subject: userID}
+	stored := model.RefreshToken{ID: claims.ID, UserID: userID, FamilyID: familyID}
+
+	testCases := []struct {
+		name         string
+		refreshToken string
+		setup        func(repo *mock.MockUserRepository, hasher *secMock.MockHasher, m refreshMocks)
+		wantEmail    bool
+		wantErr      error
+	}{
+		{
+			name:         "Keeps current session",
+			refreshToken: refreshToken,
+			setup: func(repo *mock.MockUserRepository, hasher *secMock.MockHasher, m refreshMocks) {
+				repo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil)
+				hasher.EXPECT().Verify(currentPass, currentHash).Return(true, nil)
+				hasher.EXPECT().Hash(newPass).Return(newHash, nil)
+				repo.EXPECT().UpdatePassword(gomock.Any(), userID, newHash).Return(nil)
+				m.signer.EXPECT().Verify(refreshToken, security.PurposeRefresh, issuer).Return(claims, nil)
+				m.repo.EXPECT().FindRefreshToken(gomock.Any(), claims.ID).Return(stored, nil)
+				m.repo.EXPECT().RevokeOtherRefreshTokens(gomock.Any(), userID, familyID).Return(nil)
+			},
+			wantEmail: true,
+		},
+		{
+			name: "Without refresh token signs out every session",
+			setup: func(repo *mock.MockUserRepository, hasher *secMock.MockHasher, m refreshMocks) {
+				repo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil)
+				hasher.EXPECT().Verify(currentPass, currentHash).Return(true, nil)
+				hasher.EXPECT().Hash(newPass).Return(newHash, nil)
+				repo.EXPECT().UpdatePassword(gomock.Any(), userID, newHash).Return(nil)
+				m.repo.EXPECT().RevokeUserRefreshTokens(gomock.Any(), userID).Return(nil)
+			},
+			wantEmail: true,
+		},
+		{
+			name:         "Wrong current password",
+			refreshToken: refreshToken,
+			setup: func(repo *mock.MockUserRepository, hasher *secMock.MockHasher, _ refreshMocks) {
+				repo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil)
+				hasher.EXPECT().Verify(currentPass, currentHash).Return(false, nil)
+			},
+			wantErr: service.ErrWrongPassword,
+		},
+		{
+			name: "User not found",
+			setup: func(repo *mock.MockUserRepository, _ *secMock.MockHasher, _ refreshMocks) {
+				repo.EXPECT().FindUserByID(gomock.Any(), userID).Return(model.User{}, sql.ErrNoRows)
+			},
+			wantErr: service.ErrUserNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockRepo := mock.NewMockUserRepository(ctrl)
+			mockRefreshRepo := mock.NewMockRefreshTokenRepository(ctrl)
+			mockHasher := secMock.NewMockHasher(ctrl)
+			mockSigner := secMock.NewMockSigner(ctrl)
+			mockMailer := mailMock.NewMockMailer(ctrl)
+			tc.setup(mockRepo, mockHasher, refreshMocks{signer: mockSigner, repo: mockRefreshRepo})
+
+			var wg sync.WaitGroup
+			if tc.wantEmail {
+				wg.Add(1)
+				mockMailer.EXPECT().SendHTML([]string{testEmail}, gomock.Any(), "password_changed", gomock.Any()).
+					Do(func(_ []string, _, _ string, _ map[string]string) {
+						defer wg.Done()
+					})
+			}
+
+			svc := service.NewAuthService(&service.AuthServiceDeps{
+				Repo:             mockRepo,
+				RefreshTokenRepo: mockRefreshRepo,
+				Hasher:           mockHasher,
+				Signer:           mockSigner,
+				Mailer:           mockMailer,
+				Cfg:              cfg,
+			})
+
+			err := svc.ChangePassword(context.Background(), service.ChangePasswordParams{
+				UserID:          userID,
+				CurrentPassword: currentPass,
+				NewPassword:     newPass,
+				RefreshToken:    tc.refreshToken,
+			})
+			wg.Wait()
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAuthService_RequestEmailChange(t *testing.T) {
+	t.Parallel()
+	const (
+		userID   = "1"
+		oldEmail = "old@example.com"
+		newEmail = "new@example.com"
+		password = "password"
+		hash     = "hash"
+		token    = "email_change_token"
+	)
+
+	cfg := &config.Config{
+		Server: &config.ServerConfig{URL: "http://localhost:8888"},
+		JWT:    &config.JWTOptions{JTILen: 32},
+		Email:  &config.SMTPConfig{Options: &config.EmailOptions{VerifyTTL: 300}},
+	}
+	user := model.User{Model: model.Model{ID: userID}, Email: oldEmail, PasswordHash: hash}
+	params := service.RequestEmailChangeParams{UserID: userID, NewEmail: newEmail, Password: password}
+
+	testCases := []struct {
+		name     string
+		verified bool
+		existing error
+		wantSend bool
+		wantErr  error
+	}{
+		{name: "Sends confirmation to new address", verified: true, existing: sql.ErrNoRows, wantSend: true},
+		{name: "Email taken", verified: true, existing: nil, wantErr: service.ErrUserExists},
+		{name: "Wrong password", verified: false, wantErr: service.ErrWrongPassword},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockRepo := mock.NewMockUserRepository(ctrl)
+			mockTokenRepo := mock.NewMockTokenRepository(ctrl)
+			mockHasher := secMock.NewMockHasher(ctrl)
+			mockSigner := secMock.NewMockSigner(ctrl)
+			mockMailer := mailMock.NewMockMailer(ctrl)
+
+			mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil)
+			mockHasher.EXPECT().Verify(password, hash).Return(tc.verified, nil)
+			if tc.verified {
+				mockRepo.EXPECT().FindUserByEmail(gomock.Any(), newEmail).Return(model.User{}, tc.existing)
+			}
+
+			var wg sync.WaitGroup
+			if tc.wantSend {
+				wg.Add(1)
+				mockSigner.EXPECT().
+					SignWithID(security.PurposeEmailChange, gomock.Any(), userID,
+						[]string{"http://localhost:8888/auth/email/confirm"}, 300*time.Second).
+					Return(token, nil)
+				mockTokenRepo.EXPECT().SaveToken(gomock.Any(), gomock.Cond(func(p repository.SaveTokenParams) bool {
+					return p.Email == newEmail && p.Purpose == "email_change"
+				})).Return(nil)
+				mockMailer.EXPECT().SendHTML([]string{newEmail}, gomock.Any(), "email_change", gomock.Any()).
+					Do(func(_ []string, _, _ string, _ map[string]string) {
+						defer wg.Done()
+					})
+			}
+
+			svc := service.NewAuthService(&service.AuthServiceDeps{
+				Repo:      mockRepo,
+				TokenRepo: mockTokenRepo,
+				Hasher:    mockHasher,
+				Signer:    mockSigner,
+				Mailer:    mockMailer,
+				Cfg:       cfg,
+			})
+
+			err := svc.RequestEmailChange(context.Background(), params)
+			wg.Wait()
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAuthService_ConfirmEmailChange(t *testing.T) {
+	t.Parallel()
+	const (
+		userID   = "1"
+		oldEmail = "old@example.com"
+		newEmail = "new@example.com"
+		token    = "email_change_token"
+		audience = "http://localhost:8888/auth/email/confirm"
+	)
+
+	cfg := &config.Config{Server: &config.ServerConfig{URL: "http://localhost:8888"}}
+	claims := &security.Claims{ID: "jti", Subject: userID}
+	user := model.User{Model: model.Model{ID: userID}, Email: oldEmail}
+
+	testCases := []struct {
+		name       string
+		setup      func(repo *mock.MockUserRepository, tokens *mock.MockTokenRepository, signer *secMock.MockSigner)
+		wantNotice bool
+		wantErr    error
+	}{
+		{
+			name: "Email changed",
+			setup: func(repo *mock.MockUserRepository, tokens *mock.MockTokenRepository, signer *secMock.MockSigner) {
+				signer.EXPECT().Verify(token, security.PurposeEmailChange, audience).Return(claims, nil)
+				tokens.EXPECT().ConsumeToken(gomock.Any(), claims.ID, "email_change").Return(newEmail, nil)
+				repo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil)
+				repo.EXPECT().UpdateEmail(gomock.Any(), userID, newEmail).Return(nil)
+			},
+			wantNotice: true,
+		},
+		{
+			name: "Email taken since the request",
+			setup: func(repo *mock.MockUserRepository, tokens *mock.MockTokenRepository, signer *secMock.MockSigner) {
+				signer.EXPECT().Verify(token, security.PurposeEmailChange, audience).Return(claims, nil)
+				tokens.EXPECT().ConsumeToken(gomock.Any(), claims.ID, "email_change").Return(newEmail, nil)
+				repo.EXPECT().FindUserByID(gomock.Any(), userID).Return(user, nil)
+				repo.EXPECT().UpdateEmail(gomock.Any(), userID, newEmail).Return(repository.ErrEmailTaken)
+			},
+			wantErr: service.ErrUserExists,
+		},
+		{
+			name: "Used token",
+			setup: func(_ *mock.MockUserRepository, tokens *mock.MockTokenRepository, signer *secMock.MockSigner) {
+				signer.EXPECT().Verify(token, security.PurposeEmailChange, audience).Return(claims, nil)
+				tokens.EXPECT().ConsumeToken(gomock.Any(), claims.ID, "email_change").Return("", sql.ErrNoRows)
+			},
+			wantErr: service.ErrInvalidToken,
+		},
+		{
+			name: "Token of another purpose",
+			setup: func(_ *mock.MockUserRepository, _ *mock.MockTokenRepository, signer *secMock.MockSigner) {
+				signer.EXPECT().Verify(token, security.PurposeEmailChange, audience).Return(nil, security.ErrTokenPurpose)
+			},
+			wantErr: service.ErrInvalidToken,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockRepo := mock.NewMockUserRepository(ctrl)
+			mockTokenRepo := mock.NewMockTokenRepository(ctrl)
+			mockSigner := secMock.NewMockSigner(ctrl)
+			mockMailer := mailMock.NewMockMailer(ctrl)
+			tc.setup(mockRepo, mockTokenRepo, mockSigner)
+
+			var wg sync.WaitGroup
+			if tc.wantNotice {
+				wg.Add(1)
+				mockMailer.EXPECT().SendHTML([]string{oldEmail}, gomock.Any(), "email_changed", gomock.Any()).
+					Do(func(_ []string, _, _ string, _ map[string]string) {
+						defer wg.Done()
+					})
+			}
+
+			svc := service.NewAuthService(&service.AuthServiceDeps{
+				Repo:      mockRepo,
+				TokenRepo: mockTokenRepo,
+				Signer:    mockSigner,
+				Mailer:    mockMailer,
+				Cfg:       cfg,
+			})
+
+			err := svc.ConfirmEmailChange(context.Background(), token)
+			wg.Wait()
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestAuthService_VerifyMFA(t *testing.T) {
+	t.Parallel()
+	const (
+		userID   = "1"
+		token    = "mfa_challenge_token"
+		code     = "123456"
+		audience = "http://localhost:8888/auth/mfa/verify"
+	)
+
+	cfg := &config.Config{
+		Server: &config.ServerConfig{URL: "http://localhost:8888"},
+		JWT:    &config.JWTOptions{JTILen: 32, Issuer: "gojeep", Duration: 15, RefreshDuration: 10080},
+	}
+	claims := &security.Claims{ID: "jti", Subject: userID}
+	params := service.VerifyMFAParams{Token: token, Code: code}
+
+	testCases := []struct {
+		name    string
+		setup   func(tokens *mock.MockTokenRepository, mfa *svcMock.MockMFAService, m refreshMocks)
+		wantErr error
+	}{
+		{
+			name: "Login completed",
+			setup: func(tokens *mock.MockTokenRepository, mfa *svcMock.MockMFAService, m refreshMocks) {
+				m.signer.EXPECT().Verify(token, security.PurposeMFA, audience).Return(claims, nil)
+				tokens.EXPECT().ConsumeToken(gomock.Any(), claims.ID, "mfa_challenge").Return("abc@example.com", nil)
+				mfa.EXPECT().VerifyCode(gomock.Any(), userID, code).Return(nil)
+				m.users.EXPECT().FindUserStatus(gomock.Any(), userID).Return(model.UserStatus{}, nil)
+				m.sessions.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Return(nil)
+				m.roles.EXPECT().FindUserRoles(gomock.Any(), userID).Return([]string{}, nil)
+				m.signer.EXPECT().SignWithRoles(security.PurposeAccess, userID, []string{cfg.JWT.Issuer}, []string{},
+					15*time.Minute).Return("access_token", nil)
+				m.signer.EXPECT().SignWithID(security.PurposeRefresh, gomock.Any(), userID, []string{cfg.JWT.Issuer},
+					7*24*time.Hour).Return("refresh_token", nil)
+				m.repo.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "Wrong code",
+			setup: func(tokens *mock.MockTokenRepository, mfa *svcMock.MockMFAService, m refreshMocks) {
+				m.signer.EXPECT().Verify(token, security.PurposeMFA, audience).Return(claims, nil)
+				tokens.EXPECT().ConsumeToken(gomock.Any(), claims.ID, "mfa_challenge").Return("abc@example.com", nil)
+				mfa.EXPECT().VerifyCode(gomock.Any(), userID, code).Return(service.ErrInvalidMFACode)
+			},
+			wantErr: service.ErrInvalidMFACode,
+		},
+		{
+			name: "Challenge already used",
+			setup: func(tokens *mock.MockTokenRepository, _ *svcMock.MockMFAService, m refreshMocks) {
+				m.signer.EXPECT().Verify(token, security.PurposeMFA, audience).Return(claims, nil)
+				tokens.EXPECT().ConsumeToken(gomock.Any(), claims.ID, "mfa_challenge").Return("", sql.ErrNoRows)
+			},
+			wantErr: service.ErrInvalidToken,
+		},
+		{
+			name: "Access token presented as challenge",
+			setup: func(_ *mock.MockTokenRepository, _ *svcMock.MockMFAService, m refreshMocks) {
+				m.signer.EXPECT().Verify(token, security.PurposeMFA, audience).Return(nil, security.ErrTokenPurpose)
+			},
+			wantErr: service.ErrInvalidToken,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			m := refreshMocks{
+				signer:   secMock.NewMockSigner(ctrl),
+				repo:     mock.NewMockRefreshTokenRepository(ctrl),
+				roles:    mock.NewMockRoleRepository(ctrl),
+				sessions: mock.NewMockSessionRepository(ctrl),
+				users:    mock.NewMockUserRepository(ctrl),
+			}
+			mockTokenRepo := mock.NewMockTokenRepository(ctrl)
+			mockMFA := svcMock.NewMockMFAService(ctrl)
+			tc.setup(mockTokenRepo, mockMFA, m)
+
+			svc := service.NewAuthService(&service.AuthServiceDeps{
+				Repo:             m.users,
+				RefreshTokenRepo: m.repo,
+				RoleRepo:         m.roles,
+				SessionRepo:      m.sessions,
+				TokenRepo:        mockTokenRepo,
+				MFA:              mockMFA,
+				Signer:           m.signer,
+				Cfg:              cfg,
+			})
+
+			accessToken, refreshToken, err := svc.VerifyMFA(context.Background(), params)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Empty(t, accessToken)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "access_token", accessToken)
+			assert.Equal(t, "refresh_token", refreshToken)
+		})
+	}
+}
+
+func TestAuthService_VerifyMFA_LockedOut(t *testing.T) {
+	t.Parallel()
+	const (
+		threshold = 3
+		lockKey   = "mfa:" + mfaUserID
+		audience  = "http://localhost:8888/auth/mfa/verify"
+	)
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	cfg := &config.Config{
+		Server:  &config.ServerConfig{URL: "http://localhost:8888"},
+		Lockout: &config.LockoutOptions{Threshold: threshold, BaseDelay: 30, MaxDelay: 300, Window: 3600},
+	}
+
+	// The failures are kept as the database would, so the lock outlives each challenge.
+	var (
+		failures    int
+		lockedUntil time.Time
+	)
+	failureRepo := mock.NewMockLoginFailureRepository(ctrl)
+	failureRepo.EXPECT().FindLoginLock(gomock.Any(), lockKey, lockKey).
+		DoAndReturn(func(_ context.Context, _, _ string) (time.Time, error) { return lockedUntil, nil }).AnyTimes()
+	failureRepo.EXPECT().RecordLoginFailure(gomock.Any(), lockKey, time.Hour).
+		DoAndReturn(func(_ context.Context, _ string, _ time.Duration) (int, error) {
+			failures++
+			return failures, nil
+		}).AnyTimes()
+	failureRepo.EXPECT().LockLogin(gomock.Any(), lockKey, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, until time.Time) error {
+			lockedUntil = until
+			return nil
+		}).AnyTimes()
+
+	encrypter, err := security.NewAESEncrypter("server_key", "totp")
+	require.NoError(t, err)
+	mfa, _ := newTestMFA(t, encrypter, true)
+	mfaRepo := mock.NewMockMFARepository(ctrl)
+	mfaRepo.EXPECT().FindMFA(gomock.Any(), mfaUserID).Return(mfa, nil).Times(threshold)
+	mfaRepo.EXPECT().FindUnusedRecoveryCodes(gomock.Any(), mfaUserID).Return(nil, nil).Times(threshold)
+
+	mfaSvc := service.NewMFAService(&service.MFAServiceDeps{
+		Repo:      mfaRepo,
+		Lockout:   service.NewLockoutService(&service.LockoutServiceDeps{Repo: failureRepo, Cfg: cfg}),
+		Encrypter: encrypter,
+		Cfg:       cfg,
+	})
+
+	signer := secMock.NewMockSigner(ctrl)
+	tokenRepo := mock.NewMockTokenRepository(ctrl)
+	svc := service.NewAuthService(&service.AuthServiceDeps{
+		TokenRepo: tokenRepo,
+		MFA:       mfaSvc,
+		Signer:    signer,
+		Cfg:       cfg,
+	})
+
+	for attempt := 1; attempt <= threshold+1; attempt++ {
+		token := fmt.Sprintf("challenge_%d", attempt)
+		claims := &security.Claims{ID: token, Subject: mfaUserID}
+		signer.EXPECT().Verify(token, security.PurposeMFA, audience).Return(claims, nil)
+		tokenRepo.EXPECT().ConsumeToken(gomock.Any(), token, "mfa_challenge").Return("abc@example.com", nil)
+
+		_, _, err := svc.VerifyMFA(ctx, service.VerifyMFAParams{Token: token, Code: "wrong-code"})
+		if attempt <= threshold {
+			assert.ErrorIs(t, err, service.ErrInvalidMFACode, "attempt %d", attempt)
+			continue
+		}
+		assert.ErrorIs(t, err, service.ErrAccountLocked, "attempt %d", attempt)
+	}
+}
@@ -0,0 +1,343 @@
+//go:generate mockgen -destination=mock/export_service_mock.go -package=mock . ExportService
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/config"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/pkg/email"
+	"github.com/ferdiebergado/gojeep/internal/pkg/security"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+)
+
+// ExportService builds archives of the personal data held on a user, for data subject access requests.
+// An archive is built in the background and the user is emailed a signed link to download it.
+type ExportService interface {
+	RequestExport(ctx context.Context, userID string) error
+	DownloadExport(ctx context.Context, token string) (model.DataExport, error)
+	PurgeExpiredExports(ctx context.Context) (int64, error)
+}
+
+type ExportServiceDeps struct {
+	Repo        repository.ExportRepository
+	UserRepo    repository.UserRepository
+	SessionRepo repository.SessionRepository
+	AuditRepo   repository.AuditRepository
+	OAuthRepo   repository.OAuthRepository
+	Signer      security.Signer
+	Mailer      email.Mailer
+	Cfg         *config.Config
+}
+
+type exportService struct {
+	repo        repository.ExportRepository
+	userRepo    repository.UserRepository
+	sessionRepo repository.SessionRepository
+	auditRepo   repository.AuditRepository
+	oauthRepo   repository.OAuthRepository
+	signer      security.Signer
+	mailer      email.Mailer
+	cfg         *config.Config
+}
+
+var _ ExportService = (*exportService)(nil)
+
+const eventDataExportRequested = "data_export_requested"
+
+var ErrExportPending = errors.New("data export pending")
+
+// ExportPendingError is returned while a recent export has not expired. It matches ErrExportPending.
+type ExportPendingError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ExportPendingError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrExportPending, e.RetryAfter)
+}
+
+func (e *ExportPendingError) Unwrap() error {
+	return ErrExportPending
+}
+
+func NewExportService(deps *ExportServiceDeps) ExportService {
+	return &exportService{
+		repo:        deps.Repo,
+		userRepo:    deps.UserRepo,
+		sessionRepo: deps.SessionRepo,
+		auditRepo:   deps.AuditRepo,
+		oauthRepo:   deps.OAuthRepo,
+		signer:      deps.Signer,
+		mailer:      deps.Mailer,
+		cfg:         deps.Cfg,
+	}
+}
+
+// The files of an archive. Each struct lists the fields exported, so secrets such as the password hash
+// are left out.
+type (
+	exportUser struct {
+		ID         string     `json:"id"`
+		Email      string     `json:"email"`
+		Name       string     `json:"name"`
+		VerifiedAt *time.Time `json:"verified_at"`
+		CreatedAt  time.Time  `json:"created_at"`
+		UpdatedAt  time.Time  `json:"updated_at"`
+	}
+
+	exportSession struct {
+		ID              string     `json:"id"`
+		UserAgent       string     `json:"user_agent"`
+		IPAddress       string     `json:"ip_address"`
+		CreatedAt       time.Time  `json:"created_at"`
+		LastRefreshedAt *time.Time `json:"last_refreshed_at"`
+	}
+
+	exportAuditEvent struct {
+		ID        string    `json:"id"`
+		Event     string    `json:"event"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	exportIdentity struct {
+		ID        string    `json:"id"`
+		Provider  string    `json:"provider"`
+		Subject   string    `json:"subject"`
+		Email     string    `json:"email"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+)
+
+// RequestExport starts building an archive of the user's data. The user is emailed when it is ready.
+// It returns an ExportPendingError while the last export is still being built or can be downloaded.
+func (s *exportService) RequestExport(ctx context.Context, userID string) error {
+	user, err := s.userRepo.FindUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("find user %s: %w", userID, err)
+	}
+
+	if err := s.checkPending(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := s.auditRepo.CreateAuditEvent(ctx, userID, eventDataExportRequested); err != nil {
+		return fmt.Errorf("record data export request: %w", err)
+	}
+
+	go s.buildExport(context.WithoutCancel(ctx), user)
+
+	return nil
+}
+
+// checkPending refuses a new export until the last request's interval has passed and its archive, which
+// is built in the background, has expired.
+func (s *exportService) checkPending(ctx context.Context, userID string) error {
+	requestedAt, err := s.auditRepo.FindLastAuditEventTime(ctx, userID, eventDataExportRequested)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("find last data export request: %w", err)
+	}
+
+	expiresAt, err := s.repo.FindActiveExportExpiry(ctx, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("find active data export: %w", err)
+	}
+
+	until := requestedAt.Add(time.Duration(s.cfg.Account.ExportInterval) * time.Second)
+	if expiresAt.After(until) {
+		until = expiresAt
+	}
+
+	if retryAfter := time.Until(until); retryAfter > 0 {
+		return &ExportPendingError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+func (s *exportService) buildExport(ctx context.Context, user model.User) {
+	slog.Info("Building data export...", "user_id", user.ID)
+
+	archive, err := s.archive(ctx, user)
+	if err != nil {
+		slog.Error("failed to build data export", "user_id", user.ID, "reason", err)
+		return
+	}
+
+	ttl := time.Duration(s.cfg.Account.ExportTTL) * time.Second
+	params := repository.CreateExportParams{
+		UserID:    user.ID,
+		Archive:   archive,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	export, err := s.repo.CreateExport(ctx, params)
+	if err != nil {
+		slog.Error("failed to save data export", "user_id", user.ID, "reason", err)
+		return
+	}
+
+	s.sendExportReadyEmail(user, export.ID, ttl)
+}
+
+// archive builds the ZIP of the user's data, one JSON file per kind of record.
+func (s *exportService) archive(ctx context.Context, user model.User) ([]byte, error) {
+	sessions, err := s.sessionRepo.ListAllSessions(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+
+	events, err := s.auditRepo.ListAuditEvents(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list audit events: %w", err)
+	}
+
+	identities, err := s.oauthRepo.ListIdentities(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list identities: %w", err)
+	}
+
+	files := []struct {
+		name string
+		data any
+	}{
+		{"user.json", exportUser{
+			ID:         user.ID,
+			Email:      user.Email,
+			Name:       user.Name,
+			VerifiedAt: user.VerifiedAt,
+			CreatedAt:  user.CreatedAt,
+			UpdatedAt:  user.UpdatedAt,
+		}},
+		{"sessions.json", toExportSessions(sessions)},
+		{"audit_events.json", toExportAuditEvents(events)},
+		{"identities.json", toExportIdentities(identities)},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, file := range files {
+		w, err := zw.Create(file.name)
+		if err != nil {
+			return nil, fmt.Errorf("create %s: %w", file.name, err)
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(file.data); err != nil {
+			return nil, fmt.Errorf("encode %s: %w", file.name, err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("close archive: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func toExportSessions(sessions []model.Session) []exportSession {
+	exported := make([]exportSession, 0, len(sessions))
+	for _, session := range sessions {
+		exported = append(exported, exportSession{
+			ID:              session.ID,
+			UserAgent:       session.UserAgent,
+			IPAddress:       session.IPAddress,
+			CreatedAt:       session.CreatedAt,
+			LastRefreshedAt: session.LastRefreshedAt,
+		})
+	}
+	return exported
+}
+
+func toExportAuditEvents(events []model.AuditEvent) []exportAuditEvent {
+	exported := make([]exportAuditEvent, 0, len(events))
+	for _, event := range events {
+		exported = append(exported, exportAuditEvent{
+			ID:        event.ID,
+			Event:     event.Event,
+			CreatedAt: event.CreatedAt,
+		})
+	}
+	return exported
+}
+
+func toExportIdentities(identities []model.Identity) []exportIdentity {
+	exported := make([]exportIdentity, 0, len(identities))
+	for _, identity := range identities {
+		exported = append(exported, exportIdentity{
+			ID:        identity.ID,
+			Provider:  identity.Provider,
+			Subject:   identity.Subject,
+			Email:     identity.Email,
+			CreatedAt: identity.CreatedAt,
+		})
+	}
+	return exported
+}
+
+func (s *exportService) sendExportReadyEmail(user model.User, exportID string, ttl time.Duration) {
+	slog.Info("Sending data export email...")
+
+	const (
+		title   = "Data export"
+		subject = "Your data export is ready"
+	)
+
+	audience := s.audience()
+	token, err := s.signer.SignWithID(security.PurposeDataExport, exportID, user.ID, []string{audience}, ttl)
+	if err != nil {
+		slog.Error("failed to generate token", "reason", err)
+		return
+	}
+
+	data := map[string]string{
+		"Title":  title,
+		"Header": subject,
+		"Link":   audience + "?token=" + token,
+	}
+	if err := s.mailer.SendHTML([]string{user.Email}, subject, "data_export", data); err != nil {
+		slog.Error("failed to send email", "reason", err)
+		return
+	}
+}
+
+// DownloadExport returns the export a download link was signed for. The link works until the export
+// expires, and only for the user it was built for.
+func (s *exportService) DownloadExport(ctx context.Context, token string) (model.DataExport, error) {
+	claims, err := s.signer.Verify(token, security.PurposeDataExport, s.audience())
+	if err != nil {
+		return model.DataExport{}, ErrInvalidToken
+	}
+
+	export, err := s.repo.FindExport(ctx, claims.Subject, claims.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.DataExport{}, ErrInvalidToken
+		}
+		return model.DataExport{}, fmt.Errorf("find export %s: %w", claims.ID, err)
+	}
+
+	return export, nil
+}
+
+func (s *exportService) PurgeExpiredExports(ctx context.Context) (int64, error) {
+	purged, err := s.repo.PurgeExports(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("purge data exports: %w", err)
+	}
+	return purged, nil
+}
+
+func (s *exportService) audience() string {
+	return s.cfg.Server.URL + "/exports/download"
+}
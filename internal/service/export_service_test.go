@@ -0,0 +1,268 @@
+package service_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/config"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+	"github.com/ferdiebergado/gojeep/internal/repository/mock"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	mailMock "github.com/ferdiebergado/gojeep/internal/pkg/email/mock"
+	"github.com/ferdiebergado/gojeep/internal/pkg/security"
+	secMock "github.com/ferdiebergado/gojeep/internal/pkg/security/mock"
+)
+
+const (
+	exportUserID   = "1"
+	exportID       = "export-1"
+	exportAudience = "http://localhost:8888/exports/download"
+)
+
+type exportMocks struct {
+	repo     *mock.MockExportRepository
+	users    *mock.MockUserRepository
+	sessions *mock.MockSessionRepository
+	audit    *mock.MockAuditRepository
+	oauth    *mock.MockOAuthRepository
+	signer   *secMock.MockSigner
+	mailer   *mailMock.MockMailer
+}
+
+func newExportService(t *testing.T) (service.ExportService, exportMocks) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	m := exportMocks{
+		repo:     mock.NewMockExportRepository(ctrl),
+		users:    mock.NewMockUserRepository(ctrl),
+		sessions: mock.NewMockSessionRepository(ctrl),
+		audit:    mock.NewMockAuditRepository(ctrl),
+		oauth:    mock.NewMockOAuthRepository(ctrl),
+		signer:   secMock.NewMockSigner(ctrl),
+		mailer:   mailMock.NewMockMailer(ctrl),
+	}
+	cfg := &config.Config{
+		Server:  &config.ServerConfig{URL: "http://localhost:8888"},
+		Account: &config.AccountOptions{ExportTTL: 3600, ExportInterval: 600},
+	}
+	svc := service.NewExportService(&service.ExportServiceDeps{
+		Repo:        m.repo,
+		UserRepo:    m.users,
+		SessionRepo: m.sessions,
+		AuditRepo:   m.audit,
+		OAuthRepo:   m.oauth,
+		Signer:      m.signer,
+		Mailer:      m.mailer,
+		Cfg:         cfg,
+	})
+	return svc, m
+}
+
+// readArchive returns the files of a ZIP archive by name.
+func readArchive(t *testing.T, archive []byte) map[string][]byte {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.NoError(t, err)
+
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		files[f.Name] = data
+	}
+	return files
+}
+
+func TestExportService_RequestExport(t *testing.T) {
+	t.Parallel()
+	svc, m := newExportService(t)
+	ctx := context.Background()
+	user := model.User{
+		Model:        model.Model{ID: exportUserID},
+		Email:        "abc@example.com",
+		Name:         "Abc",
+		PasswordHash: "secret_hash",
+	}
+
+	var (
+		wg      sync.WaitGroup
+		archive []byte
+	)
+	wg.Add(1)
+	m.users.EXPECT().FindUserByID(ctx, exportUserID).Return(user, nil)
+	m.audit.EXPECT().FindLastAuditEventTime(ctx, exportUserID, "data_export_requested").
+		Return(time.Now().Add(-time.Hour), nil)
+	m.repo.EXPECT().FindActiveExportExpiry(ctx, exportUserID).Return(time.Time{}, sql.ErrNoRows)
+	m.audit.EXPECT().CreateAuditEvent(ctx, exportUserID, "data_export_requested").Return(nil)
+	m.sessions.EXPECT().ListAllSessions(gomock.Any(), exportUserID).
+		Return([]model.Session{{ID: "family", UserID: exportUserID, UserAgent: "curl/8.0"}}, nil)
+	m.audit.EXPECT().ListAuditEvents(gomock.Any(), exportUserID).
+		Return([]model.AuditEvent{{ID: "event-1", UserID: exportUserID, Event: "data_export_requested"}}, nil)
+	m.oauth.EXPECT().ListIdentities(gomock.Any(), exportUserID).Return([]model.Identity{}, nil)
+	m.repo.EXPECT().CreateExport(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, params repository.CreateExportParams) (model.DataExport, error) {
+			archive = params.Archive
+			assert.Equal(t, exportUserID, params.UserID)
+			assert.WithinDuration(t, time.Now().Add(time.Hour), params.ExpiresAt, time.Minute)
+			return model.DataExport{ID: exportID, UserID: exportUserID}, nil
+		})
+	m.signer.EXPECT().SignWithID(security.PurposeDataExport, exportID, exportUserID, []string{exportAudience},
+		time.Hour).Return("export_token", nil)
+	m.mailer.EXPECT().SendHTML([]string{user.Email}, "Your data export is ready", "data_export", map[string]string{
+		"Title":  "Data export",
+		"Header": "Your data export is ready",
+		"Link":   exportAudience + "?token=export_token",
+	}).Do(func(_ []string, _, _ string, _ map[string]string) {
+		defer wg.Done()
+	})
+
+	require.NoError(t, svc.RequestExport(ctx, exportUserID))
+	wg.Wait()
+
+	files := readArchive(t, archive)
+	assert.Len(t, files, 4)
+	assert.NotContains(t, string(files["user.json"]), "secret_hash")
+
+	var exportedUser map[string]any
+	require.NoError(t, json.Unmarshal(files["user.json"], &exportedUser))
+	assert.Equal(t, user.Email, exportedUser["email"])
+
+	var sessions []map[string]any
+	require.NoError(t, json.Unmarshal(files["sessions.json"], &sessions))
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "curl/8.0", sessions[0]["user_agent"])
+
+	var events []map[string]any
+	require.NoError(t, json.Unmarshal(files["audit_events.json"], &events))
+	assert.Len(t, events, 1)
+
+	var identities []map[string]any
+	require.NoError(t, json.Unmarshal(files["identities.json"], &identities))
+	assert.Empty(t, identities)
+}
+
+func TestExportService_RequestExport_UserNotFound(t *testing.T) {
+	t.Parallel()
+	svc, m := newExportService(t)
+	m.users.EXPECT().FindUserByID(gomock.Any(), exportUserID).Return(model.User{}, sql.ErrNoRows)
+
+	err := svc.RequestExport(context.Background(), exportUserID)
+	assert.ErrorIs(t, err, service.ErrUserNotFound)
+}
+
+func TestExportService_RequestExport_Pending(t *testing.T) {
+	t.Parallel()
+	user := model.User{Model: model.Model{ID: exportUserID}, Email: "abc@example.com"}
+
+	tests := []struct {
+		name        string
+		requestedAt time.Time
+		expiresAt   time.Time
+		wantRetry   time.Duration
+	}{
+		{
+			name:        "Still being built",
+			requestedAt: time.Now().Add(-time.Minute),
+			wantRetry:   9 * time.Minute,
+		},
+		{
+			name:        "Not expired",
+			requestedAt: time.Now().Add(-time.Hour),
+			expiresAt:   time.Now().Add(30 * time.Minute),
+			wantRetry:   30 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			svc, m := newExportService(t)
+			m.users.EXPECT().FindUserByID(gomock.Any(), exportUserID).Return(user, nil)
+			m.audit.EXPECT().FindLastAuditEventTime(gomock.Any(), exportUserID, "data_export_requested").
+				Return(tt.requestedAt, nil)
+			expiry := m.repo.EXPECT().FindActiveExportExpiry(gomock.Any(), exportUserID)
+			if tt.expiresAt.IsZero() {
+				expiry.Return(time.Time{}, sql.ErrNoRows)
+			} else {
+				expiry.Return(tt.expiresAt, nil)
+			}
+
+			err := svc.RequestExport(context.Background(), exportUserID)
+			assert.ErrorIs(t, err, service.ErrExportPending)
+
+			var pending *service.ExportPendingError
+			require.ErrorAs(t, err, &pending)
+			assert.InDelta(t, tt.wantRetry, pending.RetryAfter, float64(time.Second))
+		})
+	}
+}
+
+func TestExportService_DownloadExport(t *testing.T) {
+	t.Parallel()
+	const token = "export_token"
+	claims := &security.Claims{ID: exportID, Subject: exportUserID}
+	export := model.DataExport{ID: exportID, UserID: exportUserID, Archive: []byte("zip")}
+
+	tests := []struct {
+		name    string
+		setup   func(m exportMocks)
+		wantErr error
+	}{
+		{
+			name: "Downloaded",
+			setup: func(m exportMocks) {
+				m.signer.EXPECT().Verify(token, security.PurposeDataExport, exportAudience).Return(claims, nil)
+				m.repo.EXPECT().FindExport(gomock.Any(), exportUserID, exportID).Return(export, nil)
+			},
+		},
+		{
+			name: "Invalid signature",
+			setup: func(m exportMocks) {
+				m.signer.EXPECT().Verify(token, security.PurposeDataExport, exportAudience).
+					Return(nil, errors.New("token is expired"))
+			},
+			wantErr: service.ErrInvalidToken,
+		},
+		{
+			name: "Export purged",
+			setup: func(m exportMocks) {
+				m.signer.EXPECT().Verify(token, security.PurposeDataExport, exportAudience).Return(claims, nil)
+				m.repo.EXPECT().FindExport(gomock.Any(), exportUserID, exportID).Return(model.DataExport{}, sql.ErrNoRows)
+			},
+			wantErr: service.ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			svc, m := newExportService(t)
+			tt.setup(m)
+
+			got, err := svc.DownloadExport(context.Background(), token)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, export, got)
+		})
+	}
+}
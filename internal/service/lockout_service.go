@@ -0,0 +1,185 @@
+//go:generate mockgen -destination=mock/lockout_service_mock.go -package=mock . LockoutService
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/config"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+)
+
+// LockoutService slows down password guessing. Failed logins are counted per account and per IP address,
+// and each is locked out with exponential backoff once it reaches its threshold. Invalid MFA codes given
+// to the account routes are counted per user in the same way.
+type LockoutService interface {
+	Check(ctx context.Context, email, ipAddress string) error
+	RecordFailure(ctx context.Context, email, ipAddress string) error
+	Reset(ctx context.Context, email string) error
+	CheckMFA(ctx context.Context, userID string) error
+	RecordMFAFailure(ctx context.Context, userID string) error
+	ResetMFA(ctx context.Context, userID string) error
+	PurgeStale(ctx context.Context) (int64, error)
+}
+
+type LockoutServiceDeps struct {
+	Repo repository.LoginFailureRepository
+	Cfg  *config.Config
+}
+
+type lockoutService struct {
+	repo        repository.LoginFailureRepository
+	threshold   int
+	ipThreshold int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	window      time.Duration
+}
+
+var _ LockoutService = (*lockoutService)(nil)
+
+var ErrAccountLocked = errors.New("too many failed logins")
+
+// AccountLockedError is returned while logins are locked out. It matches ErrAccountLocked.
+type AccountLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrAccountLocked, e.RetryAfter)
+}
+
+func (e *AccountLockedError) Unwrap() error {
+	return ErrAccountLocked
+}
+
+func NewLockoutService(deps *LockoutServiceDeps) LockoutService {
+	opts := deps.Cfg.Lockout
+	return &lockoutService{
+		repo:        deps.Repo,
+		threshold:   opts.Threshold,
+		ipThreshold: opts.IPThreshold,
+		baseDelay:   time.Duration(opts.BaseDelay) * time.Second,
+		maxDelay:    time.Duration(opts.MaxDelay) * time.Second,
+		window:      time.Duration(opts.Window) * time.Second,
+	}
+}
+
+// Check returns an AccountLockedError if logins to the account or from the IP address are locked.
+// It runs before the password is hashed, so locked out attempts cost no Argon2 computation.
+func (s *lockoutService) Check(ctx context.Context, email, ipAddress string) error {
+	return s.checkLock(ctx, accountKey(email), ipKey(ipAddress))
+}
+
+func (s *lockoutService) checkLock(ctx context.Context, accountKey, ipKey string) error {
+	until, err := s.repo.FindLoginLock(ctx, accountKey, ipKey)
+	if err != nil {
+		return fmt.Errorf("find login lock: %w", err)
+	}
+
+	if retryAfter := time.Until(until); retryAfter > 0 {
+		return &AccountLockedError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+// RecordFailure counts a failed login against the account and the IP address. The email does not need
+// to belong to a user, so unknown accounts are locked out the same way as existing ones.
+func (s *lockoutService) RecordFailure(ctx context.Context, email, ipAddress string) error {
+	if err := s.recordFailure(ctx, accountKey(email), s.threshold); err != nil {
+		return err
+	}
+
+	if ipAddress == "" {
+		return nil
+	}
+
+	return s.recordFailure(ctx, ipKey(ipAddress), s.ipThreshold)
+}
+
+// Reset clears the failures of an account after a successful login. The IP address keeps its count, so
+// an attacker cannot reset it by signing in to an account of their own.
+func (s *lockoutService) Reset(ctx context.Context, email string) error {
+	if err := s.repo.ClearLoginFailures(ctx, accountKey(email)); err != nil {
+		return fmt.Errorf("clear login failures: %w", err)
+	}
+	return nil
+}
+
+// CheckMFA returns an AccountLockedError if the user gave too many invalid MFA codes. A user's codes are
+// not tied to an IP address, so only the user's key is looked up.
+func (s *lockoutService) CheckMFA(ctx context.Context, userID string) error {
+	return s.checkLock(ctx, mfaKey(userID), mfaKey(userID))
+}
+
+// RecordMFAFailure counts an invalid MFA code against the user.
+func (s *lockoutService) RecordMFAFailure(ctx context.Context, userID string) error {
+	return s.recordFailure(ctx, mfaKey(userID), s.threshold)
+}
+
+// ResetMFA clears the user's invalid MFA codes after a valid one.
+func (s *lockoutService) ResetMFA(ctx context.Context, userID string) error {
+	if err := s.repo.ClearLoginFailures(ctx, mfaKey(userID)); err != nil {
+		return fmt.Errorf("clear mfa failures: %w", err)
+	}
+	return nil
+}
+
+func (s *lockoutService) PurgeStale(ctx context.Context) (int64, error) {
+	purged, err := s.repo.PurgeLoginFailures(ctx, s.window)
+	if err != nil {
+		return 0, fmt.Errorf("purge login failures: %w", err)
+	}
+	return purged, nil
+}
+
+func (s *lockoutService) recordFailure(ctx context.Context, key string, threshold int) error {
+	failures, err := s.repo.RecordLoginFailure(ctx, key, s.window)
+	if err != nil {
+		return fmt.Errorf("record login failure: %w", err)
+	}
+
+	if failures < threshold {
+		return nil
+	}
+
+	until := time.Now().Add(s.backoff(failures - threshold))
+	if err := s.repo.LockLogin(ctx, key, until); err != nil {
+		return fmt.Errorf("lock login: %w", err)
+	}
+
+	return nil
+}
+
+// backoff doubles the base delay for each failure past the threshold, up to the maximum delay.
+func (s *lockoutService) backoff(excess int) time.Duration {
+	delay := s.baseDelay
+	for range excess {
+		delay *= 2
+		if delay >= s.maxDelay {
+			return s.maxDelay
+		}
+	}
+	return min(delay, s.maxDelay)
+}
+
+func accountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func mfaKey(userID string) string {
+	return "mfa:" + userID
+}
+
+// ipKey drops the port of a remote address, which changes with each connection.
+func ipKey(ipAddress string) string {
+	if host, _, err := net.SplitHostPort(ipAddress); err == nil {
+		ipAddress = host
+	}
+	return "ip:" + ipAddress
+}
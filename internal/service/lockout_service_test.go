@@ -0,0 +1,149 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/config"
+	"github.com/ferdiebergado/gojeep/internal/repository/mock"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	lockoutEmail      = "Abc@Example.com"
+	lockoutAccountKey = "account:abc@example.com"
+	lockoutIPKey      = "ip:192.0.2.1"
+	lockoutRemoteAddr = "192.0.2.1:1234"
+)
+
+func newTestLockoutService(t *testing.T) (service.LockoutService, *mock.MockLoginFailureRepository) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	repo := mock.NewMockLoginFailureRepository(ctrl)
+	cfg := &config.Config{
+		Lockout: &config.LockoutOptions{
+			Threshold:   3,
+			IPThreshold: 10,
+			BaseDelay:   30,
+			MaxDelay:    300,
+			Window:      3600,
+		},
+	}
+	return service.NewLockoutService(&service.LockoutServiceDeps{Repo: repo, Cfg: cfg}), repo
+}
+
+func TestLockoutService_Check(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		lockedUntil time.Time
+		wantLocked  bool
+	}{
+		{name: "Not locked"},
+		{name: "Lock expired", lockedUntil: time.Now().Add(-time.Second)},
+		{name: "Locked", lockedUntil: time.Now().Add(time.Minute), wantLocked: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			svc, repo := newTestLockoutService(t)
+			ctx := context.Background()
+			repo.EXPECT().FindLoginLock(ctx, lockoutAccountKey, lockoutIPKey).Return(tc.lockedUntil, nil)
+
+			err := svc.Check(ctx, lockoutEmail, lockoutRemoteAddr)
+			if !tc.wantLocked {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.ErrorIs(t, err, service.ErrAccountLocked)
+			var locked *service.AccountLockedError
+			require.ErrorAs(t, err, &locked)
+			assert.InDelta(t, time.Minute.Seconds(), locked.RetryAfter.Seconds(), 1)
+		})
+	}
+}
+
+func TestLockoutService_RecordFailure(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		failures  int
+		wantDelay time.Duration
+	}{
+		{name: "Below threshold", failures: 2},
+		{name: "At threshold", failures: 3, wantDelay: 30 * time.Second},
+		{name: "Past threshold", failures: 5, wantDelay: 2 * time.Minute},
+		{name: "Capped", failures: 20, wantDelay: 5 * time.Minute},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			svc, repo := newTestLockoutService(t)
+			ctx := context.Background()
+
+			repo.EXPECT().RecordLoginFailure(ctx, lockoutAccountKey, time.Hour).Return(tc.failures, nil)
+			repo.EXPECT().RecordLoginFailure(ctx, lockoutIPKey, time.Hour).Return(1, nil)
+			if tc.wantDelay > 0 {
+				repo.EXPECT().LockLogin(ctx, lockoutAccountKey, gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ string, until time.Time) error {
+						assert.InDelta(t, tc.wantDelay.Seconds(), time.Until(until).Seconds(), 1)
+						return nil
+					})
+			}
+
+			assert.NoError(t, svc.RecordFailure(ctx, lockoutEmail, lockoutRemoteAddr))
+		})
+	}
+}
+
+func TestLockoutService_Reset(t *testing.T) {
+	t.Parallel()
+	svc, repo := newTestLockoutService(t)
+	ctx := context.Background()
+	repo.EXPECT().ClearLoginFailures(ctx, lockoutAccountKey).Return(nil)
+
+	assert.NoError(t, svc.Reset(ctx, lockoutEmail))
+}
+
+func TestLockoutService_MFA(t *testing.T) {
+	t.Parallel()
+	const (
+		userID = "1"
+		key    = "mfa:1"
+	)
+	ctx := context.Background()
+
+	t.Run("Check", func(t *testing.T) {
+		t.Parallel()
+		svc, repo := newTestLockoutService(t)
+		repo.EXPECT().FindLoginLock(ctx, key, key).Return(time.Now().Add(time.Minute), nil)
+
+		assert.ErrorIs(t, svc.CheckMFA(ctx, userID), service.ErrAccountLocked)
+	})
+
+	t.Run("Record failure", func(t *testing.T) {
+		t.Parallel()
+		svc, repo := newTestLockoutService(t)
+		repo.EXPECT().RecordLoginFailure(ctx, key, time.Hour).Return(3, nil)
+		repo.EXPECT().LockLogin(ctx, key, gomock.Any()).Return(nil)
+
+		assert.NoError(t, svc.RecordMFAFailure(ctx, userID))
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		t.Parallel()
+		svc, repo := newTestLockoutService(t)
+		repo.EXPECT().ClearLoginFailures(ctx, key).Return(nil)
+
+		assert.NoError(t, svc.ResetMFA(ctx, userID))
+	})
+}
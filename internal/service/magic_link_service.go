@@ -0,0 +1,177 @@
+//go:generate mockgen -destination=mock/magic_link_service_mock.go -package=mock . MagicLinkService
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/config"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/pkg/email"
+	"github.com/ferdiebergado/gojeep/internal/pkg/security"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+)
+
+// MagicLinkService signs users in without a password through a single-use link sent to their email.
+//
+// A link only works in the browser that asked for it: RequestLink returns a random binding that the
+// caller keeps in that browser, and the link's token ID is the hash of the binding. A link forwarded to,
+// or phished by, someone else cannot be used without the binding.
+type MagicLinkService interface {
+	RequestLink(ctx context.Context, email string) (binding string, err error)
+	ConsumeLink(ctx context.Context, token, binding string) (LoginResult, error)
+}
+
+type MagicLinkServiceDeps struct {
+	UserRepo  repository.UserRepository
+	TokenRepo repository.TokenRepository
+	Auth      AuthService
+	Signer    security.Signer
+	Mailer    email.Mailer
+	Cfg       *config.Config
+}
+
+type magicLinkService struct {
+	userRepo  repository.UserRepository
+	tokenRepo repository.TokenRepository
+	auth      AuthService
+	signer    security.Signer
+	mailer    email.Mailer
+	cfg       *config.Config
+}
+
+var _ MagicLinkService = (*magicLinkService)(nil)
+
+const purposeMagicLink = "magic_link"
+
+func NewMagicLinkService(deps *MagicLinkServiceDeps) MagicLinkService {
+	return &magicLinkService{
+		userRepo:  deps.UserRepo,
+		tokenRepo: deps.TokenRepo,
+		auth:      deps.Auth,
+		signer:    deps.Signer,
+		mailer:    deps.Mailer,
+		cfg:       deps.Cfg,
+	}
+}
+
+// RequestLink emails a login link if the address belongs to a verified user, at most once per resend
+// interval. A binding is returned either way so the response does not reveal which emails are registered.
+func (s *magicLinkService) RequestLink(ctx context.Context, email string) (string, error) {
+	binding, err := security.GenerateRandomBytesEncoded(s.cfg.JWT.JTILen)
+	if err != nil {
+		return "", fmt.Errorf("generate magic link binding: %w", err)
+	}
+
+	user, err := s.userRepo.FindUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			slog.Info("Magic link requested for unknown email")
+			return binding, nil
+		}
+		return "", err
+	}
+
+	if user.VerifiedAt == nil {
+		slog.Info("Magic link requested for unverified email", "user_id", user.ID)
+		return binding, nil
+	}
+
+	interval := time.Duration(s.cfg.Email.Options.ResendInterval) * time.Second
+	sent, err := s.tokenRepo.CountTokensSince(ctx, email, purposeMagicLink, time.Now().Add(-interval))
+	if err != nil {
+		return "", fmt.Errorf("count magic link tokens: %w", err)
+	}
+
+	if sent > 0 {
+		slog.Info("Magic link request throttled", "user_id", user.ID)
+		return binding, nil
+	}
+
+	go s.sendMagicLinkEmail(context.WithoutCancel(ctx), user, security.HashToken(binding))
+
+	return binding, nil
+}
+
+func (s *magicLinkService) sendMagicLinkEmail(ctx context.Context, user model.User, id string) {
+	slog.Info("Sending magic link email...")
+
+	const (
+		title   = "Login link"
+		subject = "Your login link"
+	)
+
+	audience := s.audience()
+	ttl := time.Duration(s.cfg.Email.Options.MagicLinkTTL) * time.Second
+	token, err := s.signer.SignWithID(security.PurposeMagicLink, id, user.ID, []string{audience}, ttl)
+	if err != nil {
+		slog.Error("failed to generate token", "reason", err)
+		return
+	}
+
+	params := repository.SaveTokenParams{
+		ID:        id,
+		Email:     user.Email,
+		Purpose:   purposeMagicLink,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.tokenRepo.SaveToken(ctx, params); err != nil {
+		slog.Error("failed to save token", "reason", err)
+		return
+	}
+
+	data := map[string]string{
+		"Title":  title,
+		"Header": subject,
+		"Link":   audience + "?token=" + token,
+	}
+	if err := s.mailer.SendHTML([]string{user.Email}, subject, "magic_link", data); err != nil {
+		slog.Error("failed to send email", "reason", err)
+		return
+	}
+}
+
+// ConsumeLink signs in the user of a login link opened in the browser holding its binding. The link
+// is the first factor, so users with MFA still get a challenge.
+func (s *magicLinkService) ConsumeLink(ctx context.Context, token, binding string) (LoginResult, error) {
+	claims, err := s.signer.Verify(token, security.PurposeMagicLink, s.audience())
+	if err != nil {
+		return LoginResult{}, ErrInvalidToken
+	}
+
+	// Checked before the token is consumed, so opening a link in the wrong browser does not burn it.
+	if binding == "" || subtle.ConstantTimeCompare([]byte(claims.ID), []byte(security.HashToken(binding))) != 1 {
+		return LoginResult{}, ErrInvalidToken
+	}
+
+	email, err := s.tokenRepo.ConsumeToken(ctx, claims.ID, purposeMagicLink)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return LoginResult{}, ErrInvalidToken
+		}
+		return LoginResult{}, err
+	}
+
+	user, err := s.userRepo.FindUserByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return LoginResult{}, ErrInvalidToken
+		}
+		return LoginResult{}, err
+	}
+
+	if user.ID != claims.Subject {
+		return LoginResult{}, ErrInvalidToken
+	}
+
+	return s.auth.CompleteLogin(ctx, user)
+}
+
+func (s *magicLinkService) audience() string {
+	return s.cfg.Server.URL + "/auth/magic-link/consume"
+}
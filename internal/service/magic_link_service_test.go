@@ -0,0 +1,235 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/config"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+	"github.com/ferdiebergado/gojeep/internal/repository/mock"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	mailMock "github.com/ferdiebergado/gojeep/internal/pkg/email/mock"
+	"github.com/ferdiebergado/gojeep/internal/pkg/security"
+	secMock "github.com/ferdiebergado/gojeep/internal/pkg/security/mock"
+	svcMock "github.com/ferdiebergado/gojeep/internal/service/mock"
+)
+
+const magicLinkAudience = "http://localhost:8888/auth/magic-link/consume"
+
+type magicLinkMocks struct {
+	userRepo  *mock.MockUserRepository
+	tokenRepo *mock.MockTokenRepository
+	auth      *svcMock.MockAuthService
+	signer    *secMock.MockSigner
+	mailer    *mailMock.MockMailer
+}
+
+func newMagicLinkService(t *testing.T) (service.MagicLinkService, magicLinkMocks) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	m := magicLinkMocks{
+		userRepo:  mock.NewMockUserRepository(ctrl),
+		tokenRepo: mock.NewMockTokenRepository(ctrl),
+		auth:      svcMock.NewMockAuthService(ctrl),
+		signer:    secMock.NewMockSigner(ctrl),
+		mailer:    mailMock.NewMockMailer(ctrl),
+	}
+	cfg := &config.Config{
+		Server: &config.ServerConfig{URL: "http://localhost:8888"},
+		JWT:    &config.JWTOptions{JTILen: 32},
+		Email:  &config.SMTPConfig{Options: &config.EmailOptions{MagicLinkTTL: 300, ResendInterval: 60}},
+	}
+	svc := service.NewMagicLinkService(&service.MagicLinkServiceDeps{
+		UserRepo:  m.userRepo,
+		TokenRepo: m.tokenRepo,
+		Auth:      m.auth,
+		Signer:    m.signer,
+		Mailer:    m.mailer,
+		Cfg:       cfg,
+	})
+	return svc, m
+}
+
+func TestMagicLinkService_RequestLink(t *testing.T) {
+	t.Parallel()
+	const (
+		userID    = "1"
+		testEmail = "abc@example.com"
+		token     = "magic_token"
+	)
+
+	t.Run("Verified user", func(t *testing.T) {
+		t.Parallel()
+		svc, m := newMagicLinkService(t)
+		verifiedAt := time.Now()
+		user := model.User{Model: model.Model{ID: userID}, Email: testEmail, VerifiedAt: &verifiedAt}
+
+		var (
+			wg    sync.WaitGroup
+			jti   string
+			saved string
+		)
+		wg.Add(1)
+		m.userRepo.EXPECT().FindUserByEmail(gomock.Any(), testEmail).Return(user, nil)
+		m.tokenRepo.EXPECT().CountTokensSince(gomock.Any(), testEmail, "magic_link", gomock.Any()).Return(0, nil)
+		m.signer.EXPECT().SignWithID(security.PurposeMagicLink, gomock.Any(), userID,
+			[]string{magicLinkAudience}, 300*time.Second).
+			DoAndReturn(func(_ security.Purpose, id, _ string, _ []string, _ time.Duration) (string, error) {
+				jti = id
+				return token, nil
+			})
+		m.tokenRepo.EXPECT().SaveToken(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, p repository.SaveTokenParams) error {
+				assert.Equal(t, testEmail, p.Email)
+				assert.Equal(t, "magic_link", p.Purpose)
+				saved = p.ID
+				return nil
+			})
+		m.mailer.EXPECT().SendHTML([]string{testEmail}, "Your login link", "magic_link", map[string]string{
+			"Title":  "Login link",
+			"Header": "Your login link",
+			"Link":   magicLinkAudience + "?token=" + token,
+		}).Do(func(_ []string, _, _ string, _ map[string]string) {
+			defer wg.Done()
+		})
+
+		binding, err := svc.RequestLink(context.Background(), testEmail)
+		require.NoError(t, err)
+		wg.Wait()
+
+		assert.NotEmpty(t, binding)
+		assert.Equal(t, security.HashToken(binding), jti, "token id is not bound to the browser")
+		assert.Equal(t, jti, saved)
+	})
+
+	t.Run("Link sent within the resend interval", func(t *testing.T) {
+		t.Parallel()
+		svc, m := newMagicLinkService(t)
+		verifiedAt := time.Now()
+		user := model.User{Model: model.Model{ID: userID}, Email: testEmail, VerifiedAt: &verifiedAt}
+		m.userRepo.EXPECT().FindUserByEmail(gomock.Any(), testEmail).Return(user, nil)
+		m.tokenRepo.EXPECT().CountTokensSince(gomock.Any(), testEmail, "magic_link", gomock.Any()).Return(1, nil)
+
+		binding, err := svc.RequestLink(context.Background(), testEmail)
+		require.NoError(t, err)
+		assert.NotEmpty(t, binding)
+	})
+
+	t.Run("Unknown email", func(t *testing.T) {
+		t.Parallel()
+		svc, m := newMagicLinkService(t)
+		m.userRepo.EXPECT().FindUserByEmail(gomock.Any(), testEmail).Return(model.User{}, sql.ErrNoRows)
+
+		binding, err := svc.RequestLink(context.Background(), testEmail)
+		require.NoError(t, err)
+		assert.NotEmpty(t, binding)
+	})
+
+	t.Run("Unverified user", func(t *testing.T) {
+		t.Parallel()
+		svc, m := newMagicLinkService(t)
+		user := model.User{Model: model.Model{ID: userID}, Email: testEmail}
+		m.userRepo.EXPECT().FindUserByEmail(gomock.Any(), testEmail).Return(user, nil)
+
+		binding, err := svc.RequestLink(context.Background(), testEmail)
+		require.NoError(t, err)
+		assert.NotEmpty(t, binding)
+	})
+}
+
+func TestMagicLinkService_ConsumeLink(t *testing.T) {
+	t.Parallel()
+	const (
+		userID    = "1"
+		testEmail = "abc@example.com"
+		token     = "magic_token"
+		binding   = "binding"
+	)
+
+	claims := &security.Claims{ID: security.HashToken(binding), Subject: userID}
+	user := model.User{Model: model.Model{ID: userID}, Email: testEmail}
+	result := service.LoginResult{AccessToken: "access", RefreshToken: "refresh"}
+
+	tests := []struct {
+		name    string
+		binding string
+		setup   func(m magicLinkMocks)
+		want    service.LoginResult
+		wantErr error
+	}{
+		{
+			name:    "Success",
+			binding: binding,
+			setup: func(m magicLinkMocks) {
+				m.signer.EXPECT().Verify(token, security.PurposeMagicLink, magicLinkAudience).Return(claims, nil)
+				m.tokenRepo.EXPECT().ConsumeToken(gomock.Any(), claims.ID, "magic_link").Return(testEmail, nil)
+				m.userRepo.EXPECT().FindUserByEmail(gomock.Any(), testEmail).Return(user, nil)
+				m.auth.EXPECT().CompleteLogin(gomock.Any(), user).Return(result, nil)
+			},
+			want: result,
+		},
+		{
+			name:    "Other browser",
+			binding: "other",
+			setup: func(m magicLinkMocks) {
+				m.signer.EXPECT().Verify(token, security.PurposeMagicLink, magicLinkAudience).Return(claims, nil)
+			},
+			wantErr: service.ErrInvalidToken,
+		},
+		{
+			name:    "Already used",
+			binding: binding,
+			setup: func(m magicLinkMocks) {
+				m.signer.EXPECT().Verify(token, security.PurposeMagicLink, magicLinkAudience).Return(claims, nil)
+				m.tokenRepo.EXPECT().ConsumeToken(gomock.Any(), claims.ID, "magic_link").Return("", sql.ErrNoRows)
+			},
+			wantErr: service.ErrInvalidToken,
+		},
+		{
+			name:    "Expired",
+			binding: binding,
+			setup: func(m magicLinkMocks) {
+				m.signer.EXPECT().Verify(token, security.PurposeMagicLink, magicLinkAudience).
+					Return(nil, errors.New("token is expired"))
+			},
+			wantErr: service.ErrInvalidToken,
+		},
+		{
+			name:    "Email now belongs to another user",
+			binding: binding,
+			setup: func(m magicLinkMocks) {
+				other := model.User{Model: model.Model{ID: "2"}, Email: testEmail}
+				m.signer.EXPECT().Verify(token, security.PurposeMagicLink, magicLinkAudience).Return(claims, nil)
+				m.tokenRepo.EXPECT().ConsumeToken(gomock.Any(), claims.ID, "magic_link").Return(testEmail, nil)
+				m.userRepo.EXPECT().FindUserByEmail(gomock.Any(), testEmail).Return(other, nil)
+			},
+			wantErr: service.ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			svc, m := newMagicLinkService(t)
+			tt.setup(m)
+
+			got, err := svc.ConsumeLink(context.Background(), token, tt.binding)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,355 @@
+//go:generate mockgen -destination=mock/mfa_service_mock.go -package=mock . MFAService
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/config"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/pkg/email"
+	"github.com/ferdiebergado/gojeep/internal/pkg/security"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+)
+
+// MFAService manages TOTP two-factor authentication.
+type MFAService interface {
+	Enroll(ctx context.Context, userID string) (MFAEnrollment, error)
+	Confirm(ctx context.Context, userID, code string) (recoveryCodes []string, err error)
+	Disable(ctx context.Context, userID, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error)
+	IsEnabled(ctx context.Context, userID string) (bool, error)
+	VerifyCode(ctx context.Context, userID, code string) error
+}
+
+type MFAServiceDeps struct {
+	Repo      repository.MFARepository
+	UserRepo  repository.UserRepository
+	AuditRepo repository.AuditRepository
+	Lockout   LockoutService
+	Encrypter security.Encrypter
+	Hasher    security.Hasher
+	Mailer    email.Mailer
+	Cfg       *config.Config
+}
+
+type mfaService struct {
+	repo      repository.MFARepository
+	userRepo  repository.UserRepository
+	auditRepo repository.AuditRepository
+	lockout   LockoutService
+	encrypter security.Encrypter
+	hasher    security.Hasher
+	mailer    email.Mailer
+	cfg       *config.Config
+}
+
+var _ MFAService = (*mfaService)(nil)
+
+// recoveryCodeCount is the size of a set of recovery codes.
+const recoveryCodeCount = 10
+
+// Audit events of MFA.
+const (
+	eventRecoveryCodeUsed         = "mfa_recovery_code_used"
+	eventRecoveryCodesRegenerated = "mfa_recovery_codes_regenerated"
+)
+
+var (
+	ErrMFAEnabled     = errors.New("mfa already enabled")
+	ErrMFANotEnabled  = errors.New("mfa not enabled")
+	ErrInvalidMFACode = errors.New("invalid mfa code")
+)
+
+func NewMFAService(deps *MFAServiceDeps) MFAService {
+	return &mfaService{
+		repo:      deps.Repo,
+		userRepo:  deps.UserRepo,
+		auditRepo: deps.AuditRepo,
+		lockout:   deps.Lockout,
+		encrypter: deps.Encrypter,
+		hasher:    deps.Hasher,
+		mailer:    deps.Mailer,
+		cfg:       deps.Cfg,
+	}
+}
+
+// MFAEnrollment is what the user adds to an authenticator app, either by scanning the URI or typing the secret.
+type MFAEnrollment struct {
+	Secret string
+	URI    string
+}
+
+// Enroll creates a new TOTP secret. MFA is not enforced until the secret is confirmed with a code.
+func (s *mfaService) Enroll(ctx context.Context, userID string) (MFAEnrollment, error) {
+	user, err := s.userRepo.FindUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return MFAEnrollment{}, ErrUserNotFound
+		}
+		return MFAEnrollment{}, err
+	}
+
+	secret, err := security.GenerateTOTPSecret()
+	if err != nil {
+		return MFAEnrollment{}, fmt.Errorf("generate totp secret: %w", err)
+	}
+
+	encrypted, err := s.encrypter.Encrypt(secret)
+	if err != nil {
+		return MFAEnrollment{}, fmt.Errorf("encrypt totp secret: %w", err)
+	}
+
+	if err := s.repo.SaveMFASecret(ctx, userID, encrypted); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return MFAEnrollment{}, ErrMFAEnabled
+		}
+		return MFAEnrollment{}, fmt.Errorf("save totp secret of user %s: %w", userID, err)
+	}
+
+	enrollment := MFAEnrollment{
+		Secret: security.EncodeTOTPSecret(secret),
+		URI:    security.TOTPURI(s.cfg.MFA.Issuer, user.Email, secret),
+	}
+	return enrollment, nil
+}
+
+// Confirm enables MFA once the user proves the authenticator app produces valid codes. It returns the
+// first set of recovery codes, which are shown to the user only this once.
+func (s *mfaService) Confirm(ctx context.Context, userID, code string) ([]string, error) {
+	mfa, err := s.findMFA(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if mfa.ConfirmedAt != nil {
+		return nil, ErrMFAEnabled
+	}
+
+	if err := s.checkCode(ctx, mfa, code); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.replaceRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ConfirmMFA(ctx, userID); err != nil {
+		return nil, fmt.Errorf("confirm mfa of user %s: %w", userID, err)
+	}
+
+	return codes, nil
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes with a new set, for example after using
+// some of them. The code is a TOTP or recovery code, as for VerifyCode.
+func (s *mfaService) RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error) {
+	if err := s.VerifyCode(ctx, userID, code); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.replaceRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.auditRepo.CreateAuditEvent(ctx, userID, eventRecoveryCodesRegenerated); err != nil {
+		return nil, fmt.Errorf("record audit event of user %s: %w", userID, err)
+	}
+
+	return codes, nil
+}
+
+// Disable turns MFA off. A current code is required so a stolen access token alone cannot remove it.
+func (s *mfaService) Disable(ctx context.Context, userID, code string) error {
+	if err := s.VerifyCode(ctx, userID, code); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteMFA(ctx, userID); err != nil {
+		return fmt.Errorf("delete mfa of user %s: %w", userID, err)
+	}
+
+	return nil
+}
+
+func (s *mfaService) IsEnabled(ctx context.Context, userID string) (bool, error) {
+	mfa, err := s.repo.FindMFA(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("find mfa of user %s: %w", userID, err)
+	}
+
+	return mfa.ConfirmedAt != nil, nil
+}
+
+// VerifyCode checks a TOTP code or recovery code of a user with MFA enabled. Each code is accepted only once.
+// A new login challenge or an access token allows another attempt, so invalid codes are counted and lock
+// the user out with the login backoff.
+func (s *mfaService) VerifyCode(ctx context.Context, userID, code string) error {
+	if err := s.lockout.CheckMFA(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := s.verifyCode(ctx, userID, code); err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			if recordErr := s.lockout.RecordMFAFailure(ctx, userID); recordErr != nil {
+				return recordErr
+			}
+		}
+		return err
+	}
+
+	return s.lockout.ResetMFA(ctx, userID)
+}
+
+func (s *mfaService) verifyCode(ctx context.Context, userID, code string) error {
+	mfa, err := s.findMFA(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if mfa.ConfirmedAt == nil {
+		return ErrMFANotEnabled
+	}
+
+	if isTOTPCode(code) {
+		return s.checkCode(ctx, mfa, code)
+	}
+
+	return s.useRecoveryCode(ctx, userID, code)
+}
+
+// isTOTPCode tells a TOTP code, which is all digits, from a recovery code.
+func isTOTPCode(code string) bool {
+	_, err := strconv.ParseUint(code, 10, 64)
+	return err == nil
+}
+
+func (s *mfaService) findMFA(ctx context.Context, userID string) (model.MFA, error) {
+	mfa, err := s.repo.FindMFA(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.MFA{}, ErrMFANotEnabled
+		}
+		return model.MFA{}, fmt.Errorf("find mfa of user %s: %w", userID, err)
+	}
+	return mfa, nil
+}
+
+func (s *mfaService) checkCode(ctx context.Context, mfa model.MFA, code string) error {
+	secret, err := s.encrypter.Decrypt(mfa.Secret)
+	if err != nil {
+		return fmt.Errorf("decrypt totp secret of user %s: %w", mfa.UserID, err)
+	}
+
+	step, ok := security.ValidateTOTP(secret, code, time.Now())
+	if !ok || step <= mfa.LastUsedStep {
+		return ErrInvalidMFACode
+	}
+
+	if err := s.repo.UseMFAStep(ctx, mfa.UserID, step); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidMFACode
+		}
+		return fmt.Errorf("record mfa step of user %s: %w", mfa.UserID, err)
+	}
+
+	return nil
+}
+
+// replaceRecoveryCodes generates a new set of recovery codes and stores their hashes.
+func (s *mfaService) replaceRecoveryCodes(ctx context.Context, userID string) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		code, err := security.GenerateRecoveryCode()
+		if err != nil {
+			return nil, fmt.Errorf("generate recovery code: %w", err)
+		}
+
+		hash, err := s.hasher.Hash(security.NormalizeRecoveryCode(code))
+		if err != nil {
+			return nil, fmt.Errorf("hash recovery code: %w", err)
+		}
+
+		codes = append(codes, code)
+		hashes = append(hashes, hash)
+	}
+
+	if err := s.repo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, fmt.Errorf("save recovery codes of user %s: %w", userID, err)
+	}
+
+	return codes, nil
+}
+
+// useRecoveryCode accepts an unused recovery code and notifies the user that it was used. The hashes are
+// salted, so the code is checked against each unused one.
+func (s *mfaService) useRecoveryCode(ctx context.Context, userID, code string) error {
+	codes, err := s.repo.FindUnusedRecoveryCodes(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("find recovery codes of user %s: %w", userID, err)
+	}
+
+	code = security.NormalizeRecoveryCode(code)
+	for _, recoveryCode := range codes {
+		ok, err := s.hasher.Verify(code, recoveryCode.CodeHash)
+		if err != nil {
+			return fmt.Errorf("verify recovery code: %w", err)
+		}
+
+		if !ok {
+			continue
+		}
+
+		if err := s.repo.UseRecoveryCode(ctx, recoveryCode.ID); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrInvalidMFACode
+			}
+			return fmt.Errorf("use recovery code of user %s: %w", userID, err)
+		}
+
+		if err := s.auditRepo.CreateAuditEvent(ctx, userID, eventRecoveryCodeUsed); err != nil {
+			return fmt.Errorf("record audit event of user %s: %w", userID, err)
+		}
+
+		go s.sendRecoveryCodeUsedEmail(context.WithoutCancel(ctx), userID, len(codes)-1)
+		return nil
+	}
+
+	return ErrInvalidMFACode
+}
+
+func (s *mfaService) sendRecoveryCodeUsedEmail(ctx context.Context, userID string, remaining int) {
+	slog.Info("Sending recovery code used email...")
+
+	user, err := s.userRepo.FindUserByID(ctx, userID)
+	if err != nil {
+		slog.Error("failed to find user", "reason", err)
+		return
+	}
+
+	const (
+		title   = "Recovery code used"
+		subject = "A recovery code was used to sign in"
+	)
+
+	data := map[string]string{
+		"Title":     title,
+		"Header":    subject,
+		"Remaining": strconv.Itoa(remaining),
+	}
+	if err := s.mailer.SendHTML([]string{user.Email}, subject, "recovery_code_used", data); err != nil {
+		slog.Error("failed to send email", "reason", err)
+		return
+	}
+}
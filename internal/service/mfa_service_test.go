@@ -0,0 +1,339 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/config"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/pkg/security"
+	"github.com/ferdiebergado/gojeep/internal/repository/mock"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	mailMock "github.com/ferdiebergado/gojeep/internal/pkg/email/mock"
+	secMock "github.com/ferdiebergado/gojeep/internal/pkg/security/mock"
+	svcMock "github.com/ferdiebergado/gojeep/internal/service/mock"
+)
+
+const mfaUserID = "1"
+
+// newTestMFA returns an MFA row holding a freshly encrypted secret, along with the plain secret.
+func newTestMFA(t *testing.T, encrypter security.Encrypter, confirmed bool) (model.MFA, []byte) {
+	t.Helper()
+	secret, err := security.GenerateTOTPSecret()
+	require.NoError(t, err)
+	encrypted, err := encrypter.Encrypt(secret)
+	require.NoError(t, err)
+
+	mfa := model.MFA{UserID: mfaUserID, Secret: encrypted}
+	if confirmed {
+		now := time.Now()
+		mfa.ConfirmedAt = &now
+	}
+	return mfa, secret
+}
+
+type mfaMocks struct {
+	repo     *mock.MockMFARepository
+	userRepo *mock.MockUserRepository
+	audit    *mock.MockAuditRepository
+	lockout  *svcMock.MockLockoutService
+	hasher   *secMock.MockHasher
+	mailer   *mailMock.MockMailer
+}
+
+func newTestMFAService(t *testing.T) (service.MFAService, security.Encrypter, mfaMocks) {
+	t.Helper()
+	encrypter, err := security.NewAESEncrypter("server_key", "totp")
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	m := mfaMocks{
+		repo:     mock.NewMockMFARepository(ctrl),
+		userRepo: mock.NewMockUserRepository(ctrl),
+		audit:    mock.NewMockAuditRepository(ctrl),
+		lockout:  svcMock.NewMockLockoutService(ctrl),
+		hasher:   secMock.NewMockHasher(ctrl),
+		mailer:   mailMock.NewMockMailer(ctrl),
+	}
+
+	svc := service.NewMFAService(&service.MFAServiceDeps{
+		Repo:      m.repo,
+		UserRepo:  m.userRepo,
+		AuditRepo: m.audit,
+		Lockout:   m.lockout,
+		Encrypter: encrypter,
+		Hasher:    m.hasher,
+		Mailer:    m.mailer,
+		Cfg:       &config.Config{MFA: &config.MFAOptions{Issuer: "gojeep"}},
+	})
+	return svc, encrypter, m
+}
+
+func TestMFAService_Enroll(t *testing.T) {
+	t.Parallel()
+	svc, encrypter, m := newTestMFAService(t)
+	mockRepo, mockUserRepo := m.repo, m.userRepo
+
+	var stored string
+	mockUserRepo.EXPECT().FindUserByID(gomock.Any(), mfaUserID).
+		Return(model.User{Model: model.Model{ID: mfaUserID}, Email: "abc@example.com"}, nil)
+	mockRepo.EXPECT().SaveMFASecret(gomock.Any(), mfaUserID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, secret string) error {
+			stored = secret
+			return nil
+		})
+
+	enrollment, err := svc.Enroll(context.Background(), mfaUserID)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(enrollment.URI, "otpauth://totp/gojeep:abc@example.com?"))
+	assert.Contains(t, enrollment.URI, "secret="+enrollment.Secret)
+
+	// The secret is stored encrypted, never as the base32 shown to the user.
+	assert.NotContains(t, stored, enrollment.Secret)
+	secret, err := encrypter.Decrypt(stored)
+	require.NoError(t, err)
+	assert.Equal(t, enrollment.Secret, security.EncodeTOTPSecret(secret))
+}
+
+func TestMFAService_Enroll_AlreadyEnabled(t *testing.T) {
+	t.Parallel()
+	svc, _, m := newTestMFAService(t)
+	mockRepo, mockUserRepo := m.repo, m.userRepo
+
+	mockUserRepo.EXPECT().FindUserByID(gomock.Any(), mfaUserID).
+		Return(model.User{Model: model.Model{ID: mfaUserID}}, nil)
+	mockRepo.EXPECT().SaveMFASecret(gomock.Any(), mfaUserID, gomock.Any()).Return(sql.ErrNoRows)
+
+	_, err := svc.Enroll(context.Background(), mfaUserID)
+	assert.ErrorIs(t, err, service.ErrMFAEnabled)
+}
+
+func TestMFAService_Confirm(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		confirmed bool
+		wrongCode bool
+		usedStep  bool
+		wantErr   error
+	}{
+		{name: "Confirmed"},
+		{name: "Wrong code", wrongCode: true, wantErr: service.ErrInvalidMFACode},
+		{name: "Code already used", usedStep: true, wantErr: service.ErrInvalidMFACode},
+		{name: "Already enabled", confirmed: true, wantErr: service.ErrMFAEnabled},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			svc, encrypter, m := newTestMFAService(t)
+			mockRepo := m.repo
+
+			mfa, secret := newTestMFA(t, encrypter, tc.confirmed)
+			now := time.Now()
+			code := security.TOTPCode(secret, now)
+			if tc.wrongCode {
+				code = security.TOTPCode(secret, now.Add(time.Hour))
+			}
+			if tc.usedStep {
+				mfa.LastUsedStep = now.Unix()/30 + 1
+			}
+
+			mockRepo.EXPECT().FindMFA(gomock.Any(), mfaUserID).Return(mfa, nil)
+			if tc.wantErr == nil {
+				mockRepo.EXPECT().UseMFAStep(gomock.Any(), mfaUserID, gomock.Any()).Return(nil)
+				m.hasher.EXPECT().Hash(gomock.Any()).Return("hashed", nil).Times(10)
+				mockRepo.EXPECT().ReplaceRecoveryCodes(gomock.Any(), mfaUserID, gomock.Len(10)).Return(nil)
+				mockRepo.EXPECT().ConfirmMFA(gomock.Any(), mfaUserID).Return(nil)
+			}
+
+			codes, err := svc.Confirm(context.Background(), mfaUserID, code)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, codes, 10)
+		})
+	}
+}
+
+func TestMFAService_Disable(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name      string
+		findErr   error
+		confirmed bool
+		wrongCode bool
+		locked    bool
+		wantErr   error
+	}{
+		{name: "Disabled", confirmed: true},
+		{name: "Only enrolled", wantErr: service.ErrMFANotEnabled},
+		{name: "Never enrolled", findErr: sql.ErrNoRows, wantErr: service.ErrMFANotEnabled},
+		{name: "Wrong code counted", confirmed: true, wrongCode: true, wantErr: service.ErrInvalidMFACode},
+		{name: "Locked out", locked: true, wantErr: service.ErrAccountLocked},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			svc, encrypter, m := newTestMFAService(t)
+			mockRepo := m.repo
+
+			mfa, secret := newTestMFA(t, encrypter, tc.confirmed)
+			code := security.TOTPCode(secret, time.Now())
+			switch {
+			case tc.locked:
+				m.lockout.EXPECT().CheckMFA(gomock.Any(), mfaUserID).
+					Return(&service.AccountLockedError{RetryAfter: time.Minute})
+			case tc.wrongCode:
+				code = "wrong-code"
+				m.lockout.EXPECT().CheckMFA(gomock.Any(), mfaUserID).Return(nil)
+				mockRepo.EXPECT().FindMFA(gomock.Any(), mfaUserID).Return(mfa, nil)
+				mockRepo.EXPECT().FindUnusedRecoveryCodes(gomock.Any(), mfaUserID).Return(nil, nil)
+				m.lockout.EXPECT().RecordMFAFailure(gomock.Any(), mfaUserID).Return(nil)
+			default:
+				m.lockout.EXPECT().CheckMFA(gomock.Any(), mfaUserID).Return(nil)
+				mockRepo.EXPECT().FindMFA(gomock.Any(), mfaUserID).Return(mfa, tc.findErr)
+			}
+			if tc.wantErr == nil {
+				mockRepo.EXPECT().UseMFAStep(gomock.Any(), mfaUserID, gomock.Any()).Return(nil)
+				m.lockout.EXPECT().ResetMFA(gomock.Any(), mfaUserID).Return(nil)
+				mockRepo.EXPECT().DeleteMFA(gomock.Any(), mfaUserID).Return(nil)
+			}
+
+			err := svc.Disable(context.Background(), mfaUserID, code)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestMFAService_IsEnabled(t *testing.T) {
+	t.Parallel()
+	confirmedAt := time.Now()
+
+	testCases := []struct {
+		name    string
+		mfa     model.MFA
+		findErr error
+		want    bool
+	}{
+		{name: "Confirmed", mfa: model.MFA{ConfirmedAt: &confirmedAt}, want: true},
+		{name: "Pending confirmation", mfa: model.MFA{}},
+		{name: "Not enrolled", findErr: sql.ErrNoRows},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			svc, _, m := newTestMFAService(t)
+			mockRepo := m.repo
+
+			mockRepo.EXPECT().FindMFA(gomock.Any(), mfaUserID).Return(tc.mfa, tc.findErr)
+
+			enabled, err := svc.IsEnabled(context.Background(), mfaUserID)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, enabled)
+		})
+	}
+}
+
+func TestMFAService_VerifyCode_RecoveryCode(t *testing.T) {
+	t.Parallel()
+	const (
+		recoveryCode = "ABCD-efgh-ijkl-mnop"
+		normalized   = "abcdefghijklmnop"
+	)
+	codes := []model.RecoveryCode{
+		{ID: "a", UserID: mfaUserID, CodeHash: "hash_a"},
+		{ID: "b", UserID: mfaUserID, CodeHash: "hash_b"},
+	}
+
+	testCases := []struct {
+		name    string
+		match   string
+		useErr  error
+		wantErr error
+	}{
+		{name: "Unused code", match: "hash_b"},
+		{name: "Unknown code", wantErr: service.ErrInvalidMFACode},
+		{name: "Used concurrently", match: "hash_a", useErr: sql.ErrNoRows, wantErr: service.ErrInvalidMFACode},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			svc, encrypter, m := newTestMFAService(t)
+			mfa, _ := newTestMFA(t, encrypter, true)
+
+			m.lockout.EXPECT().CheckMFA(gomock.Any(), mfaUserID).Return(nil)
+			if tc.wantErr == nil {
+				m.lockout.EXPECT().ResetMFA(gomock.Any(), mfaUserID).Return(nil)
+			} else {
+				m.lockout.EXPECT().RecordMFAFailure(gomock.Any(), mfaUserID).Return(nil)
+			}
+			m.repo.EXPECT().FindMFA(gomock.Any(), mfaUserID).Return(mfa, nil)
+			m.repo.EXPECT().FindUnusedRecoveryCodes(gomock.Any(), mfaUserID).Return(codes, nil)
+			for _, code := range codes {
+				m.hasher.EXPECT().Verify(normalized, code.CodeHash).Return(code.CodeHash == tc.match, nil).MaxTimes(1)
+			}
+
+			var wg sync.WaitGroup
+			if tc.match != "" {
+				m.repo.EXPECT().UseRecoveryCode(gomock.Any(), gomock.Any()).Return(tc.useErr)
+			}
+			if tc.wantErr == nil {
+				wg.Add(1)
+				m.audit.EXPECT().CreateAuditEvent(gomock.Any(), mfaUserID, "mfa_recovery_code_used").Return(nil)
+				m.userRepo.EXPECT().FindUserByID(gomock.Any(), mfaUserID).
+					Return(model.User{Model: model.Model{ID: mfaUserID}, Email: "abc@example.com"}, nil)
+				m.mailer.EXPECT().SendHTML([]string{"abc@example.com"}, gomock.Any(), "recovery_code_used",
+					gomock.Cond(func(data map[string]string) bool { return data["Remaining"] == "1" })).
+					Do(func(_ []string, _, _ string, _ map[string]string) {
+						defer wg.Done()
+					})
+			}
+
+			err := svc.VerifyCode(context.Background(), mfaUserID, recoveryCode)
+			wg.Wait()
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestMFAService_RegenerateRecoveryCodes(t *testing.T) {
+	t.Parallel()
+	svc, encrypter, m := newTestMFAService(t)
+	mfa, secret := newTestMFA(t, encrypter, true)
+
+	m.lockout.EXPECT().CheckMFA(gomock.Any(), mfaUserID).Return(nil)
+	m.repo.EXPECT().FindMFA(gomock.Any(), mfaUserID).Return(mfa, nil)
+	m.repo.EXPECT().UseMFAStep(gomock.Any(), mfaUserID, gomock.Any()).Return(nil)
+	m.lockout.EXPECT().ResetMFA(gomock.Any(), mfaUserID).Return(nil)
+	m.hasher.EXPECT().Hash(gomock.Any()).Return("hashed", nil).Times(10)
+	m.repo.EXPECT().ReplaceRecoveryCodes(gomock.Any(), mfaUserID, gomock.Len(10)).Return(nil)
+	m.audit.EXPECT().CreateAuditEvent(gomock.Any(), mfaUserID, "mfa_recovery_codes_regenerated").Return(nil)
+
+	codes, err := svc.RegenerateRecoveryCodes(context.Background(), mfaUserID, security.TOTPCode(secret, time.Now()))
+	require.NoError(t, err)
+	assert.Len(t, codes, 10)
+}
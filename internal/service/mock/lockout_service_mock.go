@@ -0,0 +1,140 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/gojeep/internal/service (interfaces: LockoutService)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mock/lockout_service_mock.go -package=mock . LockoutService
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockLockoutService is a mock of LockoutService interface.
+type MockLockoutService struct {
+	ctrl     *gomock.Controller
+	recorder *MockLockoutServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockLockoutServiceMockRecorder is the mock recorder for MockLockoutService.
+type MockLockoutServiceMockRecorder struct {
+	mock *MockLockoutService
+}
+
+// NewMockLockoutService creates a new mock instance.
+func NewMockLockoutService(ctrl *gomock.Controller) *MockLockoutService {
+	mock := &MockLockoutService{ctrl: ctrl}
+	mock.recorder = &MockLockoutServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLockoutService) EXPECT() *MockLockoutServiceMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockLockoutService) Check(ctx context.Context, email, ipAddress string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, email, ipAddress)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockLockoutServiceMockRecorder) Check(ctx, email, ipAddress any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockLockoutService)(nil).Check), ctx, email, ipAddress)
+}
+
+// CheckMFA mocks base method.
+func (m *MockLockoutService) CheckMFA(ctx context.Context, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckMFA", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckMFA indicates an expected call of CheckMFA.
+func (mr *MockLockoutServiceMockRecorder) CheckMFA(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckMFA", reflect.TypeOf((*MockLockoutService)(nil).CheckMFA), ctx, userID)
+}
+
+// PurgeStale mocks base method.
+func (m *MockLockoutService) PurgeStale(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeStale", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeStale indicates an expected call of PurgeStale.
+func (mr *MockLockoutServiceMockRecorder) PurgeStale(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeStale", reflect.TypeOf((*MockLockoutService)(nil).PurgeStale), ctx)
+}
+
+// RecordFailure mocks base method.
+func (m *MockLockoutService) RecordFailure(ctx context.Context, email, ipAddress string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailure", ctx, email, ipAddress)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordFailure indicates an expected call of RecordFailure.
+func (mr *MockLockoutServiceMockRecorder) RecordFailure(ctx, email, ipAddress any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailure", reflect.TypeOf((*MockLockoutService)(nil).RecordFailure), ctx, email, ipAddress)
+}
+
+// RecordMFAFailure mocks base method.
+func (m *MockLockoutService) RecordMFAFailure(ctx context.Context, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordMFAFailure", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordMFAFailure indicates an expected call of RecordMFAFailure.
+func (mr *MockLockoutServiceMockRecorder) RecordMFAFailure(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordMFAFailure", reflect.TypeOf((*MockLockoutService)(nil).RecordMFAFailure), ctx, userID)
+}
+
+// Reset mocks base method.
+func (m *MockLockoutService) Reset(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockLockoutServiceMockRecorder) Reset(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockLockoutService)(nil).Reset), ctx, email)
+}
+
+// ResetMFA mocks base method.
+func (m *MockLockoutService) ResetMFA(ctx context.Context, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetMFA", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetMFA indicates an expected call of ResetMFA.
+func (mr *MockLockoutServiceMockRecorder) ResetMFA(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetMFA", reflect.TypeOf((*MockLockoutService)(nil).ResetMFA), ctx, userID)
+}
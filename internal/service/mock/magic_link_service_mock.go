@@ -0,0 +1,72 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/gojeep/internal/service (interfaces: MagicLinkService)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mock/magic_link_service_mock.go -package=mock . MagicLinkService
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	service "github.com/ferdiebergado/gojeep/internal/service"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMagicLinkService is a mock of MagicLinkService interface.
+type MockMagicLinkService struct {
+	ctrl     *gomock.Controller
+	recorder *MockMagicLinkServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockMagicLinkServiceMockRecorder is the mock recorder for MockMagicLinkService.
+type MockMagicLinkServiceMockRecorder struct {
+	mock *MockMagicLinkService
+}
+
+// NewMockMagicLinkService creates a new mock instance.
+func NewMockMagicLinkService(ctrl *gomock.Controller) *MockMagicLinkService {
+	mock := &MockMagicLinkService{ctrl: ctrl}
+	mock.recorder = &MockMagicLinkServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMagicLinkService) EXPECT() *MockMagicLinkServiceMockRecorder {
+	return m.recorder
+}
+
+// ConsumeLink mocks base method.
+func (m *MockMagicLinkService) ConsumeLink(ctx context.Context, token, binding string) (service.LoginResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeLink", ctx, token, binding)
+	ret0, _ := ret[0].(service.LoginResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeLink indicates an expected call of ConsumeLink.
+func (mr *MockMagicLinkServiceMockRecorder) ConsumeLink(ctx, token, binding any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeLink", reflect.TypeOf((*MockMagicLinkService)(nil).ConsumeLink), ctx, token, binding)
+}
+
+// RequestLink mocks base method.
+func (m *MockMagicLinkService) RequestLink(ctx context.Context, email string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestLink", ctx, email)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequestLink indicates an expected call of RequestLink.
+func (mr *MockMagicLinkServiceMockRecorder) RequestLink(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestLink", reflect.TypeOf((*MockMagicLinkService)(nil).RequestLink), ctx, email)
+}
@@ -0,0 +1,102 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/gojeep/internal/service (interfaces: ProviderService)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mock/provider_service_mock.go -package=mock . ProviderService
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	service "github.com/ferdiebergado/gojeep/internal/service"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockProviderService is a mock of ProviderService interface.
+type MockProviderService struct {
+	ctrl     *gomock.Controller
+	recorder *MockProviderServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockProviderServiceMockRecorder is the mock recorder for MockProviderService.
+type MockProviderServiceMockRecorder struct {
+	mock *MockProviderService
+}
+
+// NewMockProviderService creates a new mock instance.
+func NewMockProviderService(ctrl *gomock.Controller) *MockProviderService {
+	mock := &MockProviderService{ctrl: ctrl}
+	mock.recorder = &MockProviderServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProviderService) EXPECT() *MockProviderServiceMockRecorder {
+	return m.recorder
+}
+
+// Authorize mocks base method.
+func (m *MockProviderService) Authorize(ctx context.Context, userID string, params service.AuthorizeParams) (service.AuthorizeResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorize", ctx, userID, params)
+	ret0, _ := ret[0].(service.AuthorizeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authorize indicates an expected call of Authorize.
+func (mr *MockProviderServiceMockRecorder) Authorize(ctx, userID, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorize", reflect.TypeOf((*MockProviderService)(nil).Authorize), ctx, userID, params)
+}
+
+// Exchange mocks base method.
+func (m *MockProviderService) Exchange(ctx context.Context, params service.ExchangeCodeParams) (service.TokenResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exchange", ctx, params)
+	ret0, _ := ret[0].(service.TokenResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exchange indicates an expected call of Exchange.
+func (mr *MockProviderServiceMockRecorder) Exchange(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exchange", reflect.TypeOf((*MockProviderService)(nil).Exchange), ctx, params)
+}
+
+// PurgeExpiredCodes mocks base method.
+func (m *MockProviderService) PurgeExpiredCodes(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpiredCodes", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpiredCodes indicates an expected call of PurgeExpiredCodes.
+func (mr *MockProviderServiceMockRecorder) PurgeExpiredCodes(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpiredCodes", reflect.TypeOf((*MockProviderService)(nil).PurgeExpiredCodes), ctx)
+}
+
+// UserInfo mocks base method.
+func (m *MockProviderService) UserInfo(ctx context.Context, accessToken string) (service.UserInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserInfo", ctx, accessToken)
+	ret0, _ := ret[0].(service.UserInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UserInfo indicates an expected call of UserInfo.
+func (mr *MockProviderServiceMockRecorder) UserInfo(ctx, accessToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserInfo", reflect.TypeOf((*MockProviderService)(nil).UserInfo), ctx, accessToken)
+}
@@ -0,0 +1,114 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/gojeep/internal/service (interfaces: RevocationService)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mock/revocation_service_mock.go -package=mock . RevocationService
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRevocationService is a mock of RevocationService interface.
+type MockRevocationService struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevocationServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockRevocationServiceMockRecorder is the mock recorder for MockRevocationService.
+type MockRevocationServiceMockRecorder struct {
+	mock *MockRevocationService
+}
+
+// NewMockRevocationService creates a new mock instance.
+func NewMockRevocationService(ctrl *gomock.Controller) *MockRevocationService {
+	mock := &MockRevocationService{ctrl: ctrl}
+	mock.recorder = &MockRevocationServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevocationService) EXPECT() *MockRevocationServiceMockRecorder {
+	return m.recorder
+}
+
+// ForgetUser mocks base method.
+func (m *MockRevocationService) ForgetUser(userID string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ForgetUser", userID)
+}
+
+// ForgetUser indicates an expected call of ForgetUser.
+func (mr *MockRevocationServiceMockRecorder) ForgetUser(userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgetUser", reflect.TypeOf((*MockRevocationService)(nil).ForgetUser), userID)
+}
+
+// IsTokenRevoked mocks base method.
+func (m *MockRevocationService) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTokenRevoked", ctx, jti)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTokenRevoked indicates an expected call of IsTokenRevoked.
+func (mr *MockRevocationServiceMockRecorder) IsTokenRevoked(ctx, jti any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTokenRevoked", reflect.TypeOf((*MockRevocationService)(nil).IsTokenRevoked), ctx, jti)
+}
+
+// IsUserSuspended mocks base method.
+func (m *MockRevocationService) IsUserSuspended(ctx context.Context, userID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsUserSuspended", ctx, userID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsUserSuspended indicates an expected call of IsUserSuspended.
+func (mr *MockRevocationServiceMockRecorder) IsUserSuspended(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsUserSuspended", reflect.TypeOf((*MockRevocationService)(nil).IsUserSuspended), ctx, userID)
+}
+
+// IsUserTokenRevoked mocks base method.
+func (m *MockRevocationService) IsUserTokenRevoked(ctx context.Context, userID string, issuedAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsUserTokenRevoked", ctx, userID, issuedAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsUserTokenRevoked indicates an expected call of IsUserTokenRevoked.
+func (mr *MockRevocationServiceMockRecorder) IsUserTokenRevoked(ctx, userID, issuedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsUserTokenRevoked", reflect.TypeOf((*MockRevocationService)(nil).IsUserTokenRevoked), ctx, userID, issuedAt)
+}
+
+// PurgeExpiredTokens mocks base method.
+func (m *MockRevocationService) PurgeExpiredTokens(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpiredTokens", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpiredTokens indicates an expected call of PurgeExpiredTokens.
+func (mr *MockRevocationServiceMockRecorder) PurgeExpiredTokens(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpiredTokens", reflect.TypeOf((*MockRevocationService)(nil).PurgeExpiredTokens), ctx)
+}
@@ -0,0 +1,117 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/ferdiebergado/gojeep/internal/service (interfaces: WebAuthnService)
+//
+// Generated by this command:
+//
+//	mockgen -destination=mock/webauthn_service_mock.go -package=mock . WebAuthnService
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	webauthn "github.com/ferdiebergado/gojeep/internal/pkg/webauthn"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockWebAuthnService is a mock of WebAuthnService interface.
+type MockWebAuthnService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebAuthnServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockWebAuthnServiceMockRecorder is the mock recorder for MockWebAuthnService.
+type MockWebAuthnServiceMockRecorder struct {
+	mock *MockWebAuthnService
+}
+
+// NewMockWebAuthnService creates a new mock instance.
+func NewMockWebAuthnService(ctrl *gomock.Controller) *MockWebAuthnService {
+	mock := &MockWebAuthnService{ctrl: ctrl}
+	mock.recorder = &MockWebAuthnServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebAuthnService) EXPECT() *MockWebAuthnServiceMockRecorder {
+	return m.recorder
+}
+
+// BeginLogin mocks base method.
+func (m *MockWebAuthnService) BeginLogin(ctx context.Context) (webauthn.RequestOptions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginLogin", ctx)
+	ret0, _ := ret[0].(webauthn.RequestOptions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginLogin indicates an expected call of BeginLogin.
+func (mr *MockWebAuthnServiceMockRecorder) BeginLogin(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginLogin", reflect.TypeOf((*MockWebAuthnService)(nil).BeginLogin), ctx)
+}
+
+// BeginRegistration mocks base method.
+func (m *MockWebAuthnService) BeginRegistration(ctx context.Context, userID string) (webauthn.CreationOptions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginRegistration", ctx, userID)
+	ret0, _ := ret[0].(webauthn.CreationOptions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginRegistration indicates an expected call of BeginRegistration.
+func (mr *MockWebAuthnServiceMockRecorder) BeginRegistration(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginRegistration", reflect.TypeOf((*MockWebAuthnService)(nil).BeginRegistration), ctx, userID)
+}
+
+// FinishLogin mocks base method.
+func (m *MockWebAuthnService) FinishLogin(ctx context.Context, res webauthn.LoginResponse) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishLogin", ctx, res)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// FinishLogin indicates an expected call of FinishLogin.
+func (mr *MockWebAuthnServiceMockRecorder) FinishLogin(ctx, res any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishLogin", reflect.TypeOf((*MockWebAuthnService)(nil).FinishLogin), ctx, res)
+}
+
+// FinishRegistration mocks base method.
+func (m *MockWebAuthnService) FinishRegistration(ctx context.Context, userID string, res webauthn.RegistrationResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishRegistration", ctx, userID, res)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishRegistration indicates an expected call of FinishRegistration.
+func (mr *MockWebAuthnServiceMockRecorder) FinishRegistration(ctx, userID, res any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishRegistration", reflect.TypeOf((*MockWebAuthnService)(nil).FinishRegistration), ctx, userID, res)
+}
+
+// PurgeExpiredChallenges mocks base method.
+func (m *MockWebAuthnService) PurgeExpiredChallenges(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpiredChallenges", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpiredChallenges indicates an expected call of PurgeExpiredChallenges.
+func (mr *MockWebAuthnServiceMockRecorder) PurgeExpiredChallenges(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpiredChallenges", reflect.TypeOf((*MockWebAuthnService)(nil).PurgeExpiredChallenges), ctx)
+}
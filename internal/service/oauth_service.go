@@ -0,0 +1,343 @@
+//go:generate mockgen -destination=mock/oauth_service_mock.go -package=mock . OAuthService
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/config"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/pkg/oidc"
+	"github.com/ferdiebergado/gojeep/internal/pkg/security"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+)
+
+// OAuthService signs users in with external OpenID Connect providers. A provider account is linked to a
+// user through an identity, which is created on the first sign-in.
+type OAuthService interface {
+	StartLogin(ctx context.Context, provider string) (OAuthStart, error)
+	FinishLogin(ctx context.Context, params FinishOAuthLoginParams) (LoginResult, error)
+	PurgeExpiredStates(ctx context.Context) (int64, error)
+}
+
+type OAuthServiceDeps struct {
+	Repo     repository.OAuthRepository
+	UserRepo repository.UserRepository
+	Auth     AuthService
+	Hasher   security.Hasher
+	Cfg      *config.Config
+}
+
+type oauthService struct {
+	repo     repository.OAuthRepository
+	userRepo repository.UserRepository
+	auth     AuthService
+	hasher   security.Hasher
+	clients  map[string]*oidc.Client
+	ttl      time.Duration
+}
+
+var _ OAuthService = (*oauthService)(nil)
+
+const (
+	// oauthHTTPTimeout bounds each request to a provider.
+	oauthHTTPTimeout = 10 * time.Second
+
+	// oauthSecretLen is the number of random bytes in a state or nonce.
+	oauthSecretLen = 32
+
+	// maxNameLen is the length of the users.name column, in characters.
+	maxNameLen = 255
+)
+
+var (
+	ErrUnknownProvider      = errors.New("unknown oauth provider")
+	ErrInvalidOAuthState    = errors.New("invalid oauth state")
+	ErrOAuthFailed          = errors.New("oauth sign-in failed")
+	ErrOAuthEmailUnverified = errors.New("provider email not verified")
+)
+
+func NewOAuthService(deps *OAuthServiceDeps) OAuthService {
+	s := &oauthService{
+		repo:     deps.Repo,
+		userRepo: deps.UserRepo,
+		auth:     deps.Auth,
+		hasher:   deps.Hasher,
+		clients:  make(map[string]*oidc.Client),
+	}
+
+	opts := deps.Cfg.OAuth
+	if opts == nil {
+		return s
+	}
+
+	s.ttl = time.Duration(opts.StateTTL) * time.Second
+	httpClient := &http.Client{Timeout: oauthHTTPTimeout}
+	for name, provider := range opts.Providers {
+		s.clients[name] = oidc.NewClient(oidc.Config{
+			Issuer:       provider.Issuer,
+			ClientID:     provider.ClientID,
+			ClientSecret: provider.ClientSecret,
+			RedirectURL:  deps.Cfg.Server.URL + "/auth/oauth/" + name + "/callback",
+			Scopes:       provider.Scopes,
+		}, httpClient)
+	}
+
+	return s
+}
+
+// OAuthStart is where to send the user to sign in, and the state to bind to their browser.
+type OAuthStart struct {
+	URL   string
+	State string
+}
+
+// StartLogin begins a sign-in with a provider. The state, nonce and PKCE verifier are kept until the
+// provider redirects back, or until they expire.
+func (s *oauthService) StartLogin(ctx context.Context, provider string) (OAuthStart, error) {
+	client, ok := s.clients[provider]
+	if !ok {
+		return OAuthStart{}, ErrUnknownProvider
+	}
+
+	state, err := newOAuthSecret()
+	if err != nil {
+		return OAuthStart{}, fmt.Errorf("generate oauth state: %w", err)
+	}
+
+	nonce, err := newOAuthSecret()
+	if err != nil {
+		return OAuthStart{}, fmt.Errorf("generate oauth nonce: %w", err)
+	}
+
+	verifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		return OAuthStart{}, fmt.Errorf("generate code verifier: %w", err)
+	}
+
+	authURL, err := client.AuthCodeURL(ctx, state, nonce, oidc.CodeChallenge(verifier))
+	if err != nil {
+		return OAuthStart{}, fmt.Errorf("%w: %w", ErrOAuthFailed, err)
+	}
+
+	params := repository.SaveStateParams{
+		State:        state,
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().Add(s.ttl),
+	}
+	if err := s.repo.SaveState(ctx, params); err != nil {
+		return OAuthStart{}, fmt.Errorf("save oauth state: %w", err)
+	}
+
+	return OAuthStart{URL: authURL, State: state}, nil
+}
+
+// FinishOAuthLoginParams hold the callback of a provider: the state it echoed back and the
+// authorization code.
+type FinishOAuthLoginParams struct {
+	Provider string
+	State    string
+	Code     string
+}
+
+func (p *FinishOAuthLoginParams) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("provider", p.Provider),
+		slog.String("state", "*"),
+		slog.String("code", "*"),
+	)
+}
+
+// FinishLogin redeems the code, verifies the ID token and signs in the user the identity belongs to.
+// On a first sign-in the identity is linked to the user with the same email if the provider verified
+// the email, or to a new user otherwise. It answers like LoginUser once the user is known.
+func (s *oauthService) FinishLogin(ctx context.Context, params FinishOAuthLoginParams) (LoginResult, error) {
+	client, ok := s.clients[params.Provider]
+	if !ok {
+		return LoginResult{}, ErrUnknownProvider
+	}
+
+	state, err := s.repo.ConsumeState(ctx, params.State, params.Provider)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return LoginResult{}, ErrInvalidOAuthState
+		}
+		return LoginResult{}, fmt.Errorf("consume oauth state: %w", err)
+	}
+
+	idToken, err := client.Exchange(ctx, params.Code, state.CodeVerifier)
+	if err != nil {
+		return LoginResult{}, fmt.Errorf("%w: %w", ErrOAuthFailed, err)
+	}
+
+	claims, err := client.VerifyIDToken(ctx, idToken, state.Nonce)
+	if err != nil {
+		return LoginResult{}, fmt.Errorf("%w: %w", ErrOAuthFailed, err)
+	}
+
+	user, err := s.identityUser(ctx, params.Provider, claims)
+	if err != nil {
+		return LoginResult{}, err
+	}
+
+	if user.VerifiedAt == nil {
+		return LoginResult{}, ErrUserNotVerified
+	}
+
+	return s.auth.CompleteLogin(ctx, user)
+}
+
+func (s *oauthService) PurgeExpiredStates(ctx context.Context) (int64, error) {
+	purged, err := s.repo.PurgeStates(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("purge oauth states: %w", err)
+	}
+	return purged, nil
+}
+
+// identityUser returns the user a provider account signs in as, linking the account on first use.
+func (s *oauthService) identityUser(ctx context.Context, provider string, claims oidc.Claims) (model.User, error) {
+	identity, err := s.repo.FindIdentity(ctx, provider, claims.Subject)
+	if err == nil {
+		user, err := s.userRepo.FindUserByID(ctx, identity.UserID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return model.User{}, fmt.Errorf("%w: user %s is deleted", ErrOAuthFailed, identity.UserID)
+			}
+			return model.User{}, fmt.Errorf("find user %s: %w", identity.UserID, err)
+		}
+		return user, nil
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return model.User{}, fmt.Errorf("find identity: %w", err)
+	}
+
+	if claims.Email == "" {
+		return model.User{}, fmt.Errorf("%w: provider shared no email", ErrOAuthFailed)
+	}
+
+	existing, err := s.userRepo.FindUserByEmail(ctx, claims.Email)
+	if err == nil {
+		return s.linkIdentity(ctx, provider, claims, existing)
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return model.User{}, fmt.Errorf("find user by email: %w", err)
+	}
+
+	return s.createIdentityUser(ctx, provider, claims)
+}
+
+// linkIdentity links a provider account to the user with the same email. Only an email the provider
+// verified proves the account belongs to the user.
+func (s *oauthService) linkIdentity(ctx context.Context, provider string, claims oidc.Claims,
+	user model.User) (model.User, error) {
+	if !claims.EmailVerified {
+		return model.User{}, ErrOAuthEmailUnverified
+	}
+
+	// An unverified user may have been registered by someone else in the email owner's name, so its
+	// password is replaced before the owner is signed in to it.
+	if user.VerifiedAt == nil {
+		hash, err := s.randomPasswordHash()
+		if err != nil {
+			return model.User{}, err
+		}
+
+		if err := s.userRepo.UpdatePassword(ctx, user.ID, hash); err != nil {
+			return model.User{}, fmt.Errorf("reset password of user %s: %w", user.ID, err)
+		}
+
+		if err := s.userRepo.VerifyUser(ctx, user.ID); err != nil {
+			return model.User{}, fmt.Errorf("verify user %s: %w", user.ID, err)
+		}
+
+		now := time.Now()
+		user.VerifiedAt = &now
+	}
+
+	identity := model.Identity{
+		UserID:   user.ID,
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	}
+	if err := s.repo.CreateIdentity(ctx, identity); err != nil {
+		return model.User{}, fmt.Errorf("create identity: %w", err)
+	}
+
+	slog.Info("Linked oauth identity", "user_id", user.ID, "provider", provider)
+
+	return user, nil
+}
+
+// createIdentityUser signs up a user through a provider. The user has no usable password until they
+// reset it. If the provider did not verify the email, a verification link is sent as on registration.
+func (s *oauthService) createIdentityUser(ctx context.Context, provider string,
+	claims oidc.Claims) (model.User, error) {
+	hash, err := s.randomPasswordHash()
+	if err != nil {
+		return model.User{}, err
+	}
+
+	params := repository.CreateIdentityUserParams{
+		Email:        claims.Email,
+		Name:         truncateName(claims.Name),
+		PasswordHash: hash,
+		Verified:     bool(claims.EmailVerified),
+		Provider:     provider,
+		Subject:      claims.Subject,
+	}
+	user, err := s.repo.CreateIdentityUser(ctx, params)
+	if err != nil {
+		return model.User{}, fmt.Errorf("create user from identity: %w", err)
+	}
+
+	if user.VerifiedAt == nil {
+		if err := s.auth.ResendVerification(ctx, user.Email); err != nil {
+			return model.User{}, err
+		}
+	}
+
+	return user, nil
+}
+
+func (s *oauthService) randomPasswordHash() (string, error) {
+	secret, err := newOAuthSecret()
+	if err != nil {
+		return "", fmt.Errorf("generate password: %w", err)
+	}
+
+	hash, err := s.hasher.Hash(secret)
+	if err != nil {
+		return "", fmt.Errorf("hasher hash: %w", err)
+	}
+
+	return hash, nil
+}
+
+func newOAuthSecret() (string, error) {
+	b, err := security.GenerateRandomBytes(oauthSecretLen)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// truncateName cuts a name from a provider to fit the users table.
+func truncateName(name string) string {
+	runes := []rune(name)
+	if len(runes) <= maxNameLen {
+		return name
+	}
+	return string(runes[:maxNameLen])
+}
@@ -0,0 +1,274 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/config"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/pkg/oidc/oidctest"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+	"github.com/ferdiebergado/gojeep/internal/repository/mock"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	secMock "github.com/ferdiebergado/gojeep/internal/pkg/security/mock"
+	svcMock "github.com/ferdiebergado/gojeep/internal/service/mock"
+)
+
+const (
+	oauthProvider = "stub"
+	oauthSubject  = "248289761001"
+	oauthEmail    = "jane@example.com"
+	oauthUserID   = "5f1d6c2e-3b4a-4e8f-9a0b-1c2d3e4f5a6b"
+	oauthServer   = "https://gojeep.test"
+)
+
+type oauthMocks struct {
+	repo     *mock.MockOAuthRepository
+	userRepo *mock.MockUserRepository
+	auth     *svcMock.MockAuthService
+	hasher   *secMock.MockHasher
+	provider *oidctest.Provider
+}
+
+func newTestOAuthService(t *testing.T) (service.OAuthService, oauthMocks) {
+	t.Helper()
+	provider, err := oidctest.NewProvider("gojeep", "secret")
+	require.NoError(t, err)
+	t.Cleanup(provider.Close)
+
+	ctrl := gomock.NewController(t)
+	m := oauthMocks{
+		repo:     mock.NewMockOAuthRepository(ctrl),
+		userRepo: mock.NewMockUserRepository(ctrl),
+		auth:     svcMock.NewMockAuthService(ctrl),
+		hasher:   secMock.NewMockHasher(ctrl),
+		provider: provider,
+	}
+
+	cfg := &config.Config{
+		Server: &config.ServerConfig{URL: oauthServer},
+		OAuth: &config.OAuthOptions{
+			StateTTL: 600,
+			Providers: map[string]config.OAuthProviderOptions{
+				oauthProvider: {
+					Issuer:       provider.Issuer(),
+					ClientID:     "gojeep",
+					ClientSecret: "secret",
+					Scopes:       []string{"openid", "email", "profile"},
+				},
+			},
+		},
+	}
+	svc := service.NewOAuthService(&service.OAuthServiceDeps{
+		Repo:     m.repo,
+		UserRepo: m.userRepo,
+		Auth:     m.auth,
+		Hasher:   m.hasher,
+		Cfg:      cfg,
+	})
+	return svc, m
+}
+
+// authorize starts a sign-in, has the stub provider approve it and returns the callback parameters.
+func authorize(t *testing.T, svc service.OAuthService, m oauthMocks,
+	identity oidctest.Identity) service.FinishOAuthLoginParams {
+	t.Helper()
+	ctx := context.Background()
+	m.provider.SetIdentity(identity)
+
+	var saved repository.SaveStateParams
+	m.repo.EXPECT().SaveState(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, params repository.SaveStateParams) error {
+			saved = params
+			return nil
+		})
+
+	start, err := svc.StartLogin(ctx, oauthProvider)
+	require.NoError(t, err)
+
+	u, err := url.Parse(start.URL)
+	require.NoError(t, err)
+	assert.Equal(t, oauthServer+"/auth/oauth/stub/callback", u.Query().Get("redirect_uri"))
+	assert.Equal(t, saved.State, start.State)
+	assert.Equal(t, oauthProvider, saved.Provider)
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), saved.ExpiresAt, time.Second)
+
+	code, state, err := m.provider.Authorize(start.URL)
+	require.NoError(t, err)
+
+	m.repo.EXPECT().ConsumeState(ctx, state, oauthProvider).
+		Return(repository.OAuthState{Nonce: saved.Nonce, CodeVerifier: saved.CodeVerifier}, nil)
+
+	return service.FinishOAuthLoginParams{Provider: oauthProvider, State: state, Code: code}
+}
+
+func oauthUser(verified bool) model.User {
+	user := model.User{Model: model.Model{ID: oauthUserID}, Email: oauthEmail}
+	if verified {
+		now := time.Now()
+		user.VerifiedAt = &now
+	}
+	return user
+}
+
+func TestOAuthService_FinishLogin(t *testing.T) {
+	t.Parallel()
+
+	session := service.LoginResult{AccessToken: "access", RefreshToken: "refresh"}
+
+	testCases := []struct {
+		name          string
+		emailVerified bool
+		setup         func(ctx context.Context, m oauthMocks)
+		wantErr       error
+	}{
+		{
+			name:          "Linked identity",
+			emailVerified: true,
+			setup: func(ctx context.Context, m oauthMocks) {
+				m.repo.EXPECT().FindIdentity(ctx, oauthProvider, oauthSubject).
+					Return(model.Identity{UserID: oauthUserID}, nil)
+				m.userRepo.EXPECT().FindUserByID(ctx, oauthUserID).Return(oauthUser(true), nil)
+				m.auth.EXPECT().CompleteLogin(ctx, gomock.Any()).Return(session, nil)
+			},
+		},
+		{
+			name:          "New user with verified email",
+			emailVerified: true,
+			setup: func(ctx context.Context, m oauthMocks) {
+				m.repo.EXPECT().FindIdentity(ctx, oauthProvider, oauthSubject).Return(model.Identity{}, sql.ErrNoRows)
+				m.userRepo.EXPECT().FindUserByEmail(ctx, oauthEmail).Return(model.User{}, sql.ErrNoRows)
+				m.hasher.EXPECT().Hash(gomock.Any()).Return("hash", nil)
+				m.repo.EXPECT().CreateIdentityUser(ctx, repository.CreateIdentityUserParams{
+					Email:        oauthEmail,
+					Name:         "Jane Doe",
+					PasswordHash: "hash",
+					Verified:     true,
+					Provider:     oauthProvider,
+					Subject:      oauthSubject,
+				}).Return(oauthUser(true), nil)
+				m.auth.EXPECT().CompleteLogin(ctx, gomock.Any()).Return(session, nil)
+			},
+		},
+		{
+			name: "New user with unverified email",
+			setup: func(ctx context.Context, m oauthMocks) {
+				m.repo.EXPECT().FindIdentity(ctx, oauthProvider, oauthSubject).Return(model.Identity{}, sql.ErrNoRows)
+				m.userRepo.EXPECT().FindUserByEmail(ctx, oauthEmail).Return(model.User{}, sql.ErrNoRows)
+				m.hasher.EXPECT().Hash(gomock.Any()).Return("hash", nil)
+				m.repo.EXPECT().CreateIdentityUser(ctx, gomock.Any()).Return(oauthUser(false), nil)
+				m.auth.EXPECT().ResendVerification(ctx, oauthEmail).Return(nil)
+			},
+			wantErr: service.ErrUserNotVerified,
+		},
+		{
+			name:          "Existing user with verified email",
+			emailVerified: true,
+			setup: func(ctx context.Context, m oauthMocks) {
+				m.repo.EXPECT().FindIdentity(ctx, oauthProvider, oauthSubject).Return(model.Identity{}, sql.ErrNoRows)
+				m.userRepo.EXPECT().FindUserByEmail(ctx, oauthEmail).Return(oauthUser(true), nil)
+				m.repo.EXPECT().CreateIdentity(ctx, model.Identity{
+					UserID:   oauthUserID,
+					Provider: oauthProvider,
+					Subject:  oauthSubject,
+					Email:    oauthEmail,
+				}).Return(nil)
+				m.auth.EXPECT().CompleteLogin(ctx, gomock.Any()).Return(session, nil)
+			},
+		},
+		{
+			name:          "Existing unverified user with verified email",
+			emailVerified: true,
+			setup: func(ctx context.Context, m oauthMocks) {
+				m.repo.EXPECT().FindIdentity(ctx, oauthProvider, oauthSubject).Return(model.Identity{}, sql.ErrNoRows)
+				m.userRepo.EXPECT().FindUserByEmail(ctx, oauthEmail).Return(oauthUser(false), nil)
+				m.hasher.EXPECT().Hash(gomock.Any()).Return("hash", nil)
+				m.userRepo.EXPECT().UpdatePassword(ctx, oauthUserID, "hash").Return(nil)
+				m.userRepo.EXPECT().VerifyUser(ctx, oauthUserID).Return(nil)
+				m.repo.EXPECT().CreateIdentity(ctx, gomock.Any()).Return(nil)
+				m.auth.EXPECT().CompleteLogin(ctx, gomock.Any()).Return(session, nil)
+			},
+		},
+		{
+			name: "Existing user with unverified email",
+			setup: func(ctx context.Context, m oauthMocks) {
+				m.repo.EXPECT().FindIdentity(ctx, oauthProvider, oauthSubject).Return(model.Identity{}, sql.ErrNoRows)
+				m.userRepo.EXPECT().FindUserByEmail(ctx, oauthEmail).Return(oauthUser(true), nil)
+			},
+			wantErr: service.ErrOAuthEmailUnverified,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			svc, m := newTestOAuthService(t)
+			ctx := context.Background()
+
+			params := authorize(t, svc, m, oidctest.Identity{
+				Subject:       oauthSubject,
+				Email:         oauthEmail,
+				EmailVerified: tc.emailVerified,
+				Name:          "Jane Doe",
+			})
+			tc.setup(ctx, m)
+
+			result, err := svc.FinishLogin(ctx, params)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, session, result)
+		})
+	}
+}
+
+func TestOAuthService_FinishLogin_InvalidState(t *testing.T) {
+	t.Parallel()
+	svc, m := newTestOAuthService(t)
+	ctx := context.Background()
+
+	m.repo.EXPECT().ConsumeState(ctx, "forged", oauthProvider).Return(repository.OAuthState{}, sql.ErrNoRows)
+
+	params := service.FinishOAuthLoginParams{Provider: oauthProvider, State: "forged", Code: "code"}
+	_, err := svc.FinishLogin(ctx, params)
+	assert.ErrorIs(t, err, service.ErrInvalidOAuthState)
+}
+
+func TestOAuthService_FinishLogin_CodeReplayed(t *testing.T) {
+	t.Parallel()
+	svc, m := newTestOAuthService(t)
+	ctx := context.Background()
+
+	params := authorize(t, svc, m, oidctest.Identity{Subject: oauthSubject, Email: oauthEmail})
+	m.repo.EXPECT().FindIdentity(ctx, oauthProvider, oauthSubject).Return(model.Identity{}, sql.ErrNoRows)
+	m.userRepo.EXPECT().FindUserByEmail(ctx, oauthEmail).Return(oauthUser(true), nil)
+	_, err := svc.FinishLogin(ctx, params)
+	require.ErrorIs(t, err, service.ErrOAuthEmailUnverified)
+
+	// The provider accepts each code once, even with a state that is still valid.
+	m.repo.EXPECT().ConsumeState(ctx, params.State, oauthProvider).
+		Return(repository.OAuthState{Nonce: "nonce", CodeVerifier: "verifier"}, nil)
+	_, err = svc.FinishLogin(ctx, params)
+	assert.ErrorIs(t, err, service.ErrOAuthFailed)
+}
+
+func TestOAuthService_UnknownProvider(t *testing.T) {
+	t.Parallel()
+	svc, _ := newTestOAuthService(t)
+	ctx := context.Background()
+
+	_, err := svc.StartLogin(ctx, "unknown")
+	require.ErrorIs(t, err, service.ErrUnknownProvider)
+
+	_, err = svc.FinishLogin(ctx, service.FinishOAuthLoginParams{Provider: "unknown"})
+	assert.ErrorIs(t, err, service.ErrUnknownProvider)
+}
@@ -0,0 +1,400 @@
+//go:generate mockgen -destination=mock/provider_service_mock.go -package=mock . ProviderService
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/config"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/pkg/oidc"
+	"github.com/ferdiebergado/gojeep/internal/pkg/security"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+)
+
+// ProviderService makes gojeep an OpenID provider for other apps. Registered clients sign users in with
+// the authorization code flow and PKCE, and receive ID tokens signed with the server's signing key.
+type ProviderService interface {
+	Authorize(ctx context.Context, userID string, params AuthorizeParams) (AuthorizeResult, error)
+	Exchange(ctx context.Context, params ExchangeCodeParams) (TokenResult, error)
+	UserInfo(ctx context.Context, accessToken string) (UserInfo, error)
+	PurgeExpiredCodes(ctx context.Context) (int64, error)
+}
+
+type ProviderServiceDeps struct {
+	Repo       repository.ClientRepository
+	UserRepo   repository.UserRepository
+	Revocation RevocationService
+	Signer     security.Signer
+	Cfg        *config.Config
+}
+
+type providerService struct {
+	repo           repository.ClientRepository
+	userRepo       repository.UserRepository
+	revocation     RevocationService
+	signer         security.Signer
+	userInfoURL    string
+	codeTTL        time.Duration
+	accessTokenTTL time.Duration
+	idTokenTTL     time.Duration
+}
+
+var _ ProviderService = (*providerService)(nil)
+
+// Scopes a client can be granted. Every request must ask for openid.
+const (
+	ScopeOpenID  = "openid"
+	ScopeProfile = "profile"
+	ScopeEmail   = "email"
+)
+
+// authorizationCodeLen is the number of random bytes in an authorization code.
+const authorizationCodeLen = 32
+
+var (
+	ErrInvalidClient        = errors.New("invalid oauth client")
+	ErrInvalidRedirectURI   = errors.New("redirect uri not registered for client")
+	ErrInvalidGrant         = errors.New("invalid authorization grant")
+	ErrUnsupportedGrantType = errors.New("unsupported grant type")
+)
+
+func NewProviderService(deps *ProviderServiceDeps) ProviderService {
+	opts := deps.Cfg.OIDC
+	return &providerService{
+		repo:           deps.Repo,
+		userRepo:       deps.UserRepo,
+		revocation:     deps.Revocation,
+		signer:         deps.Signer,
+		userInfoURL:    deps.Cfg.JWT.Issuer + "/userinfo",
+		codeTTL:        time.Duration(opts.CodeTTL) * time.Second,
+		accessTokenTTL: time.Duration(opts.AccessTokenTTL) * time.Second,
+		idTokenTTL:     time.Duration(opts.IDTokenTTL) * time.Second,
+	}
+}
+
+// AuthorizeParams are the parameters of an authorization request. Consent is the user's answer to the
+// consent prompt, and nil until they are asked.
+type AuthorizeParams struct {
+	ClientID            string
+	RedirectURI         string
+	ResponseType        string
+	Scope               string
+	State               string
+	Nonce               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	Consent             *bool
+}
+
+// AuthorizeResult is where to send the user back to the client, or, if ConsentRequired is set, what to
+// ask the user to consent to.
+type AuthorizeResult struct {
+	RedirectURL     string
+	ConsentRequired bool
+	ClientName      string
+	Scope           string
+}
+
+// Authorize issues an authorization code to a client for the signed-in user. Only an unknown client or
+// redirect URI is returned as an error; other problems are reported to the client through the redirect,
+// as RFC 6749 section 4.1.2.1 requires.
+func (s *providerService) Authorize(ctx context.Context, userID string,
+	params AuthorizeParams) (AuthorizeResult, error) {
+	client, err := s.repo.FindClient(ctx, params.ClientID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return AuthorizeResult{}, ErrInvalidClient
+		}
+		return AuthorizeResult{}, fmt.Errorf("find client %s: %w", params.ClientID, err)
+	}
+
+	if !slices.Contains(client.RedirectURIs, params.RedirectURI) {
+		return AuthorizeResult{}, ErrInvalidRedirectURI
+	}
+
+	if params.ResponseType != "code" {
+		return redirectError(params, "unsupported_response_type", "only the code response type is supported")
+	}
+
+	scope := grantedScope(params.Scope)
+	if !slices.Contains(strings.Fields(scope), ScopeOpenID) {
+		return redirectError(params, "invalid_scope", "the openid scope is required")
+	}
+
+	if params.CodeChallenge == "" || params.CodeChallengeMethod != "S256" {
+		return redirectError(params, "invalid_request", "PKCE with the S256 method is required")
+	}
+
+	if !client.FirstParty {
+		if params.Consent == nil {
+			return AuthorizeResult{ConsentRequired: true, ClientName: client.Name, Scope: scope}, nil
+		}
+
+		if !*params.Consent {
+			return redirectError(params, "access_denied", "the user denied the request")
+		}
+	}
+
+	code, err := security.GenerateRandomBytesEncoded(authorizationCodeLen)
+	if err != nil {
+		return AuthorizeResult{}, fmt.Errorf("generate authorization code: %w", err)
+	}
+
+	saveParams := repository.SaveAuthorizationCodeParams{
+		CodeHash: security.HashToken(code),
+		AuthorizationCode: model.AuthorizationCode{
+			ClientID:      client.ID,
+			UserID:        userID,
+			RedirectURI:   params.RedirectURI,
+			Scope:         scope,
+			Nonce:         params.Nonce,
+			CodeChallenge: params.CodeChallenge,
+		},
+		ExpiresAt: time.Now().Add(s.codeTTL),
+	}
+	if err := s.repo.SaveAuthorizationCode(ctx, saveParams); err != nil {
+		return AuthorizeResult{}, fmt.Errorf("save authorization code: %w", err)
+	}
+
+	redirectURL, err := withQuery(params.RedirectURI, url.Values{"code": {code}, "state": {params.State}})
+	if err != nil {
+		return AuthorizeResult{}, err
+	}
+
+	return AuthorizeResult{RedirectURL: redirectURL}, nil
+}
+
+// ExchangeCodeParams are the parameters of a token request. ClientSecret is empty for public clients.
+type ExchangeCodeParams struct {
+	GrantType    string
+	Code         string
+	RedirectURI  string
+	ClientID     string
+	ClientSecret string
+	CodeVerifier string
+}
+
+// TokenResult is a successful token response. ExpiresIn is the lifetime of the access token in seconds.
+type TokenResult struct {
+	AccessToken string
+	IDToken     string
+	Scope       string
+	ExpiresIn   int
+}
+
+// Exchange redeems an authorization code for an access token and an ID token. The code is consumed even
+// if the request fails, so a stolen code cannot be retried. A code of a user suspended since it was issued
+// is refused.
+func (s *providerService) Exchange(ctx context.Context, params ExchangeCodeParams) (TokenResult, error) {
+	if params.GrantType != "authorization_code" {
+		return TokenResult{}, ErrUnsupportedGrantType
+	}
+
+	client, err := s.authenticateClient(ctx, params.ClientID, params.ClientSecret)
+	if err != nil {
+		return TokenResult{}, err
+	}
+
+	code, err := s.repo.ConsumeAuthorizationCode(ctx, security.HashToken(params.Code))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TokenResult{}, fmt.Errorf("%w: unknown or expired code", ErrInvalidGrant)
+		}
+		return TokenResult{}, fmt.Errorf("consume authorization code: %w", err)
+	}
+
+	if code.ClientID != client.ID || code.RedirectURI != params.RedirectURI {
+		return TokenResult{}, fmt.Errorf("%w: code issued to another client or redirect uri", ErrInvalidGrant)
+	}
+
+	challenge := oidc.CodeChallenge(params.CodeVerifier)
+	if subtle.ConstantTimeCompare([]byte(challenge), []byte(code.CodeChallenge)) != 1 {
+		return TokenResult{}, fmt.Errorf("%w: code verifier does not match", ErrInvalidGrant)
+	}
+
+	user, err := s.userRepo.FindUserByID(ctx, code.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TokenResult{}, fmt.Errorf("%w: user no longer exists", ErrInvalidGrant)
+		}
+		return TokenResult{}, fmt.Errorf("find user %s: %w", code.UserID, err)
+	}
+
+	if user.Suspended(time.Now()) {
+		return TokenResult{}, fmt.Errorf("%w: user suspended", ErrInvalidGrant)
+	}
+
+	accessToken, err := s.signer.SignWithScope(security.PurposeOAuthAccess, user.ID, []string{s.userInfoURL},
+		code.Scope, s.accessTokenTTL)
+	if err != nil {
+		return TokenResult{}, fmt.Errorf("sign access token: %w", err)
+	}
+
+	info := userInfo(user, code.Scope)
+	idToken, err := s.signer.SignIDToken(security.IDTokenClaims{
+		Subject:       info.Subject,
+		ClientID:      client.ID,
+		Nonce:         code.Nonce,
+		Email:         info.Email,
+		EmailVerified: info.EmailVerified,
+		Name:          info.Name,
+	}, s.idTokenTTL)
+	if err != nil {
+		return TokenResult{}, fmt.Errorf("sign id token: %w", err)
+	}
+
+	return TokenResult{
+		AccessToken: accessToken,
+		IDToken:     idToken,
+		Scope:       code.Scope,
+		ExpiresIn:   int(s.accessTokenTTL.Seconds()),
+	}, nil
+}
+
+// UserInfo holds the claims about a user released by the granted scope. Email and EmailVerified are set
+// with the email scope, and Name with the profile scope.
+type UserInfo struct {
+	Subject       string
+	Email         string
+	EmailVerified *bool
+	Name          string
+}
+
+// UserInfo returns the claims about the user an access token was issued for. Tokens of a user signed out
+// everywhere since they were issued, or currently suspended, are invalid.
+func (s *providerService) UserInfo(ctx context.Context, accessToken string) (UserInfo, error) {
+	claims, err := s.signer.Verify(accessToken, security.PurposeOAuthAccess, s.userInfoURL)
+	if err != nil {
+		return UserInfo{}, ErrInvalidToken
+	}
+
+	revoked, err := s.revocation.IsUserTokenRevoked(ctx, claims.Subject, claims.IssuedAt)
+	if err != nil {
+		return UserInfo{}, err
+	}
+
+	if revoked {
+		return UserInfo{}, fmt.Errorf("%w: tokens of user revoked", ErrInvalidToken)
+	}
+
+	suspended, err := s.revocation.IsUserSuspended(ctx, claims.Subject)
+	if err != nil {
+		return UserInfo{}, err
+	}
+
+	if suspended {
+		return UserInfo{}, fmt.Errorf("%w: user suspended", ErrInvalidToken)
+	}
+
+	user, err := s.userRepo.FindUserByID(ctx, claims.Subject)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return UserInfo{}, ErrInvalidToken
+		}
+		return UserInfo{}, fmt.Errorf("find user %s: %w", claims.Subject, err)
+	}
+
+	return userInfo(user, claims.Scope), nil
+}
+
+func (s *providerService) PurgeExpiredCodes(ctx context.Context) (int64, error) {
+	purged, err := s.repo.PurgeAuthorizationCodes(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("purge authorization codes: %w", err)
+	}
+	return purged, nil
+}
+
+// authenticateClient checks the secret of a confidential client. Public clients have no secret and are
+// bound to the code by PKCE only.
+func (s *providerService) authenticateClient(ctx context.Context, clientID, secret string) (model.Client, error) {
+	client, err := s.repo.FindClient(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Client{}, ErrInvalidClient
+		}
+		return model.Client{}, fmt.Errorf("find client %s: %w", clientID, err)
+	}
+
+	if client.SecretHash == "" {
+		return client, nil
+	}
+
+	if subtle.ConstantTimeCompare([]byte(security.HashToken(secret)), []byte(client.SecretHash)) != 1 {
+		return model.Client{}, fmt.Errorf("%w: wrong secret", ErrInvalidClient)
+	}
+
+	return client, nil
+}
+
+func userInfo(user model.User, scope string) UserInfo {
+	scopes := strings.Fields(scope)
+	info := UserInfo{Subject: user.ID}
+
+	if slices.Contains(scopes, ScopeEmail) {
+		verified := user.VerifiedAt != nil
+		info.Email = user.Email
+		info.EmailVerified = &verified
+	}
+
+	if slices.Contains(scopes, ScopeProfile) {
+		info.Name = user.Name
+	}
+
+	return info
+}
+
+// grantedScope keeps the requested scopes this server supports, dropping the rest as RFC 6749 allows.
+func grantedScope(requested string) string {
+	var granted []string
+	for _, scope := range strings.Fields(requested) {
+		supported := scope == ScopeOpenID || scope == ScopeProfile || scope == ScopeEmail
+		if supported && !slices.Contains(granted, scope) {
+			granted = append(granted, scope)
+		}
+	}
+	return strings.Join(granted, " ")
+}
+
+// redirectError sends an authorization error back to the client's redirect URI.
+func redirectError(params AuthorizeParams, code, description string) (AuthorizeResult, error) {
+	query := url.Values{"error": {code}, "error_description": {description}}
+	if params.State != "" {
+		query.Set("state", params.State)
+	}
+
+	redirectURL, err := withQuery(params.RedirectURI, query)
+	if err != nil {
+		return AuthorizeResult{}, err
+	}
+
+	return AuthorizeResult{RedirectURL: redirectURL}, nil
+}
+
+// withQuery adds parameters to a URL, keeping the query it already has.
+func withQuery(rawURL string, params url.Values) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("parse redirect uri: %w", err)
+	}
+
+	query := u.Query()
+	for key, values := range params {
+		for _, v := range values {
+			if v != "" {
+				query.Add(key, v)
+			}
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
@@ -0,0 +1,399 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/config"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/pkg/oidc"
+	"github.com/ferdiebergado/gojeep/internal/pkg/security"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+	"github.com/ferdiebergado/gojeep/internal/repository/mock"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	secMock "github.com/ferdiebergado/gojeep/internal/pkg/security/mock"
+	svcMock "github.com/ferdiebergado/gojeep/internal/service/mock"
+)
+
+const (
+	providerIssuer      = "https://gojeep.test"
+	providerClientID    = "intranet"
+	providerSecret      = "client-secret"
+	providerRedirectURI = "https://intranet.example.com/callback?tenant=1"
+	providerUserID      = "0c9e3b2a-7d4f-4a6b-8e1c-2f3a4b5c6d7e"
+	providerVerifier    = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+)
+
+type providerMocks struct {
+	repo       *mock.MockClientRepository
+	userRepo   *mock.MockUserRepository
+	revocation *svcMock.MockRevocationService
+	signer     *secMock.MockSigner
+}
+
+func newTestProviderService(t *testing.T) (service.ProviderService, providerMocks) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	m := providerMocks{
+		repo:       mock.NewMockClientRepository(ctrl),
+		userRepo:   mock.NewMockUserRepository(ctrl),
+		revocation: svcMock.NewMockRevocationService(ctrl),
+		signer:     secMock.NewMockSigner(ctrl),
+	}
+
+	cfg := &config.Config{
+		JWT:  &config.JWTOptions{Issuer: providerIssuer},
+		OIDC: &config.OIDCOptions{CodeTTL: 60, AccessTokenTTL: 3600, IDTokenTTL: 600},
+	}
+	svc := service.NewProviderService(&service.ProviderServiceDeps{
+		Repo:       m.repo,
+		UserRepo:   m.userRepo,
+		Revocation: m.revocation,
+		Signer:     m.signer,
+		Cfg:        cfg,
+	})
+	return svc, m
+}
+
+func providerClient(firstParty bool) model.Client {
+	return model.Client{
+		ID:           providerClientID,
+		Name:         "Intranet",
+		SecretHash:   security.HashToken(providerSecret),
+		RedirectURIs: []string{providerRedirectURI},
+		FirstParty:   firstParty,
+	}
+}
+
+func authorizeParams() service.AuthorizeParams {
+	return service.AuthorizeParams{
+		ClientID:            providerClientID,
+		RedirectURI:         providerRedirectURI,
+		ResponseType:        "code",
+		Scope:               "openid email offline_access",
+		State:               "xyz",
+		Nonce:               "n-0S6_WzA2Mj",
+		CodeChallenge:       oidc.CodeChallenge(providerVerifier),
+		CodeChallengeMethod: "S256",
+	}
+}
+
+func TestProviderService_Authorize(t *testing.T) {
+	t.Parallel()
+	approve, deny := true, false
+
+	testCases := []struct {
+		name        string
+		firstParty  bool
+		modify      func(p *service.AuthorizeParams)
+		wantCode    bool
+		wantError   string
+		wantConsent bool
+		wantErr     error
+		findErr     error
+	}{
+		{name: "First-party client", firstParty: true, wantCode: true},
+		{
+			name:        "Consent required",
+			wantConsent: true,
+		},
+		{
+			name:     "Consent given",
+			modify:   func(p *service.AuthorizeParams) { p.Consent = &approve },
+			wantCode: true,
+		},
+		{
+			name:      "Consent denied",
+			modify:    func(p *service.AuthorizeParams) { p.Consent = &deny },
+			wantError: "access_denied",
+		},
+		{
+			name:       "Missing openid scope",
+			firstParty: true,
+			modify:     func(p *service.AuthorizeParams) { p.Scope = "email" },
+			wantError:  "invalid_scope",
+		},
+		{
+			name:       "Missing PKCE",
+			firstParty: true,
+			modify:     func(p *service.AuthorizeParams) { p.CodeChallenge = "" },
+			wantError:  "invalid_request",
+		},
+		{
+			name:       "Plain PKCE",
+			firstParty: true,
+			modify:     func(p *service.AuthorizeParams) { p.CodeChallengeMethod = "plain" },
+			wantError:  "invalid_request",
+		},
+		{
+			name:       "Implicit flow",
+			firstParty: true,
+			modify:     func(p *service.AuthorizeParams) { p.ResponseType = "token" },
+			wantError:  "unsupported_response_type",
+		},
+		{
+			name:       "Unregistered redirect URI",
+			firstParty: true,
+			modify:     func(p *service.AuthorizeParams) { p.RedirectURI = "https://evil.example.com/callback" },
+			wantErr:    service.ErrInvalidRedirectURI,
+		},
+		{
+			name:    "Unknown client",
+			findErr: sql.ErrNoRows,
+			wantErr: service.ErrInvalidClient,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			svc, m := newTestProviderService(t)
+			ctx := context.Background()
+
+			params := authorizeParams()
+			if tc.modify != nil {
+				tc.modify(&params)
+			}
+
+			m.repo.EXPECT().FindClient(ctx, providerClientID).Return(providerClient(tc.firstParty), tc.findErr)
+
+			var saved repository.SaveAuthorizationCodeParams
+			if tc.wantCode {
+				m.repo.EXPECT().SaveAuthorizationCode(ctx, gomock.Any()).DoAndReturn(
+					func(_ context.Context, params repository.SaveAuthorizationCodeParams) error {
+						saved = params
+						return nil
+					})
+			}
+
+			result, err := svc.Authorize(ctx, providerUserID, params)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			if tc.wantConsent {
+				assert.True(t, result.ConsentRequired)
+				assert.Equal(t, "Intranet", result.ClientName)
+				assert.Equal(t, "openid email", result.Scope)
+				assert.Empty(t, result.RedirectURL)
+				return
+			}
+
+			redirect, err := url.Parse(result.RedirectURL)
+			require.NoError(t, err)
+			assert.Equal(t, "intranet.example.com", redirect.Host)
+			query := redirect.Query()
+			assert.Equal(t, "1", query.Get("tenant"))
+			assert.Equal(t, "xyz", query.Get("state"))
+
+			if tc.wantError != "" {
+				assert.Equal(t, tc.wantError, query.Get("error"))
+				assert.Empty(t, query.Get("code"))
+				return
+			}
+
+			code := query.Get("code")
+			require.NotEmpty(t, code)
+			assert.Equal(t, security.HashToken(code), saved.CodeHash)
+			assert.Equal(t, providerUserID, saved.UserID)
+			assert.Equal(t, "openid email", saved.Scope)
+			assert.Equal(t, params.Nonce, saved.Nonce)
+			assert.WithinDuration(t, time.Now().Add(time.Minute), saved.ExpiresAt, time.Second)
+		})
+	}
+}
+
+func TestProviderService_Exchange(t *testing.T) {
+	t.Parallel()
+	const code = "code"
+
+	issued := model.AuthorizationCode{
+		ClientID:      providerClientID,
+		UserID:        providerUserID,
+		RedirectURI:   providerRedirectURI,
+		Scope:         "openid email",
+		Nonce:         "nonce",
+		CodeChallenge: oidc.CodeChallenge(providerVerifier),
+	}
+
+	testCases := []struct {
+		name         string
+		modify       func(p *service.ExchangeCodeParams)
+		consumeErr   error
+		consumesCode bool
+		suspended    bool
+		wantErr      error
+	}{
+		{name: "Redeemed", consumesCode: true},
+		{
+			name:    "Unsupported grant type",
+			modify:  func(p *service.ExchangeCodeParams) { p.GrantType = "password" },
+			wantErr: service.ErrUnsupportedGrantType,
+		},
+		{
+			name:    "Wrong client secret",
+			modify:  func(p *service.ExchangeCodeParams) { p.ClientSecret = "wrong" },
+			wantErr: service.ErrInvalidClient,
+		},
+		{
+			name:         "Unknown code",
+			consumeErr:   sql.ErrNoRows,
+			consumesCode: true,
+			wantErr:      service.ErrInvalidGrant,
+		},
+		{
+			name:         "Wrong redirect URI",
+			modify:       func(p *service.ExchangeCodeParams) { p.RedirectURI = "https://intranet.example.com/other" },
+			consumesCode: true,
+			wantErr:      service.ErrInvalidGrant,
+		},
+		{
+			name:         "Wrong code verifier",
+			modify:       func(p *service.ExchangeCodeParams) { p.CodeVerifier = "wrong" },
+			consumesCode: true,
+			wantErr:      service.ErrInvalidGrant,
+		},
+		{
+			name:         "User suspended",
+			consumesCode: true,
+			suspended:    true,
+			wantErr:      service.ErrInvalidGrant,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			svc, m := newTestProviderService(t)
+			ctx := context.Background()
+
+			params := service.ExchangeCodeParams{
+				GrantType:    "authorization_code",
+				Code:         code,
+				RedirectURI:  providerRedirectURI,
+				ClientID:     providerClientID,
+				ClientSecret: providerSecret,
+				CodeVerifier: providerVerifier,
+			}
+			if tc.modify != nil {
+				tc.modify(&params)
+			}
+
+			if params.GrantType == "authorization_code" {
+				m.repo.EXPECT().FindClient(ctx, providerClientID).Return(providerClient(true), nil)
+			}
+			if tc.consumesCode {
+				m.repo.EXPECT().ConsumeAuthorizationCode(ctx, security.HashToken(code)).Return(issued, tc.consumeErr)
+			}
+			now := time.Now()
+			user := model.User{Model: model.Model{ID: providerUserID}, Email: "user@example.com", Name: "User"}
+			user.VerifiedAt = &now
+			if tc.suspended {
+				user.SuspendedAt = &now
+				m.userRepo.EXPECT().FindUserByID(ctx, providerUserID).Return(user, nil)
+			}
+			if tc.wantErr == nil {
+				m.userRepo.EXPECT().FindUserByID(ctx, providerUserID).Return(user, nil)
+				m.signer.EXPECT().SignWithScope(security.PurposeOAuthAccess, providerUserID,
+					[]string{providerIssuer + "/userinfo"}, "openid email", time.Hour).Return("access", nil)
+				verified := true
+				m.signer.EXPECT().SignIDToken(security.IDTokenClaims{
+					Subject:       providerUserID,
+					ClientID:      providerClientID,
+					Nonce:         "nonce",
+					Email:         "user@example.com",
+					EmailVerified: &verified,
+				}, 10*time.Minute).Return("id", nil)
+			}
+
+			result, err := svc.Exchange(ctx, params)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, service.TokenResult{
+				AccessToken: "access",
+				IDToken:     "id",
+				Scope:       "openid email",
+				ExpiresIn:   3600,
+			}, result)
+		})
+	}
+}
+
+func TestProviderService_UserInfo(t *testing.T) {
+	t.Parallel()
+	issuedAt := time.Now()
+	claims := &security.Claims{Subject: providerUserID, Scope: "openid profile", IssuedAt: issuedAt}
+
+	testCases := []struct {
+		name    string
+		setup   func(m providerMocks)
+		wantErr error
+	}{
+		{
+			name: "Valid token",
+			setup: func(m providerMocks) {
+				m.signer.EXPECT().Verify("access", security.PurposeOAuthAccess, providerIssuer+"/userinfo").
+					Return(claims, nil)
+				m.revocation.EXPECT().IsUserTokenRevoked(gomock.Any(), providerUserID, issuedAt).Return(false, nil)
+				m.revocation.EXPECT().IsUserSuspended(gomock.Any(), providerUserID).Return(false, nil)
+				user := model.User{Model: model.Model{ID: providerUserID}, Email: "user@example.com", Name: "User"}
+				m.userRepo.EXPECT().FindUserByID(gomock.Any(), providerUserID).Return(user, nil)
+			},
+		},
+		{
+			name: "Invalid token",
+			setup: func(m providerMocks) {
+				m.signer.EXPECT().Verify("access", security.PurposeOAuthAccess, providerIssuer+"/userinfo").
+					Return(nil, security.ErrTokenPurpose)
+			},
+			wantErr: service.ErrInvalidToken,
+		},
+		{
+			name: "User signed out everywhere",
+			setup: func(m providerMocks) {
+				m.signer.EXPECT().Verify("access", security.PurposeOAuthAccess, providerIssuer+"/userinfo").
+					Return(claims, nil)
+				m.revocation.EXPECT().IsUserTokenRevoked(gomock.Any(), providerUserID, issuedAt).Return(true, nil)
+			},
+			wantErr: service.ErrInvalidToken,
+		},
+		{
+			name: "User suspended",
+			setup: func(m providerMocks) {
+				m.signer.EXPECT().Verify("access", security.PurposeOAuthAccess, providerIssuer+"/userinfo").
+					Return(claims, nil)
+				m.revocation.EXPECT().IsUserTokenRevoked(gomock.Any(), providerUserID, issuedAt).Return(false, nil)
+				m.revocation.EXPECT().IsUserSuspended(gomock.Any(), providerUserID).Return(true, nil)
+			},
+			wantErr: service.ErrInvalidToken,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			svc, m := newTestProviderService(t)
+			tc.setup(m)
+
+			info, err := svc.UserInfo(context.Background(), "access")
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, service.UserInfo{Subject: providerUserID, Name: "User"}, info)
+		})
+	}
+}
@@ -0,0 +1,173 @@
+//go:generate mockgen -destination=mock/revocation_service_mock.go -package=mock . RevocationService
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/config"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+)
+
+// RevocationService decides whether the credentials presented on a request are still honoured.
+type RevocationService interface {
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	IsUserTokenRevoked(ctx context.Context, userID string, issuedAt time.Time) (bool, error)
+	IsUserSuspended(ctx context.Context, userID string) (bool, error)
+	ForgetUser(userID string)
+	PurgeExpiredTokens(ctx context.Context) (int64, error)
+}
+
+type RevocationServiceDeps struct {
+	UserRepo         repository.UserRepository
+	RevokedTokenRepo repository.RevokedTokenRepository
+	RefreshTokenRepo repository.RefreshTokenRepository
+	TokenRepo        repository.TokenRepository
+	Cfg              *config.Config
+}
+
+type revocationService struct {
+	userRepo         repository.UserRepository
+	revokedTokenRepo repository.RevokedTokenRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	tokenRepo        repository.TokenRepository
+	statuses         *statusCache
+}
+
+var _ RevocationService = (*revocationService)(nil)
+
+func NewRevocationService(deps *RevocationServiceDeps) RevocationService {
+	return &revocationService{
+		userRepo:         deps.UserRepo,
+		revokedTokenRepo: deps.RevokedTokenRepo,
+		refreshTokenRepo: deps.RefreshTokenRepo,
+		tokenRepo:        deps.TokenRepo,
+		statuses:         newStatusCache(time.Duration(deps.Cfg.Account.StatusCacheTTL) * time.Second),
+	}
+}
+
+// statusSweepSize is the number of cached statuses above which expired ones are dropped.
+const statusSweepSize = 10_000
+
+// statusCache keeps the statuses of users for a short while, so the checks made on every authenticated
+// request do not each query the database. A user that no longer exists is cached with a nil status.
+type statusCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]cachedStatus
+}
+
+type cachedStatus struct {
+	status    *model.UserStatus
+	expiresAt time.Time
+}
+
+func newStatusCache(ttl time.Duration) *statusCache {
+	return &statusCache{ttl: ttl, entries: make(map[string]cachedStatus)}
+}
+
+func (c *statusCache) get(userID string, now time.Time) (*model.UserStatus, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[userID]
+	if !ok || !now.Before(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.status, true
+}
+
+func (c *statusCache) put(userID string, status *model.UserStatus, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= statusSweepSize {
+		for id, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, id)
+			}
+		}
+	}
+	c.entries[userID] = cachedStatus{status: status, expiresAt: now.Add(c.ttl)}
+}
+
+func (c *statusCache) forget(userID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, userID)
+}
+
+func (s *revocationService) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	return s.revokedTokenRepo.IsTokenRevoked(ctx, jti)
+}
+
+// IsUserTokenRevoked reports whether a token issued to the user at issuedAt was revoked with the rest of
+// the user's credentials, as when the account is deleted or suspended.
+func (s *revocationService) IsUserTokenRevoked(ctx context.Context, userID string, issuedAt time.Time) (bool, error) {
+	status, err := s.userStatus(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return status == nil || !status.AcceptsToken(issuedAt), nil
+}
+
+// IsUserSuspended reports whether the user is currently suspended.
+func (s *revocationService) IsUserSuspended(ctx context.Context, userID string) (bool, error) {
+	status, err := s.userStatus(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return status != nil && status.Suspended(time.Now()), nil
+}
+
+// ForgetUser drops the cached status of the user, so a change to it takes effect at once on this
+// instance.
+func (s *revocationService) ForgetUser(userID string) {
+	s.statuses.forget(userID)
+}
+
+// userStatus returns the status of the user, or nil if the user does not exist.
+func (s *revocationService) userStatus(ctx context.Context, userID string) (*model.UserStatus, error) {
+	now := time.Now()
+	if status, ok := s.statuses.get(userID, now); ok {
+		return status, nil
+	}
+
+	var status *model.UserStatus
+	found, err := s.userRepo.FindUserStatus(ctx, userID)
+	switch {
+	case err == nil:
+		status = &found
+	case !errors.Is(err, sql.ErrNoRows):
+		return nil, fmt.Errorf("find status of user %s: %w", userID, err)
+	}
+
+	s.statuses.put(userID, status, now)
+	return status, nil
+}
+
+// PurgeExpiredTokens drops revocation entries, refresh tokens and single-use tokens that have expired,
+// since an expired token is rejected by the signer anyway.
+func (s *revocationService) PurgeExpiredTokens(ctx context.Context) (int64, error) {
+	revoked, err := s.revokedTokenRepo.PurgeRevokedTokens(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("purge revoked tokens: %w", err)
+	}
+
+	refresh, err := s.refreshTokenRepo.PurgeRefreshTokens(ctx)
+	if err != nil {
+		return revoked, fmt.Errorf("purge refresh tokens: %w", err)
+	}
+
+	single, err := s.tokenRepo.PurgeTokens(ctx)
+	if err != nil {
+		return revoked + refresh, fmt.Errorf("purge tokens: %w", err)
+	}
+
+	return revoked + refresh + single, nil
+}
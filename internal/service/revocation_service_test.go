@@ -0,0 +1,206 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/config"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/repository/mock"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRevocationService_IsTokenRevoked(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mockRevokedRepo := mock.NewMockRevokedTokenRepository(ctrl)
+	mockRevokedRepo.EXPECT().IsTokenRevoked(gomock.Any(), "jti").Return(true, nil)
+
+	svc := service.NewRevocationService(&service.RevocationServiceDeps{
+		RevokedTokenRepo: mockRevokedRepo,
+		Cfg:              revocationConfig(),
+	})
+	revoked, err := svc.IsTokenRevoked(context.Background(), "jti")
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func revocationConfig() *config.Config {
+	return &config.Config{Account: &config.AccountOptions{StatusCacheTTL: 30}}
+}
+
+func TestRevocationService_IsUserTokenRevoked(t *testing.T) {
+	t.Parallel()
+	issuedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	revokedAfter := issuedAt.Add(time.Minute)
+	revokedSameSecond := issuedAt.Add(500 * time.Millisecond)
+
+	tests := []struct {
+		name      string
+		status    model.UserStatus
+		statusErr error
+		want      bool
+	}{
+		{name: "Never revoked", want: false},
+		{name: "Revoked after issue", status: model.UserStatus{TokensRevokedAt: &revokedAfter}, want: true},
+		{name: "Revoked within the second", status: model.UserStatus{TokensRevokedAt: &revokedSameSecond}, want: false},
+		{name: "Deleted user", statusErr: sql.ErrNoRows, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockUserRepo := mock.NewMockUserRepository(ctrl)
+			mockUserRepo.EXPECT().FindUserStatus(gomock.Any(), "1").Return(tt.status, tt.statusErr)
+
+			svc := service.NewRevocationService(&service.RevocationServiceDeps{
+				UserRepo: mockUserRepo,
+				Cfg:      revocationConfig(),
+			})
+			revoked, err := svc.IsUserTokenRevoked(context.Background(), "1", issuedAt)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, revoked)
+		})
+	}
+}
+
+func TestRevocationService_IsUserSuspended(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		status    model.UserStatus
+		statusErr error
+		want      bool
+	}{
+		{name: "Not suspended", want: false},
+		{name: "Suspended indefinitely", status: suspendedStatus(&past, nil), want: true},
+		{name: "Suspended until later", status: suspendedStatus(&past, &future), want: true},
+		{name: "Suspension over", status: suspendedStatus(&past, &past), want: false},
+		{name: "Deleted user", statusErr: sql.ErrNoRows, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockUserRepo := mock.NewMockUserRepository(ctrl)
+			mockUserRepo.EXPECT().FindUserStatus(gomock.Any(), "1").Return(tt.status, tt.statusErr)
+
+			svc := service.NewRevocationService(&service.RevocationServiceDeps{
+				UserRepo: mockUserRepo,
+				Cfg:      revocationConfig(),
+			})
+			suspended, err := svc.IsUserSuspended(context.Background(), "1")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, suspended)
+		})
+	}
+}
+
+func suspendedStatus(at, until *time.Time) model.UserStatus {
+	return model.UserStatus{Suspension: model.Suspension{SuspendedAt: at, SuspendedUntil: until}}
+}
+
+func TestRevocationService_StatusCache(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	now := time.Now()
+	ctrl := gomock.NewController(t)
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+
+	svc := service.NewRevocationService(&service.RevocationServiceDeps{
+		UserRepo: mockUserRepo,
+		Cfg:      revocationConfig(),
+	})
+
+	mockUserRepo.EXPECT().FindUserStatus(gomock.Any(), "1").Return(model.UserStatus{}, nil).Times(1)
+	revoked, err := svc.IsUserTokenRevoked(ctx, "1", now)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+	suspended, err := svc.IsUserSuspended(ctx, "1")
+	require.NoError(t, err)
+	assert.False(t, suspended, "cached status not used")
+
+	svc.ForgetUser("1")
+	mockUserRepo.EXPECT().FindUserStatus(gomock.Any(), "1").Return(suspendedStatus(&now, nil), nil).Times(1)
+	suspended, err = svc.IsUserSuspended(ctx, "1")
+	require.NoError(t, err)
+	assert.True(t, suspended, "forgotten status still cached")
+}
+
+func TestRevocationService_StatusError(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mockUserRepo := mock.NewMockUserRepository(ctrl)
+	dbErr := errors.New("connection refused")
+	mockUserRepo.EXPECT().FindUserStatus(gomock.Any(), "1").Return(model.UserStatus{}, dbErr).Times(2)
+
+	svc := service.NewRevocationService(&service.RevocationServiceDeps{
+		UserRepo: mockUserRepo,
+		Cfg:      revocationConfig(),
+	})
+	_, err := svc.IsUserSuspended(context.Background(), "1")
+	assert.ErrorIs(t, err, dbErr)
+	_, err = svc.IsUserSuspended(context.Background(), "1")
+	assert.ErrorIs(t, err, dbErr, "failed lookup was cached")
+}
+
+func TestRevocationService_PurgeExpiredTokens(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		revokedErr error
+		refreshErr error
+		want       int64
+	}{
+		{name: "Success", want: 6},
+		{name: "Failure_RevokedTokens", revokedErr: errors.New("delete failed")},
+		{name: "Failure_RefreshTokens", refreshErr: errors.New("delete failed"), want: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockRevokedRepo := mock.NewMockRevokedTokenRepository(ctrl)
+			mockRefreshRepo := mock.NewMockRefreshTokenRepository(ctrl)
+			mockTokenRepo := mock.NewMockTokenRepository(ctrl)
+
+			if tc.revokedErr != nil {
+				mockRevokedRepo.EXPECT().PurgeRevokedTokens(gomock.Any()).Return(int64(0), tc.revokedErr)
+			} else {
+				mockRevokedRepo.EXPECT().PurgeRevokedTokens(gomock.Any()).Return(int64(2), nil)
+				mockRefreshRepo.EXPECT().PurgeRefreshTokens(gomock.Any()).Return(int64(3), tc.refreshErr)
+			}
+			if tc.revokedErr == nil && tc.refreshErr == nil {
+				mockTokenRepo.EXPECT().PurgeTokens(gomock.Any()).Return(int64(1), nil)
+			}
+
+			svc := service.NewRevocationService(&service.RevocationServiceDeps{
+				RevokedTokenRepo: mockRevokedRepo,
+				RefreshTokenRepo: mockRefreshRepo,
+				TokenRepo:        mockTokenRepo,
+				Cfg:              revocationConfig(),
+			})
+			purged, err := svc.PurgeExpiredTokens(context.Background())
+			if tc.revokedErr != nil || tc.refreshErr != nil {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.want, purged)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+//go:generate mockgen -destination=mock/role_service_mock.go -package=mock . RoleService
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ferdiebergado/gojeep/internal/repository"
+)
+
+// RoleService manages the roles of users and checks the permissions they grant.
+type RoleService interface {
+	UserRoles(ctx context.Context, userID string) ([]string, error)
+	HasPermission(ctx context.Context, userID, permission string) (bool, error)
+	AssignRole(ctx context.Context, userID, role string) error
+	RevokeRole(ctx context.Context, userID, role string) error
+}
+
+type RoleServiceDeps struct {
+	Repo      repository.RoleRepository
+	UserRepo  repository.UserRepository
+	AuditRepo repository.AuditRepository
+}
+
+type roleService struct {
+	repo      repository.RoleRepository
+	userRepo  repository.UserRepository
+	auditRepo repository.AuditRepository
+}
+
+var _ RoleService = (*roleService)(nil)
+
+// Audit events of role changes. The role is appended to the event.
+const (
+	eventRoleAssigned = "role_assigned"
+	eventRoleRevoked  = "role_revoked"
+)
+
+var (
+	ErrUnknownRole     = errors.New("unknown role")
+	ErrRoleNotAssigned = errors.New("role not assigned")
+)
+
+func NewRoleService(deps *RoleServiceDeps) RoleService {
+	return &roleService{
+		repo:      deps.Repo,
+		userRepo:  deps.UserRepo,
+		auditRepo: deps.AuditRepo,
+	}
+}
+
+func (s *roleService) UserRoles(ctx context.Context, userID string) ([]string, error) {
+	if err := s.ensureUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	roles, err := s.repo.FindUserRoles(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("find roles of user %s: %w", userID, err)
+	}
+	return roles, nil
+}
+
+// HasPermission reports whether the user currently holds a role granting the permission. It reads the
+// database rather than the roles in the access token, so revoking a role takes effect immediately.
+func (s *roleService) HasPermission(ctx context.Context, userID, permission string) (bool, error) {
+	ok, err := s.repo.HasPermission(ctx, userID, permission)
+	if err != nil {
+		return false, fmt.Errorf("check permission %s of user %s: %w", permission, userID, err)
+	}
+	return ok, nil
+}
+
+// AssignRole gives the user a role. The user's access tokens carry the role once they are next issued.
+func (s *roleService) AssignRole(ctx context.Context, userID, role string) error {
+	if err := s.ensureUser(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.AssignRole(ctx, userID, role); err != nil {
+		if errors.Is(err, repository.ErrUnknownRole) {
+			return ErrUnknownRole
+		}
+		return fmt.Errorf("assign role %s to user %s: %w", role, userID, err)
+	}
+
+	if err := s.auditRepo.CreateAuditEvent(ctx, userID, eventRoleAssigned+":"+role); err != nil {
+		return fmt.Errorf("record role assignment: %w", err)
+	}
+	return nil
+}
+
+func (s *roleService) RevokeRole(ctx context.Context, userID, role string) error {
+	if err := s.repo.RevokeRole(ctx, userID, role); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrRoleNotAssigned
+		}
+		return fmt.Errorf("revoke role %s of user %s: %w", role, userID, err)
+	}
+
+	if err := s.auditRepo.CreateAuditEvent(ctx, userID, eventRoleRevoked+":"+role); err != nil {
+		return fmt.Errorf("record role revocation: %w", err)
+	}
+	return nil
+}
+
+func (s *roleService) ensureUser(ctx context.Context, userID string) error {
+	if _, err := s.userRepo.FindUserByID(ctx, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("find user %s: %w", userID, err)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+	"github.com/ferdiebergado/gojeep/internal/repository/mock"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	roleUserID = "1"
+	roleAdmin  = "admin"
+)
+
+type roleMocks struct {
+	repo  *mock.MockRoleRepository
+	users *mock.MockUserRepository
+	audit *mock.MockAuditRepository
+}
+
+func newTestRoleService(t *testing.T) (service.RoleService, roleMocks) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	m := roleMocks{
+		repo:  mock.NewMockRoleRepository(ctrl),
+		users: mock.NewMockUserRepository(ctrl),
+		audit: mock.NewMockAuditRepository(ctrl),
+	}
+	svc := service.NewRoleService(&service.RoleServiceDeps{Repo: m.repo, UserRepo: m.users, AuditRepo: m.audit})
+	return svc, m
+}
+
+func TestRoleService_AssignRole(t *testing.T) {
+	t.Parallel()
+	user := model.User{Model: model.Model{ID: roleUserID}}
+
+	testCases := []struct {
+		name    string
+		setup   func(m roleMocks)
+		wantErr error
+	}{
+		{
+			name: "Assigned",
+			setup: func(m roleMocks) {
+				m.users.EXPECT().FindUserByID(gomock.Any(), roleUserID).Return(user, nil)
+				m.repo.EXPECT().AssignRole(gomock.Any(), roleUserID, roleAdmin).Return(nil)
+				m.audit.EXPECT().CreateAuditEvent(gomock.Any(), roleUserID, "role_assigned:admin").Return(nil)
+			},
+		},
+		{
+			name: "Unknown user",
+			setup: func(m roleMocks) {
+				m.users.EXPECT().FindUserByID(gomock.Any(), roleUserID).Return(model.User{}, sql.ErrNoRows)
+			},
+			wantErr: service.ErrUserNotFound,
+		},
+		{
+			name: "Unknown role",
+			setup: func(m roleMocks) {
+				m.users.EXPECT().FindUserByID(gomock.Any(), roleUserID).Return(user, nil)
+				m.repo.EXPECT().AssignRole(gomock.Any(), roleUserID, roleAdmin).Return(repository.ErrUnknownRole)
+			},
+			wantErr: service.ErrUnknownRole,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			svc, m := newTestRoleService(t)
+			tc.setup(m)
+
+			err := svc.AssignRole(context.Background(), roleUserID, roleAdmin)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestRoleService_RevokeRole(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		repoErr error
+		wantErr error
+	}{
+		{name: "Revoked"},
+		{name: "Role not assigned", repoErr: sql.ErrNoRows, wantErr: service.ErrRoleNotAssigned},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			svc, m := newTestRoleService(t)
+			m.repo.EXPECT().RevokeRole(gomock.Any(), roleUserID, roleAdmin).Return(tc.repoErr)
+			if tc.repoErr == nil {
+				m.audit.EXPECT().CreateAuditEvent(gomock.Any(), roleUserID, "role_revoked:admin").Return(nil)
+			}
+
+			err := svc.RevokeRole(context.Background(), roleUserID, roleAdmin)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestRoleService_UserRoles(t *testing.T) {
+	t.Parallel()
+	svc, m := newTestRoleService(t)
+	m.users.EXPECT().FindUserByID(gomock.Any(), roleUserID).
+		Return(model.User{Model: model.Model{ID: roleUserID}}, nil)
+	m.repo.EXPECT().FindUserRoles(gomock.Any(), roleUserID).Return([]string{roleAdmin}, nil)
+
+	roles, err := svc.UserRoles(context.Background(), roleUserID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{roleAdmin}, roles)
+}
@@ -8,28 +8,145 @@ import (
 )
 
 type Dependencies struct {
-	Repo   repository.Repository
-	Hasher security.Hasher
-	Signer security.Signer
-	Mailer email.Mailer
-	Cfg    *config.Config
+	Repo      repository.Repository
+	Hasher    security.Hasher
+	Signer    security.Signer
+	Encrypter security.Encrypter
+	Mailer    email.Mailer
+	Cfg       *config.Config
 }
 
 type Service struct {
-	Base BaseService
-	User AuthService
+	Base       BaseService
+	Auth       AuthService
+	User       UserService
+	MFA        MFAService
+	WebAuthn   WebAuthnService
+	Lockout    LockoutService
+	OAuth      OAuthService
+	MagicLink  MagicLinkService
+	Provider   ProviderService
+	Role       RoleService
+	APIKey     APIKeyService
+	Session    SessionService
+	Revocation RevocationService
+	Export     ExportService
+	Admin      AdminService
 }
 
 func NewService(deps *Dependencies) *Service {
-	userSvcDeps := &AuthServiceDeps{
-		Repo:   deps.Repo.User,
-		Hasher: deps.Hasher,
-		Signer: deps.Signer,
-		Mailer: deps.Mailer,
-		Cfg:    deps.Cfg,
+	lockoutSvc := NewLockoutService(&LockoutServiceDeps{
+		Repo: deps.Repo.LoginFailure,
+		Cfg:  deps.Cfg,
+	})
+	mfaSvc := NewMFAService(&MFAServiceDeps{
+		Repo:      deps.Repo.MFA,
+		UserRepo:  deps.Repo.User,
+		AuditRepo: deps.Repo.Audit,
+		Lockout:   lockoutSvc,
+		Encrypter: deps.Encrypter,
+		Hasher:    deps.Hasher,
+		Mailer:    deps.Mailer,
+		Cfg:       deps.Cfg,
+	})
+	revocationSvc := NewRevocationService(&RevocationServiceDeps{
+		UserRepo:         deps.Repo.User,
+		RevokedTokenRepo: deps.Repo.RevokedToken,
+		RefreshTokenRepo: deps.Repo.RefreshToken,
+		TokenRepo:        deps.Repo.Token,
+		Cfg:              deps.Cfg,
+	})
+	authSvcDeps := &AuthServiceDeps{
+		Repo:             deps.Repo.User,
+		RefreshTokenRepo: deps.Repo.RefreshToken,
+		RevokedTokenRepo: deps.Repo.RevokedToken,
+		TokenRepo:        deps.Repo.Token,
+		RoleRepo:         deps.Repo.Role,
+		SessionRepo:      deps.Repo.Session,
+		MFA:              mfaSvc,
+		Lockout:          lockoutSvc,
+		Revocation:       revocationSvc,
+		Hasher:           deps.Hasher,
+		Signer:           deps.Signer,
+		Mailer:           deps.Mailer,
+		Cfg:              deps.Cfg,
 	}
+	authSvc := NewAuthService(authSvcDeps)
+	webAuthnSvcDeps := &WebAuthnServiceDeps{
+		Repo:     deps.Repo.WebAuthn,
+		UserRepo: deps.Repo.User,
+		Auth:     authSvc,
+		Cfg:      deps.Cfg,
+	}
+	oauthSvcDeps := &OAuthServiceDeps{
+		Repo:     deps.Repo.OAuth,
+		UserRepo: deps.Repo.User,
+		Auth:     authSvc,
+		Hasher:   deps.Hasher,
+		Cfg:      deps.Cfg,
+	}
+	magicLinkSvcDeps := &MagicLinkServiceDeps{
+		UserRepo:  deps.Repo.User,
+		TokenRepo: deps.Repo.Token,
+		Auth:      authSvc,
+		Signer:    deps.Signer,
+		Mailer:    deps.Mailer,
+		Cfg:       deps.Cfg,
+	}
+	roleSvcDeps := &RoleServiceDeps{
+		Repo:      deps.Repo.Role,
+		UserRepo:  deps.Repo.User,
+		AuditRepo: deps.Repo.Audit,
+	}
+	userSvcDeps := &UserServiceDeps{
+		Repo:       deps.Repo.User,
+		Revocation: revocationSvc,
+		Cfg:        deps.Cfg,
+	}
+	exportSvcDeps := &ExportServiceDeps{
+		Repo:        deps.Repo.Export,
+		UserRepo:    deps.Repo.User,
+		SessionRepo: deps.Repo.Session,
+		AuditRepo:   deps.Repo.Audit,
+		OAuthRepo:   deps.Repo.OAuth,
+		Signer:      deps.Signer,
+		Mailer:      deps.Mailer,
+		Cfg:         deps.Cfg,
+	}
+	adminSvcDeps := &AdminServiceDeps{
+		UserRepo:   deps.Repo.User,
+		RoleRepo:   deps.Repo.Role,
+		AuditRepo:  deps.Repo.Audit,
+		Revocation: revocationSvc,
+	}
+
+	// The OpenID provider is optional; Provider stays nil unless it is configured.
+	var providerSvc ProviderService
+	if deps.Cfg.OIDC != nil {
+		providerSvc = NewProviderService(&ProviderServiceDeps{
+			Repo:       deps.Repo.Client,
+			UserRepo:   deps.Repo.User,
+			Revocation: revocationSvc,
+			Signer:     deps.Signer,
+			Cfg:        deps.Cfg,
+		})
+	}
+
 	return &Service{
-		Base: NewBaseService(deps.Repo.Base),
-		User: NewAuthService(userSvcDeps),
+		Base:       NewBaseService(deps.Repo.Base),
+		Auth:       authSvc,
+		User:       NewUserService(userSvcDeps),
+		MFA:        mfaSvc,
+		WebAuthn:   NewWebAuthnService(webAuthnSvcDeps),
+		Lockout:    lockoutSvc,
+		OAuth:      NewOAuthService(oauthSvcDeps),
+		MagicLink:  NewMagicLinkService(magicLinkSvcDeps),
+		Provider:   providerSvc,
+		Role:       NewRoleService(roleSvcDeps),
+		APIKey:     NewAPIKeyService(&APIKeyServiceDeps{Repo: deps.Repo.APIKey}),
+		Session:    NewSessionService(&SessionServiceDeps{Repo: deps.Repo.Session}),
+		Revocation: revocationSvc,
+		Export:     NewExportService(exportSvcDeps),
+		Admin:      NewAdminService(adminSvcDeps),
 	}
 }
@@ -0,0 +1,79 @@
+//go:generate mockgen -destination=mock/session_service_mock.go -package=mock . SessionService
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+)
+
+// SessionService lists where a user is signed in and signs individual devices out.
+type SessionService interface {
+	ListSessions(ctx context.Context, userID string) ([]model.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+	PurgeStaleSessions(ctx context.Context) (int64, error)
+}
+
+type SessionServiceDeps struct {
+	Repo repository.SessionRepository
+}
+
+type sessionService struct {
+	repo repository.SessionRepository
+}
+
+var _ SessionService = (*sessionService)(nil)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+func NewSessionService(deps *SessionServiceDeps) SessionService {
+	return &sessionService{repo: deps.Repo}
+}
+
+// ClientInfo describes the device a request came from. It is recorded with the sessions started by the
+// request.
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+type clientCtxKey struct{}
+
+// NewClientContext attaches the requesting device to ctx, for the sessions a login starts.
+func NewClientContext(ctx context.Context, client ClientInfo) context.Context {
+	return context.WithValue(ctx, clientCtxKey{}, client)
+}
+
+func clientFromContext(ctx context.Context) ClientInfo {
+	client, _ := ctx.Value(clientCtxKey{}).(ClientInfo)
+	return client
+}
+
+func (s *sessionService) ListSessions(ctx context.Context, userID string) ([]model.Session, error) {
+	sessions, err := s.repo.ListSessions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list sessions of user %s: %w", userID, err)
+	}
+	return sessions, nil
+}
+
+// RevokeSession signs a device out. Its refresh token stops working at once; its access token lasts
+// until it expires.
+func (s *sessionService) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	if err := s.repo.RevokeSession(ctx, userID, sessionID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrSessionNotFound
+		}
+		return fmt.Errorf("revoke session %s: %w", sessionID, err)
+	}
+	return nil
+}
+
+// PurgeStaleSessions deletes the sessions whose refresh tokens have all expired or been revoked.
+func (s *sessionService) PurgeStaleSessions(ctx context.Context) (int64, error) {
+	return s.repo.PurgeSessions(ctx)
+}
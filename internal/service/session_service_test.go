@@ -0,0 +1,154 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/config"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/pkg/security"
+	secMock "github.com/ferdiebergado/gojeep/internal/pkg/security/mock"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+	"github.com/ferdiebergado/gojeep/internal/repository/mock"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	svcMock "github.com/ferdiebergado/gojeep/internal/service/mock"
+)
+
+func TestAuthService_StartSession_RecordsClient(t *testing.T) {
+	t.Parallel()
+	const userID = "1"
+	ctrl := gomock.NewController(t)
+	users := mock.NewMockUserRepository(ctrl)
+	sessions := mock.NewMockSessionRepository(ctrl)
+	roles := mock.NewMockRoleRepository(ctrl)
+	refreshTokens := mock.NewMockRefreshTokenRepository(ctrl)
+	signer := secMock.NewMockSigner(ctrl)
+	cfg := &config.Config{
+		JWT: &config.JWTOptions{JTILen: 16, Issuer: "gojeep", Duration: 15, RefreshDuration: 60},
+	}
+
+	client := service.ClientInfo{UserAgent: "curl/8.0", IPAddress: "192.0.2.1"}
+	ctx := service.NewClientContext(context.Background(), client)
+
+	var familyID string
+	users.EXPECT().FindUserStatus(ctx, userID).Return(model.UserStatus{}, nil)
+	sessions.EXPECT().CreateSession(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, s model.Session) error {
+		assert.Equal(t, userID, s.UserID)
+		assert.Equal(t, client.UserAgent, s.UserAgent)
+		assert.Equal(t, client.IPAddress, s.IPAddress)
+		familyID = s.ID
+		return nil
+	})
+	roles.EXPECT().FindUserRoles(ctx, userID).Return([]string{}, nil)
+	signer.EXPECT().SignWithRoles(security.PurposeAccess, userID, gomock.Any(), []string{}, 15*time.Minute).
+		Return("access_token", nil)
+	signer.EXPECT().SignWithID(security.PurposeRefresh, gomock.Any(), userID, gomock.Any(), time.Hour).
+		Return("refresh_token", nil)
+	refreshTokens.EXPECT().CreateRefreshToken(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, p repository.CreateRefreshTokenParams) error {
+			assert.Equal(t, familyID, p.FamilyID)
+			return nil
+		})
+
+	svc := service.NewAuthService(&service.AuthServiceDeps{
+		Repo:             users,
+		RefreshTokenRepo: refreshTokens,
+		RoleRepo:         roles,
+		SessionRepo:      sessions,
+		Signer:           signer,
+		Cfg:              cfg,
+	})
+
+	result, err := svc.StartSession(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "refresh_token", result.RefreshToken)
+}
+
+func TestAuthService_StartSession_RestoresDeletedUser(t *testing.T) {
+	t.Parallel()
+	const userID = "1"
+
+	testCases := []struct {
+		name       string
+		restoreErr error
+		wantErr    error
+	}{
+		{name: "Restored"},
+		{name: "Purged since the login", restoreErr: sql.ErrNoRows, wantErr: service.ErrUserNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			users := mock.NewMockUserRepository(ctrl)
+			sessions := mock.NewMockSessionRepository(ctrl)
+			roles := mock.NewMockRoleRepository(ctrl)
+			refreshTokens := mock.NewMockRefreshTokenRepository(ctrl)
+			signer := secMock.NewMockSigner(ctrl)
+			revocation := svcMock.NewMockRevocationService(ctrl)
+			cfg := &config.Config{
+				JWT: &config.JWTOptions{JTILen: 16, Issuer: "gojeep", Duration: 15, RefreshDuration: 60},
+			}
+
+			ctx := context.Background()
+			users.EXPECT().FindUserStatus(ctx, userID).Return(model.UserStatus{}, sql.ErrNoRows)
+			restore := users.EXPECT().RestoreUser(ctx, userID).Return(tc.restoreErr)
+			if tc.wantErr == nil {
+				revocation.EXPECT().ForgetUser(userID).After(restore)
+				sessions.EXPECT().CreateSession(ctx, gomock.Any()).Return(nil).After(restore)
+				roles.EXPECT().FindUserRoles(ctx, userID).Return([]string{}, nil)
+				signer.EXPECT().SignWithRoles(security.PurposeAccess, userID, gomock.Any(), []string{}, 15*time.Minute).
+					Return("access_token", nil)
+				signer.EXPECT().SignWithID(security.PurposeRefresh, gomock.Any(), userID, gomock.Any(), time.Hour).
+					Return("refresh_token", nil)
+				refreshTokens.EXPECT().CreateRefreshToken(ctx, gomock.Any()).Return(nil)
+			}
+
+			svc := service.NewAuthService(&service.AuthServiceDeps{
+				Repo:             users,
+				RefreshTokenRepo: refreshTokens,
+				RoleRepo:         roles,
+				SessionRepo:      sessions,
+				Revocation:       revocation,
+				Signer:           signer,
+				Cfg:              cfg,
+			})
+
+			_, err := svc.StartSession(ctx, userID)
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
+
+func TestSessionService_RevokeSession(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		repoErr error
+		wantErr error
+	}{
+		{name: "Revoked"},
+		{name: "Unknown session", repoErr: sql.ErrNoRows, wantErr: service.ErrSessionNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			repo := mock.NewMockSessionRepository(ctrl)
+			repo.EXPECT().RevokeSession(gomock.Any(), "1", "family").Return(tc.repoErr)
+
+			svc := service.NewSessionService(&service.SessionServiceDeps{Repo: repo})
+			err := svc.RevokeSession(context.Background(), "1", "family")
+			assert.ErrorIs(t, err, tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+//go:generate mockgen -destination=mock/user_service_mock.go -package=mock . UserService
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/config"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+)
+
+// UserService manages the account of the signed-in user.
+type UserService interface {
+	GetUser(ctx context.Context, userID string) (model.User, error)
+	UpdateUser(ctx context.Context, userID string, params UpdateUserParams) (model.User, error)
+	DeleteUser(ctx context.Context, userID string) error
+	PurgeDeletedUsers(ctx context.Context) (int64, error)
+}
+
+type UserServiceDeps struct {
+	Repo       repository.UserRepository
+	Revocation RevocationService
+	Cfg        *config.Config
+}
+
+type userService struct {
+	repo       repository.UserRepository
+	revocation RevocationService
+	cfg        *config.Config
+}
+
+var _ UserService = (*userService)(nil)
+
+func NewUserService(deps *UserServiceDeps) UserService {
+	return &userService{
+		repo:       deps.Repo,
+		revocation: deps.Revocation,
+		cfg:        deps.Cfg,
+	}
+}
+
+type UpdateUserParams struct {
+	Name *string
+}
+
+func (s *userService) GetUser(ctx context.Context, userID string) (model.User, error) {
+	user, err := s.repo.FindUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.User{}, ErrUserNotFound
+		}
+		return model.User{}, fmt.Errorf("find user %s: %w", userID, err)
+	}
+	return user, nil
+}
+
+func (s *userService) UpdateUser(ctx context.Context, userID string, params UpdateUserParams) (model.User, error) {
+	user, err := s.repo.UpdateUser(ctx, userID, repository.UpdateUserParams{Name: params.Name})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.User{}, ErrUserNotFound
+		}
+		return model.User{}, fmt.Errorf("update user %s: %w", userID, err)
+	}
+	return user, nil
+}
+
+// DeleteUser closes the account and signs it out everywhere. Logging in with the password during the
+// grace period restores it; after that PurgeDeletedUsers removes it for good.
+func (s *userService) DeleteUser(ctx context.Context, userID string) error {
+	if err := s.repo.DeleteUser(ctx, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("delete user %s: %w", userID, err)
+	}
+	s.revocation.ForgetUser(userID)
+	return nil
+}
+
+// PurgeDeletedUsers removes the accounts whose deletion grace period has passed.
+func (s *userService) PurgeDeletedUsers(ctx context.Context) (int64, error) {
+	gracePeriod := time.Duration(s.cfg.Account.DeletionGracePeriod) * time.Second
+	purged, err := s.repo.PurgeDeletedUsers(ctx, time.Now().Add(-gracePeriod))
+	if err != nil {
+		return 0, fmt.Errorf("purge deleted users: %w", err)
+	}
+	return purged, nil
+}
@@ -0,0 +1,124 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/config"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+	"github.com/ferdiebergado/gojeep/internal/repository/mock"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	svcMock "github.com/ferdiebergado/gojeep/internal/service/mock"
+)
+
+func TestUserService_GetUser(t *testing.T) {
+	t.Parallel()
+	const userID = "1"
+
+	testCases := []struct {
+		name    string
+		repoErr error
+		wantErr error
+	}{
+		{name: "Found"},
+		{name: "Not found", repoErr: sql.ErrNoRows, wantErr: service.ErrUserNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockRepo := mock.NewMockUserRepository(ctrl)
+			mockRepo.EXPECT().FindUserByID(gomock.Any(), userID).
+				Return(model.User{Model: model.Model{ID: userID}}, tc.repoErr)
+
+			svc := service.NewUserService(&service.UserServiceDeps{Repo: mockRepo})
+			user, err := svc.GetUser(context.Background(), userID)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, userID, user.ID)
+		})
+	}
+}
+
+func TestUserService_UpdateUser(t *testing.T) {
+	t.Parallel()
+	const userID = "1"
+	name := "New Name"
+
+	ctrl := gomock.NewController(t)
+	mockRepo := mock.NewMockUserRepository(ctrl)
+	mockRepo.EXPECT().UpdateUser(gomock.Any(), userID, repository.UpdateUserParams{Name: &name}).
+		Return(model.User{Model: model.Model{ID: userID}, Name: name}, nil)
+
+	svc := service.NewUserService(&service.UserServiceDeps{Repo: mockRepo})
+	user, err := svc.UpdateUser(context.Background(), userID, service.UpdateUserParams{Name: &name})
+	assert.NoError(t, err)
+	assert.Equal(t, name, user.Name)
+}
+
+func TestUserService_DeleteUser(t *testing.T) {
+	t.Parallel()
+	const userID = "1"
+
+	testCases := []struct {
+		name    string
+		repoErr error
+		wantErr error
+	}{
+		{name: "Deleted"},
+		{name: "Not found", repoErr: sql.ErrNoRows, wantErr: service.ErrUserNotFound},
+		{name: "Database error", repoErr: errors.New("connection refused")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ctrl := gomock.NewController(t)
+			mockRepo := mock.NewMockUserRepository(ctrl)
+			mockRepo.EXPECT().DeleteUser(gomock.Any(), userID).Return(tc.repoErr)
+			mockRevocation := svcMock.NewMockRevocationService(ctrl)
+			if tc.repoErr == nil {
+				mockRevocation.EXPECT().ForgetUser(userID)
+			}
+
+			svc := service.NewUserService(&service.UserServiceDeps{Repo: mockRepo, Revocation: mockRevocation})
+			err := svc.DeleteUser(context.Background(), userID)
+			switch {
+			case tc.wantErr != nil:
+				assert.ErrorIs(t, err, tc.wantErr)
+			case tc.repoErr != nil:
+				assert.ErrorIs(t, err, tc.repoErr)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUserService_PurgeDeletedUsers(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	mockRepo := mock.NewMockUserRepository(ctrl)
+
+	const gracePeriod = 3600
+	cfg := &config.Config{Account: &config.AccountOptions{DeletionGracePeriod: gracePeriod}}
+	mockRepo.EXPECT().PurgeDeletedUsers(gomock.Any(), gomock.Cond(func(before time.Time) bool {
+		return time.Until(before) < -(gracePeriod*time.Second - time.Minute)
+	})).Return(int64(2), nil)
+
+	svc := service.NewUserService(&service.UserServiceDeps{Repo: mockRepo, Cfg: cfg})
+	purged, err := svc.PurgeDeletedUsers(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+}
@@ -0,0 +1,253 @@
+//go:generate mockgen -destination=mock/webauthn_service_mock.go -package=mock . WebAuthnService
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ferdiebergado/gojeep/internal/config"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/pkg/webauthn"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+)
+
+// WebAuthnService registers passkeys and signs users in with them.
+type WebAuthnService interface {
+	BeginRegistration(ctx context.Context, userID string) (webauthn.CreationOptions, error)
+	FinishRegistration(ctx context.Context, userID string, res webauthn.RegistrationResponse) error
+	BeginLogin(ctx context.Context) (webauthn.RequestOptions, error)
+	FinishLogin(ctx context.Context, res webauthn.LoginResponse) (accessToken, refreshToken string, err error)
+	PurgeExpiredChallenges(ctx context.Context) (int64, error)
+}
+
+type WebAuthnServiceDeps struct {
+	Repo     repository.WebAuthnRepository
+	UserRepo repository.UserRepository
+	Auth     AuthService
+	Cfg      *config.Config
+}
+
+type webAuthnService struct {
+	repo     repository.WebAuthnRepository
+	userRepo repository.UserRepository
+	auth     AuthService
+	rp       webauthn.RelyingParty
+	ttl      time.Duration
+}
+
+var _ WebAuthnService = (*webAuthnService)(nil)
+
+// Ceremonies a challenge is issued for.
+const (
+	ceremonyRegistration = "registration"
+	ceremonyLogin        = "login"
+)
+
+var ErrInvalidPasskey = errors.New("invalid passkey response")
+
+func NewWebAuthnService(deps *WebAuthnServiceDeps) WebAuthnService {
+	opts := deps.Cfg.WebAuthn
+	return &webAuthnService{
+		repo:     deps.Repo,
+		userRepo: deps.UserRepo,
+		auth:     deps.Auth,
+		rp:       webauthn.RelyingParty{ID: opts.RPID, Name: opts.RPName, Origin: opts.Origin},
+		ttl:      time.Duration(opts.ChallengeTTL) * time.Second,
+	}
+}
+
+// BeginRegistration returns the options to create a passkey for the signed-in user.
+func (s *webAuthnService) BeginRegistration(ctx context.Context, userID string) (webauthn.CreationOptions, error) {
+	user, err := s.userRepo.FindUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return webauthn.CreationOptions{}, ErrUserNotFound
+		}
+		return webauthn.CreationOptions{}, err
+	}
+
+	existing, err := s.repo.ListCredentialIDs(ctx, userID)
+	if err != nil {
+		return webauthn.CreationOptions{}, fmt.Errorf("list credentials of user %s: %w", userID, err)
+	}
+
+	challenge, err := s.newChallenge(ctx, userID, ceremonyRegistration)
+	if err != nil {
+		return webauthn.CreationOptions{}, err
+	}
+
+	displayName := user.Name
+	if displayName == "" {
+		displayName = user.Email
+	}
+	entity := webauthn.UserEntity{
+		ID:          userHandle(user.ID),
+		Name:        user.Email,
+		DisplayName: displayName,
+	}
+
+	return s.rp.CreationOptions(challenge, entity, existing, s.ttl), nil
+}
+
+// FinishRegistration verifies the authenticator's response and stores the new passkey.
+func (s *webAuthnService) FinishRegistration(ctx context.Context, userID string,
+	res webauthn.RegistrationResponse) error {
+	challenge, err := s.consumeChallenge(ctx, res.Response.ClientDataJSON, ceremonyRegistration)
+	if err != nil {
+		return err
+	}
+
+	if challenge.userID != userID {
+		return fmt.Errorf("%w: challenge issued to another user", ErrInvalidPasskey)
+	}
+
+	cred, err := s.rp.VerifyRegistration(res, challenge.value)
+	if err != nil {
+		return passkeyError(err)
+	}
+
+	credential := model.Credential{
+		ID:        cred.ID,
+		UserID:    userID,
+		PublicKey: cred.PublicKey,
+		SignCount: cred.SignCount,
+	}
+	if err := s.repo.CreateCredential(ctx, credential); err != nil {
+		if errors.Is(err, repository.ErrCredentialExists) {
+			return fmt.Errorf("%w: %w", ErrInvalidPasskey, err)
+		}
+		return fmt.Errorf("create credential of user %s: %w", userID, err)
+	}
+
+	return nil
+}
+
+// BeginLogin returns the options to sign in with any passkey of the site. The user is identified by
+// the credential the authenticator picks, so no email is asked for.
+func (s *webAuthnService) BeginLogin(ctx context.Context) (webauthn.RequestOptions, error) {
+	challenge, err := s.newChallenge(ctx, "", ceremonyLogin)
+	if err != nil {
+		return webauthn.RequestOptions{}, err
+	}
+
+	return s.rp.RequestOptions(challenge, s.ttl), nil
+}
+
+// FinishLogin verifies an assertion and starts a session for the credential's owner.
+func (s *webAuthnService) FinishLogin(ctx context.Context,
+	res webauthn.LoginResponse) (accessToken, refreshToken string, err error) {
+	challenge, err := s.consumeChallenge(ctx, res.Response.ClientDataJSON, ceremonyLogin)
+	if err != nil {
+		return "", "", err
+	}
+
+	cred, err := s.repo.FindCredential(ctx, res.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", "", fmt.Errorf("%w: unknown credential", ErrInvalidPasskey)
+		}
+		return "", "", fmt.Errorf("find credential: %w", err)
+	}
+
+	if res.Response.UserHandle != "" && res.Response.UserHandle != userHandle(cred.UserID) {
+		return "", "", fmt.Errorf("%w: user handle mismatch", ErrInvalidPasskey)
+	}
+
+	signCount, err := s.rp.VerifyLogin(res, challenge.value, cred.PublicKey, cred.SignCount)
+	if err != nil {
+		if errors.Is(err, webauthn.ErrSignCount) {
+			slog.Warn("Passkey signature counter went backwards, possible cloned authenticator",
+				"credential_id", cred.ID, "user_id", cred.UserID)
+		}
+		return "", "", passkeyError(err)
+	}
+
+	if err := s.repo.UpdateCredentialSignCount(ctx, cred.ID, signCount); err != nil {
+		return "", "", fmt.Errorf("update credential %s: %w", cred.ID, err)
+	}
+
+	// The passkeys of a deleted account are kept for the grace period, but do not sign in to it.
+	if _, err := s.userRepo.FindUserByID(ctx, cred.UserID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", "", fmt.Errorf("%w: user deleted", ErrInvalidPasskey)
+		}
+		return "", "", fmt.Errorf("find user %s: %w", cred.UserID, err)
+	}
+
+	result, err := s.auth.StartSession(ctx, cred.UserID)
+	if err != nil {
+		return "", "", err
+	}
+
+	return result.AccessToken, result.RefreshToken, nil
+}
+
+func (s *webAuthnService) PurgeExpiredChallenges(ctx context.Context) (int64, error) {
+	purged, err := s.repo.PurgeChallenges(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("purge webauthn challenges: %w", err)
+	}
+	return purged, nil
+}
+
+func (s *webAuthnService) newChallenge(ctx context.Context, userID, ceremony string) (string, error) {
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return "", fmt.Errorf("generate webauthn challenge: %w", err)
+	}
+
+	params := repository.SaveChallengeParams{
+		Challenge: challenge,
+		UserID:    userID,
+		Ceremony:  ceremony,
+		ExpiresAt: time.Now().Add(s.ttl),
+	}
+	if err := s.repo.SaveChallenge(ctx, params); err != nil {
+		return "", fmt.Errorf("save webauthn challenge: %w", err)
+	}
+
+	return challenge, nil
+}
+
+type issuedChallenge struct {
+	value  string
+	userID string
+}
+
+// consumeChallenge uses up the challenge a response was made for, so each response is accepted once and
+// only before the challenge expires.
+func (s *webAuthnService) consumeChallenge(ctx context.Context, clientDataJSON,
+	ceremony string) (issuedChallenge, error) {
+	challenge, err := webauthn.ResponseChallenge(clientDataJSON)
+	if err != nil {
+		return issuedChallenge{}, passkeyError(err)
+	}
+
+	userID, err := s.repo.ConsumeChallenge(ctx, challenge, ceremony)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return issuedChallenge{}, fmt.Errorf("%w: unknown or expired challenge", ErrInvalidPasskey)
+		}
+		return issuedChallenge{}, fmt.Errorf("consume webauthn challenge: %w", err)
+	}
+
+	return issuedChallenge{value: challenge, userID: userID}, nil
+}
+
+// passkeyError maps failed checks of a response to ErrInvalidPasskey.
+func passkeyError(err error) error {
+	if errors.Is(err, webauthn.ErrInvalidResponse) || errors.Is(err, webauthn.ErrSignCount) {
+		return fmt.Errorf("%w: %w", ErrInvalidPasskey, err)
+	}
+	return err
+}
+
+// userHandle is the WebAuthn user handle of a user: the base64url user ID, which carries no personal data.
+func userHandle(userID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(userID))
+}
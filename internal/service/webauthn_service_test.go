@@ -0,0 +1,231 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"testing"
+
+	"github.com/ferdiebergado/gojeep/internal/config"
+	"github.com/ferdiebergado/gojeep/internal/model"
+	"github.com/ferdiebergado/gojeep/internal/pkg/webauthn"
+	"github.com/ferdiebergado/gojeep/internal/pkg/webauthn/webauthntest"
+	"github.com/ferdiebergado/gojeep/internal/repository"
+	"github.com/ferdiebergado/gojeep/internal/repository/mock"
+	"github.com/ferdiebergado/gojeep/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	svcMock "github.com/ferdiebergado/gojeep/internal/service/mock"
+)
+
+const (
+	passkeyUserID = "8b2d3f4e-1a2b-4c5d-9e8f-0a1b2c3d4e5f"
+	passkeyOrigin = "https://gojeep.test"
+)
+
+func passkeyUser() model.User {
+	return model.User{Model: model.Model{ID: passkeyUserID}, Email: "passkey@example.com"}
+}
+
+type webAuthnMocks struct {
+	repo     *mock.MockWebAuthnRepository
+	userRepo *mock.MockUserRepository
+	auth     *svcMock.MockAuthService
+}
+
+func newTestWebAuthnService(t *testing.T) (service.WebAuthnService, webAuthnMocks) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	m := webAuthnMocks{
+		repo:     mock.NewMockWebAuthnRepository(ctrl),
+		userRepo: mock.NewMockUserRepository(ctrl),
+		auth:     svcMock.NewMockAuthService(ctrl),
+	}
+
+	cfg := &config.Config{
+		WebAuthn: &config.WebAuthnOptions{
+			RPID:         "gojeep.test",
+			RPName:       "gojeep",
+			Origin:       passkeyOrigin,
+			ChallengeTTL: 300,
+		},
+	}
+	svc := service.NewWebAuthnService(&service.WebAuthnServiceDeps{
+		Repo:     m.repo,
+		UserRepo: m.userRepo,
+		Auth:     m.auth,
+		Cfg:      cfg,
+	})
+	return svc, m
+}
+
+// register runs a registration ceremony with the software authenticator and returns the stored credential.
+func register(t *testing.T, svc service.WebAuthnService, m webAuthnMocks,
+	authenticator *webauthntest.Authenticator) model.Credential {
+	t.Helper()
+	ctx := context.Background()
+
+	var challenge string
+	m.userRepo.EXPECT().FindUserByID(ctx, passkeyUserID).Return(passkeyUser(), nil)
+	m.repo.EXPECT().ListCredentialIDs(ctx, passkeyUserID).Return(nil, nil)
+	m.repo.EXPECT().SaveChallenge(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, params repository.SaveChallengeParams) error {
+			challenge = params.Challenge
+			assert.Equal(t, passkeyUserID, params.UserID)
+			return nil
+		})
+
+	opts, err := svc.BeginRegistration(ctx, passkeyUserID)
+	require.NoError(t, err)
+	assert.Equal(t, challenge, opts.Challenge)
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString([]byte(passkeyUserID)), opts.User.ID)
+
+	res, err := authenticator.Register(opts)
+	require.NoError(t, err)
+
+	var stored model.Credential
+	m.repo.EXPECT().ConsumeChallenge(ctx, challenge, "registration").Return(passkeyUserID, nil)
+	m.repo.EXPECT().CreateCredential(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, cred model.Credential) error {
+			stored = cred
+			return nil
+		})
+
+	require.NoError(t, svc.FinishRegistration(ctx, passkeyUserID, res))
+	assert.Equal(t, authenticator.CredentialID(), stored.ID)
+	assert.Equal(t, passkeyUserID, stored.UserID)
+	return stored
+}
+
+// beginLogin starts a login ceremony and returns the authenticator's assertion for it.
+func beginLogin(t *testing.T, svc service.WebAuthnService, m webAuthnMocks,
+	authenticator *webauthntest.Authenticator) (webauthn.LoginResponse, string) {
+	t.Helper()
+	ctx := context.Background()
+
+	var challenge string
+	m.repo.EXPECT().SaveChallenge(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, params repository.SaveChallengeParams) error {
+			challenge = params.Challenge
+			assert.Empty(t, params.UserID)
+			return nil
+		})
+
+	opts, err := svc.BeginLogin(ctx)
+	require.NoError(t, err)
+
+	res, err := authenticator.Login(opts)
+	require.NoError(t, err)
+	return res, challenge
+}
+
+func TestWebAuthnService_RegisterAndLogin(t *testing.T) {
+	t.Parallel()
+	svc, m := newTestWebAuthnService(t)
+	authenticator, err := webauthntest.NewAuthenticator(passkeyOrigin)
+	require.NoError(t, err)
+
+	cred := register(t, svc, m, authenticator)
+
+	ctx := context.Background()
+	res, challenge := beginLogin(t, svc, m, authenticator)
+	m.repo.EXPECT().ConsumeChallenge(ctx, challenge, "login").Return("", nil)
+	m.repo.EXPECT().FindCredential(ctx, cred.ID).Return(cred, nil)
+	m.repo.EXPECT().UpdateCredentialSignCount(ctx, cred.ID, gomock.Any()).Return(nil)
+	m.userRepo.EXPECT().FindUserByID(ctx, passkeyUserID).Return(passkeyUser(), nil)
+	m.auth.EXPECT().StartSession(ctx, passkeyUserID).
+		Return(service.LoginResult{AccessToken: "access", RefreshToken: "refresh"}, nil)
+
+	accessToken, refreshToken, err := svc.FinishLogin(ctx, res)
+	require.NoError(t, err)
+	assert.Equal(t, "access", accessToken)
+	assert.Equal(t, "refresh", refreshToken)
+}
+
+func TestWebAuthnService_FinishRegistration_ChallengeOfAnotherUser(t *testing.T) {
+	t.Parallel()
+	svc, m := newTestWebAuthnService(t)
+	authenticator, err := webauthntest.NewAuthenticator(passkeyOrigin)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	m.userRepo.EXPECT().FindUserByID(ctx, passkeyUserID).Return(passkeyUser(), nil)
+	m.repo.EXPECT().ListCredentialIDs(ctx, passkeyUserID).Return(nil, nil)
+	m.repo.EXPECT().SaveChallenge(ctx, gomock.Any()).Return(nil)
+
+	opts, err := svc.BeginRegistration(ctx, passkeyUserID)
+	require.NoError(t, err)
+	res, err := authenticator.Register(opts)
+	require.NoError(t, err)
+
+	m.repo.EXPECT().ConsumeChallenge(ctx, opts.Challenge, "registration").Return("another-user", nil)
+
+	err = svc.FinishRegistration(ctx, passkeyUserID, res)
+	assert.ErrorIs(t, err, service.ErrInvalidPasskey)
+}
+
+func TestWebAuthnService_FinishLogin_Rejected(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name  string
+		setup func(m webAuthnMocks, cred model.Credential, challenge string)
+	}{
+		{
+			name: "Unknown or expired challenge",
+			setup: func(m webAuthnMocks, _ model.Credential, challenge string) {
+				m.repo.EXPECT().ConsumeChallenge(gomock.Any(), challenge, "login").Return("", sql.ErrNoRows)
+			},
+		},
+		{
+			name: "Unknown credential",
+			setup: func(m webAuthnMocks, cred model.Credential, challenge string) {
+				m.repo.EXPECT().ConsumeChallenge(gomock.Any(), challenge, "login").Return("", nil)
+				m.repo.EXPECT().FindCredential(gomock.Any(), cred.ID).Return(model.Credential{}, sql.ErrNoRows)
+			},
+		},
+		{
+			name: "Credential of another user",
+			setup: func(m webAuthnMocks, cred model.Credential, challenge string) {
+				cred.UserID = "another-user"
+				m.repo.EXPECT().ConsumeChallenge(gomock.Any(), challenge, "login").Return("", nil)
+				m.repo.EXPECT().FindCredential(gomock.Any(), cred.ID).Return(cred, nil)
+			},
+		},
+		{
+			name: "Signature counter went backwards",
+			setup: func(m webAuthnMocks, cred model.Credential, challenge string) {
+				cred.SignCount = 1 << 20
+				m.repo.EXPECT().ConsumeChallenge(gomock.Any(), challenge, "login").Return("", nil)
+				m.repo.EXPECT().FindCredential(gomock.Any(), cred.ID).Return(cred, nil)
+			},
+		},
+		{
+			name: "Deleted user",
+			setup: func(m webAuthnMocks, cred model.Credential, challenge string) {
+				m.repo.EXPECT().ConsumeChallenge(gomock.Any(), challenge, "login").Return("", nil)
+				m.repo.EXPECT().FindCredential(gomock.Any(), cred.ID).Return(cred, nil)
+				m.repo.EXPECT().UpdateCredentialSignCount(gomock.Any(), cred.ID, gomock.Any()).Return(nil)
+				m.userRepo.EXPECT().FindUserByID(gomock.Any(), cred.UserID).Return(model.User{}, sql.ErrNoRows)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			svc, m := newTestWebAuthnService(t)
+			authenticator, err := webauthntest.NewAuthenticator(passkeyOrigin)
+			require.NoError(t, err)
+
+			cred := register(t, svc, m, authenticator)
+			res, challenge := beginLogin(t, svc, m, authenticator)
+			tc.setup(m, cred, challenge)
+
+			_, _, err = svc.FinishLogin(context.Background(), res)
+			assert.ErrorIs(t, err, service.ErrInvalidPasskey)
+		})
+	}
+}